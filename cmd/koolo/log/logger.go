@@ -7,6 +7,9 @@ import (
 	"log/slog"
 	"os"
 	"time"
+
+	"github.com/hectorgimenez/koolo/internal/config/secrets"
+	"github.com/hectorgimenez/koolo/internal/logstream"
 )
 
 var logFileHandler *os.File
@@ -58,7 +61,7 @@ func NewLogger(debug bool, logDir, supervisor string) (*slog.Logger, error) {
 		Level: level,
 		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
 			if a.Key != slog.TimeKey {
-				return a
+				return redactAttr(a)
 			}
 
 			t := a.Value.Time()
@@ -69,5 +72,33 @@ func NewLogger(debug bool, logDir, supervisor string) (*slog.Logger, error) {
 	}
 	handler := slog.NewTextHandler(io.MultiWriter(logFileHandler, os.Stdout), opts)
 
-	return slog.New(handler), nil
+	// Records are kept in memory too, to be followed and searched from the web UI
+	return slog.New(logstream.NewHandler(handler, logstream.Default, supervisor)), nil
+}
+
+// redactAttr hides the secrets, like passwords and tokens, that end up in log messages or values. Only strings,
+// errors and Stringers are inspected, formatting every other value just to search it would be too expensive.
+func redactAttr(a slog.Attr) slog.Attr {
+	var value string
+	switch a.Value.Kind() {
+	case slog.KindString:
+		value = a.Value.String()
+	case slog.KindAny:
+		switch v := a.Value.Any().(type) {
+		case error:
+			value = v.Error()
+		case fmt.Stringer:
+			value = v.String()
+		default:
+			return a
+		}
+	default:
+		return a
+	}
+
+	if redacted := secrets.Redact(value); redacted != value {
+		a.Value = slog.StringValue(redacted)
+	}
+
+	return a
 }
@@ -6,6 +6,7 @@ import (
 	"log"
 	"log/slog"
 	_ "net/http/pprof"
+	"os"
 	"path/filepath"
 	"runtime/debug"
 
@@ -15,7 +16,9 @@ import (
 	"github.com/hectorgimenez/koolo/internal/event"
 	"github.com/hectorgimenez/koolo/internal/remote/discord"
 	"github.com/hectorgimenez/koolo/internal/remote/droplog"
+	"github.com/hectorgimenez/koolo/internal/remote/notify"
 	"github.com/hectorgimenez/koolo/internal/remote/telegram"
+	"github.com/hectorgimenez/koolo/internal/retention"
 	"github.com/hectorgimenez/koolo/internal/server"
 	"github.com/hectorgimenez/koolo/internal/utils"
 	"github.com/hectorgimenez/koolo/internal/utils/winproc"
@@ -48,6 +51,10 @@ func main() {
 	_ = buildID
 	_ = buildTime
 
+	if len(os.Args) > 1 && os.Args[1] == "secrets" {
+		os.Exit(runSecretsCommand(os.Args[2:]))
+	}
+
 	err := config.Load()
 	if err != nil {
 		utils.ShowDialog("Error loading configuration", err.Error())
@@ -90,7 +97,13 @@ func main() {
 	manager := bot.NewSupervisorManager(logger, eventListener)
 	scheduler := bot.NewScheduler(manager, logger)
 	go scheduler.Start()
-	srv, err := server.New(logger, manager)
+	configWatcher := bot.NewConfigWatcher(manager, logger)
+	go configWatcher.Start()
+	retentionService := retention.NewService(logger, config.RetentionSettings)
+	g.Go(wrapWithRecover(logger, func() error {
+		return retentionService.Start(ctx)
+	}))
+	srv, err := server.New(logger, manager, retentionService)
 	if err != nil {
 		log.Fatalf("Error starting local server: %s", err.Error())
 	}
@@ -122,29 +135,55 @@ func main() {
 		return nil
 	}))
 
+	// Notification router, when enabled it replaces the per-notifier event filtering
+	var notificationRouter *notify.Router
+	if config.Koolo.Notifications.Enabled {
+		notificationRouter = notify.NewRouter(logger)
+		eventListener.Register(notificationRouter.Handle)
+		g.Go(wrapWithRecover(logger, func() error {
+			return notificationRouter.Start(ctx)
+		}))
+	}
+
+	// Notifiers whose token is still a secret reference wait for the secrets store to be unlocked and koolo restarted
+	if config.Koolo.Discord.Enabled && !config.Koolo.SecretResolved("discord.token") {
+		logger.Warn("Discord disabled, its token can't be read from the locked secrets store")
+	}
+	if config.Koolo.Telegram.Enabled && !config.Koolo.SecretResolved("telegram.token") {
+		logger.Warn("Telegram disabled, its token can't be read from the locked secrets store")
+	}
+
 	// Discord Bot initialization
-	if config.Koolo.Discord.Enabled {
+	if config.Koolo.Discord.Enabled && config.Koolo.SecretResolved("discord.token") {
 		discordBot, err := discord.NewBot(config.Koolo.Discord.Token, config.Koolo.Discord.ChannelID, manager)
 		if err != nil {
 			logger.Error("Discord could not been initialized", slog.Any("error", err))
 			return
 		}
 
-		eventListener.Register(discordBot.Handle)
+		if notificationRouter != nil {
+			notificationRouter.Register(notify.ChannelDiscord, discordBot)
+		} else {
+			eventListener.Register(discordBot.Handle)
+		}
 		g.Go(wrapWithRecover(logger, func() error {
 			return discordBot.Start(ctx)
 		}))
 	}
 
 	// Telegram Bot initialization
-	if config.Koolo.Telegram.Enabled {
+	if config.Koolo.Telegram.Enabled && config.Koolo.SecretResolved("telegram.token") {
 		telegramBot, err := telegram.NewBot(config.Koolo.Telegram.Token, config.Koolo.Telegram.ChatID, logger)
 		if err != nil {
 			logger.Error("Telegram could not been initialized", slog.Any("error", err))
 			return
 		}
 
-		eventListener.Register(telegramBot.Handle)
+		if notificationRouter != nil {
+			notificationRouter.Register(notify.ChannelTelegram, telegramBot)
+		} else {
+			eventListener.Register(telegramBot.Handle)
+		}
 		g.Go(wrapWithRecover(logger, func() error {
 			return telegramBot.Start(ctx)
 		}))
@@ -166,6 +205,7 @@ func main() {
 		cancel()
 		manager.StopAll()
 		scheduler.Stop()
+		configWatcher.Stop()
 		err = srv.Stop()
 		if err != nil {
 			logger.Error("error stopping local server", slog.Any("error", err))
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hectorgimenez/koolo/internal/config"
+	"github.com/hectorgimenez/koolo/internal/config/secrets"
+)
+
+const secretsUsage = `Usage: koolo secrets <command>
+
+Commands:
+  init-keyfile          create the keyfile, the master key of the secrets store, in the user config folder
+  list                  list the stored secret names
+  set <name> [value]    store or rotate a secret, the value is read from stdin when not given
+  delete <name>         delete a secret
+  rotate-key            encrypt the secrets with a new keyfile, or with the passphrase in KOOLO_SECRETS_NEW_PASSPHRASE
+
+Reference a secret from a config file with "secret://<name>". The store is opened with the keyfile in
+KOOLO_SECRETS_KEYFILE, the koolo/secrets.key keyfile of the user config folder (%AppData% on Windows) or the
+passphrase in ` + secrets.PassphraseEnv + `.`
+
+// runSecretsCommand manages the secrets store from the command line, returns the process exit code
+func runSecretsCommand(args []string) int {
+	if len(args) == 0 {
+		fmt.Println(secretsUsage)
+		return 2
+	}
+
+	if err := secretsCommand(args[0], args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+
+	return 0
+}
+
+func secretsCommand(command string, args []string) error {
+	if command == "init-keyfile" {
+		return config.GenerateSecretsKeyfile()
+	}
+	if command == "rotate-key" {
+		return config.RotateSecretsKey(os.Getenv("KOOLO_SECRETS_NEW_PASSPHRASE"))
+	}
+
+	store, err := config.SecretsStore()
+	if err != nil {
+		return err
+	}
+
+	switch command {
+	case "list":
+		for _, name := range store.Names() {
+			fmt.Println(name)
+		}
+		return nil
+	case "set":
+		if len(args) == 0 {
+			return fmt.Errorf("missing secret name")
+		}
+		value := ""
+		if len(args) > 1 {
+			value = args[1]
+		} else {
+			// Reading from stdin keeps the value out of the shell history
+			value, err = bufio.NewReader(os.Stdin).ReadString('\n')
+			if err != nil && value == "" {
+				return fmt.Errorf("error reading secret value: %w", err)
+			}
+		}
+		if err = store.Set(args[0], strings.TrimRight(value, "\r\n")); err != nil {
+			return err
+		}
+		fmt.Printf("Secret stored, reference it with %s\n", secrets.Reference(args[0]))
+		return nil
+	case "delete":
+		if len(args) == 0 {
+			return fmt.Errorf("missing secret name")
+		}
+		return store.Delete(args[0])
+	}
+
+	return fmt.Errorf("unknown command %q\n%s", command, secretsUsage)
+}
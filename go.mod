@@ -5,12 +5,14 @@ go 1.23
 require (
 	github.com/billgraziano/dpapi v0.5.0
 	github.com/bwmarrin/discordgo v0.28.1
+	github.com/expr-lang/expr v1.16.9
 	github.com/go-telegram-bot-api/telegram-bot-api/v5 v5.5.1
 	github.com/gorilla/websocket v1.5.3
 	github.com/hectorgimenez/d2go v0.0.0-20250314185000-169a76515285
 	github.com/inkeliz/gowebview v1.0.1
 	github.com/lxn/win v0.0.0-20210218163916-a377121e959e
 	github.com/otiai10/copy v1.14.0
+	golang.org/x/crypto v0.31.0
 	golang.org/x/sync v0.10.0
 	golang.org/x/sys v0.28.0
 	gopkg.in/yaml.v3 v3.0.1
@@ -18,10 +20,8 @@ require (
 
 require (
 	git.wow.st/gmp/jni v0.0.0-20210610011705-34026c7e22d0 // indirect
-	github.com/expr-lang/expr v1.16.9 // indirect
 	github.com/inkeliz/w32 v1.0.2 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
-	golang.org/x/crypto v0.31.0 // indirect
 )
 
 replace github.com/hectorgimenez/d2go => github.com/kwader2k/d2go v0.0.0-20251130084418-9dfe4cd042e6
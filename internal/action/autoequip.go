@@ -12,6 +12,7 @@ import (
 	"github.com/hectorgimenez/d2go/pkg/data/skill"
 	"github.com/hectorgimenez/d2go/pkg/data/stat"
 	"github.com/hectorgimenez/koolo/internal/action/step"
+	"github.com/hectorgimenez/koolo/internal/config"
 	"github.com/hectorgimenez/koolo/internal/context"
 	"github.com/hectorgimenez/koolo/internal/game"
 	"github.com/hectorgimenez/koolo/internal/ui"
@@ -56,9 +57,8 @@ var (
 	}
 )
 
-func isBarbLevelingCharacter() bool {
-	ctx := context.Get()
-	return ctx.CharacterCfg.Character.Class == "barb_leveling"
+func isBarbLevelingCharacter(cfg *config.CharacterCfg) bool {
+	return cfg.Character.Class == "barb_leveling"
 }
 
 // AutoEquip evaluates and equips items for both player and mercenary
@@ -116,7 +116,7 @@ func AutoEquip() error {
 		// Mercenary
 		// We need to refresh data after player equip, as it might have changed inventory
 		if playerChanged {
-			*ctx.Data = ctx.GameReader.GetData()
+			ctx.RefreshGameData()
 			allItems = ctx.Data.Inventory.ByLocation(locations...)
 		}
 
@@ -145,7 +145,7 @@ func AutoEquip() error {
 				ctx.Logger.Error(fmt.Sprintf("CTA equip error: %v", err))
 			}
 			if ctaChanged {
-				*ctx.Data = ctx.GameReader.GetData()
+				ctx.RefreshGameData()
 				continue
 			}
 
@@ -153,7 +153,7 @@ func AutoEquip() error {
 		}
 
 		// If something changed, let's refresh data and loop again to ensure stability
-		*ctx.Data = ctx.GameReader.GetData()
+		ctx.RefreshGameData()
 		ctx.Logger.Debug("Equipment changed, re-evaluating for stability...")
 	}
 }
@@ -167,13 +167,14 @@ func IsBetterThanEquipped(itm data.Item, forMerc bool, scoreFunc func(data.Item)
 	}
 
 	scores := scoreFunc(itm)
+	snapshot := currentScoringSnapshot()
 
 	for loc, itmScore := range scores {
-		if !isEquippable(itm, loc, loc) {
+		if !isEquippable(snapshot, itm, loc, loc) {
 			continue
 		}
 
-		if !isValidLocation(itm, loc, loc) {
+		if !isValidLocation(snapshot, itm, loc, loc) {
 			continue
 		}
 
@@ -221,7 +222,7 @@ func equipCTAIfFound(allItems []data.Item) (bool, error) {
 	// Check secondary weapon slot
 	ctx.HID.PressKeyBinding(ctx.Data.KeyBindings.SwapWeapons)
 	utils.Sleep(EquipDelayMS)
-	*ctx.Data = ctx.GameReader.GetData()
+	ctx.RefreshGameData()
 
 	equippedWeapon := GetEquippedItem(ctx.Data.Inventory, item.LocLeftArm)
 	equippedShield := GetEquippedItem(ctx.Data.Inventory, item.LocRightArm)
@@ -253,15 +254,15 @@ func equipCTAIfFound(allItems []data.Item) (bool, error) {
 	// Switch back to primary
 	ctx.HID.PressKeyBinding(ctx.Data.KeyBindings.SwapWeapons)
 	utils.Sleep(EquipDelayMS)
-	*ctx.Data = ctx.GameReader.GetData()
+	ctx.RefreshGameData()
 
 	return changed, nil
 }
 
 // isEquippable checks if an item can be equipped, considering the stats of the item that would be unequipped.
 // It requires the specific body location to perform an accurate stat check.
-func isEquippable(newItem data.Item, bodyloc item.LocationType, target item.LocationType) bool {
-	ctx := context.Get()
+func isEquippable(snapshot ScoringSnapshot, newItem data.Item, bodyloc item.LocationType, target item.LocationType) bool {
+	gameData := snapshot.Data
 
 	// General item property checks
 	if len(newItem.Desc().GetType().BodyLocs) == 0 {
@@ -275,11 +276,11 @@ func isEquippable(newItem data.Item, bodyloc item.LocationType, target item.Loca
 		return false
 	}
 
-	isSorc := ctx.CharacterCfg.Character.Class == "sorceress_leveling"
-	isBarbLeveling := isBarbLevelingCharacter()
+	isSorc := snapshot.CharacterCfg.Character.Class == "sorceress_leveling"
+	isBarbLeveling := isBarbLevelingCharacter(snapshot.CharacterCfg)
 
 	if target == item.LocationEquipped && isBarbLeveling {
-		if !barblogic(newItem, bodyloc) {
+		if !barblogic(gameData, newItem, bodyloc) {
 			return false
 		}
 	}
@@ -287,7 +288,7 @@ func isEquippable(newItem data.Item, bodyloc item.LocationType, target item.Loca
 	if _, isTwoHanded := newItem.FindStat(stat.TwoHandedMinDamage, 0); isTwoHanded {
 		// We need to fetch the level stat safely.
 		playerLevel := 0
-		if lvl, found := ctx.Data.PlayerUnit.FindStat(stat.Level, 0); found {
+		if lvl, found := gameData.PlayerUnit.FindStat(stat.Level, 0); found {
 			playerLevel = lvl.Value
 		}
 
@@ -303,12 +304,12 @@ func isEquippable(newItem data.Item, bodyloc item.LocationType, target item.Loca
 
 	// Class specific item type checks
 	for class, items := range classItems {
-		if ctx.Data.PlayerUnit.Class != class && slices.Contains(items, newItem.Desc().Type) {
+		if gameData.PlayerUnit.Class != class && slices.Contains(items, newItem.Desc().Type) {
 			return false
 		}
 	}
 	isBowOrXbow := newItem.Desc().Type == "bow" || newItem.Desc().Type == "xbow" || newItem.Desc().Type == "bowq" || newItem.Desc().Type == "xbowq"
-	isAmazon := ctx.Data.PlayerUnit.Class == data.Amazon
+	isAmazon := gameData.PlayerUnit.Class == data.Amazon
 	if target == item.LocationEquipped && isBowOrXbow && !isAmazon {
 		return false
 	}
@@ -316,7 +317,7 @@ func isEquippable(newItem data.Item, bodyloc item.LocationType, target item.Loca
 	// Main Requirement Check (Level, Strength, Dexterity)
 	if target == item.LocationEquipped {
 		var playerLevel int
-		if lvl, found := ctx.Data.PlayerUnit.FindStat(stat.Level, 0); found {
+		if lvl, found := gameData.PlayerUnit.FindStat(stat.Level, 0); found {
 			playerLevel = lvl.Value
 		}
 
@@ -331,10 +332,10 @@ func isEquippable(newItem data.Item, bodyloc item.LocationType, target item.Loca
 		}
 
 		// Now check stats, considering the item that will be unequipped
-		baseStr := ctx.Data.PlayerUnit.Stats[stat.Strength].Value
-		baseDex := ctx.Data.PlayerUnit.Stats[stat.Dexterity].Value
+		baseStr := gameData.PlayerUnit.Stats[stat.Strength].Value
+		baseDex := gameData.PlayerUnit.Stats[stat.Dexterity].Value
 
-		currentlyEquipped := GetEquippedItem(ctx.Data.Inventory, bodyloc)
+		currentlyEquipped := GetEquippedItem(gameData.Inventory, bodyloc)
 		if currentlyEquipped.UnitID != 0 {
 			if strBonus, found := currentlyEquipped.FindStat(stat.Strength, 0); found {
 				baseStr -= strBonus.Value
@@ -351,7 +352,7 @@ func isEquippable(newItem data.Item, bodyloc item.LocationType, target item.Loca
 
 	if target == item.LocationMercenary {
 		var mercStr, mercDex, mercLvl int
-		for _, m := range ctx.Data.Monsters {
+		for _, m := range gameData.Monsters {
 			if m.IsMerc() {
 				mercStr = m.Stats[stat.Strength]
 				mercDex = m.Stats[stat.Dexterity]
@@ -375,16 +376,16 @@ func isEquippable(newItem data.Item, bodyloc item.LocationType, target item.Loca
 	return true
 }
 
-func isValidLocation(i data.Item, bodyLoc item.LocationType, target item.LocationType) bool {
-	ctx := context.Get()
-	class := ctx.Data.PlayerUnit.Class
+func isValidLocation(snapshot ScoringSnapshot, i data.Item, bodyLoc item.LocationType, target item.LocationType) bool {
+	gameData := snapshot.Data
+	class := gameData.PlayerUnit.Class
 	itemType := i.Desc().Type
 	isShield := slices.Contains(shieldTypes, string(itemType))
 
 	if target == item.LocationMercenary {
 		if slices.Contains(mercBodyLocs, bodyLoc) {
 			if bodyLoc == item.LocLeftArm {
-				if isAct2MercenaryPresent(npc.Guard) {
+				if isAct2MercenaryPresent(gameData, npc.Guard) {
 					return itemType == "spea" || itemType == "pole" || itemType == "jave"
 				} else {
 					return itemType == "bow"
@@ -407,13 +408,13 @@ func isValidLocation(i data.Item, bodyLoc item.LocationType, target item.Locatio
 		switch class {
 		case data.Barbarian:
 			// barb_leveling shield 31+
-			isBarbLeveling := isBarbLevelingCharacter()
+			isBarbLeveling := isBarbLevelingCharacter(snapshot.CharacterCfg)
 			if isBarbLeveling {
 				playerLevel := 0
-				if lvl, found := ctx.Data.PlayerUnit.FindStat(stat.Level, 0); found {
+				if lvl, found := gameData.PlayerUnit.FindStat(stat.Level, 0); found {
 					playerLevel = lvl.Value
 				}
-				hasWarCry := ctx.Data.PlayerUnit.Skills[skill.WarCry].Level > 0
+				hasWarCry := gameData.PlayerUnit.Skills[skill.WarCry].Level > 0
 				shieldsAllowed := playerLevel >= 31 || hasWarCry
 				if shieldsAllowed {
 					return false
@@ -427,7 +428,7 @@ func isValidLocation(i data.Item, bodyLoc item.LocationType, target item.Locatio
 			isClaws := itemType == "h2h" || itemType == "h2h2"
 
 			if isClaws && bodyLoc == item.LocRightArm {
-				for _, equippedItem := range ctx.Data.Inventory.ByLocation(item.LocationEquipped) {
+				for _, equippedItem := range gameData.Inventory.ByLocation(item.LocationEquipped) {
 					if equippedItem.Location.BodyLocation == item.LocLeftArm {
 						return equippedItem.Desc().Type == "h2h" || equippedItem.Desc().Type == "h2h2"
 					}
@@ -444,9 +445,8 @@ func isValidLocation(i data.Item, bodyLoc item.LocationType, target item.Locatio
 }
 
 // isAct2MercenaryPresent checks for the existence of an Act 2 mercenary
-func isAct2MercenaryPresent(mercName npc.ID) bool {
-	ctx := context.Get()
-	for _, monster := range ctx.Data.Monsters {
+func isAct2MercenaryPresent(gameData *game.Data, mercName npc.ID) bool {
+	for _, monster := range gameData.Monsters {
 		if monster.IsMerc() && monster.Name == mercName {
 			return true
 		}
@@ -459,6 +459,7 @@ func evaluateItems(items []data.Item, target item.LocationType, scoreFunc func(d
 	ctx := context.Get()
 	itemsByLoc := make(map[item.LocationType][]data.Item)
 	itemScores := make(map[data.UnitID]map[item.LocationType]float64)
+	snapshot := currentScoringSnapshot()
 
 	for _, itm := range items {
 		// Exclude Keys from being equipped
@@ -482,11 +483,11 @@ func evaluateItems(items []data.Item, target item.LocationType, scoreFunc func(d
 			}
 
 			for bodyLoc, score := range bodyLocScores {
-				if !isEquippable(itm, bodyLoc, target) {
+				if !isEquippable(snapshot, itm, bodyLoc, target) {
 					continue
 				}
 
-				if !isValidLocation(itm, bodyLoc, target) {
+				if !isValidLocation(snapshot, itm, bodyLoc, target) {
 					continue
 				}
 
@@ -514,7 +515,7 @@ func evaluateItems(items []data.Item, target item.LocationType, scoreFunc func(d
 	// "Best Combo" logic for Two-Handed Weapons
 	if target == item.LocationEquipped {
 		class := ctx.Data.PlayerUnit.Class
-		isBarbLeveling := isBarbLevelingCharacter()
+		isBarbLeveling := isBarbLevelingCharacter(ctx.CharacterCfg)
 
 		if items, ok := itemsByLoc[item.LocLeftArm]; ok && len(items) > 0 {
 			if _, found := items[0].FindStat(stat.TwoHandedMinDamage, 0); found {
@@ -561,7 +562,7 @@ func equipBestItems(itemsByLoc map[item.LocationType][]data.Item, itemScores map
 	equippedSomething := false
 
 	// special logik for barb leveling
-	isBarbLeveling := isBarbLevelingCharacter()
+	isBarbLeveling := isBarbLevelingCharacter(ctx.CharacterCfg)
 	if isBarbLeveling && target == item.LocationEquipped {
 		weaponsChanged, err := equipBestWeapons(itemsByLoc, itemScores)
 		if err != nil {
@@ -569,7 +570,7 @@ func equipBestItems(itemsByLoc map[item.LocationType][]data.Item, itemScores map
 		}
 		if weaponsChanged {
 			equippedSomething = true
-			*ctx.Data = ctx.GameReader.GetData()
+			ctx.RefreshGameData()
 			delete(itemsByLoc, item.LocLeftArm)
 			delete(itemsByLoc, item.LocRightArm)
 		}
@@ -635,8 +636,8 @@ func equipBestItems(itemsByLoc map[item.LocationType][]data.Item, itemScores map
 		if err == nil {
 			ctx.Logger.Info(fmt.Sprintf("Successfully equipped %s to %s", bestCandidate.IdentifiedName, loc))
 			equippedSomething = true
-			*ctx.Data = ctx.GameReader.GetData() // Refresh data after a successful equip
-			continue                             // Move to the next location
+			ctx.RefreshGameData() // Refresh data after a successful equip
+			continue              // Move to the next location
 		}
 
 		// Handle specific errors
@@ -666,7 +667,7 @@ func equipBestItems(itemsByLoc map[item.LocationType][]data.Item, itemScores map
 				return false, fmt.Errorf("failed to sell junk to make space: %w", sellErr)
 			}
 			equippedSomething = true // We made a change (selling junk), so we should re-evaluate
-			*ctx.Data = ctx.GameReader.GetData()
+			ctx.RefreshGameData()
 			if _, found := findInventorySpace(currentlyEquipped); !found {
 				return false, err
 			}
@@ -795,7 +796,7 @@ func equipBestWeapons(itemsByLoc map[item.LocationType][]data.Item, itemScores m
 				}
 				ctx.HID.ClickWithModifier(game.LeftButton, rightArmCoords.X, rightArmCoords.Y, game.ShiftKey)
 				utils.Sleep(1000)
-				*ctx.Data = ctx.GameReader.GetData()
+				ctx.RefreshGameData()
 				itemAfterUnequip := GetEquippedItem(ctx.Data.Inventory, item.LocRightArm)
 				if itemAfterUnequip.UnitID != 0 {
 					ctx.Logger.Warn("Failed to unequip weapon from right arm.")
@@ -1028,7 +1029,7 @@ func equip(itm data.Item, bodyloc item.LocationType, target item.LocationType) e
 		SwitchStashTab(tab)
 		ctx.HID.ClickWithModifier(game.LeftButton, ui.GetScreenCoordsForItem(itm).X, ui.GetScreenCoordsForItem(itm).Y, game.CtrlKey)
 		utils.Sleep(EquipDelayMS)
-		*ctx.Data = ctx.GameReader.GetData()
+		ctx.RefreshGameData()
 		var found bool
 		for _, updatedItem := range ctx.Data.Inventory.ByLocation(item.LocationInventory) {
 			if updatedItem.UnitID == itm.UnitID {
@@ -1057,7 +1058,7 @@ func equip(itm data.Item, bodyloc item.LocationType, target item.LocationType) e
 		} else {
 			currentlyEquipped := GetEquippedItem(ctx.Data.Inventory, bodyloc)
 			isRingSwap := itm.Desc().Type == "ring" && currentlyEquipped.UnitID != 0
-			isBarbLeveling := isBarbLevelingCharacter()
+			isBarbLeveling := isBarbLevelingCharacter(ctx.CharacterCfg)
 			isBarbLocRightArm := isBarbLeveling && bodyloc == item.LocRightArm && currentlyEquipped.UnitID != 0
 
 			if isRingSwap {
@@ -1074,7 +1075,7 @@ func equip(itm data.Item, bodyloc item.LocationType, target item.LocationType) e
 
 				ctx.HID.ClickWithModifier(game.LeftButton, oldRingCoords.X, oldRingCoords.Y, game.ShiftKey)
 				utils.Sleep(1000)
-				*ctx.Data = ctx.GameReader.GetData()
+				ctx.RefreshGameData()
 
 				itemAfterUnequip := GetEquippedItem(ctx.Data.Inventory, bodyloc)
 				if itemAfterUnequip.UnitID != 0 {
@@ -1111,7 +1112,7 @@ func equip(itm data.Item, bodyloc item.LocationType, target item.LocationType) e
 
 				ctx.HID.ClickWithModifier(game.LeftButton, rightArmCoords.X, rightArmCoords.Y, game.ShiftKey)
 				utils.Sleep(1000)
-				*ctx.Data = ctx.GameReader.GetData()
+				ctx.RefreshGameData()
 
 				itemAfterUnequip := GetEquippedItem(ctx.Data.Inventory, item.LocRightArm)
 				if itemAfterUnequip.UnitID != 0 {
@@ -1147,11 +1148,11 @@ func equip(itm data.Item, bodyloc item.LocationType, target item.LocationType) e
 		}
 
 		// Verification loop
-		*ctx.Data = ctx.GameReader.GetData()
+		ctx.RefreshGameData()
 		var itemEquipped bool
 		for i := 0; i < 3; i++ {
 			utils.Sleep(800)
-			*ctx.Data = ctx.GameReader.GetData()
+			ctx.RefreshGameData()
 			for _, inPlace := range ctx.Data.Inventory.ByLocation(target) {
 				if inPlace.UnitID == itm.UnitID && inPlace.Location.BodyLocation == bodyloc {
 					itemEquipped = true
@@ -1233,7 +1234,7 @@ func findInventorySpace(itm data.Item) (data.Position, bool) {
 
 // wrapper for barb leveling boss equipment
 func IsItemEquippable(newItem data.Item, bodyloc item.LocationType, target item.LocationType) bool {
-	return isEquippable(newItem, bodyloc, target)
+	return isEquippable(currentScoringSnapshot(), newItem, bodyloc, target)
 }
 
 // wrapper for barb leveling boss equipment
@@ -1278,7 +1279,7 @@ func UnEquipMercenary() error {
 
 	// Loop multiple times to ensure all items are stashed.
 	for i := 0; i < 3; i++ {
-		*ctx.Data = ctx.GameReader.GetData()
+		ctx.RefreshGameData()
 		inventoryItems := ctx.Data.Inventory.ByLocation(item.LocationInventory)
 		if len(inventoryItems) == 0 {
 			break
@@ -1311,7 +1312,7 @@ func UnEquipMercenary() error {
 	utils.Sleep(EquipDelayMS)
 
 	// Refresh data to ensure the new menu state is recognized
-	*ctx.Data = ctx.GameReader.GetData()
+	ctx.RefreshGameData()
 
 	// Use predefined screen coordinates for the mercenary's gear slots
 	var mercGearCoords []data.Position
@@ -1341,17 +1342,16 @@ func UnEquipMercenary() error {
 }
 
 // Special Barb Logic
-func barblogic(newItem data.Item, bodyloc item.LocationType) bool {
-	ctx := context.Get()
+func barblogic(gameData *game.Data, newItem data.Item, bodyloc item.LocationType) bool {
 	itemType := newItem.Desc().Type
 	isShield := slices.Contains(shieldTypes, string(itemType))
 
 	if isShield {
 		playerLevel := 0
-		if lvl, found := ctx.Data.PlayerUnit.FindStat(stat.Level, 0); found {
+		if lvl, found := gameData.PlayerUnit.FindStat(stat.Level, 0); found {
 			playerLevel = lvl.Value
 		}
-		hasWarCry := ctx.Data.PlayerUnit.Skills[skill.WarCry].Level > 0
+		hasWarCry := gameData.PlayerUnit.Skills[skill.WarCry].Level > 0
 
 		if playerLevel >= 31 || hasWarCry {
 			return bodyloc == item.LocRightArm
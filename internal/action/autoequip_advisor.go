@@ -0,0 +1,208 @@
+package action
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/d2go/pkg/data/item"
+	"github.com/hectorgimenez/d2go/pkg/data/stat"
+	"github.com/hectorgimenez/koolo/internal/game"
+)
+
+const (
+	UpgradeSourceInventory   = "inventory"
+	UpgradeSourceStash       = "stash"
+	UpgradeSourceSharedStash = "sharedStash"
+	UpgradeSourceMule        = "mule"
+)
+
+var playerBodyLocs = []item.LocationType{
+	item.LocHead,
+	item.LocNeck,
+	item.LocTorso,
+	item.LocLeftArm,
+	item.LocRightArm,
+	item.LocLeftRing,
+	item.LocRightRing,
+	item.LocBelt,
+	item.LocFeet,
+	item.LocGloves,
+}
+
+// AdvisorItem is an item that can be suggested as an upgrade, Owner is the character holding it when it's a mule
+type AdvisorItem struct {
+	Item   data.Item
+	Source string
+	Owner  string
+}
+
+type UpgradeCandidate struct {
+	Item   string      `json:"item"`
+	UnitID data.UnitID `json:"unitId"`
+	Source string      `json:"source"`
+	Owner  string      `json:"owner,omitempty"`
+	Score  float64     `json:"score"`
+	Gain   float64     `json:"gain"`
+	// Blocked is set when the item scores better but can't be equipped yet, Reason explains why
+	Blocked     bool             `json:"blocked,omitempty"`
+	Reason      string           `json:"reason,omitempty"`
+	Explanation ScoreExplanation `json:"explanation"`
+}
+
+type SlotAdvice struct {
+	Location      item.LocationType  `json:"location"`
+	Merc          bool               `json:"merc"`
+	Equipped      string             `json:"equipped,omitempty"`
+	EquippedScore float64            `json:"equippedScore"`
+	Candidates    []UpgradeCandidate `json:"candidates"`
+}
+
+type UpgradeReport struct {
+	Player    []SlotAdvice `json:"player"`
+	Merc      []SlotAdvice `json:"merc"`
+	MercAlive bool         `json:"mercAlive"`
+}
+
+// AdviseUpgrades ranks, for every player and merc slot, the items scoring better than the equipped one. It uses the
+// same scores and rules as AutoEquip, resists are capped with the current character resists, but nothing is moved in
+// game. Items that can't be equipped yet are kept in the report as blocked. limit is the max candidates per slot.
+func AdviseUpgrades(snapshot ScoringSnapshot, items []AdvisorItem, limit int) UpgradeReport {
+	report := UpgradeReport{
+		Player:    adviseSlots(snapshot, items, playerBodyLocs, item.LocationEquipped, limit),
+		Merc:      make([]SlotAdvice, 0),
+		MercAlive: snapshot.Data.MercHPPercent() > 0,
+	}
+	if report.MercAlive {
+		report.Merc = adviseSlots(snapshot, items, mercBodyLocs, item.LocationMercenary, limit)
+	}
+
+	return report
+}
+
+func adviseSlots(snapshot ScoringSnapshot, items []AdvisorItem, locations []item.LocationType, target item.LocationType, limit int) []SlotAdvice {
+	forMerc := target == item.LocationMercenary
+	explain := ExplainPlayerScore
+	if forMerc {
+		explain = ExplainMercScore
+	}
+
+	advice := make([]SlotAdvice, 0, len(locations))
+	for _, loc := range locations {
+		slot := SlotAdvice{Location: loc, Merc: forMerc, Candidates: make([]UpgradeCandidate, 0)}
+
+		equipped := GetEquippedItem(snapshot.Data.Inventory, loc)
+		if forMerc {
+			equipped = GetMercEquippedItem(snapshot.Data.Inventory, loc)
+		}
+		if equipped.UnitID != 0 {
+			slot.Equipped = scoreItemName(equipped)
+			if explanation, ok := explain(snapshot, equipped, loc); ok {
+				slot.EquippedScore = explanation.Total
+			}
+		}
+
+		for _, candidate := range items {
+			itm := candidate.Item
+			// Items already worn by the player or the merc are not moved between them, same as AutoEquip
+			if itm.Location.LocationType == item.LocationEquipped || itm.Location.LocationType == item.LocationMercenary {
+				continue
+			}
+			if !isValidLocation(snapshot, itm, loc, target) {
+				continue
+			}
+
+			explanation, ok := explain(snapshot, itm, loc)
+			if !ok || explanation.Total <= slot.EquippedScore {
+				continue
+			}
+
+			upgrade := UpgradeCandidate{
+				Item:        explanation.Item,
+				UnitID:      itm.UnitID,
+				Source:      candidate.Source,
+				Owner:       candidate.Owner,
+				Score:       explanation.Total,
+				Gain:        explanation.Total - slot.EquippedScore,
+				Explanation: explanation,
+			}
+			if !isEquippable(snapshot, itm, loc, target) {
+				upgrade.Blocked = true
+				upgrade.Reason = upgradeBlockReason(snapshot.Data, itm, forMerc)
+			}
+			slot.Candidates = append(slot.Candidates, upgrade)
+		}
+
+		// Usable items first, then by score gain
+		slices.SortStableFunc(slot.Candidates, func(a, b UpgradeCandidate) int {
+			if a.Blocked != b.Blocked {
+				if a.Blocked {
+					return 1
+				}
+				return -1
+			}
+			return cmp.Compare(b.Gain, a.Gain)
+		})
+		if limit > 0 && len(slot.Candidates) > limit {
+			slot.Candidates = slot.Candidates[:limit]
+		}
+
+		advice = append(advice, slot)
+	}
+
+	return advice
+}
+
+// upgradeBlockReason describes why isEquippable rejected the item, the most common reasons are checked first
+func upgradeBlockReason(gameData *game.Data, itm data.Item, forMerc bool) string {
+	if !itm.Identified {
+		return "not identified"
+	}
+
+	var level, strength, dexterity int
+	if forMerc {
+		for _, m := range gameData.Monsters {
+			if m.IsMerc() {
+				level, strength, dexterity = m.Stats[stat.Level], m.Stats[stat.Strength], m.Stats[stat.Dexterity]
+			}
+		}
+	} else {
+		lvl, _ := gameData.PlayerUnit.FindStat(stat.Level, 0)
+		level = lvl.Value
+		strength = gameData.PlayerUnit.Stats[stat.Strength].Value
+		dexterity = gameData.PlayerUnit.Stats[stat.Dexterity].Value
+
+		for class, items := range classItems {
+			if gameData.PlayerUnit.Class != class && slices.Contains(items, itm.Desc().Type) {
+				return "class specific item"
+			}
+		}
+	}
+
+	if lvlReq, found := itm.FindStat(stat.LevelRequire, 0); found && level < lvlReq.Value {
+		return fmt.Sprintf("requires level %d", lvlReq.Value)
+	}
+	if strength < itm.Desc().RequiredStrength {
+		return fmt.Sprintf("requires %d strength", itm.Desc().RequiredStrength)
+	}
+	if dexterity < itm.Desc().RequiredDexterity {
+		return fmt.Sprintf("requires %d dexterity", itm.Desc().RequiredDexterity)
+	}
+
+	return "not allowed by the autoequip rules"
+}
+
+// AdvisorSource returns the advisor source of an item from the character inventory, false for equipped items
+func AdvisorSource(itm data.Item) (string, bool) {
+	switch itm.Location.LocationType {
+	case item.LocationInventory:
+		return UpgradeSourceInventory, true
+	case item.LocationStash:
+		return UpgradeSourceStash, true
+	case item.LocationSharedStash:
+		return UpgradeSourceSharedStash, true
+	}
+
+	return "", false
+}
@@ -4,18 +4,16 @@ import (
 	"github.com/hectorgimenez/d2go/pkg/data"
 	"github.com/hectorgimenez/d2go/pkg/data/item"
 	"github.com/hectorgimenez/d2go/pkg/data/stat"
-	"github.com/hectorgimenez/koolo/internal/context"
+	"github.com/hectorgimenez/koolo/internal/config"
 )
 
-func getMercenaryMetaItemScore(it data.Item) (float64, bool) {
+func getMercenaryMetaItemScore(cfg *config.CharacterCfg, it data.Item) (float64, bool) {
 
 	name := getItemNameForScore(it)
 
-	ctx := context.Get()
-
 	totalScore := 0.0
 
-	_, tierRule := ctx.CharacterCfg.Runtime.Rules.EvaluateTiers(it, ctx.CharacterCfg.Runtime.TierRules)
+	_, tierRule := cfg.Runtime.Rules.EvaluateTiers(it, cfg.Runtime.TierRules)
 	if tierRule.MercTier() > 0 {
 		totalScore = tierRule.MercTier()
 	}
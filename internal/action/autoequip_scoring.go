@@ -0,0 +1,221 @@
+package action
+
+import (
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/d2go/pkg/data/item"
+	"github.com/hectorgimenez/d2go/pkg/data/stat"
+	"github.com/hectorgimenez/koolo/internal/config"
+	"github.com/hectorgimenez/koolo/internal/context"
+	"github.com/hectorgimenez/koolo/internal/game"
+)
+
+// scoringWeights are the default weights with the character scoring profile applied
+type scoringWeights struct {
+	skill          map[stat.ID]float64
+	resistMain     map[stat.ID]float64
+	resistOther    map[stat.ID]float64
+	general        map[stat.ID]float64
+	classModifiers map[stat.ID]float64
+	merc           map[stat.ID]float64
+	uniqueItems    map[item.Name]float64
+}
+
+type scoringWeightsKey struct {
+	profile *config.AutoEquipScoring
+	class   data.Class
+}
+
+var (
+	scoringWeightsMu    sync.Mutex
+	scoringWeightsCache = make(map[scoringWeightsKey]scoringWeights)
+)
+
+// ScoringSnapshot is the character state items are scored with. AutoEquip scores with the data of the bot goroutine,
+// other goroutines must pass a copy, the bot refreshes its data while they read it otherwise.
+type ScoringSnapshot struct {
+	Data         *game.Data
+	CharacterCfg *config.CharacterCfg
+	weights      scoringWeights
+}
+
+func NewScoringSnapshot(data *game.Data, cfg *config.CharacterCfg) ScoringSnapshot {
+	return ScoringSnapshot{
+		Data:         data,
+		CharacterCfg: cfg,
+		weights:      scoringWeightsFor(cfg.Runtime.AutoEquipScoring, data.PlayerUnit.Class),
+	}
+}
+
+func currentScoringSnapshot() ScoringSnapshot {
+	ctx := context.Get()
+
+	return NewScoringSnapshot(ctx.Data, ctx.CharacterCfg)
+}
+
+// scoringWeightsFor returns the weights of a scoring profile (nil for the default weights) and class. They are merged
+// once per loaded profile, the profile is replaced, not modified, when the character config is loaded again.
+func scoringWeightsFor(profile *config.AutoEquipScoring, class data.Class) scoringWeights {
+	scoringWeightsMu.Lock()
+	defer scoringWeightsMu.Unlock()
+
+	key := scoringWeightsKey{profile: profile, class: class}
+	if weights, found := scoringWeightsCache[key]; found {
+		return weights
+	}
+
+	// Profiles of the characters loaded before the last config load are not used anymore
+	loaded := make(map[*config.AutoEquipScoring]bool)
+	for _, cfg := range config.GetCharacters() {
+		loaded[cfg.Runtime.AutoEquipScoring] = true
+	}
+	for cached := range scoringWeightsCache {
+		if cached.profile != nil && !loaded[cached.profile] {
+			delete(scoringWeightsCache, cached)
+		}
+	}
+
+	weights := mergeScoringWeights(profile, class)
+	scoringWeightsCache[key] = weights
+
+	return weights
+}
+
+func mergeScoringWeights(profile *config.AutoEquipScoring, class data.Class) scoringWeights {
+	if profile == nil {
+		profile = &config.AutoEquipScoring{}
+	}
+
+	weights := scoringWeights{
+		skill:          mergeWeights(skillWeights, profile.SkillWeights),
+		resistMain:     mergeWeights(resistWeightsMain, profile.ResistWeightsMain),
+		resistOther:    mergeWeights(resistWeightsOther, profile.ResistWeightsOther),
+		general:        mergeWeights(generalWeights, profile.GeneralWeights),
+		classModifiers: mergeWeights(classWeightModifiers[class], profile.ClassWeightModifiers),
+		merc:           mergeWeights(mercWeights, profile.MercWeights),
+		uniqueItems:    make(map[item.Name]float64, len(uniqueItemScores)+len(profile.UniqueItemScores)),
+	}
+	for name, score := range uniqueItemScores {
+		weights.uniqueItems[name] = score
+	}
+	for name, score := range profile.UniqueItemScores {
+		weights.uniqueItems[item.Name(name)] = score
+	}
+
+	return weights
+}
+
+func mergeWeights(defaults map[stat.ID]float64, overrides map[config.ScoreStat]float64) map[stat.ID]float64 {
+	merged := make(map[stat.ID]float64, len(defaults)+len(overrides))
+	for id, weight := range defaults {
+		merged[id] = weight
+	}
+	for id, weight := range overrides {
+		merged[stat.ID(id)] = weight
+	}
+
+	return merged
+}
+
+// ScoreExplanation is the breakdown of PlayerScore or MercScore for an item in a body location
+type ScoreExplanation struct {
+	Item     string            `json:"item"`
+	UnitID   data.UnitID       `json:"unitId"`
+	Location item.LocationType `json:"location"`
+	Merc     bool              `json:"merc"`
+	Total    float64           `json:"total"`
+	Base     float64           `json:"base"`
+	// TierRule is the tier (or merc tier) of the matching pickit rule, 0 if there isn't one
+	TierRule float64 `json:"tierRule"`
+
+	// Player scores, General includes Belt, Sockets, PerLevel and BaseStats
+	General        float64 `json:"general"`
+	UniqueOverride bool    `json:"uniqueOverride,omitempty"`
+	Belt           float64 `json:"belt"`
+	Sockets        float64 `json:"sockets"`
+	PerLevel       float64 `json:"perLevel"`
+	BaseStats      float64 `json:"baseStats"`
+	Resist         float64 `json:"resist"`
+	MainResist     float64 `json:"mainResist"`
+	OtherResist    float64 `json:"otherResist"`
+	Skill          float64 `json:"skill"`
+	// Penalty is applied to broken ethereal items
+	Penalty float64 `json:"penalty,omitempty"`
+
+	// Merc scores, meta items ignore the rest of the stats
+	MetaItem        bool    `json:"metaItem,omitempty"`
+	ElementalDamage float64 `json:"elementalDamage,omitempty"`
+	ChanceToCast    float64 `json:"chanceToCast,omitempty"`
+
+	Stats []StatScore `json:"stats"`
+}
+
+// StatScore is the score given by a single stat of the item
+type StatScore struct {
+	Section string  `json:"section"`
+	Stat    string  `json:"stat"`
+	Value   int     `json:"value"`
+	Weight  float64 `json:"weight"`
+	Score   float64 `json:"score"`
+}
+
+func (e *ScoreExplanation) addStat(section string, id stat.ID, value int, weight, score float64) {
+	if e == nil {
+		return
+	}
+
+	name, _ := config.ScoreStat(id).MarshalText()
+	e.Stats = append(e.Stats, StatScore{Section: section, Stat: string(name), Value: value, Weight: weight, Score: score})
+}
+
+// sortStats keeps the output stable, stats are collected iterating maps
+func (e *ScoreExplanation) sortStats() {
+	slices.SortFunc(e.Stats, func(a, b StatScore) int {
+		if c := strings.Compare(a.Section, b.Section); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Stat, b.Stat)
+	})
+}
+
+// ExplainPlayerScore returns how PlayerScore is calculated for the item in the given body location, false if the item
+// can't be equipped there
+func ExplainPlayerScore(snapshot ScoringSnapshot, itm data.Item, loc item.LocationType) (ScoreExplanation, bool) {
+	if !slices.Contains(itm.Desc().GetType().BodyLocs, loc) {
+		return ScoreExplanation{}, false
+	}
+
+	explanation := ScoreExplanation{Item: scoreItemName(itm), UnitID: itm.UnitID, Location: loc, Stats: make([]StatScore, 0)}
+	explanation.Total = playerScore(snapshot, itm, loc, &explanation)
+	explanation.sortStats()
+
+	return explanation, true
+}
+
+// ExplainMercScore returns how MercScore is calculated for the item in the given body location, false if the item
+// can't be equipped there
+func ExplainMercScore(snapshot ScoringSnapshot, itm data.Item, loc item.LocationType) (ScoreExplanation, bool) {
+	if !slices.Contains(itm.Desc().GetType().BodyLocs, loc) {
+		return ScoreExplanation{}, false
+	}
+
+	explanation := ScoreExplanation{Item: scoreItemName(itm), UnitID: itm.UnitID, Location: loc, Merc: true, Stats: make([]StatScore, 0)}
+	// The merc tier is part of the meta item score, it's only shown here
+	_, mercTierRule := snapshot.CharacterCfg.Runtime.Rules.EvaluateTiers(itm, snapshot.CharacterCfg.Runtime.TierRules)
+	explanation.TierRule = mercTierRule.MercTier()
+	explanation.Total = mercScore(snapshot, itm, &explanation)
+	explanation.sortStats()
+
+	return explanation, true
+}
+
+func scoreItemName(itm data.Item) string {
+	if itm.IdentifiedName != "" {
+		return itm.IdentifiedName
+	}
+
+	return string(getItemNameForScore(itm))
+}
@@ -0,0 +1,150 @@
+package action
+
+import (
+	"maps"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/d2go/pkg/data/item"
+	"github.com/hectorgimenez/d2go/pkg/data/stat"
+	"github.com/hectorgimenez/koolo/internal/config"
+)
+
+func TestMergeWeights(t *testing.T) {
+	defaults := map[stat.ID]float64{stat.FireResist: 3, stat.ColdResist: 2}
+
+	tests := []struct {
+		name      string
+		overrides map[config.ScoreStat]float64
+		want      map[stat.ID]float64
+	}{
+		{
+			name: "without overrides",
+			want: map[stat.ID]float64{stat.FireResist: 3, stat.ColdResist: 2},
+		},
+		{
+			name:      "replaced weight",
+			overrides: map[config.ScoreStat]float64{config.ScoreStat(stat.FireResist): 5},
+			want:      map[stat.ID]float64{stat.FireResist: 5, stat.ColdResist: 2},
+		},
+		{
+			name:      "new stat",
+			overrides: map[config.ScoreStat]float64{config.ScoreStat(stat.MagicFind): 1.5},
+			want:      map[stat.ID]float64{stat.FireResist: 3, stat.ColdResist: 2, stat.MagicFind: 1.5},
+		},
+		{
+			name:      "disabled stat",
+			overrides: map[config.ScoreStat]float64{config.ScoreStat(stat.ColdResist): 0},
+			want:      map[stat.ID]float64{stat.FireResist: 3, stat.ColdResist: 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mergeWeights(defaults, tt.overrides)
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+			if defaults[stat.FireResist] != 3 || len(defaults) != 2 {
+				t.Errorf("the default weights were modified: %v", defaults)
+			}
+		})
+	}
+
+	if got := mergeWeights(nil, nil); got == nil || len(got) != 0 {
+		t.Errorf("got %v, want an empty map", got)
+	}
+}
+
+func TestScoringWeightsFor(t *testing.T) {
+	resetScoringWeightsCache(t)
+
+	profile := &config.AutoEquipScoring{
+		GeneralWeights:       map[config.ScoreStat]float64{config.ScoreStat(stat.MagicFind): 3},
+		ClassWeightModifiers: map[config.ScoreStat]float64{config.ScoreStat(stat.Energy): 4},
+		UniqueItemScores:     map[string]float64{"HarlequinCrest": 5000},
+	}
+	reloaded := &config.AutoEquipScoring{GeneralWeights: map[config.ScoreStat]float64{config.ScoreStat(stat.MagicFind): 6}}
+	setLoadedProfiles(t, profile, reloaded)
+
+	defaults := scoringWeightsFor(nil, data.Sorceress)
+	if !maps.Equal(defaults.general, generalWeights) || !maps.Equal(defaults.classModifiers, classWeightModifiers[data.Sorceress]) {
+		t.Error("expected the default weights without profile")
+	}
+	if !maps.Equal(defaults.uniqueItems, uniqueItemScores) {
+		t.Error("expected the default unique item scores without profile")
+	}
+
+	weights := scoringWeightsFor(profile, data.Sorceress)
+	if weights.general[stat.MagicFind] != 3 || weights.general[stat.Vitality] != generalWeights[stat.Vitality] {
+		t.Errorf("profile weights not merged: %v", weights.general)
+	}
+	if weights.classModifiers[stat.Energy] != 4 || weights.classModifiers[stat.FasterCastRate] != classWeightModifiers[data.Sorceress][stat.FasterCastRate] {
+		t.Errorf("class modifiers not merged: %v", weights.classModifiers)
+	}
+	if weights.uniqueItems["HarlequinCrest"] != 5000 || weights.uniqueItems[item.Name(item.ArachnidMesh)] != uniqueItemScores[item.Name(item.ArachnidMesh)] {
+		t.Errorf("unique item scores not merged: %v", weights.uniqueItems)
+	}
+	if barbarian := scoringWeightsFor(profile, data.Barbarian); barbarian.classModifiers[stat.Strength] != classWeightModifiers[data.Barbarian][stat.Strength] {
+		t.Errorf("the class modifiers must be the ones of the class, got %v", barbarian.classModifiers)
+	}
+
+	// The same profile is merged once, a profile loaded again is merged again
+	if again := scoringWeightsFor(profile, data.Sorceress); !sameWeights(again, weights) {
+		t.Error("expected the cached weights for the same profile")
+	}
+	if got := scoringWeightsFor(reloaded, data.Sorceress); sameWeights(got, weights) || got.general[stat.MagicFind] != 6 {
+		t.Errorf("expected the weights of the reloaded profile, got %v", got.general)
+	}
+}
+
+func TestScoringWeightsForDropsUnusedProfiles(t *testing.T) {
+	resetScoringWeightsCache(t)
+
+	loaded := &config.AutoEquipScoring{}
+	setLoadedProfiles(t, loaded)
+
+	unused := &config.AutoEquipScoring{}
+	scoringWeightsFor(unused, data.Sorceress)
+	scoringWeightsFor(nil, data.Sorceress)
+	scoringWeightsFor(loaded, data.Sorceress)
+
+	for key := range scoringWeightsCache {
+		if key.profile == unused {
+			t.Error("weights of a profile no character uses must be dropped")
+		}
+	}
+	if len(scoringWeightsCache) != 2 {
+		t.Errorf("expected the default and the loaded profile weights, got %d entries", len(scoringWeightsCache))
+	}
+}
+
+func resetScoringWeightsCache(t *testing.T) {
+	t.Helper()
+
+	scoringWeightsMu.Lock()
+	scoringWeightsCache = make(map[scoringWeightsKey]scoringWeights)
+	scoringWeightsMu.Unlock()
+}
+
+// setLoadedProfiles loads a character for every profile, weights of profiles not loaded are dropped from the cache
+func setLoadedProfiles(t *testing.T, profiles ...*config.AutoEquipScoring) {
+	t.Helper()
+
+	previous := config.Characters
+	t.Cleanup(func() { config.Characters = previous })
+
+	config.Characters = make(map[string]*config.CharacterCfg, len(profiles))
+	for i, profile := range profiles {
+		cfg := &config.CharacterCfg{}
+		cfg.Runtime.AutoEquipScoring = profile
+		config.Characters[strconv.Itoa(i)] = cfg
+	}
+}
+
+// sameWeights reports whether both weights share the same maps, that is, they were merged once
+func sameWeights(a, b scoringWeights) bool {
+	return reflect.ValueOf(a.general).Pointer() == reflect.ValueOf(b.general).Pointer()
+}
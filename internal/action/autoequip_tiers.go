@@ -7,6 +7,7 @@ import (
 	"github.com/hectorgimenez/d2go/pkg/data/skill"
 	"github.com/hectorgimenez/d2go/pkg/data/stat"
 	"github.com/hectorgimenez/koolo/internal/context"
+	"github.com/hectorgimenez/koolo/internal/game"
 )
 
 const (
@@ -256,41 +257,63 @@ func PlayerScore(itm data.Item) map[item.LocationType]float64 {
 
 	// Should move valid location checks here maybe to avoid unneccessary calcs
 	scores := make(map[item.LocationType]float64)
+	snapshot := currentScoringSnapshot()
 
 	for _, loc := range bodyLocs {
-		generalScore := calculateGeneralScore(itm)
-		resistScore := calculateResistScore(itm, loc)
-		skillScore := calculateSkillScore(itm)
+		scores[loc] = playerScore(snapshot, itm, loc, nil)
+	}
+	//ctx.Logger.Debug(fmt.Sprintf("Item %s score: %v", itm.IdentifiedName, scores))
+	return scores
+}
 
-		totalScore := BaseScore + generalScore + resistScore + skillScore
+// playerScore is the item score for a single body location, the breakdown is stored in explanation if it's not nil
+func playerScore(snapshot ScoringSnapshot, itm data.Item, loc item.LocationType, explanation *ScoreExplanation) float64 {
+	generalScore := calculateGeneralScore(snapshot, itm, explanation)
+	resistScore := calculateResistScore(snapshot, itm, loc, explanation)
+	skillScore := calculateSkillScore(snapshot, itm, explanation)
 
-		if itm.IsBroken && itm.Ethereal {
-			totalScore -= 10000
-		}
+	totalScore := BaseScore + generalScore + resistScore + skillScore
 
-		scores[loc] = totalScore
+	penalty := 0.0
+	if itm.IsBroken && itm.Ethereal {
+		penalty = 10000
+		totalScore -= penalty
 	}
-	//ctx.Logger.Debug(fmt.Sprintf("Item %s score: %v", itm.IdentifiedName, scores))
-	return scores
+
+	if explanation != nil {
+		explanation.Base = BaseScore
+		explanation.General = generalScore
+		explanation.Resist = resistScore
+		explanation.Skill = skillScore
+		explanation.Penalty = penalty
+	}
+
+	return totalScore
 }
-func calculateGeneralScore(itm data.Item) float64 {
+
+func calculateGeneralScore(snapshot ScoringSnapshot, itm data.Item, explanation *ScoreExplanation) float64 {
 
 	itemName := itm.Name
 	if itm.IsRuneword {
 		itemName = item.Name(itm.RunewordName)
 	}
-	ctx := context.Get()
 
 	// Unique item override
-	if score, found := uniqueItemScores[itemName]; found {
+	if score, found := snapshot.weights.uniqueItems[itemName]; found {
+		if explanation != nil {
+			explanation.UniqueOverride = true
+		}
 		return score
 	}
 
 	score := BaseScore
 
-	tierRule, _ := ctx.CharacterCfg.Runtime.Rules.EvaluateTiers(itm, ctx.CharacterCfg.Runtime.TierRules)
+	tierRule, _ := snapshot.CharacterCfg.Runtime.Rules.EvaluateTiers(itm, snapshot.CharacterCfg.Runtime.TierRules)
 	if tierRule.Tier() > 0 {
 		score = tierRule.Tier()
+		if explanation != nil {
+			explanation.TierRule = tierRule.Tier()
+		}
 	}
 	// Handle Cannot Be Frozen
 	//if !ctx.Data.CanTeleport() && itm.FindStat(stat.CannotbeFrozen, 0) {
@@ -303,6 +326,9 @@ func calculateGeneralScore(itm data.Item) float64 {
 		beltScore := calculateBeltScore(itm)
 		//ctx.Logger.Debug(fmt.Sprintf("Belt score for %s: %.1f", itm.IdentifiedName, beltScore))
 		score += beltScore
+		if explanation != nil {
+			explanation.Belt = beltScore
+		}
 	}
 
 	// Handle sockets - this might be a bad idea becauase we won't properly use the sockets
@@ -311,13 +337,20 @@ func calculateGeneralScore(itm data.Item) float64 {
 			socketScore := float64(sockets.Value * 1)
 			//ctx.Logger.Debug(fmt.Sprintf("Socket score for %s (%d sockets): %.1f", itm.IdentifiedName, sockets.Value, socketScore))
 			score += socketScore
+			if explanation != nil {
+				explanation.Sockets = socketScore
+			}
 		}
 	}
 
-	perLevelScore := calculatePerLevelStats(itm)
-	baseStatsScore := calculateBaseStats(itm)
+	perLevelScore := calculatePerLevelStats(snapshot, itm, explanation)
+	baseStatsScore := calculateBaseStats(snapshot, itm, explanation)
 
 	score += perLevelScore + baseStatsScore
+	if explanation != nil {
+		explanation.PerLevel = perLevelScore
+		explanation.BaseStats = baseStatsScore
+	}
 	//if score > 0 {
 	//	ctx.Logger.Debug(fmt.Sprintf("Final general score for %s: %.1f (per-level: %.1f, base stats: %.1f)", itm.IdentifiedName, score, perLevelScore, baseStatsScore))
 	//}
@@ -372,15 +405,21 @@ func getCurrentBeltSize() int {
 	return 0
 }
 
-func calculatePerLevelStats(itm data.Item) float64 {
-	ctx := context.Get()
-	charLevel, _ := ctx.Data.PlayerUnit.FindStat(stat.Level, 0)
+func calculatePerLevelStats(snapshot ScoringSnapshot, itm data.Item, explanation *ScoreExplanation) float64 {
+	weights := snapshot.weights
+	charLevel, _ := snapshot.Data.PlayerUnit.FindStat(stat.Level, 0)
 
 	lifePerlvl, _ := itm.FindStat(stat.LifePerLevel, 0)
 	manaPerlvl, _ := itm.FindStat(stat.ManaPerLevel, 0)
 
-	lifeScore := (float64(lifePerlvl.Value) / 2048) * float64(charLevel.Value) * generalWeights[stat.LifePerLevel]
-	manaScore := (float64(manaPerlvl.Value) / 2048) * float64(charLevel.Value) * generalWeights[stat.ManaPerLevel]
+	lifeScore := (float64(lifePerlvl.Value) / 2048) * float64(charLevel.Value) * weights.general[stat.LifePerLevel]
+	manaScore := (float64(manaPerlvl.Value) / 2048) * float64(charLevel.Value) * weights.general[stat.ManaPerLevel]
+	if lifePerlvl.Value > 0 {
+		explanation.addStat("perLevel", stat.LifePerLevel, lifePerlvl.Value, weights.general[stat.LifePerLevel], lifeScore)
+	}
+	if manaPerlvl.Value > 0 {
+		explanation.addStat("perLevel", stat.ManaPerLevel, manaPerlvl.Value, weights.general[stat.ManaPerLevel], manaScore)
+	}
 
 	totalScore := lifeScore + manaScore
 	//if totalScore > 0 {
@@ -389,26 +428,25 @@ func calculatePerLevelStats(itm data.Item) float64 {
 	return totalScore
 }
 
-func calculateBaseStats(itm data.Item) float64 {
+func calculateBaseStats(snapshot ScoringSnapshot, itm data.Item, explanation *ScoreExplanation) float64 {
 	//ctx := context.Get()
+	weights := snapshot.weights
 	score := 0.0
-	class := context.Get().Data.PlayerUnit.Class
 
-	for statID, baseWeight := range generalWeights {
+	for statID, baseWeight := range weights.general {
 		if statData, found := itm.FindStat(statID, 0); found {
 			weight := baseWeight
 
 			// Apply class-specific modifier if it exists
-			if modifiers, hasClassModifiers := classWeightModifiers[class]; hasClassModifiers {
-				if modifier, hasStatModifier := modifiers[statID]; hasStatModifier {
-					weight += modifier
-				}
+			if modifier, hasStatModifier := weights.classModifiers[statID]; hasStatModifier {
+				weight += modifier
 			}
 
 			statScore := float64(statData.Value) * weight
 			//ctx.Logger.Debug(fmt.Sprintf("Item: %s, Stat: %s, Value: %d, Score: %.1f",
 			//	itm.IdentifiedName, statID, statData.Value, statScore))
 			score += statScore
+			explanation.addStat("general", statID, statData.Value, weight, statScore)
 		}
 	}
 
@@ -419,7 +457,7 @@ func calculateBaseStats(itm data.Item) float64 {
 // Resists
 
 // calculateResistScore evaluates item resistance values and returns a weighted score
-func calculateResistScore(itm data.Item, bodyloc item.LocationType) float64 {
+func calculateResistScore(snapshot ScoringSnapshot, itm data.Item, bodyloc item.LocationType, explanation *ScoreExplanation) float64 {
 	//ctx := context.Get()
 	newResists := getItemMainResists(itm)
 	mainScore := 0.0
@@ -430,22 +468,26 @@ func calculateResistScore(itm data.Item, bodyloc item.LocationType) float64 {
 	//ctx.Logger.Debug(fmt.Sprintf("(%s) New item resists - Fire: %d, Cold: %d, Lightning: %d, Poison: %d", itm.IdentifiedName, newResists.Fire, newResists.Cold, newResists.Lightning, newResists.Poison))
 
 	// get item resists stats from olditem currently equipped on body location
-	oldResists := getEquippedResists(bodyloc)
+	oldResists := getEquippedResists(snapshot.Data, bodyloc)
 	//ctx.Logger.Debug(fmt.Sprintf("(%s) Old equipped item resists - Fire: %d, Cold: %d, Lightning: %d, Poison: %d", itm.IdentifiedName, oldResists.Fire, oldResists.Cold, oldResists.Lightning, oldResists.Poison))
 
 	// Base resists returns what our resists would be without the equipped item (including difficulty penalty)
-	baseResists := getBaseResists(oldResists)
+	baseResists := getBaseResists(snapshot, oldResists)
 	//ctx.Logger.Debug(fmt.Sprintf("(%s) Base resists after removing equipped item - Fire: %d, Cold: %d, Lightning: %d, Poison: %d", itm.IdentifiedName, baseResists.Fire, baseResists.Cold, baseResists.Lightning, baseResists.Poison))
 
 	// subtract olditem resists from current total resists
 	effectiveResists := calculateEffectiveResists(newResists, baseResists)
 	//ctx.Logger.Debug(fmt.Sprintf("(%s) Effective resists - Fire: %d, Cold: %d, Lightning: %d, Poison: %d", itm.IdentifiedName, effectiveResists.Fire, effectiveResists.Cold, effectiveResists.Lightning, effectiveResists.Poison))
 
-	mainScore = calculateMainResistScore(effectiveResists)
+	mainScore = calculateMainResistScore(effectiveResists, snapshot.weights, explanation)
 
-	otherScore := calculateOtherResistScore(itm)
+	otherScore := calculateOtherResistScore(itm, snapshot.weights, explanation)
 
 	totalScore := mainScore + otherScore
+	if explanation != nil {
+		explanation.MainResist = mainScore
+		explanation.OtherResist = otherScore
+	}
 	//ctx.Logger.Debug(fmt.Sprintf("%v - %s Total resist score: %.1f (main: %.1f, other: %.1f)", bodyloc, itm.IdentifiedName, totalScore, mainScore, otherScore))
 
 	return totalScore
@@ -465,10 +507,9 @@ func getItemMainResists(itm data.Item) ResistStats {
 	}
 }
 
-func getEquippedResists(bodyloc item.LocationType) ResistStats {
-	ctx := context.Get()
+func getEquippedResists(gameData *game.Data, bodyloc item.LocationType) ResistStats {
 	var resists ResistStats
-	for _, equippedItem := range ctx.Data.Inventory.ByLocation(item.LocationEquipped) {
+	for _, equippedItem := range gameData.Inventory.ByLocation(item.LocationEquipped) {
 		if equippedItem.Location.BodyLocation == bodyloc {
 			fr, _ := equippedItem.FindStat(stat.FireResist, 0)
 			resists.Fire = fr.Value
@@ -486,19 +527,17 @@ func getEquippedResists(bodyloc item.LocationType) ResistStats {
 	return resists
 }
 
-func getBaseResists(equipped ResistStats) ResistStats {
-	ctx := context.Get()
-
-	fr, _ := ctx.Data.PlayerUnit.FindStat(stat.FireResist, 0)
-	cr, _ := ctx.Data.PlayerUnit.FindStat(stat.ColdResist, 0)
-	lr, _ := ctx.Data.PlayerUnit.FindStat(stat.LightningResist, 0)
-	pr, _ := ctx.Data.PlayerUnit.FindStat(stat.PoisonResist, 0)
+func getBaseResists(snapshot ScoringSnapshot, equipped ResistStats) ResistStats {
+	fr, _ := snapshot.Data.PlayerUnit.FindStat(stat.FireResist, 0)
+	cr, _ := snapshot.Data.PlayerUnit.FindStat(stat.ColdResist, 0)
+	lr, _ := snapshot.Data.PlayerUnit.FindStat(stat.LightningResist, 0)
+	pr, _ := snapshot.Data.PlayerUnit.FindStat(stat.PoisonResist, 0)
 
 	baseRes := ResistStats{
-		Fire:      fr.Value - resPenalty[ctx.CharacterCfg.Game.Difficulty] - equipped.Fire,
-		Cold:      cr.Value - resPenalty[ctx.CharacterCfg.Game.Difficulty] - equipped.Cold,
-		Lightning: lr.Value - resPenalty[ctx.CharacterCfg.Game.Difficulty] - equipped.Lightning,
-		Poison:    pr.Value - resPenalty[ctx.CharacterCfg.Game.Difficulty] - equipped.Poison,
+		Fire:      fr.Value - resPenalty[snapshot.CharacterCfg.Game.Difficulty] - equipped.Fire,
+		Cold:      cr.Value - resPenalty[snapshot.CharacterCfg.Game.Difficulty] - equipped.Cold,
+		Lightning: lr.Value - resPenalty[snapshot.CharacterCfg.Game.Difficulty] - equipped.Lightning,
+		Poison:    pr.Value - resPenalty[snapshot.CharacterCfg.Game.Difficulty] - equipped.Poison,
 	}
 
 	return baseRes
@@ -525,11 +564,27 @@ func calculateEffectiveResists(new, base ResistStats) ResistStats {
 	return effectiveRes
 }
 
-func calculateMainResistScore(resists ResistStats) float64 {
-	fireScore := float64(resists.Fire) * resistWeightsMain[stat.FireResist]
-	coldScore := float64(resists.Cold) * resistWeightsMain[stat.ColdResist]
-	lightScore := float64(resists.Lightning) * resistWeightsMain[stat.LightningResist]
-	poisonScore := float64(resists.Poison) * resistWeightsMain[stat.PoisonResist]
+func calculateMainResistScore(resists ResistStats, weights scoringWeights, explanation *ScoreExplanation) float64 {
+	fireScore := float64(resists.Fire) * weights.resistMain[stat.FireResist]
+	coldScore := float64(resists.Cold) * weights.resistMain[stat.ColdResist]
+	lightScore := float64(resists.Lightning) * weights.resistMain[stat.LightningResist]
+	poisonScore := float64(resists.Poison) * weights.resistMain[stat.PoisonResist]
+
+	// Values are the effective resists, capped by what's missing to reach the max resist
+	for _, r := range []struct {
+		id    stat.ID
+		value int
+		score float64
+	}{
+		{stat.FireResist, resists.Fire, fireScore},
+		{stat.ColdResist, resists.Cold, coldScore},
+		{stat.LightningResist, resists.Lightning, lightScore},
+		{stat.PoisonResist, resists.Poison, poisonScore},
+	} {
+		if r.value != 0 {
+			explanation.addStat("mainResist", r.id, r.value, weights.resistMain[r.id], r.score)
+		}
+	}
 
 	totalScore := fireScore + coldScore + lightScore + poisonScore
 
@@ -538,15 +593,16 @@ func calculateMainResistScore(resists ResistStats) float64 {
 	return totalScore
 }
 
-func calculateOtherResistScore(itm data.Item) float64 {
+func calculateOtherResistScore(itm data.Item, weights scoringWeights, explanation *ScoreExplanation) float64 {
 	//ctx := context.Get()
 	var score float64
 
-	for statID, weight := range resistWeightsOther {
+	for statID, weight := range weights.resistOther {
 		if statData, found := itm.FindStat(statID, 0); found {
 			statScore := float64(statData.Value) * weight
 			//ctx.Logger.Debug(fmt.Sprintf("Item: %s, Other resist %s: value %d, weight %.1f, score %.1f", itm.IdentifiedName, statID, statData.Value, weight, statScore))
 			score += statScore
+			explanation.addStat("otherResist", statID, statData.Value, weight, statScore)
 		}
 	}
 	//if score > 0 {
@@ -557,64 +613,71 @@ func calculateOtherResistScore(itm data.Item) float64 {
 
 // Skill calcs
 
-func calculateSkillScore(itm data.Item) float64 {
-	ctx := context.Get()
+func calculateSkillScore(snapshot ScoringSnapshot, itm data.Item, explanation *ScoreExplanation) float64 {
+	weights := snapshot.weights
+	playerUnit := snapshot.Data.PlayerUnit
 	score := 0.0
 
 	if statData, found := itm.FindStat(stat.AllSkills, 0); found {
-		allSkillScore := float64(statData.Value) * skillWeights[statData.ID]
+		allSkillScore := float64(statData.Value) * weights.skill[statData.ID]
 		//ctx.Logger.Debug(fmt.Sprintf("Item: %s, +All skills: %d, weight: %.1f, score: %.1f", itm.IdentifiedName, statData.Value, skillWeights[statData.ID], allSkillScore))
 		score += allSkillScore
+		explanation.addStat("skill", statData.ID, statData.Value, weights.skill[statData.ID], allSkillScore)
 	}
 
-	if classSkillsStat, found := itm.FindStat(stat.AddClassSkills, int(ctx.Data.PlayerUnit.Class)); found {
-		classSkillScore := float64(classSkillsStat.Value) * skillWeights[classSkillsStat.ID]
+	if classSkillsStat, found := itm.FindStat(stat.AddClassSkills, int(playerUnit.Class)); found {
+		classSkillScore := float64(classSkillsStat.Value) * weights.skill[classSkillsStat.ID]
 		//ctx.Logger.Debug(fmt.Sprintf("Item: %s, +Class skills: %d, weight: %.1f, score: %.1f", itm.IdentifiedName, classSkillsStat.Value, skillWeights[classSkillsStat.ID], classSkillScore))
 		score += classSkillScore
+		explanation.addStat("skill", classSkillsStat.ID, classSkillsStat.Value, weights.skill[classSkillsStat.ID], classSkillScore)
 	}
 
-	tabskill := int(ctx.Data.PlayerUnit.Class)*8 + (getMaxSkillTabPage() - 1)
+	tabskill := int(playerUnit.Class)*8 + (getMaxSkillTabPage(playerUnit) - 1)
 	if tabSkillsStat, found := itm.FindStat(stat.AddSkillTab, tabskill); found {
-		tabSkillScore := float64(tabSkillsStat.Value) * skillWeights[tabSkillsStat.ID]
+		tabSkillScore := float64(tabSkillsStat.Value) * weights.skill[tabSkillsStat.ID]
 		//ctx.Logger.Debug(fmt.Sprintf("Item: %s, +Tab skills (tab %d): %d, weight: %.1f, score: %.1f", itm.IdentifiedName, getMaxSkillTabPage(), tabSkillsStat.Value, skillWeights[tabSkillsStat.ID], tabSkillScore))
 		score += tabSkillScore
+		explanation.addStat("skill", tabSkillsStat.ID, tabSkillsStat.Value, weights.skill[tabSkillsStat.ID], tabSkillScore)
 	}
 
 	usedSkills := make([]skill.ID, 0)
 
 	//Let's ignore 1 point wonders unless we're below level 4
-	for sk, pts := range ctx.Data.PlayerUnit.Skills {
+	for sk, pts := range playerUnit.Skills {
 		if pts.Level > 1 {
 			usedSkills = append(usedSkills, sk)
-		} else if lvl, _ := ctx.Data.PlayerUnit.FindStat(stat.Level, 0); lvl.Value < 4 {
+		} else if lvl, _ := playerUnit.FindStat(stat.Level, 0); lvl.Value < 4 {
 			usedSkills = append(usedSkills, sk)
 		}
 	}
 
 	for _, usedSkill := range usedSkills {
 		if usedSkillsStat, found := itm.FindStat(stat.SingleSkill, int(usedSkill)); found {
-			usedSkillScore := float64(usedSkillsStat.Value) * skillWeights[usedSkillsStat.ID]
+			usedSkillScore := float64(usedSkillsStat.Value) * weights.skill[usedSkillsStat.ID]
 			//ctx.Logger.Debug(fmt.Sprintf("Item: %s, +%d to %s, weight: %.1f, score: %.1f", itm.IdentifiedName, usedSkillsStat.Value, usedSkill.Desc().Name, skillWeights[usedSkillsStat.ID], usedSkillScore))
 			score += usedSkillScore
+			explanation.addStat("skill", usedSkillsStat.ID, usedSkillsStat.Value, weights.skill[usedSkillsStat.ID], usedSkillScore)
 		}
 	}
 
 	if fireSkillsStat, found := itm.FindStat(stat.FireSkills, 1); found {
 		// Non-Sorcs
-		for sk := range ctx.Data.PlayerUnit.Skills {
+		for sk := range playerUnit.Skills {
 			for _, fireSkill := range fireSkills {
 				if sk == fireSkill {
 					const fireSkillWeight = 40.0
 					fireSkillScore := float64(fireSkillsStat.Value) * fireSkillWeight
 					//ctx.Logger.Debug(fmt.Sprintf("Item: %s, +%d to Fire Skills, weight: %.1f, score: %.1f", itm.IdentifiedName, fireSkillsStat.Value, fireSkillWeight, fireSkillScore))
 					score += fireSkillScore
+					explanation.addStat("skill", fireSkillsStat.ID, fireSkillsStat.Value, fireSkillWeight, fireSkillScore)
 				}
 			}
 		}
-		if ctx.Data.PlayerUnit.Class == data.Sorceress && getMaxSkillTabPage() == 1 { // Sorc using Fire tree
-			fireSkillScore := float64(fireSkillsStat.Value) * skillWeights[stat.AddSkillTab] // Consider it the same as '+x to Fire Skills (Sorceress only)'
+		if playerUnit.Class == data.Sorceress && getMaxSkillTabPage(playerUnit) == 1 { // Sorc using Fire tree
+			fireSkillScore := float64(fireSkillsStat.Value) * weights.skill[stat.AddSkillTab] // Consider it the same as '+x to Fire Skills (Sorceress only)'
 			//ctx.Logger.Debug(fmt.Sprintf("Item: %s, +%d to Fire Skills, weight: %.1f, score: %.1f", itm.IdentifiedName, fireSkillsStat.Value, skillWeights[stat.AddSkillTab], fireSkillScore))
 			score += fireSkillScore
+			explanation.addStat("skill", fireSkillsStat.ID, fireSkillsStat.Value, weights.skill[stat.AddSkillTab], fireSkillScore)
 		}
 	}
 	//if score > 0 {
@@ -633,37 +696,56 @@ func MercScore(itm data.Item) map[item.LocationType]float64 {
 
 	// Should move valid location checks here maybe to avoid unneccessary calcs
 	scores := make(map[item.LocationType]float64)
+	snapshot := currentScoringSnapshot()
 
 	for _, loc := range bodyLocs {
-		score, isMetaItem := getMercenaryMetaItemScore(itm)
+		scores[loc] = mercScore(snapshot, itm, nil)
+	}
+	//ctx.Logger.Debug(fmt.Sprintf("Item %s MERC score: %v", itm.IdentifiedName, scores))
+	return scores
+}
 
-		if isMetaItem {
-			scores[loc] = score
-			continue
+// mercScore is the item score for the merc, the breakdown is stored in explanation if it's not nil
+func mercScore(snapshot ScoringSnapshot, itm data.Item, explanation *ScoreExplanation) float64 {
+	score, isMetaItem := getMercenaryMetaItemScore(snapshot.CharacterCfg, itm)
+
+	if isMetaItem {
+		if explanation != nil {
+			explanation.MetaItem = true
 		}
+		return score
+	}
 
-		totalScore := BaseScore + sumElementalDamage(itm)*2.0
+	elementalScore := sumElementalDamage(itm) * 2.0
+	totalScore := BaseScore + elementalScore
 
-		// Base stats
-		for statID, weight := range mercWeights {
-			if statData, found := itm.FindStat(statID, 0); found {
-				mercStatScore := float64(statData.Value) * weight
-				totalScore += mercStatScore
-			}
+	// Base stats
+	for statID, weight := range snapshot.weights.merc {
+		if statData, found := itm.FindStat(statID, 0); found {
+			mercStatScore := float64(statData.Value) * weight
+			totalScore += mercStatScore
+			explanation.addStat("merc", statID, statData.Value, weight, mercStatScore)
 		}
+	}
 
-		// Chance-to-cast
-		for _, ctc := range mercCTCWeight {
-			if ctcStat, found := itm.FindStat(ctc.StatID, ctc.Layer); found {
-				mercCTCScore := float64(ctcStat.Value) * ctc.Weight
-				totalScore += mercCTCScore
-			}
+	// Chance-to-cast
+	ctcScore := 0.0
+	for _, ctc := range mercCTCWeight {
+		if ctcStat, found := itm.FindStat(ctc.StatID, ctc.Layer); found {
+			mercCTCScore := float64(ctcStat.Value) * ctc.Weight
+			totalScore += mercCTCScore
+			ctcScore += mercCTCScore
+			explanation.addStat("chanceToCast", ctc.StatID, ctcStat.Value, ctc.Weight, mercCTCScore)
 		}
+	}
 
-		scores[loc] = totalScore
+	if explanation != nil {
+		explanation.Base = BaseScore
+		explanation.ElementalDamage = elementalScore
+		explanation.ChanceToCast = ctcScore
 	}
-	//ctx.Logger.Debug(fmt.Sprintf("Item %s MERC score: %v", itm.IdentifiedName, scores))
-	return scores
+
+	return totalScore
 }
 
 // Helper functions
@@ -686,13 +768,11 @@ func calculatePoisonDamage(itm data.Item) float64 {
 	return float64(poisonMin.Value) * 125.0 / 256.0
 }
 
-func getMaxSkillTabPage() int {
-	ctx := context.Get()
-
+func getMaxSkillTabPage(playerUnit data.PlayerUnit) int {
 	tabCounts := make(map[int]int)
 	maxCount := 0
 	maxPage := 0
-	for pskill, pts := range ctx.Data.PlayerUnit.Skills {
+	for pskill, pts := range playerUnit.Skills {
 		if page := pskill.Desc().Page; page > 0 {
 			tabCounts[page] += int(pts.Level)
 			if tabCounts[page] > maxCount {
@@ -707,5 +787,5 @@ func getMaxSkillTabPage() int {
 
 // wrapper for barb leveling
 func CalculateItemScore(itm data.Item) float64 {
-	return calculateGeneralScore(itm)
+	return calculateGeneralScore(currentScoringSnapshot(), itm, nil)
 }
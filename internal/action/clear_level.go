@@ -6,6 +6,7 @@ import (
 	"log/slog"
 
 	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/d2go/pkg/data/area"
 	"github.com/hectorgimenez/d2go/pkg/data/object"
 	"github.com/hectorgimenez/d2go/pkg/data/stat"
 	"github.com/hectorgimenez/koolo/internal/action/step"
@@ -33,9 +34,30 @@ func ClearCurrentLevelEx(openChests bool, filter data.MonsterFilter, shouldInter
 	ctx := context.Get()
 	ctx.SetLastAction("ClearCurrentLevel")
 
+	return clearRooms(ctx.PathFinder.OptimizeRoomsTraverseOrder(), openChests, filter, shouldInterrupt)
+}
+
+// ClearCurrentLevelTo clears the level finishing close to the exit to the given area, so there is no need to walk back
+// through the level to leave it
+func ClearCurrentLevelTo(openChests bool, filter data.MonsterFilter, exit area.ID) error {
+	ctx := context.Get()
+	ctx.SetLastAction("ClearCurrentLevel")
+
+	for _, lvl := range ctx.Data.AdjacentLevels {
+		if lvl.Area == exit && (lvl.Position.X != 0 || lvl.Position.Y != 0) {
+			return clearRooms(ctx.PathFinder.OptimizeRoomsTraverseOrderEndingNear(lvl.Position), openChests, filter, nil)
+		}
+	}
+
+	ctx.Logger.Debug("Exit not found, clearing the level in any order", slog.String("exit", exit.Area().Name))
+	return clearRooms(ctx.PathFinder.OptimizeRoomsTraverseOrder(), openChests, filter, nil)
+}
+
+func clearRooms(rooms []data.Room, openChests bool, filter data.MonsterFilter, shouldInterrupt func() bool) error {
+	ctx := context.Get()
+
 	// We can make this configurable later, but 20 is a good starting radius.
 	const pickupRadius = 20
-	rooms := ctx.PathFinder.OptimizeRoomsTraverseOrder()
 	for _, r := range rooms {
 		if errDeath := checkPlayerDeath(ctx); errDeath != nil {
 			return errDeath
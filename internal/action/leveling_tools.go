@@ -172,7 +172,7 @@ func EnsureStatPoints() error {
 	}
 
 	remainingPoints := statPoints.Value
-	allocations := char.StatPoints()
+	allocations := levelingStatPoints(char)
 	for _, allocation := range allocations {
 		if statPoints.Value == 0 {
 			break
@@ -205,6 +205,21 @@ func EnsureStatPoints() error {
 
 }
 
+// levelingStatPoints returns the stat plan from the leveling build file if it has one, otherwise the class plan
+func levelingStatPoints(char context.LevelingCharacter) []context.StatAllocation {
+	build := context.Get().CharacterCfg.Runtime.LevelingBuild
+	if !build.HasStats() {
+		return char.StatPoints()
+	}
+
+	allocations := make([]context.StatAllocation, 0, len(build.Stats))
+	for _, target := range build.Stats {
+		allocations = append(allocations, context.StatAllocation{Stat: stat.ID(target.Stat), Points: target.Points})
+	}
+
+	return allocations
+}
+
 func spendStatPoint(statID stat.ID) bool {
 	ctx := context.Get()
 	beforePoints, _ := ctx.Data.PlayerUnit.FindStat(stat.StatPoints, 0)
@@ -372,12 +387,31 @@ func HireMerc() error {
 	return nil
 }
 
+// shouldResetSkills uses the respec points from the leveling build file if it has them, otherwise asks the class
+func shouldResetSkills(char context.LevelingCharacter) bool {
+	ctx := context.Get()
+	build := ctx.CharacterCfg.Runtime.LevelingBuild
+	if !build.HasRespec() {
+		return char.ShouldResetSkills()
+	}
+
+	lvl, _ := ctx.Data.PlayerUnit.FindStat(stat.Level, 0)
+	if build.ShouldRespec(lvl.Value, func(sk skill.ID) int {
+		return int(ctx.Data.PlayerUnit.Skills[sk].Level)
+	}) {
+		ctx.Logger.Info("Resetting skills, respec point from leveling build reached", slog.Int("level", lvl.Value))
+		return true
+	}
+
+	return false
+}
+
 func ResetStats() error {
 	ctx := context.Get()
 	ctx.SetLastAction("ResetStats")
 
 	ch, isLevelingChar := ctx.Char.(context.LevelingCharacter)
-	if isLevelingChar && ch.ShouldResetSkills() {
+	if isLevelingChar && shouldResetSkills(ch) {
 		currentArea := ctx.Data.PlayerUnit.Area
 		if ctx.Data.PlayerUnit.Area != area.RogueEncampment {
 			err := WayPoint(area.RogueEncampment)
@@ -549,7 +583,7 @@ func IsLowGold() bool {
 		playerLevel = 1
 	}
 
-	return ctx.Data.PlayerUnit.TotalPlayerGold() < playerLevel*1000
+	return ctx.Data.PlayerUnit.TotalPlayerGold() < LowGoldThreshold(playerLevel)
 }
 
 func IsBelowGoldPickupThreshold() bool {
@@ -562,7 +596,16 @@ func IsBelowGoldPickupThreshold() bool {
 		playerLevel = 1
 	}
 
-	return ctx.Data.PlayerUnit.TotalPlayerGold() < playerLevel*5000
+	return ctx.Data.PlayerUnit.TotalPlayerGold() < GoldPickupThreshold(playerLevel)
+}
+
+// LowGoldThreshold is the gold below which the character is considered low on gold
+func LowGoldThreshold(playerLevel int) int {
+	return playerLevel * 1000
+}
+
+func GoldPickupThreshold(playerLevel int) int {
+	return playerLevel * 5000
 }
 
 func GetCastersCommonRunewords() []string {
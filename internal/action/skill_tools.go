@@ -42,7 +42,7 @@ func EnsureSkillPoints() error {
 		return nil
 	}
 
-	skillsBuild := char.SkillPoints()
+	skillsBuild := levelingSkillPoints(char)
 	targetLevels := make(map[skill.ID]int)
 
 	for _, sk := range skillsBuild {
@@ -69,6 +69,39 @@ func EnsureSkillPoints() error {
 	return step.CloseAllMenus()
 }
 
+// levelingSkillPoints returns the skill plan from the leveling build file if it has one, otherwise the class plan
+func levelingSkillPoints(char context.LevelingCharacter) []skill.ID {
+	ctx := context.Get()
+	build := ctx.CharacterCfg.Runtime.LevelingBuild
+	if !build.HasSkills() {
+		return char.SkillPoints()
+	}
+
+	lvl, _ := ctx.Data.PlayerUnit.FindStat(stat.Level, 0)
+	return build.SkillPlan(lvl.Value)
+}
+
+// levelingSkillsToBind returns the skill bindings from the leveling build file if it has them, otherwise the class ones
+func levelingSkillsToBind(char context.LevelingCharacter) (skill.ID, []skill.ID) {
+	ctx := context.Get()
+	build := ctx.CharacterCfg.Runtime.LevelingBuild
+	if !build.HasBindings() {
+		return char.SkillsToBind()
+	}
+
+	lvl, _ := ctx.Data.PlayerUnit.FindStat(stat.Level, 0)
+	mainSkill, skillsToBind := build.SkillBindings(lvl.Value, func(sk skill.ID) bool {
+		if sk == skill.TomeOfTownPortal {
+			_, found := ctx.Data.Inventory.Find(item.TomeOfTownPortal, item.LocationInventory)
+			return found
+		}
+		return sk == skill.AttackSkill || ctx.Data.PlayerUnit.Skills[sk].Level > 0
+	})
+	ctx.Logger.Info("Skills bound", "mainSkill", mainSkill, "skillBindings", skillsToBind)
+
+	return mainSkill, skillsToBind
+}
+
 func spendSkillPoint(skillID skill.ID) bool {
 	ctx := context.Get()
 	beforePoints, _ := ctx.Data.PlayerUnit.FindStat(stat.SkillPoints, 0)
@@ -113,7 +146,7 @@ func EnsureSkillBindings() error {
 		return nil
 	}
 
-	mainSkill, skillsToBind := char.SkillsToBind()
+	mainSkill, skillsToBind := levelingSkillsToBind(char)
 
 	notBoundSkills := make([]skill.ID, 0)
 	for _, sk := range skillsToBind {
@@ -27,6 +27,9 @@ type Bot struct {
 	lastActivityTime      time.Time
 	lastKnownPosition     data.Position
 	lastPositionCheckTime time.Time
+	lifecycle             *lifecycleTracker
+	forensics             *forensicsRecorder
+	progress              *progressTracker
 	MuleManager
 }
 
@@ -35,11 +38,15 @@ func (b *Bot) NeedsTPsToContinue() bool {
 }
 
 func NewBot(ctx *botCtx.Context, mm MuleManager) *Bot {
+	progress := newProgressTracker(ctx.Name, ctx.Logger)
 	return &Bot{
 		ctx:                   ctx,
 		lastActivityTime:      time.Now(),      // Initialize
 		lastKnownPosition:     data.Position{}, // Will be updated on first game data refresh
 		lastPositionCheckTime: time.Now(),      // Initialize
+		lifecycle:             newLifecycleTracker(progress),
+		forensics:             newForensicsRecorder(),
+		progress:              progress,
 		MuleManager:           mm,
 	}
 }
@@ -70,6 +77,7 @@ func (b *Bot) Run(ctx context.Context, firstRun bool, runs []run.Run) error {
 	gameStartedAt := time.Now()
 	b.ctx.SwitchPriority(botCtx.PriorityNormal) // Restore priority to normal, in case it was stopped in previous game
 	b.ctx.CurrentGame = botCtx.NewGameHelper()  // Reset current game helper structure
+	b.forensics.reset()
 
 	err := b.ctx.GameReader.FetchMapData()
 	if err != nil {
@@ -103,6 +111,7 @@ func (b *Bot) Run(ctx context.Context, firstRun bool, runs []run.Run) error {
 					continue
 				}
 				b.ctx.RefreshGameData()
+				b.lifecycle.update(b.ctx)
 				// Update activity here because the bot is actively refreshing game data.
 				b.updateActivityAndPosition()
 			}
@@ -126,9 +135,11 @@ func (b *Bot) Run(ctx context.Context, firstRun bool, runs []run.Run) error {
 				if b.ctx.ExecutionPriority == botCtx.PriorityPause {
 					continue
 				}
+				b.forensics.record(b.ctx, false)
 				err = b.ctx.HealthManager.HandleHealthAndMana()
 				if err != nil {
 					b.ctx.Logger.Info("HealthManager: Detected critical error (chicken/death), stopping bot.", "error", err.Error())
+					b.saveForensicsReport(err)
 					cancel()
 					b.Stop()
 					return err
@@ -360,6 +371,7 @@ func (b *Bot) Run(ctx context.Context, firstRun bool, runs []run.Run) error {
 				}
 
 				event.Send(event.RunStarted(event.Text(b.ctx.Name, fmt.Sprintf("Starting run: %s", r.Name())), r.Name()))
+				b.progress.runStarted(b.ctx, r.Name())
 
 				// Update activity here because a new run sequence is starting.
 				b.updateActivityAndPosition()
@@ -401,6 +413,7 @@ func (b *Bot) Run(ctx context.Context, firstRun bool, runs []run.Run) error {
 				}
 
 				event.Send(event.RunFinished(event.Text(b.ctx.Name, fmt.Sprintf("Finished run: %s", r.Name())), r.Name(), runFinishReason))
+				b.progress.runFinished(b.ctx, r.Name())
 
 				if err != nil {
 					return err
@@ -417,7 +430,10 @@ func (b *Bot) Run(ctx context.Context, firstRun bool, runs []run.Run) error {
 		return nil
 	})
 
-	return g.Wait()
+	err = g.Wait()
+	b.progress.gameFinished(b.ctx)
+
+	return err
 }
 
 func (b *Bot) Stop() {
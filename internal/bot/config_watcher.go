@@ -0,0 +1,135 @@
+package bot
+
+import (
+	"io/fs"
+	"log/slog"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hectorgimenez/koolo/internal/config"
+)
+
+const configWatchInterval = 3 * time.Second
+
+// watchedConfigExtensions are the files reloaded when changed, backups and logs written to the config folder are ignored
+var watchedConfigExtensions = []string{".yaml", ".nip", ".json"}
+
+type watchedFile struct {
+	modTime int64
+	size    int64
+}
+
+// ConfigWatcher polls the config folder and reloads the config when a file changes. Polling is used instead of file
+// system notifications to behave the same on every file system, including network drives.
+type ConfigWatcher struct {
+	manager *SupervisorManager
+	logger  *slog.Logger
+	stop    chan struct{}
+	files   map[string]watchedFile
+}
+
+func NewConfigWatcher(manager *SupervisorManager, logger *slog.Logger) *ConfigWatcher {
+	return &ConfigWatcher{
+		manager: manager,
+		logger:  logger,
+		stop:    make(chan struct{}),
+	}
+}
+
+func (w *ConfigWatcher) Start() {
+	w.logger.Info("Config watcher started")
+	w.files = w.scan()
+
+	ticker := time.NewTicker(configWatchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.check()
+		case <-w.stop:
+			w.logger.Info("Config watcher stopped")
+			return
+		}
+	}
+}
+
+func (w *ConfigWatcher) Stop() {
+	close(w.stop)
+}
+
+func (w *ConfigWatcher) check() {
+	files := w.scan()
+	if maps.Equal(files, w.files) {
+		return
+	}
+	changed := changedFiles(w.files, files)
+	w.files = files
+
+	// Files saved by koolo itself, like the bot progress or the web UI settings, are already loaded
+	var changes map[string][]config.ConfigChange
+	if !slices.ContainsFunc(changed, func(path string) bool { return !config.IsSelfWrite(path) }) {
+		w.logger.Debug("Config files saved by koolo changed", slog.Any("files", changed))
+		changes = w.manager.ApplyLoadedConfig()
+	} else {
+		w.logger.Info("Config files changed, reloading", slog.Any("files", changed))
+		var err error
+		if changes, err = w.manager.ReloadConfig(); err != nil {
+			w.logger.Error("Error reloading config", slog.Any("error", err))
+			return
+		}
+	}
+
+	for supervisor, supervisorChanges := range changes {
+		for _, change := range supervisorChanges {
+			w.logger.Debug("Config change", slog.String("supervisor", supervisor), slog.String("setting", change.Path), slog.Bool("restartRequired", !change.Safe))
+		}
+	}
+}
+
+// scan returns the watched files of the config folder, and the centralized pickit folder when it's used
+func (w *ConfigWatcher) scan() map[string]watchedFile {
+	files := make(map[string]watchedFile)
+
+	dirs := []string{"config"}
+	if config.Koolo != nil && config.Koolo.CentralizedPickitPath != "" {
+		dirs = append(dirs, config.Koolo.CentralizedPickitPath)
+	}
+
+	for _, dir := range dirs {
+		_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || !slices.Contains(watchedConfigExtensions, strings.ToLower(filepath.Ext(path))) {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			files[path] = watchedFile{modTime: info.ModTime().UnixNano(), size: info.Size()}
+
+			return nil
+		})
+	}
+
+	return files
+}
+
+func changedFiles(old, new map[string]watchedFile) []string {
+	changed := make([]string, 0)
+	for path, file := range new {
+		if oldFile, found := old[path]; !found || oldFile != file {
+			changed = append(changed, path)
+		}
+	}
+	for path := range old {
+		if _, found := new[path]; !found {
+			changed = append(changed, path)
+		}
+	}
+	slices.Sort(changed)
+
+	return changed
+}
@@ -0,0 +1,312 @@
+package bot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/d2go/pkg/data/npc"
+	"github.com/hectorgimenez/d2go/pkg/data/stat"
+	"github.com/hectorgimenez/koolo/internal/config"
+	botCtx "github.com/hectorgimenez/koolo/internal/context"
+	"github.com/hectorgimenez/koolo/internal/event"
+	"github.com/hectorgimenez/koolo/internal/health"
+)
+
+const (
+	forensicsWindow         = 15 * time.Second
+	forensicsSampleInterval = 250 * time.Millisecond
+	forensicsMaxReports     = 50
+	forensicsMonsterRange   = 25
+)
+
+// ForensicsSample is a compact snapshot of the character state, taken periodically while in game
+type ForensicsSample struct {
+	Time          time.Time          `json:"time"`
+	HPPercent     int                `json:"hpPercent"`
+	MPPercent     int                `json:"mpPercent"`
+	MercHPPercent int                `json:"mercHpPercent"`
+	Position      data.Position      `json:"position"`
+	Area          string             `json:"area"`
+	States        []string           `json:"states"`
+	Monsters      []ForensicsMonster `json:"monsters"`
+	LastAction    string             `json:"lastAction"`
+	LastStep      string             `json:"lastStep"`
+}
+
+type ForensicsMonster struct {
+	Name     npc.ID           `json:"name"`
+	Type     data.MonsterType `json:"type"`
+	Distance int              `json:"distance"`
+	// States contains the auras and curses affecting the monster
+	States       []string `json:"states,omitempty"`
+	Enchantments []string `json:"enchantments,omitempty"`
+	Immunities   []string `json:"immunities,omitempty"`
+}
+
+// ForensicsReport contains the samples taken before a death or a chicken
+type ForensicsReport struct {
+	ID         string            `json:"id"`
+	Supervisor string            `json:"supervisor"`
+	Character  string            `json:"character"`
+	Reason     string            `json:"reason"`
+	Error      string            `json:"error"`
+	Time       time.Time         `json:"time"`
+	Samples    []ForensicsSample `json:"samples"`
+}
+
+// ForensicsReportSummary is the report without samples, used to list them
+type ForensicsReportSummary struct {
+	ID     string    `json:"id"`
+	Reason string    `json:"reason"`
+	Error  string    `json:"error"`
+	Time   time.Time `json:"time"`
+	Area   string    `json:"area"`
+}
+
+// forensicsRecorder is a ring buffer with the last forensicsWindow of samples
+type forensicsRecorder struct {
+	samples    []ForensicsSample
+	next       int
+	full       bool
+	lastSample time.Time
+}
+
+func newForensicsRecorder() *forensicsRecorder {
+	return &forensicsRecorder{samples: make([]ForensicsSample, int(forensicsWindow/forensicsSampleInterval))}
+}
+
+// record takes a new sample if the sample interval has passed, or always when force is set
+func (r *forensicsRecorder) record(ctx *botCtx.Context, force bool) {
+	if !force && time.Since(r.lastSample) < forensicsSampleInterval {
+		return
+	}
+	r.lastSample = time.Now()
+
+	r.add(takeForensicsSample(ctx))
+}
+
+// add stores the sample replacing the oldest one when the buffer is full
+func (r *forensicsRecorder) add(sample ForensicsSample) {
+	r.samples[r.next] = sample
+	r.next = (r.next + 1) % len(r.samples)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// snapshot returns the samples from the oldest to the newest
+func (r *forensicsRecorder) snapshot() []ForensicsSample {
+	if !r.full {
+		return slices.Clone(r.samples[:r.next])
+	}
+
+	return append(slices.Clone(r.samples[r.next:]), r.samples[:r.next]...)
+}
+
+func (r *forensicsRecorder) reset() {
+	r.next = 0
+	r.full = false
+	r.lastSample = time.Time{}
+}
+
+func takeForensicsSample(ctx *botCtx.Context) ForensicsSample {
+	d := ctx.Data
+	sample := ForensicsSample{
+		Time:          time.Now(),
+		HPPercent:     d.PlayerUnit.HPPercent(),
+		MPPercent:     d.PlayerUnit.MPPercent(),
+		MercHPPercent: d.MercHPPercent(),
+		Position:      d.PlayerUnit.Position,
+		Area:          d.PlayerUnit.Area.Area().Name,
+		States:        make([]string, 0, len(d.PlayerUnit.States)),
+		Monsters:      make([]ForensicsMonster, 0),
+	}
+	for _, st := range d.PlayerUnit.States {
+		sample.States = append(sample.States, fmt.Sprint(st))
+	}
+	if debug, found := ctx.ContextDebug[botCtx.PriorityNormal]; found {
+		sample.LastAction = debug.LastAction
+		sample.LastStep = debug.LastStep
+	}
+
+	for _, m := range d.Monsters.Enemies() {
+		distance := ctx.PathFinder.DistanceFromMe(m.Position)
+		if distance > forensicsMonsterRange {
+			continue
+		}
+
+		monster := ForensicsMonster{Name: m.Name, Type: m.Type, Distance: distance, Enchantments: monsterEnchantments(m)}
+		for _, st := range m.States {
+			monster.States = append(monster.States, fmt.Sprint(st))
+		}
+		for _, resist := range []struct {
+			id   stat.ID
+			name string
+		}{{stat.FireResist, "fire"}, {stat.ColdResist, "cold"}, {stat.LightningResist, "lightning"}, {stat.PoisonResist, "poison"}} {
+			if m.Stats[resist.id] >= 100 {
+				monster.Immunities = append(monster.Immunities, resist.name)
+			}
+		}
+		sample.Monsters = append(sample.Monsters, monster)
+	}
+	slices.SortFunc(sample.Monsters, func(a, b ForensicsMonster) int {
+		return a.Distance - b.Distance
+	})
+
+	return sample
+}
+
+// monsterEnchantments returns the enchantments of elite monsters. They are not read from the game, they are detected
+// from the stats each enchantment adds to the monster, like the cold damage of cold enchanted monsters.
+func monsterEnchantments(m data.Monster) []string {
+	if !m.IsElite() {
+		return nil
+	}
+
+	fire := m.Stats[stat.FireMinDamage] > 0
+	cold := m.Stats[stat.ColdMinDamage] > 0
+	lightning := m.Stats[stat.LightningMinDamage] > 0
+
+	var enchantments []string
+	if fire && cold && lightning {
+		// Spectral hit adds every elemental damage
+		enchantments = append(enchantments, "spectral hit")
+	} else {
+		for _, e := range []struct {
+			found bool
+			name  string
+		}{{fire, "fire enchanted"}, {cold, "cold enchanted"}, {lightning, "lightning enchanted"}} {
+			if e.found {
+				enchantments = append(enchantments, e.name)
+			}
+		}
+	}
+	if m.Stats[stat.DamagePercent] > 0 {
+		enchantments = append(enchantments, "extra strong")
+	}
+	if m.Stats[stat.DamageReduced] > 0 {
+		enchantments = append(enchantments, "stone skin")
+	}
+
+	return enchantments
+}
+
+// saveForensicsReport dumps the recorded samples when the health manager detected a death or a chicken
+func (b *Bot) saveForensicsReport(err error) {
+	var reason event.FinishReason
+	switch {
+	case errors.Is(err, health.ErrDied):
+		reason = event.FinishedDied
+	case errors.Is(err, health.ErrChicken):
+		reason = event.FinishedChicken
+	case errors.Is(err, health.ErrMercChicken):
+		reason = event.FinishedMercChicken
+	default:
+		return
+	}
+
+	// Last sample with the state that triggered it
+	b.forensics.record(b.ctx, true)
+	path, saveErr := saveForensicsReport(ForensicsReport{
+		Supervisor: b.ctx.Name,
+		Character:  b.ctx.CharacterCfg.CharacterName,
+		Reason:     string(reason),
+		Error:      err.Error(),
+		Time:       time.Now(),
+		Samples:    b.forensics.snapshot(),
+	})
+	if saveErr != nil {
+		b.ctx.Logger.Error("Failed to save forensics report", slog.Any("error", saveErr))
+		return
+	}
+	b.ctx.Logger.Info("Forensics report saved", slog.String("path", path))
+}
+
+func forensicsDir(supervisor string) string {
+	base := config.Koolo.LogSaveDirectory
+	if base == "" {
+		base = "logs"
+	}
+
+	return filepath.Join(base, "forensics", supervisor)
+}
+
+// saveForensicsReport writes the report to the supervisor forensics folder, only the newest reports are kept
+func saveForensicsReport(report ForensicsReport) (string, error) {
+	dir := forensicsDir(report.Supervisor)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	report.ID = fmt.Sprintf("%s-%s.json", report.Time.Format("20060102-150405"), strings.ReplaceAll(report.Reason, " ", "_"))
+	content, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, report.ID)
+	if err = os.WriteFile(path, content, 0o644); err != nil {
+		return "", err
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	slices.Sort(files)
+	for len(files) > forensicsMaxReports {
+		_ = os.Remove(files[0])
+		files = files[1:]
+	}
+
+	return path, nil
+}
+
+// ListForensicsReports returns the forensics reports of the supervisor, newest first
+func ListForensicsReports(supervisor string) ([]ForensicsReportSummary, error) {
+	files, err := filepath.Glob(filepath.Join(forensicsDir(supervisor), "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(files)
+	slices.Reverse(files)
+
+	summaries := make([]ForensicsReportSummary, 0, len(files))
+	for _, file := range files {
+		report, err := LoadForensicsReport(supervisor, filepath.Base(file))
+		if err != nil {
+			continue
+		}
+
+		summary := ForensicsReportSummary{ID: report.ID, Reason: report.Reason, Error: report.Error, Time: report.Time}
+		if len(report.Samples) > 0 {
+			summary.Area = report.Samples[len(report.Samples)-1].Area
+		}
+		summaries = append(summaries, summary)
+	}
+
+	return summaries, nil
+}
+
+// LoadForensicsReport reads a report by id, the id must be a file name in the supervisor forensics folder
+func LoadForensicsReport(supervisor, id string) (ForensicsReport, error) {
+	if id != filepath.Base(id) || !strings.HasSuffix(id, ".json") {
+		return ForensicsReport{}, fmt.Errorf("invalid report id %q", id)
+	}
+
+	content, err := os.ReadFile(filepath.Join(forensicsDir(supervisor), id))
+	if err != nil {
+		return ForensicsReport{}, err
+	}
+
+	var report ForensicsReport
+	if err = json.Unmarshal(content, &report); err != nil {
+		return ForensicsReport{}, fmt.Errorf("error reading forensics report %s: %w", id, err)
+	}
+
+	return report, nil
+}
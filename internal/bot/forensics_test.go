@@ -0,0 +1,113 @@
+package bot
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/d2go/pkg/data/stat"
+)
+
+func TestForensicsRecorderWraparound(t *testing.T) {
+	r := newForensicsRecorder()
+	size := len(r.samples)
+
+	for i := 0; i < size-1; i++ {
+		r.add(ForensicsSample{HPPercent: i})
+	}
+	if got := hpPercents(r.snapshot()); !slices.Equal(got, sequence(0, size-2)) {
+		t.Fatalf("before wrapping got %v", got)
+	}
+
+	// The oldest samples are replaced once the buffer is full
+	for i := size - 1; i < size+5; i++ {
+		r.add(ForensicsSample{HPPercent: i})
+	}
+	snapshot := r.snapshot()
+	if len(snapshot) != size {
+		t.Fatalf("got %d samples, want %d", len(snapshot), size)
+	}
+	if got := hpPercents(snapshot); !slices.Equal(got, sequence(5, size+4)) {
+		t.Errorf("after wrapping got %v", got)
+	}
+
+	// Snapshots don't share the buffer
+	snapshot[0].HPPercent = -1
+	if r.snapshot()[0].HPPercent == -1 {
+		t.Error("snapshot shares the recorder buffer")
+	}
+
+	r.reset()
+	if len(r.snapshot()) != 0 {
+		t.Error("samples kept after reset")
+	}
+	r.add(ForensicsSample{HPPercent: 1})
+	if got := hpPercents(r.snapshot()); !slices.Equal(got, []int{1}) {
+		t.Errorf("after reset got %v", got)
+	}
+}
+
+func TestMonsterEnchantments(t *testing.T) {
+	tests := []struct {
+		name    string
+		monster data.Monster
+		want    []string
+	}{
+		{
+			name:    "normal monsters are not enchanted",
+			monster: data.Monster{Stats: map[stat.ID]int{stat.ColdMinDamage: 5}},
+			want:    nil,
+		},
+		{
+			name:    "cold enchanted",
+			monster: data.Monster{Type: data.MonsterTypeChampion, Stats: map[stat.ID]int{stat.ColdMinDamage: 5}},
+			want:    []string{"cold enchanted"},
+		},
+		{
+			name: "several enchantments",
+			monster: data.Monster{Type: data.MonsterTypeUnique, Stats: map[stat.ID]int{
+				stat.FireMinDamage:      5,
+				stat.LightningMinDamage: 1,
+				stat.DamageReduced:      20,
+			}},
+			want: []string{"fire enchanted", "lightning enchanted", "stone skin"},
+		},
+		{
+			name: "spectral hit",
+			monster: data.Monster{Type: data.MonsterTypeUnique, Stats: map[stat.ID]int{
+				stat.FireMinDamage:      5,
+				stat.ColdMinDamage:      5,
+				stat.LightningMinDamage: 5,
+				stat.DamagePercent:      100,
+			}},
+			want: []string{"spectral hit", "extra strong"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := monsterEnchantments(tt.monster); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func hpPercents(samples []ForensicsSample) []int {
+	values := make([]int, 0, len(samples))
+	for _, s := range samples {
+		values = append(values, s.HPPercent)
+	}
+
+	return values
+}
+
+// sequence returns the numbers from first to last, both included
+func sequence(first, last int) []int {
+	values := make([]int, 0, last-first+1)
+	for i := first; i <= last; i++ {
+		values = append(values, i)
+	}
+
+	return values
+}
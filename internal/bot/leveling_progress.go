@@ -0,0 +1,337 @@
+package bot
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hectorgimenez/d2go/pkg/data/stat"
+	"github.com/hectorgimenez/koolo/internal/config"
+	botCtx "github.com/hectorgimenez/koolo/internal/context"
+)
+
+const (
+	maxProgressGames  = 1000
+	maxProgressRuns   = 500
+	xpPerHourWindow   = 2 * time.Hour
+	milestoneLevel    = 90
+	progressFileName  = "leveling_progress.json"
+	maxCharacterLevel = 99
+)
+
+// levelExperience is the total experience needed to reach every level, index 0 is level 1
+var levelExperience = []uint64{
+	0, 500, 1500, 3750, 7875, 14175, 22680, 32886,
+	44396, 57715, 72144, 90180, 112725, 140906, 176132, 220165,
+	275207, 344008, 430010, 537513, 671891, 839864, 1049830, 1312287,
+	1640359, 2050449, 2563061, 3203826, 3902260, 4663553, 5493363, 6397855,
+	7383752, 8458379, 9629723, 10906488, 12298162, 13815086, 15468534, 17270791,
+	19235252, 21376515, 23710491, 26254525, 29027522, 32050088, 35344686, 38935798,
+	42850109, 47116709, 51767302, 56836449, 62361819, 68384473, 74949165, 82104680,
+	89904191, 98405658, 107672256, 117772849, 128782495, 140783010, 153863570, 168121381,
+	183662396, 200602101, 219066380, 239192444, 261129853, 285041630, 311105466, 339515048,
+	370481492, 404234916, 441026148, 481128591, 524840254, 572485967, 624419793, 681027665,
+	742730244, 809986056, 883294891, 963201521, 1050299747, 1145236814, 1248718217, 1361512946,
+	1484459201, 1618470619, 1764543065, 1923762030, 2097310703, 2286478756, 2492671933, 2717422497,
+	2962400612, 3229426756, 3520485254,
+}
+
+// ProgressSample is the character experience at the end of a game
+type ProgressSample struct {
+	Time       time.Time `json:"time"`
+	Level      int       `json:"level"`
+	Experience uint64    `json:"experience"`
+	Difficulty string    `json:"difficulty"`
+}
+
+// RunSample is the experience gained by a single run
+type RunSample struct {
+	Name       string        `json:"name"`
+	Time       time.Time     `json:"time"`
+	Duration   time.Duration `json:"duration"`
+	Experience uint64        `json:"experience"`
+}
+
+// RunProgress aggregates the experience gained by all the runs with the same name
+type RunProgress struct {
+	Name       string        `json:"name"`
+	Runs       int           `json:"runs"`
+	Experience uint64        `json:"experience"`
+	Duration   time.Duration `json:"duration"`
+	XPPerRun   float64       `json:"xpPerRun"`
+	XPPerHour  float64       `json:"xpPerHour"`
+}
+
+type ProgressMilestone struct {
+	Key         string    `json:"key"`
+	Time        time.Time `json:"time"`
+	Level       int       `json:"level"`
+	Description string    `json:"description"`
+}
+
+// LevelingProgress is the persisted experience history of a character
+type LevelingProgress struct {
+	Current    ProgressSample          `json:"current"`
+	Games      []ProgressSample        `json:"games"`
+	RecentRuns []RunSample             `json:"recentRuns"`
+	Runs       map[string]*RunProgress `json:"runs"`
+	Milestones []ProgressMilestone     `json:"milestones"`
+}
+
+// LevelingProgressSummary contains the rates and projections calculated from the progress, durations are 0 when
+// they can't be calculated yet
+type LevelingProgressSummary struct {
+	Level             int                 `json:"level"`
+	XPPerHour         float64             `json:"xpPerHour"`
+	TimeToNextLevel   time.Duration       `json:"timeToNextLevel"`
+	TargetLevel       int                 `json:"targetLevel"`
+	TimeToTargetLevel time.Duration       `json:"timeToTargetLevel"`
+	Runs              []RunProgress       `json:"runs"`
+	Milestones        []ProgressMilestone `json:"milestones"`
+}
+
+// progressTracker samples the character experience per game and run, the progress is saved after every run so it's
+// kept across restarts
+type progressTracker struct {
+	mu         sync.Mutex
+	supervisor string
+	logger     *slog.Logger
+	progress   LevelingProgress
+	runName    string
+	runStart   time.Time
+	runStartXP uint64
+}
+
+func newProgressTracker(supervisor string, logger *slog.Logger) *progressTracker {
+	progress, err := LoadLevelingProgress(supervisor)
+	if err != nil {
+		logger.Warn("Failed to load leveling progress, starting a new one", slog.Any("error", err))
+		progress = LevelingProgress{}
+	}
+	if progress.Runs == nil {
+		progress.Runs = make(map[string]*RunProgress)
+	}
+
+	return &progressTracker{supervisor: supervisor, logger: logger, progress: progress}
+}
+
+func progressPath(supervisor string) string {
+	base := config.Koolo.LogSaveDirectory
+	if base == "" {
+		base = "logs"
+	}
+
+	return filepath.Join(base, "progress", supervisor, progressFileName)
+}
+
+// LoadLevelingProgress reads the persisted progress of the supervisor, empty if nothing was tracked yet
+func LoadLevelingProgress(supervisor string) (LevelingProgress, error) {
+	content, err := os.ReadFile(progressPath(supervisor))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return LevelingProgress{}, nil
+		}
+		return LevelingProgress{}, err
+	}
+
+	var progress LevelingProgress
+	if err = json.Unmarshal(content, &progress); err != nil {
+		return LevelingProgress{}, fmt.Errorf("error reading leveling progress: %w", err)
+	}
+
+	return progress, nil
+}
+
+// WriteLevelingProgress persists the progress of the supervisor. It's written to a temporary file renamed over the
+// previous one, so a crash while saving doesn't lose the whole history.
+func WriteLevelingProgress(supervisor string, progress LevelingProgress) error {
+	path := progressPath(supervisor)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	content, err := json.Marshal(progress)
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err = os.WriteFile(tmp, content, 0o644); err != nil {
+		return err
+	}
+	if err = os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+
+	return nil
+}
+
+func (t *progressTracker) save() {
+	if err := WriteLevelingProgress(t.supervisor, t.progress); err != nil {
+		t.logger.Warn("Failed to save leveling progress", slog.Any("error", err))
+	}
+}
+
+func currentSample(ctx *botCtx.Context) (ProgressSample, bool) {
+	lvl, _ := ctx.Data.PlayerUnit.FindStat(stat.Level, 0)
+	exp, _ := ctx.Data.PlayerUnit.FindStat(stat.Experience, 0)
+	if lvl.Value == 0 {
+		return ProgressSample{}, false
+	}
+
+	return ProgressSample{
+		Time:  time.Now(),
+		Level: lvl.Value,
+		// Treat as unsigned to handle values > 2^31-1
+		Experience: uint64(uint32(exp.Value)),
+		Difficulty: string(ctx.CharacterCfg.Game.Difficulty),
+	}, true
+}
+
+func (t *progressTracker) runStarted(ctx *botCtx.Context, runName string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.runName = ""
+	if sample, ok := currentSample(ctx); ok {
+		t.runName = runName
+		t.runStart = time.Now()
+		t.runStartXP = sample.Experience
+	}
+}
+
+func (t *progressTracker) runFinished(ctx *botCtx.Context, runName string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	sample, ok := currentSample(ctx)
+	if !ok || t.runName != runName {
+		return
+	}
+	t.runName = ""
+	t.progress.Current = sample
+
+	// Experience is lost on death, the run didn't give any
+	var gained uint64
+	if sample.Experience > t.runStartXP {
+		gained = sample.Experience - t.runStartXP
+	}
+	duration := time.Since(t.runStart)
+
+	t.progress.RecentRuns = append(t.progress.RecentRuns, RunSample{Name: runName, Time: sample.Time, Duration: duration, Experience: gained})
+	if len(t.progress.RecentRuns) > maxProgressRuns {
+		t.progress.RecentRuns = t.progress.RecentRuns[len(t.progress.RecentRuns)-maxProgressRuns:]
+	}
+
+	runProgress, found := t.progress.Runs[runName]
+	if !found {
+		runProgress = &RunProgress{Name: runName}
+		t.progress.Runs[runName] = runProgress
+	}
+	runProgress.Runs++
+	runProgress.Experience += gained
+	runProgress.Duration += duration
+
+	t.save()
+}
+
+func (t *progressTracker) gameFinished(ctx *botCtx.Context) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	sample, ok := currentSample(ctx)
+	if !ok {
+		return
+	}
+	t.progress.Current = sample
+	t.progress.Games = append(t.progress.Games, sample)
+	if len(t.progress.Games) > maxProgressGames {
+		t.progress.Games = t.progress.Games[len(t.progress.Games)-maxProgressGames:]
+	}
+
+	t.save()
+}
+
+// milestone records a milestone once, the key identifies it
+func (t *progressTracker) milestone(key string, level int, description string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if slices.ContainsFunc(t.progress.Milestones, func(m ProgressMilestone) bool { return m.Key == key }) {
+		return
+	}
+	t.progress.Milestones = append(t.progress.Milestones, ProgressMilestone{Key: key, Time: time.Now(), Level: level, Description: description})
+
+	t.save()
+}
+
+func (t *progressTracker) summary(targetLevel int) LevelingProgressSummary {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.progress.Summary(targetLevel, time.Now())
+}
+
+// Summary calculates the experience rates and the time to reach the next level and targetLevel (StopLevelingAt, 0 to
+// skip it). XP per hour is calculated with the runs of the last hours, time between runs is not counted.
+func (p LevelingProgress) Summary(targetLevel int, now time.Time) LevelingProgressSummary {
+	summary := LevelingProgressSummary{
+		Level:       p.Current.Level,
+		TargetLevel: targetLevel,
+		Runs:        make([]RunProgress, 0, len(p.Runs)),
+		Milestones:  p.Milestones,
+	}
+	if summary.Milestones == nil {
+		summary.Milestones = make([]ProgressMilestone, 0)
+	}
+
+	for _, runProgress := range p.Runs {
+		rp := *runProgress
+		if rp.Runs > 0 {
+			rp.XPPerRun = float64(rp.Experience) / float64(rp.Runs)
+		}
+		if rp.Duration > 0 {
+			rp.XPPerHour = float64(rp.Experience) / rp.Duration.Hours()
+		}
+		summary.Runs = append(summary.Runs, rp)
+	}
+	sort.Slice(summary.Runs, func(i, j int) bool {
+		return summary.Runs[i].XPPerHour > summary.Runs[j].XPPerHour
+	})
+
+	var experience uint64
+	var duration time.Duration
+	for _, run := range p.RecentRuns {
+		if now.Sub(run.Time) <= xpPerHourWindow {
+			experience += run.Experience
+			duration += run.Duration
+		}
+	}
+	if duration > 0 {
+		summary.XPPerHour = float64(experience) / duration.Hours()
+	}
+
+	if summary.XPPerHour > 0 && p.Current.Level > 0 && p.Current.Level < maxCharacterLevel {
+		summary.TimeToNextLevel = timeToExperience(p.Current.Experience, levelExperience[p.Current.Level], summary.XPPerHour)
+		if targetLevel > p.Current.Level && targetLevel <= maxCharacterLevel {
+			summary.TimeToTargetLevel = timeToExperience(p.Current.Experience, levelExperience[targetLevel-1], summary.XPPerHour)
+		}
+	}
+
+	return summary
+}
+
+func timeToExperience(current, wanted uint64, xpPerHour float64) time.Duration {
+	if wanted <= current {
+		return 0
+	}
+
+	return time.Duration(float64(wanted-current) / xpPerHour * float64(time.Hour))
+}
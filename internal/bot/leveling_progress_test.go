@@ -0,0 +1,152 @@
+package bot
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hectorgimenez/koolo/internal/config"
+)
+
+func TestLevelingProgressSummary(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name                  string
+		progress              LevelingProgress
+		targetLevel           int
+		wantXPPerHour         float64
+		wantTimeToNextLevel   time.Duration
+		wantTimeToTargetLevel time.Duration
+	}{
+		{
+			name:     "nothing tracked yet",
+			progress: LevelingProgress{},
+		},
+		{
+			name: "only recent runs are counted",
+			progress: LevelingProgress{
+				// Level 3 needs 1500 experience, 1000 are missing
+				Current: ProgressSample{Level: 2, Experience: 500},
+				RecentRuns: []RunSample{
+					{Time: now.Add(-3 * time.Hour), Duration: time.Hour, Experience: 100000},
+					{Time: now.Add(-time.Hour), Duration: 30 * time.Minute, Experience: 1000},
+					{Time: now.Add(-10 * time.Minute), Duration: 30 * time.Minute, Experience: 1000},
+				},
+			},
+			wantXPPerHour:       2000,
+			wantTimeToNextLevel: 30 * time.Minute,
+		},
+		{
+			name: "time to target level",
+			progress: LevelingProgress{
+				Current:    ProgressSample{Level: 2, Experience: 500},
+				RecentRuns: []RunSample{{Time: now, Duration: time.Hour, Experience: 1000}},
+			},
+			// Level 4 needs 3750 experience, 3250 are missing
+			targetLevel:           4,
+			wantXPPerHour:         1000,
+			wantTimeToNextLevel:   time.Hour,
+			wantTimeToTargetLevel: 3*time.Hour + 15*time.Minute,
+		},
+		{
+			name: "target level already reached",
+			progress: LevelingProgress{
+				Current:    ProgressSample{Level: 5, Experience: 8000},
+				RecentRuns: []RunSample{{Time: now, Duration: time.Hour, Experience: 1000}},
+			},
+			targetLevel:   4,
+			wantXPPerHour: 1000,
+			// Level 6 needs 14175 experience
+			wantTimeToNextLevel: time.Duration(6175 * float64(time.Hour) / 1000),
+		},
+		{
+			name: "max level",
+			progress: LevelingProgress{
+				Current:    ProgressSample{Level: maxCharacterLevel, Experience: 3520485254},
+				RecentRuns: []RunSample{{Time: now, Duration: time.Hour, Experience: 1000}},
+			},
+			targetLevel:   maxCharacterLevel,
+			wantXPPerHour: 1000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.progress.Summary(tt.targetLevel, now)
+			if got.XPPerHour != tt.wantXPPerHour {
+				t.Errorf("got %v xp per hour, want %v", got.XPPerHour, tt.wantXPPerHour)
+			}
+			if got.TimeToNextLevel != tt.wantTimeToNextLevel {
+				t.Errorf("got %v to next level, want %v", got.TimeToNextLevel, tt.wantTimeToNextLevel)
+			}
+			if got.TimeToTargetLevel != tt.wantTimeToTargetLevel {
+				t.Errorf("got %v to target level, want %v", got.TimeToTargetLevel, tt.wantTimeToTargetLevel)
+			}
+			if got.Runs == nil || got.Milestones == nil {
+				t.Error("runs and milestones must never be nil")
+			}
+		})
+	}
+}
+
+func TestLevelingProgressSummaryRuns(t *testing.T) {
+	progress := LevelingProgress{
+		Runs: map[string]*RunProgress{
+			"pit":   {Name: "pit", Runs: 4, Experience: 4000, Duration: time.Hour},
+			"cows":  {Name: "cows", Runs: 2, Experience: 6000, Duration: time.Hour},
+			"empty": {Name: "empty"},
+		},
+	}
+
+	runs := progress.Summary(0, time.Now()).Runs
+	if len(runs) != 3 {
+		t.Fatalf("got %d runs, want 3", len(runs))
+	}
+	// Sorted by experience per hour
+	if runs[0].Name != "cows" || runs[1].Name != "pit" || runs[2].Name != "empty" {
+		t.Errorf("unexpected order: %s, %s, %s", runs[0].Name, runs[1].Name, runs[2].Name)
+	}
+	if runs[1].XPPerRun != 1000 || runs[1].XPPerHour != 4000 {
+		t.Errorf("unexpected pit rates: %+v", runs[1])
+	}
+	if progress.Runs["pit"].XPPerRun != 0 {
+		t.Error("the summary modified the progress")
+	}
+}
+
+func TestWriteLevelingProgress(t *testing.T) {
+	previous := config.Koolo
+	t.Cleanup(func() { config.Koolo = previous })
+	config.Koolo = &config.KooloCfg{LogSaveDirectory: t.TempDir()}
+
+	progress := LevelingProgress{
+		Current:    ProgressSample{Level: 10, Experience: 72144},
+		Milestones: []ProgressMilestone{{Key: "level:90", Level: 90}, {Key: "stopLevelingAt:90", Level: 90}},
+	}
+	if err := WriteLevelingProgress("koza", progress); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadLevelingProgress("koza")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Current != progress.Current || len(loaded.Milestones) != 2 {
+		t.Errorf("got %+v", loaded)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(filepath.Dir(progressPath("koza")), "*"))
+	if len(files) != 1 || filepath.Base(files[0]) != progressFileName {
+		t.Errorf("temporary file left behind: %v", files)
+	}
+
+	// A broken file is reported instead of silently starting over
+	if err = os.WriteFile(progressPath("koza"), []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = LoadLevelingProgress("koza"); err == nil {
+		t.Error("expected an error reading a broken progress file")
+	}
+}
@@ -0,0 +1,177 @@
+package bot
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/hectorgimenez/d2go/pkg/data/area"
+	"github.com/hectorgimenez/d2go/pkg/data/difficulty"
+	"github.com/hectorgimenez/d2go/pkg/data/quest"
+	"github.com/hectorgimenez/d2go/pkg/data/stat"
+	botCtx "github.com/hectorgimenez/koolo/internal/context"
+	"github.com/hectorgimenez/koolo/internal/event"
+)
+
+const (
+	nearbyMonsterDistance = 15
+	maxNearbyMonsters     = 10
+)
+
+var questNames = map[quest.Quest]string{
+	quest.Act1DenOfEvil:             "Den of Evil",
+	quest.Act1SistersBurialGrounds:  "Sisters' Burial Grounds",
+	quest.Act1TheSearchForCain:      "The Search for Cain",
+	quest.Act1TheForgottenTower:     "The Forgotten Tower",
+	quest.Act1ToolsOfTheTrade:       "Tools of the Trade",
+	quest.Act1SistersToTheSlaughter: "Sisters to the Slaughter",
+	quest.Act2RadamentsLair:         "Radament's Lair",
+	quest.Act2TheHoradricStaff:      "The Horadric Staff",
+	quest.Act2TaintedSun:            "Tainted Sun",
+	quest.Act2TheSummoner:           "The Summoner",
+	quest.Act2TheSevenTombs:         "The Seven Tombs",
+	quest.Act3TheGoldenBird:         "The Golden Bird",
+	quest.Act3BladeOfTheOldReligion: "Blade of the Old Religion",
+	quest.Act3KhalimsWill:           "Khalim's Will",
+	quest.Act3LamEsensTome:          "Lam Esen's Tome",
+	quest.Act3TheBlackenedTemple:    "The Blackened Temple",
+	quest.Act3TheGuardian:           "The Guardian",
+	quest.Act4TheFallenAngel:        "The Fallen Angel",
+	quest.Act4HellForge:             "Hell's Forge",
+	quest.Act4TerrorsEnd:            "Terror's End",
+	quest.Act5SiegeOnHarrogath:      "Siege on Harrogath",
+	quest.Act5RescueOnMountArreat:   "Rescue on Mount Arreat",
+	quest.Act5PrisonOfIce:           "Prison of Ice",
+	quest.Act5BetrayalOfHarrogath:   "Betrayal of Harrogath",
+	quest.Act5RiteOfPassage:         "Rite of Passage",
+	quest.Act5EveOfDestruction:      "Eve of Destruction",
+}
+
+// lifecycleTracker detects the character progress comparing consecutive game data refreshes. It lives as long as the
+// bot, so changes happening between games (like the difficulty) are detected on the first refresh of the next game.
+type lifecycleTracker struct {
+	initialized bool
+	difficulty  difficulty.Difficulty
+	level       int
+	completed   map[quest.Quest]bool
+	playerDead  bool
+	mercAlive   bool
+	area        area.ID
+	progress    *progressTracker
+}
+
+func newLifecycleTracker(progress *progressTracker) *lifecycleTracker {
+	return &lifecycleTracker{completed: make(map[quest.Quest]bool), progress: progress}
+}
+
+// update compares the current game data with the previous refresh and sends the events for the detected changes
+func (t *lifecycleTracker) update(ctx *botCtx.Context) {
+	d := ctx.Data
+	lvl, _ := d.PlayerUnit.FindStat(stat.Level, 0)
+	// Refreshes done while the game is loading don't contain the player yet
+	if d.PlayerUnit.Area == 0 || lvl.Value == 0 {
+		return
+	}
+
+	// The difficulty of the game being played, the config can be changed meanwhile by the leveling sequence
+	diff := d.CharacterCfg.Game.Difficulty
+	if !t.initialized || diff != t.difficulty {
+		if t.initialized {
+			event.Send(event.DifficultyChanged(event.Text(ctx.Name, fmt.Sprintf("Difficulty changed from %s to %s", t.difficulty, diff)), t.difficulty, diff))
+			t.progress.milestone("difficulty:"+string(diff), lvl.Value, fmt.Sprintf("Reached %s", diff))
+		}
+		// Quests are per difficulty, the current state is the new baseline
+		t.initialized = true
+		t.difficulty = diff
+		t.level = lvl.Value
+		t.completed = completedQuests(ctx)
+		t.playerDead = d.PlayerUnit.IsDead()
+		t.mercAlive = d.MercHPPercent() > 0
+		t.area = d.PlayerUnit.Area
+		return
+	}
+
+	if lvl.Value > t.level {
+		event.Send(event.LevelUp(event.Text(ctx.Name, fmt.Sprintf("Reached level %d in %s", lvl.Value, diff)), lvl.Value, diff))
+		t.level = lvl.Value
+		if lvl.Value >= milestoneLevel {
+			t.progress.milestone(fmt.Sprintf("level:%d", milestoneLevel), lvl.Value, fmt.Sprintf("Reached level %d", milestoneLevel))
+		}
+		if stopAt := ctx.CharacterCfg.Game.StopLevelingAt; stopAt > 0 && lvl.Value >= stopAt {
+			t.progress.milestone(fmt.Sprintf("stopLevelingAt:%d", stopAt), lvl.Value, fmt.Sprintf("Reached StopLevelingAt level %d", stopAt))
+		}
+	}
+
+	// Completed quests are only added, a partial read can't trigger the same quest twice
+	for q := range completedQuests(ctx) {
+		if t.completed[q] {
+			continue
+		}
+		t.completed[q] = true
+		name := questName(q)
+		event.Send(event.QuestCompleted(event.Text(ctx.Name, fmt.Sprintf("Quest completed: %s (%s)", name, diff)), q, name, diff))
+	}
+
+	dead := d.PlayerUnit.IsDead()
+	if dead && !t.playerDead {
+		t.sendPlayerDied(ctx)
+	}
+
+	// The merc unit is not available while changing areas, only a merc disappearing in the same area is a death
+	mercAlive := d.MercHPPercent() > 0
+	if t.mercAlive && !mercAlive && !dead && d.PlayerUnit.Area == t.area {
+		areaName := d.PlayerUnit.Area.Area().Name
+		event.Send(event.MercDied(event.Text(ctx.Name, fmt.Sprintf("Mercenary died in %s", areaName)), d.PlayerUnit.Area))
+	}
+
+	t.playerDead = dead
+	t.mercAlive = mercAlive
+	t.area = d.PlayerUnit.Area
+}
+
+func (t *lifecycleTracker) sendPlayerDied(ctx *botCtx.Context) {
+	d := ctx.Data
+
+	nearby := make([]event.NearbyMonster, 0)
+	for _, m := range d.Monsters.Enemies() {
+		distance := ctx.PathFinder.DistanceFromMe(m.Position)
+		if distance <= nearbyMonsterDistance {
+			nearby = append(nearby, event.NearbyMonster{Name: m.Name, Type: m.Type, Distance: distance})
+		}
+	}
+	slices.SortFunc(nearby, func(a, b event.NearbyMonster) int {
+		return a.Distance - b.Distance
+	})
+	if len(nearby) > maxNearbyMonsters {
+		nearby = nearby[:maxNearbyMonsters]
+	}
+
+	lastAction := ""
+	if debug, found := ctx.ContextDebug[botCtx.PriorityNormal]; found {
+		lastAction = debug.LastAction
+	}
+
+	message := fmt.Sprintf("Died in %s, %d monsters nearby", d.PlayerUnit.Area.Area().Name, len(nearby))
+	if lastAction != "" {
+		message += ", last action: " + lastAction
+	}
+	event.Send(event.PlayerDied(event.Text(ctx.Name, message), d.PlayerUnit.Area, nearby, lastAction))
+}
+
+func completedQuests(ctx *botCtx.Context) map[quest.Quest]bool {
+	completed := make(map[quest.Quest]bool)
+	for q := quest.Act1DenOfEvil; q <= quest.Act5EveOfDestruction; q++ {
+		if ctx.Data.Quests[q].Completed() {
+			completed[q] = true
+		}
+	}
+
+	return completed
+}
+
+func questName(q quest.Quest) string {
+	if name, found := questNames[q]; found {
+		return name
+	}
+
+	return fmt.Sprintf("quest %d", q)
+}
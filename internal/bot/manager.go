@@ -3,7 +3,11 @@ package bot
 import (
 	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
 	"strconv"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 	"unsafe"
@@ -23,6 +27,8 @@ import (
 )
 
 type SupervisorManager struct {
+	// mu guards the supervisors and crash detectors maps, it's not held while a supervisor starts or stops
+	mu             sync.RWMutex
 	logger         *slog.Logger
 	supervisors    map[string]Supervisor
 	crashDetectors map[string]*game.CrashDetector
@@ -52,7 +58,10 @@ func (mng *SupervisorManager) AvailableSupervisors() []string {
 
 func (mng *SupervisorManager) Start(supervisorName string, attachToExisting bool, manualMode bool, pidHwnd ...uint32) error {
 	// Avoid multiple instances of the supervisor - shitstorm prevention
-	if _, exists := mng.supervisors[supervisorName]; exists {
+	mng.mu.RLock()
+	_, exists := mng.supervisors[supervisorName]
+	mng.mu.RUnlock()
+	if exists {
 		return fmt.Errorf("supervisor %s is already running", supervisorName)
 	}
 
@@ -62,6 +71,13 @@ func (mng *SupervisorManager) Start(supervisorName string, attachToExisting bool
 		return fmt.Errorf("error loading config: %w", err)
 	}
 
+	if cfg, found := config.GetCharacter(supervisorName); found && cfg.Abstract {
+		return fmt.Errorf("%s is an abstract profile, it's only a base for other profiles", supervisorName)
+	}
+	if cfg, found := config.GetCharacter(supervisorName); found && cfg.Disabled() {
+		return fmt.Errorf("supervisor %s is disabled due to invalid config: %s", supervisorName, strings.Join(cfg.Runtime.ValidationErrors, "; "))
+	}
+
 	supervisorLogger, err := log.NewLogger(config.Koolo.Debug.Log, config.Koolo.LogSaveDirectory, supervisorName)
 	if err != nil {
 		return err
@@ -97,12 +113,14 @@ func (mng *SupervisorManager) Start(supervisorName string, attachToExisting bool
 		}
 	}
 
+	mng.mu.Lock()
 	if oldCrashDetector, exists := mng.crashDetectors[supervisorName]; exists {
 		oldCrashDetector.Stop() // Stop the old crash detector if it exists
 	}
 
 	mng.supervisors[supervisorName] = supervisor
 	mng.crashDetectors[supervisorName] = crashDetector
+	mng.mu.Unlock()
 
 	if config.Koolo.GameWindowArrangement {
 		go func() {
@@ -124,44 +142,61 @@ func (mng *SupervisorManager) Start(supervisorName string, attachToExisting bool
 	return nil
 }
 
-func (mng *SupervisorManager) ReloadConfig() error {
+// ReloadConfig reloads every profile and hands the new settings to the running supervisors, it returns the changes
+// detected for each running supervisor
+func (mng *SupervisorManager) ReloadConfig() (map[string][]config.ConfigChange, error) {
 
 	// Load fresh configs
 	if err := config.Load(); err != nil {
-		return err
+		return nil, err
 	}
 
-	// Apply new configs to running supervisors
+	return mng.ApplyLoadedConfig(), nil
+}
+
+// ApplyLoadedConfig hands the loaded settings to the running supervisors without reading the config files again, it's
+// used when the files changed were written and loaded by koolo itself
+func (mng *SupervisorManager) ApplyLoadedConfig() map[string][]config.ConfigChange {
+	mng.mu.RLock()
+	defer mng.mu.RUnlock()
+
+	changes := make(map[string][]config.ConfigChange)
 	for name, sup := range mng.supervisors {
 		newCfg, exists := config.GetCharacter(name)
 		if !exists {
 			continue
 		}
 
-		ctx := sup.GetContext()
-		if ctx == nil {
+		// Keep running with the previous settings, the broken profile can't be applied
+		if newCfg.Disabled() {
+			mng.logger.Warn("Invalid config, keeping the previous settings", slog.String("supervisor", name), slog.Any("errors", newCfg.Runtime.ValidationErrors))
 			continue
 		}
 
-		// Preserve runtime data
-		//oldRuntimeData := ctx.CharacterCfg.Runtime
+		if sup.GetContext() == nil {
+			continue
+		}
 
-		// Update the config
-		*ctx.CharacterCfg = *newCfg
-		//ctx.CharacterCfg.Runtime = oldRuntimeData
+		changes[name] = sup.UpdateConfig(newCfg)
 	}
 
-	return nil
+	return changes
 }
 
 func (mng *SupervisorManager) StopAll() {
-	for _, s := range mng.supervisors {
+	mng.mu.RLock()
+	supervisors := slices.Collect(maps.Values(mng.supervisors))
+	mng.mu.RUnlock()
+
+	for _, s := range supervisors {
 		s.Stop()
 	}
 }
 
 func (mng *SupervisorManager) Stop(supervisor string) {
+	mng.mu.RLock()
 	s, found := mng.supervisors[supervisor]
+	mng.mu.RUnlock()
 	if found {
 		// Log the stop sequence
 		mng.logger.Info("Stopping supervisor instance", slog.String("supervisor", supervisor))
@@ -170,6 +205,7 @@ func (mng *SupervisorManager) Stop(supervisor string) {
 		s.Stop()
 
 		// Delete from the list of active Supervisors
+		mng.mu.Lock()
 		delete(mng.supervisors, supervisor)
 
 		// Stop the crash detector associated with it
@@ -177,6 +213,8 @@ func (mng *SupervisorManager) Stop(supervisor string) {
 			cd.Stop()
 			delete(mng.crashDetectors, supervisor)
 		}
+		mng.mu.Unlock()
+		config.UntrackRunningConfig(supervisor)
 
 		// The logic to start the next character has been removed from here.
 		// The restartFunc is now the single source of truth for this,
@@ -185,13 +223,18 @@ func (mng *SupervisorManager) Stop(supervisor string) {
 }
 
 func (mng *SupervisorManager) TogglePause(supervisor string) {
+	mng.mu.RLock()
 	s, found := mng.supervisors[supervisor]
+	mng.mu.RUnlock()
 	if found {
 		s.TogglePause()
 	}
 }
 
 func (mng *SupervisorManager) Status(characterName string) Stats {
+	mng.mu.RLock()
+	defer mng.mu.RUnlock()
+
 	for name, supervisor := range mng.supervisors {
 		if name == characterName {
 			return supervisor.Stats()
@@ -202,6 +245,9 @@ func (mng *SupervisorManager) Status(characterName string) Stats {
 }
 
 func (mng *SupervisorManager) GetData(characterName string) *game.Data {
+	mng.mu.RLock()
+	defer mng.mu.RUnlock()
+
 	for name, supervisor := range mng.supervisors {
 		if name == characterName {
 			return supervisor.GetData()
@@ -212,6 +258,9 @@ func (mng *SupervisorManager) GetData(characterName string) *game.Data {
 }
 
 func (mng *SupervisorManager) GetContext(characterName string) *context.Context {
+	mng.mu.RLock()
+	defer mng.mu.RUnlock()
+
 	for name, supervisor := range mng.supervisors {
 		if name == characterName {
 			return supervisor.GetContext()
@@ -226,6 +275,11 @@ func (mng *SupervisorManager) buildSupervisor(supervisorName string, logger *slo
 	if !found {
 		return nil, nil, fmt.Errorf("character %s not found", supervisorName)
 	}
+	// The supervisor owns its copy, reloaded settings are applied to it through UpdateConfig between games and its
+	// saves are merged with the config file
+	supervisorCfg := *cfg
+	cfg = &supervisorCfg
+	config.TrackRunningConfig(supervisorName, cfg)
 
 	var pid uint32
 	var hwnd win.HWND
@@ -238,8 +292,16 @@ func (mng *SupervisorManager) buildSupervisor(supervisorName string, logger *slo
 			return nil, nil, fmt.Errorf("pid and hwnd are required when attaching to an existing game")
 		}
 	} else {
+		if config.Koolo.UseCustomSettings {
+			if report, err := config.CheckGameSettings(cfg); err == nil {
+				for _, problem := range report.Problems {
+					logger.Warn("Game setting not supported by the bot", slog.String("setting", problem.Key), slog.String("problem", problem.Message))
+				}
+			}
+		}
+
 		var err error
-		pid, hwnd, err = game.StartGame(cfg.Username, cfg.Password, cfg.AuthMethod, cfg.AuthToken, cfg.Realm, cfg.CommandLineArgs, config.Koolo.UseCustomSettings)
+		pid, hwnd, err = game.StartGame(cfg.Username, cfg.Password, cfg.AuthMethod, cfg.AuthToken, cfg.Realm, cfg.CommandLineArgs, config.Koolo.UseCustomSettings, cfg.GameSettings)
 		if err != nil {
 			return nil, nil, fmt.Errorf("error starting game: %w", err)
 		}
@@ -392,6 +454,9 @@ func (mng *SupervisorManager) buildSupervisor(supervisorName string, logger *slo
 }
 
 func (mng *SupervisorManager) GetSupervisorStats(supervisor string) Stats {
+	mng.mu.RLock()
+	defer mng.mu.RUnlock()
+
 	if mng.supervisors[supervisor] == nil {
 		return Stats{}
 	}
@@ -415,8 +480,12 @@ func (mng *SupervisorManager) rearrangeWindows() {
 		slog.String("max rows", strconv.FormatInt(int64(maxRows+1), 10)),
 	)
 
+	mng.mu.RLock()
+	supervisors := slices.Collect(maps.Values(mng.supervisors))
+	mng.mu.RUnlock()
+
 	var column, row int32
-	for _, sp := range mng.supervisors {
+	for _, sp := range supervisors {
 		// reminder that columns are vertical (they go up and down) and rows are horizontal (they go left and right)
 		if column > maxColumns {
 			column = 0
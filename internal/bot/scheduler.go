@@ -46,7 +46,7 @@ func (s *Scheduler) checkSchedules() {
 	currentDay := int(now.Weekday())
 
 	for supervisorName, cfg := range config.GetCharacters() {
-		if !cfg.Scheduler.Enabled {
+		if !cfg.Scheduler.Enabled || cfg.Disabled() || cfg.Abstract {
 			continue
 		}
 
@@ -251,6 +251,9 @@ func (s *SinglePlayerSupervisor) Start() error {
 		// In-game logic
 		timeSpentNotInGameStart = time.Now()
 
+		// Reloaded settings are only applied between games, runs are built with the new ones
+		s.applyPendingConfig()
+
 		stringRuns := make([]string, len(s.bot.ctx.CharacterCfg.Game.Runs))
 		for i, r := range s.bot.ctx.CharacterCfg.Game.Runs {
 			stringRuns[i] = string(r)
@@ -20,6 +20,9 @@ const (
 
 type SupervisorStatus string
 
+// maxProgressEntries is the number of level ups, quests and deaths kept in the stats, the oldest are dropped first
+const maxProgressEntries = 200
+
 type StatsHandler struct {
 	stats  *Stats
 	name   string
@@ -81,6 +84,38 @@ func (h *StatsHandler) Handle(_ context.Context, e event.Event) error {
 	case event.ItemStashedEvent:
 		h.stats.Drops = append(h.stats.Drops, evt.Item)
 
+	case event.LevelUpEvent:
+		h.stats.LevelUps = appendCapped(h.stats.LevelUps, LevelUpStats{
+			Level:      evt.Level,
+			Difficulty: string(evt.Difficulty),
+			At:         evt.OccurredAt(),
+		})
+
+	case event.QuestCompletedEvent:
+		h.stats.CompletedQuests = appendCapped(h.stats.CompletedQuests, QuestStats{
+			Name:       evt.QuestName,
+			Difficulty: string(evt.Difficulty),
+			At:         evt.OccurredAt(),
+		})
+
+	case event.DifficultyChangedEvent:
+		h.stats.DifficultyChanges = appendCapped(h.stats.DifficultyChanges, DifficultyChangeStats{
+			From: string(evt.From),
+			To:   string(evt.To),
+			At:   evt.OccurredAt(),
+		})
+
+	case event.MercDiedEvent:
+		h.stats.MercDeaths++
+
+	case event.PlayerDiedEvent:
+		h.stats.Deaths = appendCapped(h.stats.Deaths, DeathStats{
+			Area:           evt.Area.Area().Name,
+			NearbyMonsters: len(evt.NearbyMonsters),
+			LastAction:     evt.LastAction,
+			At:             evt.OccurredAt(),
+		})
+
 	case event.UsedPotionEvent:
 		if len(h.stats.Games) > 0 && len(h.stats.Games[len(h.stats.Games)-1].Runs) > 0 {
 			lastRun := &h.stats.Games[len(h.stats.Games)-1].Runs[len(h.stats.Games[len(h.stats.Games)-1].Runs)-1]
@@ -91,6 +126,18 @@ func (h *StatsHandler) Handle(_ context.Context, e event.Event) error {
 	return nil
 }
 
+// appendCapped adds the entry dropping the oldest ones over maxProgressEntries, a bot running for weeks would grow
+// them without bound otherwise
+func appendCapped[T any](entries []T, entry T) []T {
+	entries = append(entries, entry)
+	if len(entries) > maxProgressEntries {
+		// Resliced rather than shifted, the slices returned by Stats share the array
+		entries = entries[len(entries)-maxProgressEntries:]
+	}
+
+	return entries
+}
+
 func (h *StatsHandler) Stats() Stats {
 	return *h.stats
 }
@@ -106,6 +153,44 @@ type Stats struct {
 	UI               CharacterOverview
 	MuleEnabled      bool `json:"muleEnabled"`
 	ManualModeActive bool `json:"manualModeActive"`
+	// Leveling progress, detected comparing the game data refreshes
+	LevelUps          []LevelUpStats
+	CompletedQuests   []QuestStats
+	DifficultyChanges []DifficultyChangeStats
+	Deaths            []DeathStats
+	MercDeaths        int
+	// Progress contains the XP rates and projections, it's persisted across restarts
+	Progress LevelingProgressSummary
+	// ValidationErrors are the config problems of the profile, it can't be started until they are fixed
+	ValidationErrors []string
+	// Config changes of the running supervisor, waiting for the next game or for a restart
+	PendingConfigChanges   []string
+	RestartRequiredChanges []string
+}
+
+type LevelUpStats struct {
+	Level      int
+	Difficulty string
+	At         time.Time
+}
+
+type QuestStats struct {
+	Name       string
+	Difficulty string
+	At         time.Time
+}
+
+type DifficultyChangeStats struct {
+	From string
+	To   string
+	At   time.Time
+}
+
+type DeathStats struct {
+	Area           string
+	NearbyMonsters int
+	LastAction     string
+	At             time.Time
 }
 
 type GameStats struct {
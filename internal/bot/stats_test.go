@@ -0,0 +1,93 @@
+package bot
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/hectorgimenez/d2go/pkg/data/area"
+	"github.com/hectorgimenez/d2go/pkg/data/difficulty"
+	"github.com/hectorgimenez/d2go/pkg/data/quest"
+	"github.com/hectorgimenez/koolo/internal/event"
+)
+
+func TestStatsHandlerProgress(t *testing.T) {
+	h := NewStatsHandler("koza", slog.Default())
+
+	events := []event.Event{
+		event.LevelUp(event.Text("koza", "Reached level 2"), 2, difficulty.Normal),
+		event.QuestCompleted(event.Text("koza", "Quest completed"), quest.Act1DenOfEvil, "Den of Evil", difficulty.Normal),
+		event.DifficultyChanged(event.Text("koza", "Difficulty changed"), difficulty.Normal, difficulty.Nightmare),
+		event.MercDied(event.Text("koza", "Mercenary died"), area.BloodMoor),
+		event.PlayerDied(event.Text("koza", "Died"), area.BloodMoor, []event.NearbyMonster{{Distance: 3}}, "attack"),
+		// Events of other supervisors are ignored
+		event.LevelUp(event.Text("other", "Reached level 3"), 3, difficulty.Normal),
+	}
+	for _, e := range events {
+		if err := h.Handle(context.Background(), e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats := h.Stats()
+	if len(stats.LevelUps) != 1 || stats.LevelUps[0].Level != 2 || stats.LevelUps[0].Difficulty != string(difficulty.Normal) {
+		t.Errorf("unexpected level ups: %+v", stats.LevelUps)
+	}
+	if len(stats.CompletedQuests) != 1 || stats.CompletedQuests[0].Name != "Den of Evil" {
+		t.Errorf("unexpected completed quests: %+v", stats.CompletedQuests)
+	}
+	if len(stats.DifficultyChanges) != 1 || stats.DifficultyChanges[0].To != string(difficulty.Nightmare) {
+		t.Errorf("unexpected difficulty changes: %+v", stats.DifficultyChanges)
+	}
+	if stats.MercDeaths != 1 {
+		t.Errorf("got %d merc deaths, want 1", stats.MercDeaths)
+	}
+	if len(stats.Deaths) != 1 || stats.Deaths[0].NearbyMonsters != 1 || stats.Deaths[0].LastAction != "attack" {
+		t.Errorf("unexpected deaths: %+v", stats.Deaths)
+	}
+}
+
+func TestStatsHandlerProgressIsCapped(t *testing.T) {
+	h := NewStatsHandler("koza", slog.Default())
+
+	total := maxProgressEntries + 50
+	for i := 1; i <= total; i++ {
+		_ = h.Handle(context.Background(), event.LevelUp(event.Text("koza", ""), i, difficulty.Normal))
+		_ = h.Handle(context.Background(), event.QuestCompleted(event.Text("koza", ""), quest.Act1DenOfEvil, "Den of Evil", difficulty.Normal))
+		_ = h.Handle(context.Background(), event.PlayerDied(event.Text("koza", ""), area.BloodMoor, nil, ""))
+	}
+
+	stats := h.Stats()
+	for name, got := range map[string]int{
+		"level ups":        len(stats.LevelUps),
+		"completed quests": len(stats.CompletedQuests),
+		"deaths":           len(stats.Deaths),
+	} {
+		if got != maxProgressEntries {
+			t.Errorf("got %d %s, want %d", got, name, maxProgressEntries)
+		}
+	}
+	// The oldest entries are dropped
+	if first := stats.LevelUps[0].Level; first != total-maxProgressEntries+1 {
+		t.Errorf("got first level %d, want %d", first, total-maxProgressEntries+1)
+	}
+	if last := stats.LevelUps[len(stats.LevelUps)-1].Level; last != total {
+		t.Errorf("got last level %d, want %d", last, total)
+	}
+}
+
+func TestAppendCappedKeepsReturnedStats(t *testing.T) {
+	entries := make([]int, 0)
+	for i := 0; i < maxProgressEntries; i++ {
+		entries = appendCapped(entries, i)
+	}
+	snapshot := entries
+
+	entries = appendCapped(entries, maxProgressEntries)
+	if snapshot[0] != 0 || snapshot[len(snapshot)-1] != maxProgressEntries-1 {
+		t.Error("entries returned before were modified")
+	}
+	if entries[0] != 1 || entries[len(entries)-1] != maxProgressEntries {
+		t.Errorf("unexpected entries: first %d, last %d", entries[0], entries[len(entries)-1])
+	}
+}
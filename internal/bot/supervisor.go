@@ -7,8 +7,10 @@ import (
 	"log/slog"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/hectorgimenez/koolo/internal/config"
 	ct "github.com/hectorgimenez/koolo/internal/context"
 	"github.com/hectorgimenez/koolo/internal/event"
 	"github.com/hectorgimenez/koolo/internal/game"
@@ -26,6 +28,7 @@ type Supervisor interface {
 	SetWindowPosition(x, y int)
 	GetData() *game.Data
 	GetContext() *ct.Context
+	UpdateConfig(cfg *config.CharacterCfg) []config.ConfigChange
 }
 
 type baseSupervisor struct {
@@ -33,6 +36,11 @@ type baseSupervisor struct {
 	name         string
 	statsHandler *StatsHandler
 	cancelFn     context.CancelFunc
+	// Reloaded config waiting for the next game, and the changes that can't be applied without a restart
+	configMu        sync.Mutex
+	pendingCfg      *config.CharacterCfg
+	pendingChanges  []string
+	restartRequired []string
 }
 
 func newBaseSupervisor(
@@ -55,10 +63,61 @@ func (s *baseSupervisor) Stats() Stats {
 	stats := s.statsHandler.Stats()
 	if s.bot.ctx != nil {
 		stats.ManualModeActive = s.bot.ctx.ManualModeActive
+		if s.bot.ctx.CharacterCfg != nil {
+			stats.Progress = s.bot.progress.summary(s.bot.ctx.CharacterCfg.Game.StopLevelingAt)
+		}
 	}
+
+	s.configMu.Lock()
+	stats.PendingConfigChanges = s.pendingChanges
+	stats.RestartRequiredChanges = s.restartRequired
+	s.configMu.Unlock()
+
 	return stats
 }
 
+// UpdateConfig compares the reloaded config with the running one, the safe changes are applied at the next game and
+// the rest are reported until the supervisor is restarted
+func (s *baseSupervisor) UpdateConfig(cfg *config.CharacterCfg) []config.ConfigChange {
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+
+	changes := config.DiffCharacterCfg(s.bot.ctx.CharacterCfg, cfg)
+	s.pendingCfg = nil
+	s.pendingChanges = nil
+	s.restartRequired = nil
+	for _, change := range changes {
+		if change.Safe {
+			s.pendingChanges = append(s.pendingChanges, change.Path)
+		} else {
+			s.restartRequired = append(s.restartRequired, change.Path)
+		}
+	}
+	if len(s.pendingChanges) > 0 {
+		s.pendingCfg = cfg
+	}
+	if len(changes) > 0 {
+		s.bot.ctx.Logger.Info("Config changed", slog.Any("nextGame", s.pendingChanges), slog.Any("restartRequired", s.restartRequired))
+	}
+
+	return changes
+}
+
+// applyPendingConfig applies the safe changes of the last config reload, it's called between games
+func (s *baseSupervisor) applyPendingConfig() {
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+
+	if s.pendingCfg == nil {
+		return
+	}
+
+	config.ApplySafeChanges(s.bot.ctx.CharacterCfg, s.pendingCfg)
+	s.bot.ctx.Logger.Info("Applied config changes", slog.Any("changes", s.pendingChanges))
+	s.pendingCfg = nil
+	s.pendingChanges = nil
+}
+
 func (s *baseSupervisor) TogglePause() {
 	if s.bot.ctx.ExecutionPriority == ct.PriorityPause {
 		s.bot.ctx.MemoryInjector.Load()
@@ -0,0 +1,291 @@
+package buildplan
+
+import (
+	"strings"
+
+	"github.com/hectorgimenez/d2go/pkg/data/skill"
+	"github.com/hectorgimenez/d2go/pkg/data/stat"
+)
+
+const (
+	Amazon      = "amazon"
+	Assassin    = "assassin"
+	Barbarian   = "barbarian"
+	Druid       = "druid"
+	Necromancer = "necromancer"
+	Paladin     = "paladin"
+	Sorceress   = "sorceress"
+)
+
+// maxHardPoints is the max number of skill points that can be assigned to a single skill
+const maxHardPoints = 20
+
+type requirement struct {
+	level  int
+	skills []skill.ID
+}
+
+func req(level int, skills ...skill.ID) requirement {
+	return requirement{level: level, skills: skills}
+}
+
+// skillTrees contains the required character level and prerequisites for every class skill
+var skillTrees = map[string]map[skill.ID]requirement{
+	Amazon: {
+		skill.MagicArrow:      req(1),
+		skill.FireArrow:       req(1),
+		skill.ColdArrow:       req(6),
+		skill.MultipleShot:    req(6, skill.MagicArrow),
+		skill.ExplodingArrow:  req(12, skill.MultipleShot, skill.FireArrow),
+		skill.IceArrow:        req(18, skill.ColdArrow),
+		skill.GuidedArrow:     req(18, skill.ColdArrow, skill.MultipleShot),
+		skill.ImmolationArrow: req(24, skill.ExplodingArrow),
+		skill.Strafe:          req(24, skill.GuidedArrow),
+		skill.FreezingArrow:   req(30, skill.IceArrow),
+		skill.InnerSight:      req(1),
+		skill.CriticalStrike:  req(1),
+		skill.Dodge:           req(6),
+		skill.SlowMissiles:    req(12, skill.InnerSight),
+		skill.Avoid:           req(12, skill.Dodge),
+		skill.Penetrate:       req(18, skill.CriticalStrike),
+		skill.Decoy:           req(24, skill.SlowMissiles),
+		skill.Evade:           req(24, skill.Avoid),
+		skill.Valkyrie:        req(30, skill.Decoy, skill.Evade),
+		skill.Pierce:          req(30, skill.Penetrate),
+		skill.Jab:             req(1),
+		skill.PowerStrike:     req(6, skill.Jab),
+		skill.PoisonJavelin:   req(6),
+		skill.Impale:          req(12, skill.Jab),
+		skill.LightningBolt:   req(12, skill.PoisonJavelin),
+		skill.ChargedStrike:   req(18, skill.PowerStrike, skill.LightningBolt),
+		skill.PlagueJavelin:   req(18, skill.LightningBolt),
+		skill.Fend:            req(24, skill.Impale),
+		skill.LightningStrike: req(30, skill.ChargedStrike),
+		skill.LightningFury:   req(30, skill.PlagueJavelin),
+	},
+	Assassin: {
+		skill.TigerStrike:       req(1),
+		skill.DragonTalon:       req(1),
+		skill.FistsOfFire:       req(6),
+		skill.DragonClaw:        req(6, skill.DragonTalon),
+		skill.CobraStrike:       req(12, skill.TigerStrike),
+		skill.ClawsOfThunder:    req(18, skill.FistsOfFire),
+		skill.DragonTail:        req(18, skill.DragonClaw),
+		skill.BladesOfIce:       req(24, skill.ClawsOfThunder),
+		skill.DragonFlight:      req(24, skill.DragonTail),
+		skill.PhoenixStrike:     req(30, skill.BladesOfIce, skill.CobraStrike),
+		skill.ClawMastery:       req(1),
+		skill.PsychicHammer:     req(1),
+		skill.BurstOfSpeed:      req(6, skill.ClawMastery),
+		skill.WeaponBlock:       req(12, skill.ClawMastery),
+		skill.CloakOfShadows:    req(12, skill.PsychicHammer),
+		skill.Fade:              req(18, skill.BurstOfSpeed),
+		skill.ShadowWarrior:     req(18, skill.CloakOfShadows, skill.WeaponBlock),
+		skill.MindBlast:         req(24, skill.CloakOfShadows),
+		skill.Venom:             req(30, skill.Fade),
+		skill.ShadowMaster:      req(30, skill.ShadowWarrior),
+		skill.FireBlast:         req(1),
+		skill.ShockWeb:          req(6, skill.FireBlast),
+		skill.BladeSentinel:     req(6),
+		skill.ChargedBoltSentry: req(12, skill.ShockWeb),
+		skill.WakeOfFire:        req(12, skill.FireBlast),
+		skill.BladeFury:         req(18, skill.BladeSentinel),
+		skill.LightningSentry:   req(18, skill.ChargedBoltSentry),
+		skill.WakeOfInferno:     req(24, skill.WakeOfFire),
+		skill.BladeShield:       req(24, skill.BladeFury),
+		skill.DeathSentry:       req(30, skill.LightningSentry),
+	},
+	Barbarian: {
+		skill.Bash:              req(1),
+		skill.Leap:              req(6),
+		skill.DoubleSwing:       req(6, skill.Bash),
+		skill.Stun:              req(12, skill.Bash),
+		skill.DoubleThrow:       req(12, skill.DoubleSwing),
+		skill.LeapAttack:        req(18, skill.Leap),
+		skill.Concentrate:       req(18, skill.Stun),
+		skill.Frenzy:            req(24, skill.DoubleThrow),
+		skill.Whirlwind:         req(30, skill.LeapAttack, skill.Concentrate),
+		skill.Berserk:           req(30, skill.Concentrate),
+		skill.SwordMastery:      req(1),
+		skill.AxeMastery:        req(1),
+		skill.MaceMastery:       req(1),
+		skill.PolearmMastery:    req(6),
+		skill.ThrowingMastery:   req(6),
+		skill.SpearMastery:      req(6),
+		skill.IncreasedStamina:  req(12),
+		skill.IronSkin:          req(18),
+		skill.IncreasedSpeed:    req(24, skill.IncreasedStamina),
+		skill.NaturalResistance: req(30, skill.IronSkin),
+		skill.Howl:              req(1),
+		skill.FindPotion:        req(1),
+		skill.Taunt:             req(6, skill.Howl),
+		skill.Shout:             req(6, skill.Howl),
+		skill.FindItem:          req(12, skill.FindPotion),
+		skill.BattleCry:         req(18, skill.Taunt),
+		skill.BattleOrders:      req(24, skill.Shout),
+		skill.GrimWard:          req(24, skill.FindItem),
+		skill.WarCry:            req(30, skill.BattleCry, skill.BattleOrders),
+		skill.BattleCommand:     req(30, skill.BattleOrders),
+	},
+	Druid: {
+		skill.Raven:            req(1),
+		skill.PoisonCreeper:    req(1),
+		skill.OakSage:          req(6),
+		skill.SummonSpiritWolf: req(6, skill.Raven),
+		skill.CarrionVine:      req(12, skill.PoisonCreeper),
+		skill.HeartOfWolverine: req(18, skill.OakSage),
+		skill.SummonDireWolf:   req(18, skill.SummonSpiritWolf),
+		skill.SolarCreeper:     req(24, skill.CarrionVine),
+		skill.SpiritOfBarbs:    req(30, skill.HeartOfWolverine),
+		skill.SummonGrizzly:    req(30, skill.SummonDireWolf),
+		skill.Werewolf:         req(1),
+		skill.Lycanthropy:      req(1),
+		skill.Werebear:         req(6),
+		skill.FeralRage:        req(12, skill.Werewolf),
+		skill.Maul:             req(12, skill.Werebear),
+		skill.Rabies:           req(18, skill.FeralRage),
+		skill.FireClaws:        req(18, skill.FeralRage, skill.Maul),
+		skill.Hunger:           req(24, skill.FireClaws),
+		skill.ShockWave:        req(24, skill.Maul),
+		skill.Fury:             req(30, skill.Rabies),
+		skill.Firestorm:        req(1),
+		skill.MoltenBoulder:    req(6, skill.Firestorm),
+		skill.ArcticBlast:      req(6),
+		skill.Fissure:          req(12, skill.MoltenBoulder),
+		skill.CycloneArmor:     req(12, skill.ArcticBlast),
+		skill.Twister:          req(18, skill.CycloneArmor),
+		skill.Volcano:          req(24, skill.Fissure),
+		skill.Tornado:          req(24, skill.Twister),
+		skill.Hurricane:        req(30, skill.Tornado),
+		skill.Armageddon:       req(30, skill.Volcano, skill.Hurricane),
+	},
+	Necromancer: {
+		skill.AmplifyDamage:     req(1),
+		skill.DimVision:         req(6),
+		skill.Weaken:            req(6, skill.AmplifyDamage),
+		skill.IronMaiden:        req(12, skill.AmplifyDamage),
+		skill.Terror:            req(12, skill.Weaken),
+		skill.Confuse:           req(18, skill.DimVision),
+		skill.LifeTap:           req(18, skill.IronMaiden),
+		skill.Attract:           req(24, skill.Confuse),
+		skill.Decrepify:         req(24, skill.Terror),
+		skill.LowerResist:       req(30, skill.LifeTap, skill.Decrepify),
+		skill.Teeth:             req(1),
+		skill.BoneArmor:         req(1),
+		skill.PoisonDagger:      req(6),
+		skill.CorpseExplosion:   req(6, skill.Teeth),
+		skill.BoneWall:          req(12, skill.BoneArmor),
+		skill.PoisonExplosion:   req(18, skill.PoisonDagger, skill.CorpseExplosion),
+		skill.BoneSpear:         req(18, skill.CorpseExplosion),
+		skill.BonePrison:        req(24, skill.BoneSpear, skill.BoneWall),
+		skill.PoisonNova:        req(30, skill.PoisonExplosion),
+		skill.BoneSpirit:        req(30, skill.BonePrison),
+		skill.SkeletonMastery:   req(1),
+		skill.RaiseSkeleton:     req(1),
+		skill.ClayGolem:         req(6),
+		skill.GolemMastery:      req(12, skill.ClayGolem),
+		skill.RaiseSkeletalMage: req(12, skill.RaiseSkeleton),
+		skill.BloodGolem:        req(18, skill.ClayGolem),
+		skill.SummonResist:      req(24, skill.GolemMastery),
+		skill.IronGolem:         req(24, skill.BloodGolem),
+		skill.FireGolem:         req(30, skill.IronGolem),
+		skill.Revive:            req(30, skill.RaiseSkeletalMage, skill.IronGolem),
+	},
+	Paladin: {
+		skill.Sacrifice:        req(1),
+		skill.Smite:            req(6),
+		skill.HolyBolt:         req(6),
+		skill.Zeal:             req(12, skill.Sacrifice),
+		skill.Charge:           req(12, skill.Smite),
+		skill.Vengeance:        req(18, skill.Zeal),
+		skill.BlessedHammer:    req(18, skill.HolyBolt),
+		skill.Conversion:       req(24, skill.Vengeance),
+		skill.HolyShield:       req(24, skill.Charge, skill.BlessedHammer),
+		skill.FistOfTheHeavens: req(30, skill.BlessedHammer, skill.Conversion),
+		skill.Might:            req(1),
+		skill.HolyFire:         req(6, skill.Might),
+		skill.Thorns:           req(6),
+		skill.BlessedAim:       req(12, skill.Might),
+		skill.Concentration:    req(18, skill.BlessedAim),
+		skill.HolyFreeze:       req(18, skill.HolyFire),
+		skill.HolyShock:        req(24, skill.HolyFreeze),
+		skill.Sanctuary:        req(24, skill.Thorns, skill.HolyFreeze),
+		skill.Fanaticism:       req(30, skill.Concentration),
+		skill.Conviction:       req(30, skill.Sanctuary),
+		skill.Prayer:           req(1),
+		skill.ResistFire:       req(1),
+		skill.Defiance:         req(6),
+		skill.ResistCold:       req(6),
+		skill.Cleansing:        req(12, skill.Prayer),
+		skill.ResistLightning:  req(12, skill.ResistFire, skill.ResistCold),
+		skill.Vigor:            req(18, skill.Cleansing, skill.Defiance),
+		skill.Meditation:       req(24, skill.Cleansing),
+		skill.Redemption:       req(30, skill.Vigor),
+		skill.Salvation:        req(30, skill.ResistLightning),
+	},
+	Sorceress: {
+		skill.FireBolt:         req(1),
+		skill.Warmth:           req(1),
+		skill.Inferno:          req(6),
+		skill.Blaze:            req(12, skill.Inferno),
+		skill.FireBall:         req(12, skill.FireBolt),
+		skill.FireWall:         req(18, skill.Blaze),
+		skill.Enchant:          req(18, skill.Warmth, skill.FireBall),
+		skill.Meteor:           req(24, skill.FireWall, skill.FireBall),
+		skill.FireMastery:      req(30),
+		skill.Hydra:            req(30, skill.Enchant),
+		skill.ChargedBolt:      req(1),
+		skill.StaticField:      req(6),
+		skill.Telekinesis:      req(6),
+		skill.Nova:             req(12, skill.StaticField),
+		skill.Lightning:        req(12, skill.ChargedBolt),
+		skill.ChainLightning:   req(18, skill.Lightning),
+		skill.Teleport:         req(18, skill.Telekinesis),
+		skill.ThunderStorm:     req(24, skill.Nova, skill.ChainLightning),
+		skill.EnergyShield:     req(24, skill.ChainLightning, skill.Teleport),
+		skill.LightningMastery: req(30),
+		skill.IceBolt:          req(1),
+		skill.FrozenArmor:      req(1),
+		skill.FrostNova:        req(6),
+		skill.IceBlast:         req(6, skill.IceBolt),
+		skill.ShiverArmor:      req(12, skill.IceBlast, skill.FrozenArmor),
+		skill.GlacialSpike:     req(18, skill.IceBlast),
+		skill.Blizzard:         req(24, skill.FrostNova, skill.GlacialSpike),
+		skill.ChillingArmor:    req(24, skill.ShiverArmor),
+		skill.FrozenOrb:        req(30, skill.Blizzard),
+		skill.ColdMastery:      req(30),
+	},
+}
+
+// baseStats are the stats of a level 1 character
+var baseStats = map[string]map[stat.ID]int{
+	Amazon:      {stat.Strength: 20, stat.Dexterity: 25, stat.Vitality: 20, stat.Energy: 15},
+	Assassin:    {stat.Strength: 20, stat.Dexterity: 20, stat.Vitality: 20, stat.Energy: 25},
+	Barbarian:   {stat.Strength: 30, stat.Dexterity: 20, stat.Vitality: 25, stat.Energy: 10},
+	Druid:       {stat.Strength: 15, stat.Dexterity: 20, stat.Vitality: 25, stat.Energy: 20},
+	Necromancer: {stat.Strength: 15, stat.Dexterity: 25, stat.Vitality: 15, stat.Energy: 25},
+	Paladin:     {stat.Strength: 25, stat.Dexterity: 20, stat.Vitality: 25, stat.Energy: 15},
+	Sorceress:   {stat.Strength: 10, stat.Dexterity: 25, stat.Vitality: 10, stat.Energy: 35},
+}
+
+// ClassFromConfig returns the game class for a character class from the config ("sorceress_leveling", "hammerdin"...)
+func ClassFromConfig(class string) (string, bool) {
+	switch strings.ToLower(class) {
+	case "amazon_leveling", "javazon":
+		return Amazon, true
+	case "assassin", "trapsin", "mosaic":
+		return Assassin, true
+	case "barb", "barb_leveling", "berserker", "warcry_barb":
+		return Barbarian, true
+	case "druid_leveling", "winddruid":
+		return Druid, true
+	case "necromancer":
+		return Necromancer, true
+	case "paladin", "hammerdin", "foh":
+		return Paladin, true
+	case "sorceress", "sorceress_leveling", "fireballsorc", "nova", "hydraorb", "lightsorc":
+		return Sorceress, true
+	}
+
+	return "", false
+}
@@ -0,0 +1,88 @@
+package buildplan
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/hectorgimenez/d2go/pkg/data/skill"
+)
+
+// TestAmazonSkillTree checks the Amazon requirements against the reqlevel and reqskill columns of the game skills.txt
+func TestAmazonSkillTree(t *testing.T) {
+	tests := []struct {
+		skill  skill.ID
+		level  int
+		skills []skill.ID
+	}{
+		// Bow and Crossbow
+		{skill.MagicArrow, 1, nil},
+		{skill.FireArrow, 1, nil},
+		{skill.ColdArrow, 6, nil},
+		{skill.MultipleShot, 6, []skill.ID{skill.MagicArrow}},
+		{skill.ExplodingArrow, 12, []skill.ID{skill.MultipleShot, skill.FireArrow}},
+		{skill.IceArrow, 18, []skill.ID{skill.ColdArrow}},
+		{skill.GuidedArrow, 18, []skill.ID{skill.ColdArrow, skill.MultipleShot}},
+		{skill.ImmolationArrow, 24, []skill.ID{skill.ExplodingArrow}},
+		{skill.Strafe, 24, []skill.ID{skill.GuidedArrow}},
+		{skill.FreezingArrow, 30, []skill.ID{skill.IceArrow}},
+		// Passive and Magic
+		{skill.InnerSight, 1, nil},
+		{skill.CriticalStrike, 1, nil},
+		{skill.Dodge, 6, nil},
+		{skill.SlowMissiles, 12, []skill.ID{skill.InnerSight}},
+		{skill.Avoid, 12, []skill.ID{skill.Dodge}},
+		{skill.Penetrate, 18, []skill.ID{skill.CriticalStrike}},
+		{skill.Decoy, 24, []skill.ID{skill.SlowMissiles}},
+		{skill.Evade, 24, []skill.ID{skill.Avoid}},
+		{skill.Valkyrie, 30, []skill.ID{skill.Decoy, skill.Evade}},
+		{skill.Pierce, 30, []skill.ID{skill.Penetrate}},
+		// Javelin and Spear
+		{skill.Jab, 1, nil},
+		{skill.PowerStrike, 6, []skill.ID{skill.Jab}},
+		{skill.PoisonJavelin, 6, nil},
+		{skill.Impale, 12, []skill.ID{skill.Jab}},
+		{skill.LightningBolt, 12, []skill.ID{skill.PoisonJavelin}},
+		{skill.ChargedStrike, 18, []skill.ID{skill.PowerStrike, skill.LightningBolt}},
+		{skill.PlagueJavelin, 18, []skill.ID{skill.LightningBolt}},
+		{skill.Fend, 24, []skill.ID{skill.Impale}},
+		{skill.LightningStrike, 30, []skill.ID{skill.ChargedStrike}},
+		{skill.LightningFury, 30, []skill.ID{skill.PlagueJavelin}},
+	}
+
+	tree := skillTrees[Amazon]
+	if len(tree) != len(tests) {
+		t.Errorf("expected %d Amazon skills, got %d", len(tests), len(tree))
+	}
+
+	for _, tt := range tests {
+		t.Run(skill.SkillNames[tt.skill], func(t *testing.T) {
+			got, found := tree[tt.skill]
+			if !found {
+				t.Fatal("skill missing from the tree")
+			}
+			if got.level != tt.level {
+				t.Errorf("got level %d, want %d", got.level, tt.level)
+			}
+			if !slices.Equal(got.skills, tt.skills) {
+				t.Errorf("got prerequisites %v, want %v", got.skills, tt.skills)
+			}
+		})
+	}
+}
+
+func TestSkillTreesPrerequisites(t *testing.T) {
+	for class, tree := range skillTrees {
+		for sk, requirement := range tree {
+			for _, prerequisite := range requirement.skills {
+				pre, found := tree[prerequisite]
+				if !found {
+					t.Errorf("%s: prerequisite %d of skill %d is not in the tree", class, prerequisite, sk)
+					continue
+				}
+				if pre.level >= requirement.level {
+					t.Errorf("%s: prerequisite %d of skill %d requires level %d, skill requires %d", class, prerequisite, sk, pre.level, requirement.level)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,331 @@
+package buildplan
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/hectorgimenez/d2go/pkg/data/difficulty"
+	"github.com/hectorgimenez/d2go/pkg/data/skill"
+	"github.com/hectorgimenez/d2go/pkg/data/stat"
+	"github.com/hectorgimenez/koolo/internal/config"
+)
+
+const (
+	MaxLevel = 99
+
+	statPointsPerLevel  = 5
+	skillPointsPerLevel = 1
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+type Issue struct {
+	Level    int      `json:"level"`
+	Severity Severity `json:"severity"`
+	Skill    string   `json:"skill,omitempty"`
+	Message  string   `json:"message"`
+}
+
+// QuestCompletion is a quest reward received at the given character level
+type QuestCompletion struct {
+	Quest      config.Run            `json:"quest"`
+	Difficulty difficulty.Difficulty `json:"difficulty"`
+	Level      int                   `json:"level"`
+}
+
+type questReward struct {
+	statPoints  int
+	skillPoints int
+}
+
+var questRewards = map[config.Run]questReward{
+	config.DenRun:      {skillPoints: 1},
+	config.RadamentRun: {skillPoints: 1},
+	config.LamEsenRun:  {statPoints: 5},
+	config.IzualRun:    {skillPoints: 2},
+}
+
+// DefaultQuestTimeline is the usual character level when every reward quest is completed while leveling
+func DefaultQuestTimeline() []QuestCompletion {
+	timeline := make([]QuestCompletion, 0, 12)
+	levels := map[difficulty.Difficulty][]int{
+		difficulty.Normal:    {6, 15, 20, 25},
+		difficulty.Nightmare: {40, 44, 47, 50},
+		difficulty.Hell:      {62, 66, 69, 72},
+	}
+	for _, d := range []difficulty.Difficulty{difficulty.Normal, difficulty.Nightmare, difficulty.Hell} {
+		for i, q := range []config.Run{config.DenRun, config.RadamentRun, config.LamEsenRun, config.IzualRun} {
+			timeline = append(timeline, QuestCompletion{Quest: q, Difficulty: d, Level: levels[d][i]})
+		}
+	}
+
+	return timeline
+}
+
+type LevelSnapshot struct {
+	Level              int                       `json:"level"`
+	Stats              map[config.BuildStat]int  `json:"stats"`
+	Skills             map[config.BuildSkill]int `json:"skills"`
+	UnspentStatPoints  int                       `json:"unspentStatPoints"`
+	UnspentSkillPoints int                       `json:"unspentSkillPoints"`
+	Quests             []QuestCompletion         `json:"quests,omitempty"`
+	Respec             bool                      `json:"respec,omitempty"`
+}
+
+type Result struct {
+	Class  string          `json:"class"`
+	Levels []LevelSnapshot `json:"levels"`
+	Issues []Issue         `json:"issues"`
+}
+
+// Valid returns false if any step of the plan can't be done in game
+func (r Result) Valid() bool {
+	for _, issue := range r.Issues {
+		if issue.Severity == SeverityError {
+			return false
+		}
+	}
+
+	return true
+}
+
+type character struct {
+	class       string
+	level       int
+	stats       map[stat.ID]int
+	skills      map[skill.ID]int
+	statPoints  int
+	skillPoints int
+}
+
+// Simulate applies the build plan level by level, the same way EnsureStatPoints and EnsureSkillPoints do in game, up to
+// maxLevel. Every step that can't be done in game (missing prerequisites, level too low...) is reported as an issue.
+func Simulate(class string, build *config.LevelingBuildConfig, quests []QuestCompletion, maxLevel int) (Result, error) {
+	gameClass, found := ClassFromConfig(class)
+	if !found {
+		return Result{}, fmt.Errorf("unknown class %q", class)
+	}
+	if build == nil || (!build.HasStats() && !build.HasSkills()) {
+		return Result{}, fmt.Errorf("build for %s doesn't have a stat or skill plan", class)
+	}
+	maxLevel = min(max(maxLevel, 1), MaxLevel)
+
+	result := Result{Class: gameClass, Issues: make([]Issue, 0)}
+	result.Issues = append(result.Issues, validatePlan(gameClass, build)...)
+	result.Issues = append(result.Issues, validateQuests(quests)...)
+
+	c := &character{
+		class:  gameClass,
+		level:  1,
+		stats:  make(map[stat.ID]int),
+		skills: make(map[skill.ID]int),
+	}
+	for id, value := range baseStats[gameClass] {
+		c.stats[id] = value
+	}
+
+	// The same step usually fails on every level until it's possible, only the first one is reported
+	reported := make(map[string]struct{})
+	for c.level = 1; c.level <= maxLevel; c.level++ {
+		snapshot := LevelSnapshot{Level: c.level}
+		if c.level > 1 {
+			c.statPoints += statPointsPerLevel
+			c.skillPoints += skillPointsPerLevel
+		}
+
+		for _, q := range quests {
+			reward, found := questRewards[q.Quest]
+			if !found || q.Level != c.level {
+				continue
+			}
+			c.statPoints += reward.statPoints
+			c.skillPoints += reward.skillPoints
+			snapshot.Quests = append(snapshot.Quests, q)
+		}
+
+		if build.HasRespec() && build.ShouldRespec(c.level, func(id skill.ID) int { return c.skills[id] }) {
+			c.respec()
+			snapshot.Respec = true
+		}
+
+		if build.HasStats() {
+			c.allocateStats(build)
+		}
+		if build.HasSkills() {
+			for _, issue := range c.allocateSkills(build) {
+				if _, found := reported[issue.Message]; !found {
+					reported[issue.Message] = struct{}{}
+					result.Issues = append(result.Issues, issue)
+				}
+			}
+		}
+
+		snapshot.Stats = make(map[config.BuildStat]int, len(c.stats))
+		for id, value := range c.stats {
+			snapshot.Stats[config.BuildStat(id)] = value
+		}
+		snapshot.Skills = make(map[config.BuildSkill]int, len(c.skills))
+		for id, value := range c.skills {
+			snapshot.Skills[config.BuildSkill(id)] = value
+		}
+		snapshot.UnspentStatPoints = c.statPoints
+		snapshot.UnspentSkillPoints = c.skillPoints
+		result.Levels = append(result.Levels, snapshot)
+	}
+
+	result.Issues = append(result.Issues, unspentIssues(build, result.Levels)...)
+
+	return result, nil
+}
+
+// allocateStats follows the same rules as EnsureStatPoints
+func (c *character) allocateStats(build *config.LevelingBuildConfig) {
+	for _, target := range build.Stats {
+		if c.statPoints == 0 {
+			return
+		}
+		id := stat.ID(target.Stat)
+		if c.stats[id] >= target.Points {
+			continue
+		}
+		spend := min(target.Points-c.stats[id], c.statPoints)
+		c.stats[id] += spend
+		c.statPoints -= spend
+	}
+}
+
+// allocateSkills follows the same rules as EnsureSkillPoints, allocation stops at the first point that can't be spent
+func (c *character) allocateSkills(build *config.LevelingBuildConfig) []Issue {
+	targetLevels := make(map[skill.ID]int)
+	for _, id := range build.SkillPlan(c.level) {
+		targetLevels[id]++
+		if c.skillPoints == 0 {
+			return nil
+		}
+		if c.skills[id] >= targetLevels[id] {
+			continue
+		}
+
+		if issue, ok := c.canSpend(id); !ok {
+			return []Issue{issue}
+		}
+		c.skills[id]++
+		c.skillPoints--
+	}
+
+	return nil
+}
+
+// canSpend checks if a point can be assigned to the skill. Points waiting for a higher character level are warnings,
+// the rest of the plan is delayed until then. Anything else is an error, the plan is stuck from there.
+func (c *character) canSpend(id skill.ID) (Issue, bool) {
+	name := skill.SkillNames[id]
+	issue := func(severity Severity, format string, args ...any) (Issue, bool) {
+		return Issue{Level: c.level, Severity: severity, Skill: name, Message: fmt.Sprintf(format, args...)}, false
+	}
+
+	r, found := skillTrees[c.class][id]
+	if !found {
+		return issue(SeverityError, "%s is not a %s skill", name, c.class)
+	}
+	for _, prerequisite := range r.skills {
+		if c.skills[prerequisite] == 0 {
+			return issue(SeverityError, "%s requires a point in %s", name, skill.SkillNames[prerequisite])
+		}
+	}
+	if c.skills[id] >= maxHardPoints {
+		return issue(SeverityError, "%s is already at level %d", name, maxHardPoints)
+	}
+	if c.level < r.level {
+		return issue(SeverityWarning, "%s requires character level %d", name, r.level)
+	}
+	// Every additional point requires one more character level
+	if c.skills[id] >= c.level-r.level+1 {
+		return issue(SeverityWarning, "%s level %d requires character level %d", name, c.skills[id]+1, r.level+c.skills[id])
+	}
+
+	return Issue{}, true
+}
+
+// respec gives back all the points, like Akara's reset does
+func (c *character) respec() {
+	for id, value := range baseStats[c.class] {
+		c.statPoints += c.stats[id] - value
+		c.stats[id] = value
+	}
+	for _, points := range c.skills {
+		c.skillPoints += points
+	}
+	clear(c.skills)
+}
+
+// validatePlan checks the plan itself, without simulating it
+func validatePlan(class string, build *config.LevelingBuildConfig) []Issue {
+	issues := make([]Issue, 0)
+	for _, plan := range build.Skills {
+		for _, sk := range plan.Points {
+			if _, found := skillTrees[class][skill.ID(sk)]; !found {
+				issues = append(issues, Issue{
+					Level:    plan.MinLevel,
+					Severity: SeverityError,
+					Skill:    skill.SkillNames[skill.ID(sk)],
+					Message:  fmt.Sprintf("%s is not a %s skill", skill.SkillNames[skill.ID(sk)], class),
+				})
+			}
+		}
+	}
+	for _, binding := range build.Bindings {
+		id := skill.ID(binding.Skill)
+		if _, found := skillTrees[class][id]; !found && id != skill.AttackSkill && id != skill.TomeOfTownPortal && id != skill.TomeOfIdentify {
+			issues = append(issues, Issue{
+				Level:    binding.MinLevel,
+				Severity: SeverityWarning,
+				Skill:    skill.SkillNames[id],
+				Message:  fmt.Sprintf("%s is bound but it's not a %s skill, it will only be bound if it's given by an item", skill.SkillNames[id], class),
+			})
+		}
+	}
+
+	return issues
+}
+
+func validateQuests(quests []QuestCompletion) []Issue {
+	issues := make([]Issue, 0)
+	seen := make([]QuestCompletion, 0, len(quests))
+	for _, q := range quests {
+		if _, found := questRewards[q.Quest]; !found {
+			issues = append(issues, Issue{Level: q.Level, Severity: SeverityWarning, Message: fmt.Sprintf("quest %s doesn't give stat or skill points", q.Quest)})
+			continue
+		}
+		if slices.ContainsFunc(seen, func(s QuestCompletion) bool { return s.Quest == q.Quest && s.Difficulty == q.Difficulty }) {
+			issues = append(issues, Issue{Level: q.Level, Severity: SeverityError, Message: fmt.Sprintf("quest %s is completed twice on %s", q.Quest, q.Difficulty)})
+		}
+		seen = append(seen, q)
+	}
+
+	return issues
+}
+
+// unspentIssues reports the levels where points are left unspent, once per streak so the output stays readable
+func unspentIssues(build *config.LevelingBuildConfig, levels []LevelSnapshot) []Issue {
+	issues := make([]Issue, 0)
+	statStreak, skillStreak := false, false
+	for _, l := range levels {
+		if build.HasStats() && l.UnspentStatPoints > 0 && !statStreak {
+			issues = append(issues, Issue{Level: l.Level, Severity: SeverityWarning, Message: fmt.Sprintf("%d stat points are not spent, all stat targets are reached", l.UnspentStatPoints)})
+		}
+		statStreak = l.UnspentStatPoints > 0
+
+		if build.HasSkills() && l.UnspentSkillPoints > 0 && !skillStreak {
+			issues = append(issues, Issue{Level: l.Level, Severity: SeverityWarning, Message: fmt.Sprintf("%d skill points are not spent", l.UnspentSkillPoints)})
+		}
+		skillStreak = l.UnspentSkillPoints > 0
+	}
+
+	return issues
+}
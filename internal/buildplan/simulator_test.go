@@ -0,0 +1,73 @@
+package buildplan
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hectorgimenez/d2go/pkg/data/skill"
+	"github.com/hectorgimenez/d2go/pkg/data/stat"
+	"github.com/hectorgimenez/koolo/internal/config"
+)
+
+func repeat(sk skill.ID, points int) []config.BuildSkill {
+	result := make([]config.BuildSkill, points)
+	for i := range result {
+		result[i] = config.BuildSkill(sk)
+	}
+
+	return result
+}
+
+func TestSimulate(t *testing.T) {
+	build := &config.LevelingBuildConfig{
+		Stats: []config.LevelingStatTarget{
+			{Stat: config.BuildStat(stat.Strength), Points: 30},
+			{Stat: config.BuildStat(stat.Vitality), Points: 999},
+		},
+		Skills: []config.LevelingSkillPlan{
+			{MinLevel: 1, Points: append(repeat(skill.FireBolt, 10), repeat(skill.FireBall, 20)...)},
+			{MinLevel: 24, Points: append([]config.BuildSkill{config.BuildSkill(skill.IceBolt), config.BuildSkill(skill.IceBlast)}, repeat(skill.FrostNova, 20)...)},
+		},
+		Respec: []config.LevelingRespec{{Level: 24, RequiredSkills: map[config.BuildSkill]int{config.BuildSkill(skill.FireBall): 1}}},
+	}
+
+	result, err := Simulate("sorceress_leveling", build, DefaultQuestTimeline(), 30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Valid() {
+		t.Fatalf("expected a valid plan, got %v", result.Issues)
+	}
+
+	// 11 levels + Den, Fire Ball can only have one point at level 12
+	lvl12 := result.Levels[11]
+	if lvl12.Skills[config.BuildSkill(skill.FireBolt)] != 10 || lvl12.Skills[config.BuildSkill(skill.FireBall)] != 1 || lvl12.UnspentSkillPoints != 1 {
+		t.Errorf("unexpected skills at level 12: %v, unspent %d", lvl12.Skills, lvl12.UnspentSkillPoints)
+	}
+	if lvl12.Stats[config.BuildStat(stat.Strength)] != 30 || lvl12.Stats[config.BuildStat(stat.Vitality)] != 10+55-20 {
+		t.Errorf("unexpected stats at level 12: %v", lvl12.Stats)
+	}
+
+	lvl24 := result.Levels[23]
+	// 23 levels + Den + Radament, Frost Nova is limited by the character level
+	if !lvl24.Respec || lvl24.Skills[config.BuildSkill(skill.FireBolt)] != 0 || lvl24.Skills[config.BuildSkill(skill.FrostNova)] != 19 || lvl24.UnspentSkillPoints != 4 {
+		t.Errorf("unexpected skills after respec: %v", lvl24.Skills)
+	}
+
+	// Ice Blast without Ice Bolt
+	build.Skills[1].Points = build.Skills[1].Points[1:]
+	result, err = Simulate("sorceress_leveling", build, DefaultQuestTimeline(), 30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Valid() {
+		t.Fatal("expected an invalid plan")
+	}
+	found := false
+	for _, issue := range result.Issues {
+		found = found || (issue.Level == 24 && strings.Contains(issue.Message, "requires a point in"))
+	}
+	if !found {
+		t.Errorf("missing prerequisite not reported: %v", result.Issues)
+	}
+}
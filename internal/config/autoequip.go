@@ -0,0 +1,82 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+
+	"github.com/hectorgimenez/d2go/pkg/data/stat"
+	"github.com/hectorgimenez/koolo/internal/utils"
+)
+
+// AutoEquipScoring is an autoequip scoring profile. Weights replace the default weight of the same stat, stats that
+// are not listed keep the default one, so a profile only needs to contain what it changes. Stats are written by name
+// (fireresist, item_fastercastrate...) or by id.
+type AutoEquipScoring struct {
+	SkillWeights       map[ScoreStat]float64 `json:"skillWeights,omitempty"`
+	ResistWeightsMain  map[ScoreStat]float64 `json:"resistWeightsMain,omitempty"`
+	ResistWeightsOther map[ScoreStat]float64 `json:"resistWeightsOther,omitempty"`
+	GeneralWeights     map[ScoreStat]float64 `json:"generalWeights,omitempty"`
+	// ClassWeightModifiers replace the default modifiers of the character class, added to GeneralWeights
+	ClassWeightModifiers map[ScoreStat]float64 `json:"classWeightModifiers,omitempty"`
+	MercWeights          map[ScoreStat]float64 `json:"mercWeights,omitempty"`
+	// UniqueItemScores are fixed general scores by item, unique or runeword name, they ignore the rest of the stats
+	UniqueItemScores map[string]float64 `json:"uniqueItemScores,omitempty"`
+}
+
+// ScoreStat is a stat written by name or by id in the scoring profiles
+type ScoreStat stat.ID
+
+func (s ScoreStat) MarshalText() ([]byte, error) {
+	for id, name := range stat.StringStats {
+		if stat.ID(id) == stat.ID(s) && name != "" {
+			return []byte(name), nil
+		}
+	}
+
+	return []byte(strconv.Itoa(int(s))), nil
+}
+
+func (s *ScoreStat) UnmarshalText(text []byte) error {
+	if id, err := strconv.Atoi(string(text)); err == nil {
+		*s = ScoreStat(id)
+		return nil
+	}
+
+	wanted := normalizeBuildName(string(text))
+	for id, name := range stat.StringStats {
+		if name != "" && normalizeBuildName(name) == wanted {
+			*s = ScoreStat(id)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown stat %q", string(text))
+}
+
+// LoadAutoEquipScoring reads the scoring profile of the character, the one in the character config folder
+// (autoequip_scoring.json) has priority over the build one (template/autoequip_scoring/<class>.json). Returns nil if
+// none of them exist.
+func LoadAutoEquipScoring(entryName, class string) (*AutoEquipScoring, error) {
+	paths := []string{
+		getAbsPath(filepath.Join("config", entryName, "autoequip_scoring.json")),
+		getAbsPath(filepath.Join("config", "template", "autoequip_scoring", class+".json")),
+	}
+
+	for _, path := range paths {
+		jsonData, err := utils.GetJsonData(path)
+		if err != nil {
+			continue
+		}
+
+		var scoring AutoEquipScoring
+		if err = json.Unmarshal(jsonData, &scoring); err != nil {
+			return nil, fmt.Errorf("error reading autoequip scoring profile %s: %w", path, err)
+		}
+
+		return &scoring, nil
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,554 @@
+package config
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hectorgimenez/koolo/internal/config/history"
+	"github.com/hectorgimenez/koolo/internal/config/migration"
+	"gopkg.in/yaml.v3"
+)
+
+// A profile bundle is a zip file with everything needed to run a profile on another koolo installation: the effective
+// config.yaml without account settings, the profile pickit files, the leveling sequence file and the leveling build of
+// the class. The manifest lists every file with its checksum.
+
+const (
+	bundleFormat        = 1
+	bundleManifestFile  = "manifest.json"
+	bundleConfigFile    = "config.yaml"
+	bundleSequencesDir  = "sequences_leveling"
+	bundleBuildsDir     = "builds_leveling"
+	bundleScoringFile   = "autoequip_scoring.json"
+	maxBundleFileSize   = 5 << 20
+	maxBundleNameSuffix = 100
+)
+
+// bundlePickitDirs are the profile pickit folders added to the bundle, stored with the same name
+var bundlePickitDirs = []string{"pickit", "pickit_leveling"}
+
+// Bundle file actions shown in the import preview
+const (
+	BundleActionCreate    = "create"
+	BundleActionReuse     = "reuse"
+	BundleActionRename    = "rename"
+	BundleActionKeep      = "keep"
+	BundleActionOverwrite = "overwrite"
+)
+
+type BundleFile struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+}
+
+type BundleManifest struct {
+	Format       int          `json:"format"`
+	KooloVersion string       `json:"kooloVersion"`
+	Profile      string       `json:"profile"`
+	Class        string       `json:"class"`
+	CreatedAt    time.Time    `json:"createdAt"`
+	Files        []BundleFile `json:"files"`
+}
+
+// BundleImportOptions are the choices of the user when importing a bundle
+type BundleImportOptions struct {
+	// Name of the new profile, the bundle profile name when empty
+	Name string `json:"name"`
+	// OverwriteBuild replaces the leveling build of the class when it differs from the bundle one, it's shared by
+	// every profile of the class so the existing one is kept by default
+	OverwriteBuild bool `json:"overwriteBuild"`
+	// Source of the import, recorded in the config history of the written files
+	Source history.Source `json:"-"`
+}
+
+// BundleFileImport is where a bundle file is written on import
+type BundleFileImport struct {
+	Path   string `json:"path"`
+	Target string `json:"target"`
+	Action string `json:"action"`
+}
+
+// BundlePreview is the result of checking a bundle before importing it. The bundle can be imported when Errors is
+// empty, Warnings are problems the imported profile will have, like settings unknown to this koolo version.
+type BundlePreview struct {
+	Manifest      BundleManifest     `json:"manifest"`
+	Name          string             `json:"name"`
+	NameTaken     bool               `json:"nameTaken"`
+	SuggestedName string             `json:"suggestedName"`
+	Files         []BundleFileImport `json:"files"`
+	Errors        []string           `json:"errors"`
+	Warnings      []string           `json:"warnings"`
+}
+
+type bundle struct {
+	manifest BundleManifest
+	files    map[string][]byte
+	cfg      *CharacterCfg
+}
+
+// ExportBundle returns the bundle of the profile as a zip file
+func ExportBundle(supervisorName string) ([]byte, error) {
+	cfg, found := GetCharacter(supervisorName)
+	if !found {
+		return nil, fmt.Errorf("profile %s not found", supervisorName)
+	}
+
+	files := make(map[string][]byte)
+
+	// The effective config is exported, the base profile may not exist where the bundle is imported
+	stored := *cfg
+	stored.SchemaVersion = migration.LatestVersion(migration.KindCharacter)
+	stored.Extends = ""
+	stored.Username = ""
+	stored.CharacterName = ""
+	stored.Companion.GamePassword = ""
+	stored.Companion.CompanionGamePassword = ""
+	for _, field := range characterSecretFields(&stored) {
+		*field = ""
+	}
+	// The pickit files are part of the bundle, the centralized pickit folder is not
+	stored.UseCentralizedPickit = false
+	content, err := yaml.Marshal(&stored)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding config: %w", err)
+	}
+	files[bundleConfigFile] = content
+
+	pickitDirs := map[string]string{}
+	for _, dir := range bundlePickitDirs {
+		pickitDirs[dir] = getAbsPath(filepath.Join("config", supervisorName, dir))
+	}
+	if Koolo.CentralizedPickitPath != "" && cfg.UseCentralizedPickit {
+		pickitDirs["pickit"] = Koolo.CentralizedPickitPath
+	}
+	for dir, source := range pickitDirs {
+		entries, err := os.ReadDir(source)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("error reading pickit folder %s: %w", source, err)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), ".nip") {
+				continue
+			}
+			if err = addBundleFile(files, dir+"/"+entry.Name(), filepath.Join(source, entry.Name())); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	optional := map[string]string{
+		bundleScoringFile: getAbsPath(filepath.Join("config", supervisorName, bundleScoringFile)),
+		bundleBuildsDir + "/" + cfg.Character.Class + ".json": getAbsPath(filepath.Join("config", "template", bundleBuildsDir, cfg.Character.Class+".json")),
+	}
+	if sequence := cfg.Game.LevelingSequence.SequenceFile; sequence != "" {
+		optional[bundleSequencesDir+"/"+sequence+".json"] = getAbsPath(filepath.Join("config", "template", bundleSequencesDir, sequence+".json"))
+	}
+	for name, source := range optional {
+		if err = addBundleFile(files, name, source); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+
+	manifest := BundleManifest{
+		Format:       bundleFormat,
+		KooloVersion: Version,
+		Profile:      supervisorName,
+		Class:        cfg.Character.Class,
+		CreatedAt:    time.Now().UTC(),
+		Files:        make([]BundleFile, 0, len(files)),
+	}
+	for name, content := range files {
+		manifest.Files = append(manifest.Files, BundleFile{Path: name, SHA256: checksum(content), Size: int64(len(content))})
+	}
+	slices.SortFunc(manifest.Files, func(a, b BundleFile) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	return writeBundle(manifest, files)
+}
+
+func addBundleFile(files map[string][]byte, name, source string) error {
+	content, err := os.ReadFile(source)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return err
+		}
+		return fmt.Errorf("error reading %s: %w", source, err)
+	}
+	files[name] = content
+
+	return nil
+}
+
+func writeBundle(manifest BundleManifest, files map[string][]byte) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+
+	manifestContent, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	entries := append([]BundleFile{{Path: bundleManifestFile}}, manifest.Files...)
+	for _, entry := range entries {
+		content := manifestContent
+		if entry.Path != bundleManifestFile {
+			content = files[entry.Path]
+		}
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: entry.Path, Method: zip.Deflate, Modified: manifest.CreatedAt})
+		if err != nil {
+			return nil, err
+		}
+		if _, err = w.Write(content); err != nil {
+			return nil, err
+		}
+	}
+	if err = zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// PreviewBundle checks the bundle and returns what importing it with the given options would do
+func PreviewBundle(content []byte, opts BundleImportOptions) BundlePreview {
+	preview := BundlePreview{Files: make([]BundleFileImport, 0), Errors: make([]string, 0), Warnings: make([]string, 0)}
+
+	b, errs := readBundle(content)
+	for _, err := range errs {
+		preview.Errors = append(preview.Errors, err.Error())
+	}
+	if b == nil {
+		return preview
+	}
+	preview.Manifest = b.manifest
+
+	if b.manifest.KooloVersion != Version {
+		preview.Warnings = append(preview.Warnings, fmt.Sprintf("bundle exported with koolo %s, this is koolo %s", b.manifest.KooloVersion, Version))
+	}
+	if b.cfg != nil {
+		for _, err := range validateProfile(b.cfg) {
+			preview.Warnings = append(preview.Warnings, fmt.Sprintf("%s, the profile will be disabled until it's fixed", err))
+		}
+	}
+
+	preview.Name = opts.Name
+	if preview.Name == "" {
+		preview.Name = b.manifest.Profile
+	}
+	preview.SuggestedName = freeProfileName(preview.Name)
+	if err := validateProfileName(preview.Name); err != nil {
+		preview.Errors = append(preview.Errors, err.Error())
+	} else if preview.SuggestedName != preview.Name {
+		preview.NameTaken = true
+		preview.Errors = append(preview.Errors, fmt.Sprintf("profile %s already exists, choose another name", preview.Name))
+	}
+
+	if len(errs) == 0 {
+		preview.Files, _ = planBundleImport(b, preview.Name, opts.OverwriteBuild)
+	}
+
+	return preview
+}
+
+// ImportBundle creates a new profile from the bundle, returns the profile name and where every file was written
+func ImportBundle(content []byte, opts BundleImportOptions) (string, []BundleFileImport, error) {
+	b, errs := readBundle(content)
+	if len(errs) > 0 {
+		return "", nil, fmt.Errorf("invalid bundle: %w", errors.Join(errs...))
+	}
+
+	name := opts.Name
+	if name == "" {
+		name = b.manifest.Profile
+	}
+	if err := validateProfileName(name); err != nil {
+		return "", nil, err
+	}
+	profileDir := getAbsPath(filepath.Join("config", name))
+	if _, err := os.Stat(profileDir); !os.IsNotExist(err) {
+		return "", nil, fmt.Errorf("profile %s already exists", name)
+	}
+
+	imports, sequenceFile := planBundleImport(b, name, opts.OverwriteBuild)
+	b.cfg.Game.LevelingSequence.SequenceFile = sequenceFile
+	configContent, err := yaml.Marshal(b.cfg)
+	if err != nil {
+		return "", nil, fmt.Errorf("error encoding config: %w", err)
+	}
+
+	if err = writeBundleFiles(b, name, imports, configContent, opts.Source); err != nil {
+		// Shared files written before the error are kept, they are complete and may be used by other profiles
+		_ = os.RemoveAll(profileDir)
+		return "", nil, err
+	}
+
+	return name, imports, Load()
+}
+
+func writeBundleFiles(b *bundle, name string, imports []BundleFileImport, configContent []byte, source history.Source) error {
+	// The pickit folder is required to load the profile, even when the bundle has no pickit files
+	if err := os.MkdirAll(getAbsPath(filepath.Join("config", name, "pickit")), 0755); err != nil {
+		return fmt.Errorf("error creating profile folder: %w", err)
+	}
+
+	for _, imp := range imports {
+		if imp.Action == BundleActionReuse || imp.Action == BundleActionKeep {
+			continue
+		}
+
+		content := b.files[imp.Path]
+		if imp.Path == bundleConfigFile {
+			content = configContent
+		}
+		target := getAbsPath(filepath.FromSlash(imp.Target))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return fmt.Errorf("error creating folder for %s: %w", imp.Target, err)
+		}
+		if err := writeConfigFile(target, content, source, "imported from bundle "+b.manifest.Profile); err != nil {
+			return fmt.Errorf("error writing %s: %w", imp.Target, err)
+		}
+	}
+
+	return nil
+}
+
+// readBundle reads the zip file and checks every file against the manifest, the bundle is nil when it can't be read
+func readBundle(content []byte) (*bundle, []error) {
+	zr, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, []error{fmt.Errorf("not a bundle zip file: %w", err)}
+	}
+
+	files := make(map[string][]byte)
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		if f.UncompressedSize64 > maxBundleFileSize {
+			return nil, []error{fmt.Errorf("%s: file is bigger than %d bytes", f.Name, maxBundleFileSize)}
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, []error{fmt.Errorf("%s: %w", f.Name, err)}
+		}
+		data, err := io.ReadAll(io.LimitReader(rc, maxBundleFileSize+1))
+		rc.Close()
+		if err != nil {
+			return nil, []error{fmt.Errorf("%s: %w", f.Name, err)}
+		}
+		files[f.Name] = data
+	}
+
+	manifestContent, found := files[bundleManifestFile]
+	if !found {
+		return nil, []error{errors.New("manifest.json not found, the file is not a profile bundle")}
+	}
+	b := &bundle{files: files}
+	if err = json.Unmarshal(manifestContent, &b.manifest); err != nil {
+		return nil, []error{fmt.Errorf("error reading manifest.json: %w", err)}
+	}
+	if b.manifest.Format != bundleFormat {
+		return b, []error{fmt.Errorf("unsupported bundle format %d", b.manifest.Format)}
+	}
+
+	// The leveling build must be the one of the config class, the manifest class is checked against it
+	var errs []error
+	var cfgErr error
+	class := ""
+	if content, found := files[bundleConfigFile]; found {
+		if b.cfg, cfgErr = decodeBundleConfig(content); cfgErr == nil {
+			class = b.cfg.Character.Class
+			if class != b.manifest.Class {
+				errs = append(errs, fmt.Errorf("config.yaml: class %s doesn't match the manifest class %s", class, b.manifest.Class))
+			}
+		}
+	}
+
+	listed := make(map[string]bool, len(b.manifest.Files))
+	for _, file := range b.manifest.Files {
+		listed[file.Path] = true
+		data, found := files[file.Path]
+		switch {
+		case !found:
+			errs = append(errs, fmt.Errorf("%s: listed in the manifest but missing", file.Path))
+		case checksum(data) != file.SHA256:
+			errs = append(errs, fmt.Errorf("%s: checksum mismatch, the file was modified or is corrupted", file.Path))
+		case !isBundlePath(file.Path, class):
+			errs = append(errs, fmt.Errorf("%s: unexpected file in a profile bundle", file.Path))
+		}
+	}
+	for name := range files {
+		if name != bundleManifestFile && !listed[name] {
+			errs = append(errs, fmt.Errorf("%s: not listed in the manifest", name))
+		}
+	}
+	if !listed[bundleConfigFile] {
+		errs = append(errs, errors.New("config.yaml: missing"))
+	}
+	if len(errs) > 0 {
+		slices.SortFunc(errs, func(a, b error) int {
+			return strings.Compare(a.Error(), b.Error())
+		})
+		b.cfg = nil
+		return b, errs
+	}
+	if cfgErr != nil {
+		return b, []error{cfgErr}
+	}
+
+	return b, nil
+}
+
+// decodeBundleConfig reads the bundle config.yaml, configs exported by older koolo versions are migrated
+func decodeBundleConfig(content []byte) (*CharacterCfg, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("config.yaml: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, errors.New("config.yaml: empty config")
+	}
+	if _, err := migration.Migrate(migration.KindCharacter, doc.Content[0], nil); err != nil {
+		return nil, fmt.Errorf("config.yaml: %w", err)
+	}
+
+	cfg := &CharacterCfg{}
+	if err := decodeProfile(doc.Content[0], cfg); err != nil {
+		return nil, fmt.Errorf("config.yaml: %w", err)
+	}
+	if cfg.Extends != "" {
+		return nil, errors.New("config.yaml: bundles can't extend a base profile")
+	}
+	cfg.SchemaVersion = migration.LatestVersion(migration.KindCharacter)
+
+	return cfg, nil
+}
+
+// isBundlePath returns true for the files a bundle can contain, all of them are directly in their folder so they
+// can't be written outside the profile and template folders
+func isBundlePath(name, class string) bool {
+	if name == bundleConfigFile || name == bundleScoringFile {
+		return true
+	}
+
+	dir, file := path.Split(name)
+	if file == "" || file != path.Clean(file) || strings.HasPrefix(file, ".") || strings.ContainsAny(file, `\:`) {
+		return false
+	}
+	switch strings.TrimSuffix(dir, "/") {
+	case "pickit", "pickit_leveling":
+		return strings.EqualFold(path.Ext(file), ".nip")
+	case bundleSequencesDir:
+		return path.Ext(file) == ".json"
+	case bundleBuildsDir:
+		return class != "" && file == class+".json"
+	}
+
+	return false
+}
+
+// planBundleImport returns where every bundle file goes and the sequence file name to use in the imported profile.
+// Sequence files with the same name but a different content are imported with a new name, builds are kept unless
+// overwriteBuild is set.
+func planBundleImport(b *bundle, name string, overwriteBuild bool) ([]BundleFileImport, string) {
+	sequenceFile := b.cfg.Game.LevelingSequence.SequenceFile
+	imports := make([]BundleFileImport, 0, len(b.manifest.Files))
+
+	for _, file := range b.manifest.Files {
+		imp := BundleFileImport{Path: file.Path, Action: BundleActionCreate}
+		dir, base := path.Split(file.Path)
+
+		switch strings.TrimSuffix(dir, "/") {
+		case bundleSequencesDir:
+			imp.Target = path.Join("config", "template", bundleSequencesDir, base)
+			existing, err := os.ReadFile(getAbsPath(filepath.FromSlash(imp.Target)))
+			if err == nil && checksum(existing) == file.SHA256 {
+				imp.Action = BundleActionReuse
+			} else if err == nil {
+				imp.Action = BundleActionRename
+				renamed := freeSequenceName(strings.TrimSuffix(base, ".json") + "_" + name)
+				imp.Target = path.Join("config", "template", bundleSequencesDir, renamed+".json")
+				if strings.TrimSuffix(base, ".json") == sequenceFile {
+					sequenceFile = renamed
+				}
+			}
+		case bundleBuildsDir:
+			imp.Target = path.Join("config", "template", bundleBuildsDir, base)
+			existing, err := os.ReadFile(getAbsPath(filepath.FromSlash(imp.Target)))
+			if err == nil && checksum(existing) == file.SHA256 {
+				imp.Action = BundleActionReuse
+			} else if err == nil && overwriteBuild {
+				imp.Action = BundleActionOverwrite
+			} else if err == nil {
+				imp.Action = BundleActionKeep
+			}
+		default:
+			imp.Target = path.Join("config", name, file.Path)
+		}
+
+		imports = append(imports, imp)
+	}
+
+	return imports, sequenceFile
+}
+
+func validateProfileName(name string) error {
+	if name == "" {
+		return errors.New("profile name cannot be empty")
+	}
+	if strings.EqualFold(name, "template") {
+		return errors.New("template is a reserved profile name")
+	}
+	if name != filepath.Base(name) || strings.ContainsAny(name, `/\:*?"<>|`) || strings.HasPrefix(name, ".") {
+		return fmt.Errorf("invalid profile name %q", name)
+	}
+
+	return nil
+}
+
+// freeProfileName returns the name, or the name with the first free numeric suffix when the profile already exists
+func freeProfileName(name string) string {
+	return freeName(name, func(candidate string) string {
+		return getAbsPath(filepath.Join("config", candidate))
+	})
+}
+
+func freeSequenceName(name string) string {
+	return freeName(name, func(candidate string) string {
+		return getAbsPath(filepath.Join("config", "template", bundleSequencesDir, candidate+".json"))
+	})
+}
+
+func freeName(name string, pathOf func(string) string) string {
+	candidate := name
+	for i := 2; i <= maxBundleNameSuffix; i++ {
+		if _, err := os.Stat(pathOf(candidate)); os.IsNotExist(err) {
+			return candidate
+		}
+		candidate = name + "-" + strconv.Itoa(i)
+	}
+
+	return candidate
+}
+
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
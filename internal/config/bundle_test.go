@@ -0,0 +1,225 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hectorgimenez/koolo/internal/config/history"
+)
+
+const testBundleConfig = "character:\n  class: sorceress\ngame:\n  runs: [pit]\n"
+
+func TestExportImportBundle(t *testing.T) {
+	setupBundleTest(t)
+	writeTestFile(t, "config/koza/pickit/gold.nip", "[name] == gold\n")
+	writeTestFile(t, "config/template/builds_leveling/sorceress.json", "{}\n")
+	if err := Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := ExportBundle("koza")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, errs := readBundle(content)
+	if len(errs) > 0 {
+		t.Fatalf("exported bundle is invalid: %v", errs)
+	}
+	if b.manifest.Profile != "koza" || b.manifest.Class != "sorceress" {
+		t.Errorf("unexpected manifest: %+v", b.manifest)
+	}
+	paths := make([]string, 0, len(b.manifest.Files))
+	for _, f := range b.manifest.Files {
+		paths = append(paths, f.Path)
+	}
+	want := []string{"builds_leveling/sorceress.json", "config.yaml", "pickit/gold.nip"}
+	if !slices.Equal(paths, want) {
+		t.Errorf("got files %v, want %v", paths, want)
+	}
+
+	name, imports, err := ImportBundle(content, BundleImportOptions{Name: "koza2", Source: history.SourceUI})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "koza2" {
+		t.Errorf("got profile %s, want koza2", name)
+	}
+	for _, imp := range imports {
+		if imp.Path == "builds_leveling/sorceress.json" && imp.Action != BundleActionReuse {
+			t.Errorf("the existing build must be reused, got %s", imp.Action)
+		}
+	}
+
+	imported, found := GetCharacter("koza2")
+	if !found {
+		t.Fatal("imported profile not loaded")
+	}
+	if imported.Character.Class != "sorceress" || !slices.Equal(imported.Game.Runs, []Run{"pit"}) {
+		t.Errorf("unexpected imported config: %s %v", imported.Character.Class, imported.Game.Runs)
+	}
+	if got, err := os.ReadFile(filepath.Join("config", "koza2", "pickit", "gold.nip")); err != nil || string(got) != "[name] == gold\n" {
+		t.Errorf("pickit file not imported: %q %v", got, err)
+	}
+
+	// The profile already exists now
+	if _, _, err = ImportBundle(content, BundleImportOptions{Name: "koza2"}); err == nil {
+		t.Error("expected an error importing over an existing profile")
+	}
+}
+
+func TestReadBundle(t *testing.T) {
+	config := []byte(testBundleConfig)
+	build := []byte("{}\n")
+
+	tests := []struct {
+		name    string
+		class   string
+		files   map[string][]byte
+		tamper  func(manifest *BundleManifest)
+		wantErr string
+	}{
+		{
+			name:  "valid bundle",
+			class: "sorceress",
+			files: map[string][]byte{"config.yaml": config, "builds_leveling/sorceress.json": build},
+		},
+		{
+			name:    "checksum mismatch",
+			class:   "sorceress",
+			files:   map[string][]byte{"config.yaml": config, "pickit/gold.nip": []byte("[name] == gold\n")},
+			tamper:  func(m *BundleManifest) { m.Files[1].SHA256 = checksum([]byte("other")) },
+			wantErr: "pickit/gold.nip: checksum mismatch",
+		},
+		{
+			name:    "file missing",
+			class:   "sorceress",
+			files:   map[string][]byte{"config.yaml": config},
+			tamper:  func(m *BundleManifest) { m.Files = append(m.Files, BundleFile{Path: "pickit/gold.nip"}) },
+			wantErr: "pickit/gold.nip: listed in the manifest but missing",
+		},
+		{
+			name:    "class mismatch",
+			class:   "paladin",
+			files:   map[string][]byte{"config.yaml": config},
+			wantErr: "class sorceress doesn't match the manifest class paladin",
+		},
+		{
+			name:    "build of another class",
+			class:   "paladin",
+			files:   map[string][]byte{"config.yaml": config, "builds_leveling/paladin.json": build},
+			wantErr: "builds_leveling/paladin.json: unexpected file",
+		},
+		{
+			name:    "config missing",
+			class:   "sorceress",
+			files:   map[string][]byte{"pickit/gold.nip": []byte("[name] == gold\n")},
+			wantErr: "config.yaml: missing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, errs := readBundle(testBundle(t, tt.class, tt.files, tt.tamper))
+			if tt.wantErr == "" {
+				if len(errs) > 0 || b.cfg == nil {
+					t.Fatalf("unexpected errors: %v", errs)
+				}
+				return
+			}
+			if !containsError(errs, tt.wantErr) {
+				t.Errorf("got errors %v, want %q", errs, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReadBundleRejectsUnsafePaths(t *testing.T) {
+	paths := []string{
+		"../evil.nip",
+		"pickit/../../evil.nip",
+		"/etc/evil.nip",
+		`pickit\..\evil.nip`,
+		"pickit/sub/evil.nip",
+		"pickit/.hidden.nip",
+		"pickit/evil.exe",
+		"sequences_leveling/../../koolo.yaml",
+		"history/config.yaml",
+	}
+
+	for _, p := range paths {
+		t.Run(p, func(t *testing.T) {
+			files := map[string][]byte{"config.yaml": []byte(testBundleConfig), p: []byte("content")}
+			_, errs := readBundle(testBundle(t, "sorceress", files, nil))
+			if !containsError(errs, p+": unexpected file") {
+				t.Errorf("got errors %v", errs)
+			}
+		})
+	}
+}
+
+// testBundle returns a bundle with the files, tamper can modify the manifest before it's written
+func testBundle(t *testing.T, class string, files map[string][]byte, tamper func(manifest *BundleManifest)) []byte {
+	t.Helper()
+
+	manifest := BundleManifest{Format: bundleFormat, KooloVersion: Version, Profile: "koza", Class: class, CreatedAt: time.Now().UTC()}
+	for name, content := range files {
+		manifest.Files = append(manifest.Files, BundleFile{Path: name, SHA256: checksum(content), Size: int64(len(content))})
+	}
+	slices.SortFunc(manifest.Files, func(a, b BundleFile) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	if tamper != nil {
+		tamper(&manifest)
+	}
+
+	content, err := writeBundle(manifest, files)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return content
+}
+
+func containsError(errs []error, want string) bool {
+	return slices.ContainsFunc(errs, func(err error) bool {
+		return strings.Contains(err.Error(), want)
+	})
+}
+
+// setupBundleTest creates a koolo folder with the koza profile and makes it the working directory
+func setupBundleTest(t *testing.T) {
+	t.Helper()
+
+	writeTestProfiles(t, map[string]string{"koza": testBundleConfig})
+	writeTestFile(t, "config/koolo.yaml", "debug:\n  log: false\n")
+	if err := os.MkdirAll(filepath.Join("config", "koza", "pickit"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	previousKoolo, previousCharacters := Koolo, Characters
+	configHistoryMu.Lock()
+	configHistory = nil
+	configHistoryMu.Unlock()
+	t.Cleanup(func() {
+		Koolo, Characters = previousKoolo, previousCharacters
+		configHistoryMu.Lock()
+		configHistory = nil
+		configHistoryMu.Unlock()
+	})
+}
+
+func writeTestFile(t *testing.T, name, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(name, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,219 @@
+package config
+
+import (
+	"crypto/sha256"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/hectorgimenez/d2go/pkg/nip"
+	"gopkg.in/yaml.v3"
+)
+
+// PickitRulesChange is the path reported when the pickit rules of the profile changed
+const PickitRulesChange = "pickit rules"
+
+// safeChangePaths are the settings that can be applied to a running supervisor between games, any other change
+// requires restarting the supervisor
+var safeChangePaths = []string{"health", "game.runs", PickitRulesChange}
+
+var (
+	runningMu sync.Mutex
+	// runningCfgs are the settings each running supervisor had the last time it was in sync with its config file
+	runningCfgs = make(map[string]*yaml.Node)
+	// selfWrites are the checksums of the config files written and loaded by koolo itself, by absolute path
+	selfWrites = make(map[string][sha256.Size]byte)
+)
+
+// ConfigChange is a setting that differs between two versions of a profile
+type ConfigChange struct {
+	Path string `json:"path"`
+	// Safe changes are applied to running supervisors at the next game, the rest need a restart
+	Safe bool `json:"safe"`
+}
+
+// DiffCharacterCfg returns the settings changed from old to new, one change per setting, lists are a single setting
+func DiffCharacterCfg(old, new *CharacterCfg) []ConfigChange {
+	changes := make([]ConfigChange, 0)
+
+	oldNode, err := encodeProfile(old)
+	if err != nil {
+		return changes
+	}
+	newNode, err := encodeProfile(new)
+	if err != nil {
+		return changes
+	}
+
+	paths := changedPaths(oldNode, newNode, "", nil)
+	if !rulesEqual(old.Runtime.Rules, new.Runtime.Rules) {
+		paths = append(paths, PickitRulesChange)
+	}
+
+	for _, path := range paths {
+		changes = append(changes, ConfigChange{Path: path, Safe: isSafeChange(path)})
+	}
+
+	return changes
+}
+
+// ApplySafeChanges copies the settings that can change between games from src to dst
+func ApplySafeChanges(dst, src *CharacterCfg) {
+	dst.Health = src.Health
+	dst.Game.Runs = slices.Clone(src.Game.Runs)
+	dst.Runtime.Rules = src.Runtime.Rules
+	dst.Runtime.TierRules = src.Runtime.TierRules
+}
+
+func isSafeChange(path string) bool {
+	for _, safe := range safeChangePaths {
+		if path == safe || strings.HasPrefix(path, safe+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// changedPaths walks both profiles and returns the dotted path of every different value
+func changedPaths(old, new *yaml.Node, path string, paths []string) []string {
+	if old == nil || new == nil || old.Kind != yaml.MappingNode || new.Kind != yaml.MappingNode {
+		if old == nil || new == nil || !nodesEqual(old, new) {
+			paths = append(paths, path)
+		}
+		return paths
+	}
+
+	for i := 0; i+1 < len(new.Content); i += 2 {
+		key := new.Content[i].Value
+		paths = changedPaths(mappingValue(old, key), new.Content[i+1], strings.TrimPrefix(path+"."+key, "."), paths)
+	}
+	// Settings removed in the new version, encoded profiles only omit them when they are empty
+	for i := 0; i+1 < len(old.Content); i += 2 {
+		key := old.Content[i].Value
+		if mappingIndex(new, key) < 0 {
+			paths = append(paths, strings.TrimPrefix(path+"."+key, "."))
+		}
+	}
+
+	return paths
+}
+
+func rulesEqual(a, b nip.Rules) bool {
+	return slices.EqualFunc(a, b, func(x, y nip.Rule) bool {
+		return x.RawLine == y.RawLine && x.Filename == y.Filename
+	})
+}
+
+// TrackRunningConfig records the settings the running supervisor is using, the changes the bot makes to them from now
+// on are merged with the config file when saved, instead of replacing it
+func TrackRunningConfig(supervisorName string, cfg *CharacterCfg) {
+	node, err := encodeProfile(cfg)
+	if err != nil {
+		return
+	}
+
+	runningMu.Lock()
+	defer runningMu.Unlock()
+	runningCfgs[supervisorName] = node
+}
+
+// UntrackRunningConfig is called when the supervisor stops, its saves replace the config file again
+func UntrackRunningConfig(supervisorName string) {
+	runningMu.Lock()
+	defer runningMu.Unlock()
+	delete(runningCfgs, supervisorName)
+}
+
+// mergeRunningConfig returns the current profile config with the settings the bot changed in its running copy. The
+// running supervisor only gets the user changes between games, saving its copy as is would revert the ones pending.
+func mergeRunningConfig(supervisorName string, running *CharacterCfg) (*CharacterCfg, error) {
+	runningMu.Lock()
+	base := runningCfgs[supervisorName]
+	runningMu.Unlock()
+
+	current, found := GetCharacter(supervisorName)
+	if base == nil || !found || current == running {
+		return running, nil
+	}
+
+	ours, err := encodeProfile(running)
+	if err != nil {
+		return nil, err
+	}
+	theirs, err := encodeProfile(current)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := &CharacterCfg{}
+	if err = mergeChanges(base, ours, theirs).Decode(merged); err != nil {
+		return nil, err
+	}
+	// Settings not stored in the file belong to the running supervisor
+	merged.Runtime = running.Runtime
+	merged.ConfigFolderName = running.ConfigFolderName
+	merged.Game.PublicGameCounter = running.Game.PublicGameCounter
+
+	return merged, nil
+}
+
+// mergeChanges applies the differences from base to ours over theirs, mappings are merged key by key and any other
+// value changed in ours replaces the one in theirs
+func mergeChanges(base, ours, theirs *yaml.Node) *yaml.Node {
+	if base != nil && nodesEqual(base, ours) {
+		return theirs
+	}
+	if base == nil || theirs == nil || base.Kind != yaml.MappingNode || ours.Kind != yaml.MappingNode || theirs.Kind != yaml.MappingNode {
+		return ours
+	}
+
+	merged := &yaml.Node{Kind: yaml.MappingNode, Tag: theirs.Tag}
+	for i := 0; i+1 < len(theirs.Content); i += 2 {
+		key := theirs.Content[i].Value
+		value := theirs.Content[i+1]
+		if oursValue := mappingValue(ours, key); oursValue != nil {
+			value = mergeChanges(mappingValue(base, key), oursValue, value)
+		}
+		merged.Content = append(merged.Content, theirs.Content[i], value)
+	}
+	for i := 0; i+1 < len(ours.Content); i += 2 {
+		key := ours.Content[i].Value
+		if mappingIndex(theirs, key) < 0 && mappingIndex(base, key) < 0 {
+			merged.Content = append(merged.Content, ours.Content[i], ours.Content[i+1])
+		}
+	}
+
+	return merged
+}
+
+// markSelfWrite records the content koolo wrote and loaded, the config watcher doesn't need to load it again
+func markSelfWrite(path string, content []byte) {
+	runningMu.Lock()
+	defer runningMu.Unlock()
+	selfWrites[absConfigPath(path)] = sha256.Sum256(content)
+}
+
+// IsSelfWrite returns true when the file still has the content koolo wrote and loaded itself
+func IsSelfWrite(path string) bool {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+
+	runningMu.Lock()
+	defer runningMu.Unlock()
+	sum, found := selfWrites[absConfigPath(path)]
+
+	return found && sum == sha256.Sum256(content)
+}
+
+func absConfigPath(path string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+
+	return getAbsPath(path)
+}
@@ -0,0 +1,149 @@
+package config
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/hectorgimenez/d2go/pkg/nip"
+	"gopkg.in/yaml.v3"
+)
+
+func TestDiffCharacterCfg(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(cfg *CharacterCfg)
+		want   []ConfigChange
+	}{
+		{
+			name:   "no changes",
+			change: func(cfg *CharacterCfg) {},
+			want:   []ConfigChange{},
+		},
+		{
+			name:   "health is safe",
+			change: func(cfg *CharacterCfg) { cfg.Health.HealingPotionAt = 60 },
+			want:   []ConfigChange{{Path: "health.healingPotionAt", Safe: true}},
+		},
+		{
+			name:   "runs are a single safe setting",
+			change: func(cfg *CharacterCfg) { cfg.Game.Runs = []Run{"mephisto", "andariel"} },
+			want:   []ConfigChange{{Path: "game.runs", Safe: true}},
+		},
+		{
+			name: "pickit rules are safe",
+			change: func(cfg *CharacterCfg) {
+				cfg.Runtime.Rules = nip.Rules{{RawLine: "[name] == ring", Filename: "rings.nip"}}
+			},
+			want: []ConfigChange{{Path: PickitRulesChange, Safe: true}},
+		},
+		{
+			name:   "other game settings need a restart",
+			change: func(cfg *CharacterCfg) { cfg.Game.MinGoldPickupThreshold = 5000 },
+			want:   []ConfigChange{{Path: "game.minGoldPickupThreshold", Safe: false}},
+		},
+		{
+			name:   "character name needs a restart",
+			change: func(cfg *CharacterCfg) { cfg.CharacterName = "other" },
+			want:   []ConfigChange{{Path: "characterName", Safe: false}},
+		},
+		{
+			name: "safe and restart changes together",
+			change: func(cfg *CharacterCfg) {
+				cfg.Health.ManaPotionAt = 20
+				cfg.MaxGameLength = 900
+			},
+			want: []ConfigChange{
+				{Path: "maxGameLength", Safe: false},
+				{Path: "health.manaPotionAt", Safe: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old := testCharacterCfg()
+			updated := testCharacterCfg()
+			tt.change(updated)
+
+			got := DiffCharacterCfg(old, updated)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplySafeChanges(t *testing.T) {
+	dst := testCharacterCfg()
+	src := testCharacterCfg()
+	src.Health.HealingPotionAt = 70
+	src.Game.Runs = []Run{"pit"}
+	src.CharacterName = "other"
+
+	ApplySafeChanges(dst, src)
+
+	if dst.Health.HealingPotionAt != 70 || !slices.Equal(dst.Game.Runs, []Run{"pit"}) {
+		t.Errorf("safe changes not applied: %+v %v", dst.Health, dst.Game.Runs)
+	}
+	if dst.CharacterName != "koolo" {
+		t.Errorf("restart required change applied: %s", dst.CharacterName)
+	}
+
+	src.Game.Runs[0] = "cows"
+	if dst.Game.Runs[0] != "pit" {
+		t.Error("runs are shared with the source config")
+	}
+}
+
+func TestMergeChanges(t *testing.T) {
+	base := testNode(t, "a: 1\nb:\n  c: 2\n  d: 3\nlist: [x]\n")
+	// The bot changed b.c and the list, the user changed a and b.d
+	ours := testNode(t, "a: 1\nb:\n  c: 20\n  d: 3\nlist: [x, y]\n")
+	theirs := testNode(t, "a: 10\nb:\n  c: 2\n  d: 30\nlist: [x]\nnew: true\n")
+
+	got := map[string]any{}
+	if err := mergeChanges(base, ours, theirs).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]any{
+		"a":    10,
+		"b":    map[string]any{"c": 20, "d": 30},
+		"list": []any{"x", "y"},
+		"new":  true,
+	}
+	gotYAML, _ := yaml.Marshal(got)
+	wantYAML, _ := yaml.Marshal(want)
+	if string(gotYAML) != string(wantYAML) {
+		t.Errorf("got:\n%s\nwant:\n%s", gotYAML, wantYAML)
+	}
+}
+
+func TestMergeChangesWithoutBotChanges(t *testing.T) {
+	base := testNode(t, "a: 1\n")
+	theirs := testNode(t, "a: 2\n")
+
+	if got := mergeChanges(base, testNode(t, "a: 1\n"), theirs); got != theirs {
+		t.Error("the config file must be kept when the bot didn't change anything")
+	}
+}
+
+func testCharacterCfg() *CharacterCfg {
+	cfg := &CharacterCfg{CharacterName: "koolo", MaxGameLength: 1200}
+	cfg.Health.HealingPotionAt = 40
+	cfg.Health.ManaPotionAt = 10
+	cfg.Game.Runs = []Run{"mephisto"}
+
+	return cfg
+}
+
+func testNode(t *testing.T, content string) *yaml.Node {
+	t.Helper()
+
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &node); err != nil {
+		t.Fatal(err)
+	}
+
+	return node.Content[0]
+}
@@ -22,10 +22,14 @@ import (
 	cp "github.com/otiai10/copy"
 
 	"github.com/hectorgimenez/d2go/pkg/nip"
+	"github.com/hectorgimenez/koolo/internal/config/history"
+	"github.com/hectorgimenez/koolo/internal/config/migration"
 
 	"gopkg.in/yaml.v3"
 )
 
+const migrationLogPath = "config/migrations.log"
+
 var (
 	cfgMux     sync.RWMutex
 	Koolo      *KooloCfg
@@ -34,6 +38,14 @@ var (
 )
 
 type KooloCfg struct {
+	// SchemaVersion is the version of the file format, older files are migrated on load
+	SchemaVersion int `yaml:"schemaVersion"`
+	// SecretRefs are the secret references of the settings resolved from the secrets store, by setting name
+	SecretRefs map[string]string `yaml:"-"`
+	// SecretsError is set when some secrets couldn't be resolved, usually because the store is locked. Their settings
+	// keep the references and the notifiers using them are disabled.
+	SecretsError string `yaml:"-"`
+
 	Debug struct {
 		Log         bool `yaml:"log"`
 		Screenshots bool `yaml:"screenshots"`
@@ -53,6 +65,7 @@ type KooloCfg struct {
 		EnableRunFinishMessages      bool     `yaml:"enableRunFinishMessages"`
 		EnableDiscordChickenMessages bool     `yaml:"enableDiscordChickenMessages"`
 		EnableDiscordErrorMessages   bool     `yaml:"enableDiscordErrorMessages"`
+		EnableProgressMessages       bool     `yaml:"enableProgressMessages"`
 		BotAdmins                    []string `yaml:"botAdmins"`
 		ChannelID                    string   `yaml:"channelId"`
 		Token                        string   `yaml:"token"`
@@ -62,7 +75,18 @@ type KooloCfg struct {
 		ChatID  int64  `yaml:"chatId"`
 		Token   string `yaml:"token"`
 	}
-	PingMonitor struct {
+	MapCache struct {
+		Enabled   bool   `yaml:"enabled"`
+		Directory string `yaml:"directory"`
+		MaxSizeMB int    `yaml:"maxSizeMB"`
+	} `yaml:"mapCache"`
+	ConfigHistory struct {
+		Directory   string `yaml:"directory"`
+		MaxVersions int    `yaml:"maxVersions"`
+	} `yaml:"configHistory"`
+	Retention     RetentionCfg     `yaml:"retention"`
+	Notifications NotificationsCfg `yaml:"notifications"`
+	PingMonitor   struct {
 		Enabled           bool `yaml:"enabled"`
 		HighPingThreshold int  `yaml:"highPingThreshold"` // Ping threshold in ms (default 500-1000)
 		SustainedDuration int  `yaml:"sustainedDuration"` // Seconds high ping must persist (default 10-30)
@@ -85,6 +109,12 @@ type TimeRange struct {
 }
 
 type CharacterCfg struct {
+	// SchemaVersion is the version of the file format, older files are migrated on load
+	SchemaVersion int `yaml:"schemaVersion"`
+	// Extends is the base profile, only the settings overriding it are stored in the profile config.yaml
+	Extends string `yaml:"extends,omitempty"`
+	// Abstract profiles are only bases for other profiles, they are never started
+	Abstract             bool   `yaml:"abstract,omitempty"`
 	MaxGameLength        int    `yaml:"maxGameLength"`
 	Username             string `yaml:"username"`
 	Password             string `yaml:"password"`
@@ -98,6 +128,9 @@ type CharacterCfg struct {
 	CloseMiniPanel       bool   `yaml:"closeMiniPanel"`
 	UseCentralizedPickit bool   `yaml:"useCentralizedPickit"`
 	HidePortraits        bool   `yaml:"hidePortraits"`
+	// GameSettings are the D2R Settings.json values of the profile, applied over config/Settings.json when koolo uses
+	// custom game settings
+	GameSettings map[string]any `yaml:"gameSettings,omitempty"`
 
 	ConfigFolderName string `yaml:"-"`
 
@@ -337,7 +370,6 @@ type CharacterCfg struct {
 	} `yaml:"gambling"`
 	Muling struct {
 		Enabled      bool     `yaml:"enabled"`
-		SwitchToMule string   `yaml:"switchToMule"`
 		ReturnTo     string   `yaml:"returnTo"`
 		MuleProfiles []string `yaml:"muleProfiles"`
 	} `yaml:"muling"`
@@ -362,6 +394,14 @@ type CharacterCfg struct {
 		Rules     nip.Rules   `yaml:"-"`
 		TierRules []int       `yaml:"-"`
 		Drops     []data.Item `yaml:"-"`
+		// LevelingBuild is the build file of the leveling class, nil if the class doesn't have one
+		LevelingBuild *LevelingBuildConfig `yaml:"-"`
+		// AutoEquipScoring is the autoequip scoring profile of the character, nil to use the default weights
+		AutoEquipScoring *AutoEquipScoring `yaml:"-"`
+		// ValidationErrors are the problems found loading the profile, the profile is disabled when not empty
+		ValidationErrors []string `yaml:"-"`
+		// SecretRefs are the secret references of the settings resolved from the secrets store, by setting name
+		SecretRefs map[string]string `yaml:"-"`
 	} `yaml:"-"`
 }
 
@@ -416,6 +456,10 @@ func Load() error {
 	}
 
 	kooloPath := getAbsPath("config/koolo.yaml")
+	if _, err = migration.MigrateFile(migration.KindKoolo, kooloPath, getAbsPath(migrationLogPath)); err != nil {
+		return fmt.Errorf("error migrating koolo.yaml: %w", err)
+	}
+
 	r, err := os.Open(kooloPath)
 	if err != nil {
 		return fmt.Errorf("error loading koolo.yaml: %w", err)
@@ -427,84 +471,129 @@ func Load() error {
 		return fmt.Errorf("error reading config %s: %w", kooloPath, err)
 	}
 
+	// A locked store doesn't stop koolo from starting, the secrets are resolved by the reload after unlocking it
+	Koolo.SecretRefs = make(map[string]string)
+	if err = resolveSecrets(kooloSecretFields(Koolo), Koolo.SecretRefs); err != nil {
+		Koolo.SecretsError = fmt.Sprintf("error reading secrets of %s: %s", kooloPath, err)
+	}
+
 	configDir := getAbsPath("config")
 	entries, err := os.ReadDir(configDir)
 	if err != nil {
 		return fmt.Errorf("error reading config directory %s: %w", configDir, err)
 	}
 
+	// Every profile is migrated before loading any of them, base profiles are read by the profiles extending them
+	migrationErrors := make(map[string]error)
 	for _, entry := range entries {
 		if !entry.IsDir() {
 			continue
 		}
-
-		charCfg := CharacterCfg{}
-
 		charConfigPath := getAbsPath(filepath.Join("config", entry.Name(), "config.yaml"))
-		r, err = os.Open(charConfigPath)
-		if err != nil {
-			return fmt.Errorf("error loading config.yaml: %w", err)
+		if _, err = migration.MigrateFile(migration.KindCharacter, charConfigPath, getAbsPath(migrationLogPath)); err != nil && !os.IsNotExist(err) {
+			migrationErrors[entry.Name()] = fmt.Errorf("error migrating %s: %w", charConfigPath, err)
 		}
+	}
 
-		d := yaml.NewDecoder(r)
-		if err = d.Decode(&charCfg); err != nil {
-			_ = r.Close()
-			return fmt.Errorf("error reading %s character config: %w", charConfigPath, err)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
 		}
-		_ = r.Close()
-
-		charCfg.ConfigFolderName = entry.Name()
 
-		if charCfg.Game.MaxFailedMenuAttempts == 0 {
-			charCfg.Game.MaxFailedMenuAttempts = 10
+		// Broken profiles are kept disabled with their errors, so the rest can still be used
+		charCfg, errs := loadCharacter(entry.Name())
+		if err, found := migrationErrors[entry.Name()]; found {
+			errs = append([]error{err}, errs...)
+		}
+		for _, err := range errs {
+			charCfg.Runtime.ValidationErrors = append(charCfg.Runtime.ValidationErrors, err.Error())
 		}
+		Characters[entry.Name()] = charCfg
+	}
 
-		var pickitPath string
-		if Koolo.CentralizedPickitPath != "" && charCfg.UseCentralizedPickit {
-			if _, err := os.Stat(Koolo.CentralizedPickitPath); os.IsNotExist(err) {
-				utils.ShowDialog("Error loading pickit rules for "+entry.Name(), "The centralized pickit path does not exist: "+Koolo.CentralizedPickitPath+"\nPlease check your Koolo settings.\nFalling back to local pickit.")
-				pickitPath = getAbsPath(filepath.Join("config", entry.Name(), "pickit")) + "\\"
-			} else {
-				pickitPath = Koolo.CentralizedPickitPath + "\\"
-			}
+	for _, charCfg := range Characters {
+		charCfg.Validate()
+	}
+
+	return nil
+}
+
+// loadCharacter reads a character profile and its pickit rules, it returns every problem found. The profile is
+// returned even with errors, with the settings that could be read.
+func loadCharacter(entryName string) (*CharacterCfg, []error) {
+	charCfg := &CharacterCfg{ConfigFolderName: entryName}
+
+	node, _, err := resolveProfileNode(entryName, nil)
+	if err != nil {
+		return charCfg, []error{err}
+	}
+
+	if err = decodeProfile(node, charCfg); err != nil {
+		return charCfg, []error{fmt.Errorf("error reading %s character config: %w", entryName, err)}
+	}
+
+	charCfg.ConfigFolderName = entryName
+
+	errs := validateProfile(charCfg)
+
+	charCfg.Runtime.SecretRefs = make(map[string]string)
+	if err = resolveSecrets(characterSecretFields(charCfg), charCfg.Runtime.SecretRefs); err != nil {
+		errs = append(errs, err)
+	}
+
+	var pickitPath string
+	if Koolo.CentralizedPickitPath != "" && charCfg.UseCentralizedPickit {
+		if _, err := os.Stat(Koolo.CentralizedPickitPath); os.IsNotExist(err) {
+			utils.ShowDialog("Error loading pickit rules for "+entryName, "The centralized pickit path does not exist: "+Koolo.CentralizedPickitPath+"\nPlease check your Koolo settings.\nFalling back to local pickit.")
+			pickitPath = getAbsPath(filepath.Join("config", entryName, "pickit")) + "\\"
 		} else {
-			pickitPath = getAbsPath(filepath.Join("config", entry.Name(), "pickit")) + "\\"
+			pickitPath = Koolo.CentralizedPickitPath + "\\"
 		}
+	} else {
+		pickitPath = getAbsPath(filepath.Join("config", entryName, "pickit")) + "\\"
+	}
 
-		rules, err := nip.ReadDir(pickitPath)
-		if err != nil {
-			return fmt.Errorf("error reading pickit directory %s: %w", pickitPath, err)
-		}
+	rules, err := nip.ReadDir(pickitPath)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("error reading pickit directory %s: %w", pickitPath, err))
+	}
 
-		// Load the leveling pickit rules
+	// Load the leveling pickit rules
+	if runs := charCfg.Game.Runs; len(runs) > 0 && (runs[0] == LevelingRun || runs[0] == LevelingSequenceRun) {
+		build, err := LoadLevelingBuild(charCfg.Character.Class)
+		if err != nil {
+			errs = append(errs, err)
+		} else {
+			charCfg.Runtime.LevelingBuild = build
 
-		if len(charCfg.Game.Runs) > 0 && charCfg.Game.Runs[0] == "leveling" || charCfg.Game.Runs[0] == "leveling_sequence" {
-			nips := getLevelingNipFiles(&charCfg, entry.Name())
+			nips := getLevelingNipFiles(charCfg, build, entryName)
 
 			for _, nipFile := range nips {
 				classRules, err := readSinglePickitFile(nipFile)
 				if err != nil {
-					return err
+					errs = append(errs, err)
+					continue
 				}
 				rules = append(rules, classRules...)
 			}
 		}
+	}
 
-		charCfg.Runtime.Rules = rules
+	charCfg.Runtime.Rules = rules
 
-		for ruleIndex, rule := range rules {
-			if rule.Tier() > 0 || rule.MercTier() > 0 {
-				charCfg.Runtime.TierRules = append(charCfg.Runtime.TierRules, ruleIndex)
-			}
-		}
-		Characters[entry.Name()] = &charCfg
+	scoring, err := LoadAutoEquipScoring(entryName, charCfg.Character.Class)
+	if err != nil {
+		errs = append(errs, err)
 	}
+	charCfg.Runtime.AutoEquipScoring = scoring
 
-	for _, charCfg := range Characters {
-		charCfg.Validate()
+	for ruleIndex, rule := range rules {
+		if rule.Tier() > 0 || rule.MercTier() > 0 {
+			charCfg.Runtime.TierRules = append(charCfg.Runtime.TierRules, ruleIndex)
+		}
 	}
 
-	return nil
+	return charCfg, errs
 }
 
 // Helper function to read a single NIP file using the temp directory workaround
@@ -549,7 +638,8 @@ func CreateFromTemplate(name string) error {
 	return Load()
 }
 
-func ValidateAndSaveConfig(config KooloCfg) error {
+// ValidateAndSaveConfig writes koolo.yaml, source is recorded in the config history
+func ValidateAndSaveConfig(config KooloCfg, source history.Source) error {
 	config.D2LoDPath = strings.ReplaceAll(strings.ToLower(config.D2LoDPath), "game.exe", "")
 	config.D2RPath = strings.ReplaceAll(strings.ToLower(config.D2RPath), "d2r.exe", "")
 
@@ -561,33 +651,90 @@ func ValidateAndSaveConfig(config KooloCfg) error {
 		return errors.New("D2RPath is not valid")
 	}
 
+	config.SchemaVersion = migration.LatestVersion(migration.KindKoolo)
+
+	// Secrets are written as references, the values are kept in the secrets store
+	if config.SecretRefs == nil {
+		config.SecretRefs = make(map[string]string)
+	}
+	if err := referenceSecrets("koolo", kooloSecretFields(&config), config.SecretRefs); err != nil {
+		return fmt.Errorf("error storing secrets: %w", err)
+	}
+
 	text, err := yaml.Marshal(config)
 	if err != nil {
 		return fmt.Errorf("error parsing koolo config: %w", err)
 	}
 
-	err = os.WriteFile("config/koolo.yaml", text, 0644)
+	err = writeConfigFile(getAbsPath("config/koolo.yaml"), text, source, "")
 	if err != nil {
 		return fmt.Errorf("error writing koolo config: %w", err)
 	}
 
-	return Load()
+	if err = Load(); err != nil {
+		return err
+	}
+	markSelfWrite(getAbsPath("config/koolo.yaml"), text)
+
+	return nil
 }
 
+// SaveSupervisorConfig writes the profile config, it's used by the bot to store its progress. The changes the bot made
+// to its running copy are merged with the config file, the user changes not applied to the running copy yet are kept.
 func SaveSupervisorConfig(supervisorName string, config *CharacterCfg) error {
-	filePath := filepath.Join("config", supervisorName, "config.yaml")
-	d, err := yaml.Marshal(config)
+	merged, err := mergeRunningConfig(supervisorName, config)
+	if err != nil {
+		return fmt.Errorf("error merging supervisor config: %w", err)
+	}
+	if err = SaveSupervisorConfigFrom(history.SourceBot, supervisorName, merged); err != nil {
+		return err
+	}
+	TrackRunningConfig(supervisorName, config)
+
+	return nil
+}
+
+// SaveSupervisorConfigFrom writes the profile config, source is recorded in the config history
+func SaveSupervisorConfigFrom(source history.Source, supervisorName string, config *CharacterCfg) error {
+	filePath := getAbsPath(filepath.Join("config", supervisorName, "config.yaml"))
+	config.SchemaVersion = migration.LatestVersion(migration.KindCharacter)
+
+	// Secrets are written as references, the values are kept in the secrets store
+	if config.Runtime.SecretRefs == nil {
+		config.Runtime.SecretRefs = make(map[string]string)
+	}
+	stored := *config
+	if err := referenceSecrets(supervisorName, characterSecretFields(&stored), config.Runtime.SecretRefs); err != nil {
+		return fmt.Errorf("error storing secrets: %w", err)
+	}
+
+	// Profiles extending a base profile only store the settings overriding it
+	var content any = &stored
+	if config.Extends != "" {
+		overrides, err := profileOverridesNode(supervisorName, &stored)
+		if err != nil {
+			return err
+		}
+		content = overrides
+	}
+
+	d, err := yaml.Marshal(content)
 	config.Validate()
 	if err != nil {
 		return err
 	}
 
-	err = os.WriteFile(filePath, d, 0644)
+	err = writeConfigFile(filePath, d, source, "")
 	if err != nil {
 		return fmt.Errorf("error writing supervisor config: %w", err)
 	}
 
-	return Load()
+	if err = Load(); err != nil {
+		return err
+	}
+	markSelfWrite(filePath, d)
+
+	return nil
 }
 
 func (c *CharacterCfg) Validate() {
@@ -616,22 +763,34 @@ func getAbsPath(relPath string) string {
 	return filepath.Join(cwd, relPath)
 }
 
-func getLevelingNipFiles(charCfg *CharacterCfg, entryName string) []string {
+// LoadLevelingBuild reads the build file for the character class, returns nil if the class doesn't have one
+func LoadLevelingBuild(class string) (*LevelingBuildConfig, error) {
+	levelingBuildPath := getAbsPath(filepath.Join("config", "template", "builds_leveling"))
+	classBuildFile := filepath.Join(levelingBuildPath, class+".json")
+
+	jsonData, err := utils.GetJsonData(classBuildFile)
+	if err != nil {
+		return nil, nil
+	}
+
+	var buildConfig LevelingBuildConfig
+	if err = json.Unmarshal(jsonData, &buildConfig); err != nil {
+		return nil, fmt.Errorf("error reading leveling build %s: %w", classBuildFile, err)
+	}
+
+	return &buildConfig, nil
+}
+
+func getLevelingNipFiles(charCfg *CharacterCfg, build *LevelingBuildConfig, entryName string) []string {
 	var nips []string
 	levelingPickitPath := getAbsPath(filepath.Join("config", entryName, "pickit_leveling"))
-	levelingBuildPath := getAbsPath(filepath.Join("config", "template", "builds_leveling"))
 	levelingPickitTemplatePath := getAbsPath(filepath.Join("config", "template", "pickit_leveling"))
-	classBuildFile := filepath.Join(levelingBuildPath, charCfg.Character.Class+".json")
 
-	if jsonData, err := utils.GetJsonData(classBuildFile); err == nil {
-		var buildConfig LevelingBuildConfig
-		err = json.Unmarshal(jsonData, &buildConfig)
-		if err == nil {
-			for _, nip := range buildConfig.Nips {
-				nipPath, err := getNipFilePath(levelingPickitPath, levelingPickitTemplatePath, nip)
-				if err == nil {
-					nips = append(nips, nipPath)
-				}
+	if build != nil {
+		for _, nip := range build.Nips {
+			nipPath, err := getNipFilePath(levelingPickitPath, levelingPickitTemplatePath, nip)
+			if err == nil {
+				nips = append(nips, nipPath)
 			}
 		}
 	}
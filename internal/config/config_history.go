@@ -0,0 +1,128 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/hectorgimenez/koolo/internal/config/history"
+)
+
+const (
+	defaultConfigHistoryDir         = "history"
+	defaultConfigHistoryMaxVersions = 50
+)
+
+var (
+	configHistoryMu sync.Mutex
+	configHistory   *history.Store
+)
+
+// ConfigHistory returns the history of the config files, it's opened the first time it's used
+func ConfigHistory() (*history.Store, error) {
+	configHistoryMu.Lock()
+	defer configHistoryMu.Unlock()
+
+	if configHistory != nil {
+		return configHistory, nil
+	}
+
+	dir := defaultConfigHistoryDir
+	maxVersions := defaultConfigHistoryMaxVersions
+	if Koolo != nil {
+		if Koolo.ConfigHistory.Directory != "" {
+			dir = Koolo.ConfigHistory.Directory
+		}
+		if Koolo.ConfigHistory.MaxVersions > 0 {
+			maxVersions = Koolo.ConfigHistory.MaxVersions
+		}
+	}
+	if !filepath.IsAbs(dir) {
+		dir = getAbsPath(dir)
+	}
+
+	store, err := history.Open(dir, maxVersions)
+	if err != nil {
+		return nil, err
+	}
+	configHistory = store
+
+	return store, nil
+}
+
+// WriteConfigFile writes a config, pickit or sequence file and records the new content in the config history. The
+// history is best effort: a file that couldn't be recorded is still saved, and the failure is only logged.
+func WriteConfigFile(path string, content []byte, source history.Source) error {
+	return writeConfigFile(path, content, source, "")
+}
+
+func writeConfigFile(path string, content []byte, source history.Source, note string) error {
+	previous, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return fmt.Errorf("error reading %s: %w", path, err)
+		}
+		previous = nil
+	}
+
+	if err = os.WriteFile(path, content, 0644); err != nil {
+		return err
+	}
+
+	store, err := ConfigHistory()
+	if err == nil {
+		_, _, err = store.Record(HistoryArtifact(path), source, previous, content, note)
+	}
+	if err != nil {
+		slog.Warn("Config saved, but it could not be added to the config history", slog.String("path", path), slog.Any("error", err))
+	}
+
+	return nil
+}
+
+// HistoryArtifact returns the name of the file in the config history, the path relative to the koolo folder. Files
+// outside of it, like the centralized pickit folder, keep their absolute path.
+func HistoryArtifact(path string) string {
+	abs := path
+	if !filepath.IsAbs(abs) {
+		abs = getAbsPath(path)
+	}
+
+	rel, err := filepath.Rel(getAbsPath("."), abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.ToSlash(abs)
+	}
+
+	return filepath.ToSlash(rel)
+}
+
+// RollbackConfigFile restores the content of a recorded version, the rollback is recorded as a new version
+func RollbackConfigFile(id int64, source history.Source) (history.Version, error) {
+	store, err := ConfigHistory()
+	if err != nil {
+		return history.Version{}, err
+	}
+
+	version, content, err := store.Content(id)
+	if err != nil {
+		return history.Version{}, err
+	}
+
+	path := filepath.FromSlash(version.Artifact)
+	if !filepath.IsAbs(path) {
+		path = getAbsPath(path)
+	}
+	if err = writeConfigFile(path, content, source, fmt.Sprintf("rollback to version %d", version.ID)); err != nil {
+		return version, err
+	}
+
+	// The rolled back config is used right away, running supervisors get it from the config watcher
+	if strings.EqualFold(filepath.Ext(path), ".yaml") {
+		return version, Load()
+	}
+
+	return version, nil
+}
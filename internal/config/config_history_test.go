@@ -0,0 +1,31 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/hectorgimenez/koolo/internal/config/history"
+)
+
+func TestSaveWithoutConfigHistory(t *testing.T) {
+	setupBundleTest(t)
+	// The history folder can't be created over a file
+	writeTestFile(t, "blocked", "")
+	writeTestFile(t, "config/koolo.yaml", "configHistory:\n  directory: blocked\n")
+	if err := Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, found := GetCharacter("koza")
+	if !found {
+		t.Fatal("profile not loaded")
+	}
+	cfg.MaxGameLength = 1234
+	if err := SaveSupervisorConfigFrom(history.SourceUI, "koza", cfg); err != nil {
+		t.Fatalf("a config history failure must not fail the save: %v", err)
+	}
+
+	saved, _ := GetCharacter("koza")
+	if saved.MaxGameLength != 1234 {
+		t.Errorf("the saved config was not reloaded, got maxGameLength %d", saved.MaxGameLength)
+	}
+}
@@ -0,0 +1,231 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/hectorgimenez/koolo/internal/config/secrets"
+)
+
+const (
+	secretsStorePath = "config/secrets.json"
+	// legacySecretsKeyfilePath is where the keyfile was created before, it's still used when it exists
+	legacySecretsKeyfilePath = "config/secrets.key"
+	secretsKeyfileName       = "secrets.key"
+)
+
+var (
+	secretsMu    sync.Mutex
+	secretsStore *secrets.Store
+)
+
+// SecretsStore returns the secrets store, it's opened with the master key the first time it's used
+func SecretsStore() (*secrets.Store, error) {
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+
+	if secretsStore != nil {
+		return secretsStore, nil
+	}
+
+	keyfile, err := defaultSecretsKeyfile()
+	if err != nil {
+		return nil, err
+	}
+	key, err := secrets.MasterKey(keyfile)
+	if err != nil {
+		return nil, err
+	}
+
+	return openSecretsStore(key)
+}
+
+// UnlockSecrets opens the secrets store with the master passphrase, for setups without keyfile or environment variable
+func UnlockSecrets(passphrase string) error {
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+
+	_, err := openSecretsStore([]byte(passphrase))
+
+	return err
+}
+
+// GenerateSecretsKeyfile creates the default keyfile and opens the store with it
+func GenerateSecretsKeyfile() error {
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+
+	keyfile, err := defaultSecretsKeyfile()
+	if err != nil {
+		return err
+	}
+	key, err := secrets.GenerateKeyfile(secrets.KeyfilePath(keyfile))
+	if err != nil {
+		return err
+	}
+	_, err = openSecretsStore(key)
+
+	return err
+}
+
+// RotateSecretsKey encrypts the secrets with a new master key. Without passphrase a new keyfile replaces the current
+// one, otherwise the passphrase is the new master key and the default keyfile, if any, is removed. A keyfile set in
+// KOOLO_SECRETS_KEYFILE is never removed, it must be unset before switching to a passphrase.
+func RotateSecretsKey(newPassphrase string) error {
+	if newPassphrase != "" && os.Getenv(secrets.KeyfileEnv) != "" {
+		return fmt.Errorf("%s is set, unset it before rotating to a passphrase", secrets.KeyfileEnv)
+	}
+
+	store, err := SecretsStore()
+	if err != nil {
+		return err
+	}
+
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+
+	keyfile, err := defaultSecretsKeyfile()
+	if err != nil {
+		return err
+	}
+	keyfile = secrets.KeyfilePath(keyfile)
+	if newPassphrase != "" {
+		if err = store.RotateKey([]byte(newPassphrase)); err != nil {
+			return err
+		}
+		if err = os.Remove(keyfile); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("secrets rotated, but the old keyfile could not be removed: %w", err)
+		}
+		return nil
+	}
+
+	// The new keyfile only replaces the current one once every secret is encrypted with it
+	newKeyfile := keyfile + ".new"
+	key, err := secrets.GenerateKeyfile(newKeyfile)
+	if err != nil {
+		return err
+	}
+	if err = store.RotateKey(key); err != nil {
+		_ = os.Remove(newKeyfile)
+		return err
+	}
+
+	return os.Rename(newKeyfile, keyfile)
+}
+
+// defaultSecretsKeyfile returns the keyfile used when KOOLO_SECRETS_KEYFILE is not set. It's stored in the user
+// config folder, like %AppData%\koolo, so it's not copied or shared along with the koolo folder and the encrypted
+// store. Keyfiles created in the config folder by previous versions are still used.
+func defaultSecretsKeyfile() (string, error) {
+	legacy := getAbsPath(legacySecretsKeyfilePath)
+	if _, err := os.Stat(legacy); err == nil {
+		return legacy, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("error finding the secrets keyfile folder, set %s: %w", secrets.KeyfileEnv, err)
+	}
+
+	return filepath.Join(dir, "koolo", secretsKeyfileName), nil
+}
+
+func openSecretsStore(key []byte) (*secrets.Store, error) {
+	store, err := secrets.Open(getAbsPath(secretsStorePath), key)
+	if err != nil {
+		return nil, err
+	}
+	secretsStore = store
+
+	return store, nil
+}
+
+// characterSecretFields are the character settings stored as secrets, by name
+func characterSecretFields(c *CharacterCfg) map[string]*string {
+	return map[string]*string{
+		"password":  &c.Password,
+		"authToken": &c.AuthToken,
+	}
+}
+
+// kooloSecretFields are the koolo settings stored as secrets, by name
+func kooloSecretFields(k *KooloCfg) map[string]*string {
+	return map[string]*string{
+		"discord.token":  &k.Discord.Token,
+		"telegram.token": &k.Telegram.Token,
+	}
+}
+
+// SecretResolved returns false when the setting is a secret reference that couldn't be read from the store
+func (k *KooloCfg) SecretResolved(name string) bool {
+	field, found := kooloSecretFields(k)[name]
+
+	return !found || !secrets.IsReference(*field)
+}
+
+// resolveSecrets replaces the secret references with the stored values, refs keeps the references to write them back
+// when saving. Unresolved references are left as they are.
+func resolveSecrets(fields map[string]*string, refs map[string]string) error {
+	var errs []error
+	for name, field := range fields {
+		if !secrets.IsReference(*field) {
+			secrets.Register(*field)
+			continue
+		}
+
+		refs[name] = *field
+		store, err := SecretsStore()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		value, err := store.Get(secrets.ReferenceName(*field))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		*field = value
+	}
+
+	return errors.Join(errs...)
+}
+
+// referenceSecrets replaces the secret values with their references before writing a config. Changed values are
+// updated in the store, and plain text values are moved to the store when it's available.
+func referenceSecrets(prefix string, fields map[string]*string, refs map[string]string) error {
+	for name, field := range fields {
+		value := *field
+		if secrets.IsReference(value) {
+			continue
+		}
+
+		ref, found := refs[name]
+		if !found && value == "" {
+			continue
+		}
+
+		store, err := SecretsStore()
+		if err != nil {
+			if found {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			// Without a store the value stays in plain text, as it was before
+			continue
+		}
+
+		if stored, err := store.Get(secrets.ReferenceName(ref)); !found || err != nil || stored != value {
+			// Secrets shared with other configs, like the ones inherited from a base profile, are never overwritten
+			ref = secrets.Reference(prefix + "." + name)
+			if err = store.Set(secrets.ReferenceName(ref), value); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+		}
+		refs[name] = ref
+		*field = ref
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/hectorgimenez/koolo/internal/config/secrets"
+)
+
+func TestLoadWithLockedSecretsStore(t *testing.T) {
+	setupBundleTest(t)
+	writeTestFile(t, "config/koolo.yaml", "discord:\n  enabled: true\n  token: "+secrets.Reference("koolo.discord.token")+"\n")
+	t.Setenv(secrets.KeyfileEnv, filepath.Join(t.TempDir(), "missing.key"))
+	t.Setenv(secrets.PassphraseEnv, "")
+
+	secretsMu.Lock()
+	previousStore := secretsStore
+	secretsStore = nil
+	secretsMu.Unlock()
+	t.Cleanup(func() {
+		secretsMu.Lock()
+		secretsStore = previousStore
+		secretsMu.Unlock()
+	})
+
+	if err := Load(); err != nil {
+		t.Fatalf("a locked secrets store must not fail the load: %v", err)
+	}
+
+	if Koolo.SecretsError == "" {
+		t.Error("expected the unresolved secret to be reported")
+	}
+	if Koolo.Discord.Token != secrets.Reference("koolo.discord.token") {
+		t.Errorf("the unresolved reference must be kept, got %q", Koolo.Discord.Token)
+	}
+	if Koolo.SecretResolved("discord.token") {
+		t.Error("the discord token must be reported as unresolved")
+	}
+	if !Koolo.SecretResolved("telegram.token") {
+		t.Error("settings without reference are resolved")
+	}
+	if _, found := GetCharacter("koza"); !found {
+		t.Error("profiles must be loaded with a locked store")
+	}
+}
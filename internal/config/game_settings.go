@@ -4,14 +4,17 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/hectorgimenez/koolo/internal/config/gamesettings"
 	"github.com/lxn/win"
-	cp "github.com/otiai10/copy"
 )
 
 var userProfile = os.Getenv("USERPROFILE")
 var settingsPath = userProfile + "\\Saved Games\\Diablo II Resurrected"
 
-func ReplaceGameSettings(modName string) error {
+const baseGameSettingsPath = "config/Settings.json"
+
+// ReplaceGameSettings writes the game settings of the mod, config/Settings.json with the profile overrides applied
+func ReplaceGameSettings(modName string, overrides map[string]any) error {
 	modDirPath := settingsPath + "\\mods\\" + modName
 	modSettingsPath := modDirPath + "\\Settings.json"
 
@@ -34,7 +37,36 @@ func ReplaceGameSettings(modName string) error {
 		}
 	}
 
-	return cp.Copy("config/Settings.json", modSettingsPath)
+	base, err := readBaseGameSettings()
+	if err != nil {
+		return err
+	}
+	content, err := gamesettings.Encode(gamesettings.Merge(base, overrides))
+	if err != nil {
+		return fmt.Errorf("error encoding game settings: %w", err)
+	}
+
+	return os.WriteFile(modSettingsPath, content, 0644)
+}
+
+// CheckGameSettings returns the game settings of the profile, how they differ from config/Settings.json and the
+// settings the bot doesn't work with
+func CheckGameSettings(cfg *CharacterCfg) (gamesettings.Report, error) {
+	base, err := readBaseGameSettings()
+	if err != nil {
+		return gamesettings.Report{}, err
+	}
+
+	return gamesettings.Check(base, cfg.GameSettings, cfg.ClassicMode), nil
+}
+
+func readBaseGameSettings() (gamesettings.Settings, error) {
+	content, err := os.ReadFile(getAbsPath(baseGameSettingsPath))
+	if err != nil {
+		return nil, fmt.Errorf("error reading game settings: %w", err)
+	}
+
+	return gamesettings.Parse(content)
 }
 
 func InstallMod() error {
@@ -0,0 +1,191 @@
+// Package gamesettings builds the D2R Settings.json used by every profile: the base file shared by all the profiles
+// with the profile overrides applied on top, and checks the result has the settings the bot depends on.
+package gamesettings
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Settings is the content of a Settings.json file, by setting name
+type Settings map[string]any
+
+// Change is a setting with a different value in the profile settings than in the base file, Base is nil for
+// settings missing in the base file
+type Change struct {
+	Key   string `json:"key"`
+	Base  any    `json:"base"`
+	Value any    `json:"value"`
+}
+
+// Problem is a setting with a value the bot doesn't work with
+type Problem struct {
+	Key      string `json:"key"`
+	Expected any    `json:"expected,omitempty"`
+	Actual   any    `json:"actual,omitempty"`
+	Message  string `json:"message"`
+}
+
+// Report is the result of applying the profile overrides to the base file
+type Report struct {
+	Settings Settings  `json:"settings"`
+	Changes  []Change  `json:"changes"`
+	Problems []Problem `json:"problems"`
+}
+
+type requirement struct {
+	key    string
+	value  any
+	reason string
+	// applies returns false when the requirement doesn't apply to the profile
+	applies func(classicMode bool) bool
+}
+
+// requirements are the settings the bot needs, every other setting can be changed freely
+var requirements = []requirement{
+	{key: "Auto Gold Enabled", value: 1, reason: "gold is only picked up automatically"},
+	{key: "Always Run", value: 1, reason: "movement timings expect the character to run"},
+	{key: "Window Mode", value: 0, reason: "the game must run in a window"},
+	{key: "Screen Resolution (Windowed)", value: "1280x720", reason: "screen positions are mapped for 1280x720"},
+	{
+		key:    "Legacy Graphics Mode",
+		value:  0,
+		reason: "the game must start with HD graphics, enable classic mode in the profile to play with legacy graphics",
+		applies: func(classicMode bool) bool {
+			return !classicMode
+		},
+	},
+}
+
+// Parse reads a Settings.json file, numbers are kept as written
+func Parse(content []byte) (Settings, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+
+	settings := Settings{}
+	if err := decoder.Decode(&settings); err != nil {
+		return nil, fmt.Errorf("invalid Settings.json: %w", err)
+	}
+
+	return settings, nil
+}
+
+// Encode returns the settings in the Settings.json format, keys sorted and indented with 4 spaces
+func Encode(settings Settings) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "    ")
+	if err := encoder.Encode(settings); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// Merge returns a copy of the base settings with the overrides applied, neither of them is modified
+func Merge(base Settings, overrides map[string]any) Settings {
+	merged := make(Settings, len(base)+len(overrides))
+	for key, value := range base {
+		merged[key] = value
+	}
+	for key, value := range overrides {
+		merged[key] = value
+	}
+
+	return merged
+}
+
+// Diff returns the settings with a different value in settings than in base, sorted by name
+func Diff(base, settings Settings) []Change {
+	changes := make([]Change, 0)
+	for key, value := range settings {
+		baseValue, found := base[key]
+		if found && equal(baseValue, value) {
+			continue
+		}
+		if !found {
+			baseValue = nil
+		}
+		changes = append(changes, Change{Key: key, Base: baseValue, Value: value})
+	}
+	slices.SortFunc(changes, func(a, b Change) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+
+	return changes
+}
+
+// Validate returns the settings with a value the bot doesn't work with, classicMode is the profile setting
+func Validate(settings Settings, classicMode bool) []Problem {
+	problems := make([]Problem, 0)
+	for _, req := range requirements {
+		if req.applies != nil && !req.applies(classicMode) {
+			continue
+		}
+
+		value, found := settings[req.key]
+		if !found {
+			problems = append(problems, Problem{Key: req.key, Expected: req.value, Message: fmt.Sprintf("missing, it must be %v: %s", req.value, req.reason)})
+			continue
+		}
+		if !equal(value, req.value) {
+			problems = append(problems, Problem{Key: req.key, Expected: req.value, Actual: value, Message: fmt.Sprintf("must be %v, got %v: %s", req.value, value, req.reason)})
+		}
+	}
+
+	return problems
+}
+
+// Check applies the overrides to the base settings, and returns the changes and the problems of the result. Overrides
+// of settings missing in the base file are reported too, they are usually a typo in the setting name.
+func Check(base Settings, overrides map[string]any, classicMode bool) Report {
+	settings := Merge(base, overrides)
+	report := Report{
+		Settings: settings,
+		Changes:  Diff(base, settings),
+		Problems: Validate(settings, classicMode),
+	}
+
+	for _, change := range report.Changes {
+		if change.Base == nil {
+			report.Problems = append(report.Problems, Problem{Key: change.Key, Actual: change.Value, Message: "unknown setting, it's not in the base Settings.json"})
+		}
+	}
+
+	return report
+}
+
+// equal compares setting values, numbers are equal when they have the same value whatever their type
+func equal(a, b any) bool {
+	af, aNumber := number(a)
+	bf, bNumber := number(b)
+	if aNumber || bNumber {
+		return aNumber && bNumber && af == bf
+	}
+
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
+
+func number(v any) (float64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,120 @@
+package gamesettings
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const baseSettings = `{
+    "Always Run": 1,
+    "Auto Gold Enabled": 1,
+    "Gamma": 155,
+    "Legacy Graphics Mode": 0,
+    "Screen Resolution (Windowed)": "1280x720",
+    "Window Mode": 0
+}`
+
+func parseBase(t *testing.T) Settings {
+	t.Helper()
+	base, err := Parse([]byte(baseSettings))
+	if err != nil {
+		t.Fatalf("parsing base settings: %v", err)
+	}
+
+	return base
+}
+
+func TestMergeAppliesOverridesWithoutModifyingBase(t *testing.T) {
+	base := parseBase(t)
+
+	merged := Merge(base, map[string]any{"Gamma": 200})
+
+	if !equal(merged["Gamma"], 200) {
+		t.Errorf("expected overridden Gamma 200, got %v", merged["Gamma"])
+	}
+	if !equal(base["Gamma"], 155) {
+		t.Errorf("base was modified, Gamma is %v", base["Gamma"])
+	}
+	if !equal(merged["Always Run"], 1) {
+		t.Errorf("expected Always Run from the base, got %v", merged["Always Run"])
+	}
+}
+
+func TestEncodeKeepsTheFileFormat(t *testing.T) {
+	encoded, err := Encode(parseBase(t))
+	if err != nil {
+		t.Fatalf("encoding settings: %v", err)
+	}
+
+	if string(encoded) != baseSettings {
+		t.Errorf("encoded settings differ from the source file:\n%s", encoded)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	base := parseBase(t)
+	merged := Merge(base, map[string]any{"Gamma": 200, "Always Run": 1, "Unknown": "x"})
+
+	changes := Diff(base, merged)
+
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes, got %+v", changes)
+	}
+	if changes[0].Key != "Gamma" || !equal(changes[0].Base, 155) || !equal(changes[0].Value, 200) {
+		t.Errorf("unexpected Gamma change %+v", changes[0])
+	}
+	if changes[1].Key != "Unknown" || changes[1].Base != nil {
+		t.Errorf("unexpected Unknown change %+v", changes[1])
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		overrides   map[string]any
+		classicMode bool
+		problems    []string
+	}{
+		{name: "base settings", overrides: nil},
+		{name: "auto gold disabled", overrides: map[string]any{"Auto Gold Enabled": 0}, problems: []string{"Auto Gold Enabled"}},
+		{name: "walking", overrides: map[string]any{"Always Run": json.Number("0")}, problems: []string{"Always Run"}},
+		{name: "other resolution", overrides: map[string]any{"Screen Resolution (Windowed)": "1920x1080"}, problems: []string{"Screen Resolution (Windowed)"}},
+		{name: "legacy graphics", overrides: map[string]any{"Legacy Graphics Mode": 1}, problems: []string{"Legacy Graphics Mode"}},
+		{name: "legacy graphics in classic mode", overrides: map[string]any{"Legacy Graphics Mode": 1}, classicMode: true},
+		{name: "number as float", overrides: map[string]any{"Always Run": 1.0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems := Validate(Merge(parseBase(t), tt.overrides), tt.classicMode)
+
+			if len(problems) != len(tt.problems) {
+				t.Fatalf("expected problems %v, got %+v", tt.problems, problems)
+			}
+			for i, key := range tt.problems {
+				if problems[i].Key != key {
+					t.Errorf("expected problem with %s, got %+v", key, problems[i])
+				}
+			}
+		})
+	}
+}
+
+func TestValidateMissingSetting(t *testing.T) {
+	base := parseBase(t)
+	delete(base, "Window Mode")
+
+	problems := Validate(base, false)
+
+	if len(problems) != 1 || problems[0].Key != "Window Mode" || problems[0].Actual != nil {
+		t.Errorf("expected missing Window Mode, got %+v", problems)
+	}
+}
+
+func TestCheckReportsUnknownOverrides(t *testing.T) {
+	report := Check(parseBase(t), map[string]any{"Alwys Run": 1}, false)
+
+	if len(report.Problems) != 1 || report.Problems[0].Key != "Alwys Run" {
+		t.Errorf("expected unknown setting problem, got %+v", report.Problems)
+	}
+}
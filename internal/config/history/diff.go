@@ -0,0 +1,107 @@
+package history
+
+import (
+	"strings"
+)
+
+// maxDiffCells limits the memory used comparing two versions, bigger changes are shown as a full replacement
+const maxDiffCells = 4_000_000
+
+type DiffKind string
+
+const (
+	DiffEqual   DiffKind = "equal"
+	DiffAdded   DiffKind = "added"
+	DiffRemoved DiffKind = "removed"
+)
+
+// DiffLine is a line of the line by line comparison of two versions, with its line number in each version (0 when
+// the line is not in that version)
+type DiffLine struct {
+	Kind    DiffKind `json:"kind"`
+	Text    string   `json:"text"`
+	OldLine int      `json:"oldLine,omitempty"`
+	NewLine int      `json:"newLine,omitempty"`
+}
+
+// Diff compares both contents line by line using the longest common subsequence of lines
+func Diff(old, new string) []DiffLine {
+	a := splitLines(old)
+	b := splitLines(new)
+
+	// Common prefix and suffix are skipped, most changes only touch a few lines
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	lines := make([]DiffLine, 0, len(a)+len(b))
+	for i := 0; i < prefix; i++ {
+		lines = append(lines, DiffLine{Kind: DiffEqual, Text: a[i], OldLine: i + 1, NewLine: i + 1})
+	}
+	lines = append(lines, diffMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix], prefix)...)
+	for i := 0; i < suffix; i++ {
+		oldIdx, newIdx := len(a)-suffix+i, len(b)-suffix+i
+		lines = append(lines, DiffLine{Kind: DiffEqual, Text: a[oldIdx], OldLine: oldIdx + 1, NewLine: newIdx + 1})
+	}
+
+	return lines
+}
+
+func diffMiddle(a, b []string, offset int) []DiffLine {
+	lines := make([]DiffLine, 0, len(a)+len(b))
+	if len(a)*len(b) > maxDiffCells {
+		for i, text := range a {
+			lines = append(lines, DiffLine{Kind: DiffRemoved, Text: text, OldLine: offset + i + 1})
+		}
+		for i, text := range b {
+			lines = append(lines, DiffLine{Kind: DiffAdded, Text: text, NewLine: offset + i + 1})
+		}
+		return lines
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, DiffLine{Kind: DiffEqual, Text: a[i], OldLine: offset + i + 1, NewLine: offset + j + 1})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, DiffLine{Kind: DiffRemoved, Text: a[i], OldLine: offset + i + 1})
+			i++
+		default:
+			lines = append(lines, DiffLine{Kind: DiffAdded, Text: b[j], NewLine: offset + j + 1})
+			j++
+		}
+	}
+
+	return lines
+}
+
+func splitLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(strings.ReplaceAll(content, "\r\n", "\n"), "\n"), "\n")
+}
@@ -0,0 +1,291 @@
+// Package history keeps the previous versions of the config files, so a broken change can be reviewed and rolled back.
+// Versions are listed in an append only index and their content is stored once per checksum.
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	indexFile = "history.jsonl"
+	blobsDir  = "blobs"
+)
+
+// Source is who changed the file
+type Source string
+
+const (
+	SourceUI      Source = "ui"
+	SourceAPI     Source = "api"
+	SourceDiscord Source = "discord"
+	// SourceBot are the changes made by the bot while running, like the leveling progress
+	SourceBot Source = "bot"
+	// SourceInitial is the content the file had before its first recorded change
+	SourceInitial Source = "initial"
+)
+
+var ErrVersionNotFound = errors.New("config version not found")
+
+// Version is a recorded content of a config file, artifacts are the file paths relative to the koolo folder
+type Version struct {
+	ID        int64     `json:"id"`
+	Artifact  string    `json:"artifact"`
+	Timestamp time.Time `json:"timestamp"`
+	Source    Source    `json:"source"`
+	Hash      string    `json:"hash"`
+	Size      int       `json:"size"`
+	Note      string    `json:"note,omitempty"`
+}
+
+type Store struct {
+	mu          sync.Mutex
+	dir         string
+	maxVersions int
+	versions    []Version
+	nextID      int64
+}
+
+// Open reads the history stored in dir, maxVersions is the number of versions kept per file
+func Open(dir string, maxVersions int) (*Store, error) {
+	if maxVersions < 1 {
+		return nil, fmt.Errorf("invalid config history limit %d", maxVersions)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, blobsDir), 0755); err != nil {
+		return nil, fmt.Errorf("error creating config history folder: %w", err)
+	}
+
+	s := &Store{dir: dir, maxVersions: maxVersions, nextID: 1}
+
+	f, err := os.Open(filepath.Join(dir, indexFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("error reading config history: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var v Version
+		// A line cut by a crash while appending is skipped, the rest of the history is still valid
+		if err = json.Unmarshal(line, &v); err != nil {
+			continue
+		}
+		s.versions = append(s.versions, v)
+		s.nextID = max(s.nextID, v.ID+1)
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading config history: %w", err)
+	}
+
+	return s, nil
+}
+
+// Record adds the new content of the file to the history. The previous content, nil when the file didn't exist, is
+// recorded first when the file has no history yet, so the first change can be rolled back too. Returns false when the
+// content is equal to the last recorded version.
+func (s *Store) Record(artifact string, source Source, previous, content []byte, note string) (Version, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hash := checksum(content)
+	versions := s.artifactVersions(artifact)
+	if len(versions) == 0 && previous != nil && checksum(previous) != hash {
+		if _, err := s.add(artifact, SourceInitial, previous, ""); err != nil {
+			return Version{}, false, err
+		}
+	} else if len(versions) > 0 && versions[0].Hash == hash {
+		return versions[0], false, nil
+	}
+
+	v, err := s.add(artifact, source, content, note)
+	if err != nil {
+		return Version{}, false, err
+	}
+
+	// Only the last of the consecutive bot saves is kept, the bot saves its progress after every game
+	if source == SourceBot && len(versions) > 0 && versions[0].Source == SourceBot {
+		if err = s.remove([]Version{versions[0]}); err != nil {
+			return v, true, err
+		}
+	}
+
+	return v, true, s.prune(artifact)
+}
+
+// Versions returns the recorded versions of the file, newest first
+func (s *Store) Versions(artifact string) []Version {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.artifactVersions(artifact)
+}
+
+// Artifacts returns the files with recorded versions
+func (s *Store) Artifacts() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	artifacts := make([]string, 0)
+	for _, v := range s.versions {
+		if !slices.Contains(artifacts, v.Artifact) {
+			artifacts = append(artifacts, v.Artifact)
+		}
+	}
+	slices.Sort(artifacts)
+
+	return artifacts
+}
+
+// Content returns the version and the file content it recorded
+func (s *Store) Content(id int64) (Version, []byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idx := slices.IndexFunc(s.versions, func(v Version) bool { return v.ID == id })
+	if idx < 0 {
+		return Version{}, nil, fmt.Errorf("%w: %d", ErrVersionNotFound, id)
+	}
+	v := s.versions[idx]
+
+	content, err := os.ReadFile(s.blobPath(v.Hash))
+	if err != nil {
+		return v, nil, fmt.Errorf("error reading config version %d: %w", id, err)
+	}
+
+	return v, content, nil
+}
+
+func (s *Store) artifactVersions(artifact string) []Version {
+	versions := make([]Version, 0)
+	for i := len(s.versions) - 1; i >= 0; i-- {
+		if s.versions[i].Artifact == artifact {
+			versions = append(versions, s.versions[i])
+		}
+	}
+
+	return versions
+}
+
+func (s *Store) add(artifact string, source Source, content []byte, note string) (Version, error) {
+	v := Version{
+		ID:        s.nextID,
+		Artifact:  artifact,
+		Timestamp: time.Now(),
+		Source:    source,
+		Hash:      checksum(content),
+		Size:      len(content),
+		Note:      note,
+	}
+
+	blob := s.blobPath(v.Hash)
+	if _, err := os.Stat(blob); os.IsNotExist(err) {
+		if err = os.WriteFile(blob, content, 0644); err != nil {
+			return v, fmt.Errorf("error writing config version: %w", err)
+		}
+	}
+
+	line, err := json.Marshal(v)
+	if err != nil {
+		return v, err
+	}
+	f, err := os.OpenFile(filepath.Join(s.dir, indexFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return v, fmt.Errorf("error writing config history: %w", err)
+	}
+	defer f.Close()
+	if _, err = f.Write(append(line, '\n')); err != nil {
+		return v, fmt.Errorf("error writing config history: %w", err)
+	}
+
+	s.versions = append(s.versions, v)
+	s.nextID++
+
+	return v, nil
+}
+
+// prune removes the oldest versions of the file over the limit. Bot saves are not counted, so they can't evict the
+// user changes, but the ones older than the oldest version kept are removed too.
+func (s *Store) prune(artifact string) error {
+	kept := 0
+	removed := make([]Version, 0)
+	for _, v := range s.artifactVersions(artifact) {
+		if kept >= s.maxVersions {
+			removed = append(removed, v)
+		} else if v.Source != SourceBot {
+			kept++
+		}
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+
+	return s.remove(removed)
+}
+
+// remove deletes the versions from the index and the contents no longer used by any version
+func (s *Store) remove(removed []Version) error {
+	s.versions = slices.DeleteFunc(s.versions, func(v Version) bool {
+		return slices.ContainsFunc(removed, func(r Version) bool { return r.ID == v.ID })
+	})
+
+	if err := s.writeIndex(); err != nil {
+		return err
+	}
+
+	for _, r := range removed {
+		if !slices.ContainsFunc(s.versions, func(v Version) bool { return v.Hash == r.Hash }) {
+			_ = os.Remove(s.blobPath(r.Hash))
+		}
+	}
+
+	return nil
+}
+
+// writeIndex rewrites the whole index, it's replaced at once so a crash can't lose the history
+func (s *Store) writeIndex() error {
+	buf := &bytes.Buffer{}
+	for _, v := range s.versions {
+		line, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		buf.Write(append(line, '\n'))
+	}
+
+	path := filepath.Join(s.dir, indexFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("error writing config history: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("error writing config history: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Store) blobPath(hash string) string {
+	return filepath.Join(s.dir, blobsDir, hash)
+}
+
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,201 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+const artifact = "config/koolo/config.yaml"
+
+func TestRecordStoresContentOnce(t *testing.T) {
+	dir := t.TempDir()
+	store := openStore(t, dir, 10)
+
+	record(t, store, SourceUI, "a")
+	record(t, store, SourceUI, "b")
+	record(t, store, SourceUI, "a")
+	// Same content in another file
+	if _, _, err := store.Record("config/other/config.yaml", SourceUI, nil, []byte("b"), ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := len(store.Versions(artifact)); got != 3 {
+		t.Errorf("got %d versions, want 3", got)
+	}
+	if got := blobs(t, dir); len(got) != 2 {
+		t.Errorf("got %d contents stored, want 2", len(got))
+	}
+}
+
+func TestRecordSkipsUnchangedContent(t *testing.T) {
+	store := openStore(t, t.TempDir(), 10)
+
+	first := record(t, store, SourceUI, "a")
+	v, added, err := store.Record(artifact, SourceUI, []byte("a"), []byte("a"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added || v.ID != first.ID {
+		t.Errorf("unchanged content recorded as version %d", v.ID)
+	}
+}
+
+func TestRecordAddsInitialContent(t *testing.T) {
+	store := openStore(t, t.TempDir(), 10)
+
+	if _, _, err := store.Record(artifact, SourceUI, []byte("before"), []byte("after"), ""); err != nil {
+		t.Fatal(err)
+	}
+
+	versions := store.Versions(artifact)
+	if got := sources(versions); !slices.Equal(got, []Source{SourceUI, SourceInitial}) {
+		t.Fatalf("got sources %v", got)
+	}
+	if _, content, _ := store.Content(versions[1].ID); string(content) != "before" {
+		t.Errorf("got initial content %q, want %q", content, "before")
+	}
+}
+
+func TestPrune(t *testing.T) {
+	dir := t.TempDir()
+	store := openStore(t, dir, 2)
+
+	record(t, store, SourceUI, "a")
+	record(t, store, SourceUI, "b")
+	record(t, store, SourceUI, "c")
+
+	versions := store.Versions(artifact)
+	if got := contents(t, store, versions); !slices.Equal(got, []string{"c", "b"}) {
+		t.Errorf("got versions %v, want [c b]", got)
+	}
+	if got := blobs(t, dir); len(got) != 2 {
+		t.Errorf("the content of the removed version is still stored: %v", got)
+	}
+
+	// The removed version is not read again
+	reopened := openStore(t, dir, 2)
+	if got := contents(t, reopened, reopened.Versions(artifact)); !slices.Equal(got, []string{"c", "b"}) {
+		t.Errorf("got versions %v after reopening, want [c b]", got)
+	}
+}
+
+func TestBotSaves(t *testing.T) {
+	store := openStore(t, t.TempDir(), 2)
+
+	record(t, store, SourceUI, "a")
+	record(t, store, SourceBot, "bot 1")
+	record(t, store, SourceBot, "bot 2")
+	record(t, store, SourceBot, "bot 3")
+
+	// Only the last consecutive bot save is kept and it doesn't evict the user changes
+	if got := contents(t, store, store.Versions(artifact)); !slices.Equal(got, []string{"bot 3", "a"}) {
+		t.Fatalf("got versions %v, want [bot 3, a]", got)
+	}
+
+	record(t, store, SourceUI, "b")
+	record(t, store, SourceBot, "bot 4")
+	if got := contents(t, store, store.Versions(artifact)); !slices.Equal(got, []string{"bot 4", "b", "bot 3", "a"}) {
+		t.Fatalf("got versions %v, want [bot 4, b, bot 3, a]", got)
+	}
+
+	// The bot saves older than the oldest user change kept are removed with it
+	record(t, store, SourceUI, "c")
+	if got := contents(t, store, store.Versions(artifact)); !slices.Equal(got, []string{"c", "bot 4", "b"}) {
+		t.Errorf("got versions %v, want [c, bot 4, b]", got)
+	}
+}
+
+func TestRollback(t *testing.T) {
+	dir := t.TempDir()
+	store := openStore(t, dir, 10)
+
+	first := record(t, store, SourceUI, "a")
+	record(t, store, SourceUI, "b")
+
+	// A rollback records the old content as a new version
+	v, content, err := store.Content(first.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rollback, added, err := store.Record(v.Artifact, SourceDiscord, []byte("b"), content, "rollback")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !added || rollback.Hash != first.Hash || rollback.Source != SourceDiscord {
+		t.Errorf("unexpected rollback version %+v", rollback)
+	}
+	if got := contents(t, store, store.Versions(artifact)); !slices.Equal(got, []string{"a", "b", "a"}) {
+		t.Errorf("got versions %v, want [a b a]", got)
+	}
+	if got := blobs(t, dir); len(got) != 2 {
+		t.Errorf("got %d contents stored, want 2", len(got))
+	}
+
+	if _, _, err = store.Content(999); err == nil {
+		t.Error("expected an error reading a missing version")
+	}
+}
+
+func openStore(t *testing.T, dir string, maxVersions int) *Store {
+	t.Helper()
+
+	store, err := Open(dir, maxVersions)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return store
+}
+
+func record(t *testing.T, store *Store, source Source, content string) Version {
+	t.Helper()
+
+	v, _, err := store.Record(artifact, source, nil, []byte(content), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return v
+}
+
+func contents(t *testing.T, store *Store, versions []Version) []string {
+	t.Helper()
+
+	result := make([]string, 0, len(versions))
+	for _, v := range versions {
+		_, content, err := store.Content(v.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result = append(result, string(content))
+	}
+
+	return result
+}
+
+func sources(versions []Version) []Source {
+	result := make([]Source, 0, len(versions))
+	for _, v := range versions {
+		result = append(result, v.Source)
+	}
+
+	return result
+}
+
+func blobs(t *testing.T, dir string) []string {
+	t.Helper()
+
+	entries, err := os.ReadDir(filepath.Join(dir, blobsDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+
+	return names
+}
@@ -0,0 +1,371 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hectorgimenez/koolo/internal/config/secrets"
+	"gopkg.in/yaml.v3"
+)
+
+// Profiles can declare "extends: <base profile>" and store only the settings that differ from the base. Mappings are
+// merged key by key, scalars and lists from the profile replace the base value. Positional lists, like the belt
+// columns or the inventory lock rows, can't be merged safely, so only lists tagged with !merge are merged: the
+// elements of the profile list missing in the base list are added after the base ones:
+//
+//	runs: [pit, cows]   # only pit and cows, the base runs are discarded
+//	runs: !merge [pit]  # base runs followed by pit
+//
+// Lists saved from the web UI are stored as the added elements with !merge when possible, and as the whole list
+// otherwise. The !replace tag written by previous versions is still read, it's the same as an untagged list. Base
+// profiles declaring "abstract: true" are only bases, they are never started, and the abstract setting is not
+// inherited.
+
+// mergeTag marks the lists merged with the base list instead of replacing it
+const mergeTag = "!merge"
+
+// ProfileOverride is a setting of the profile that differs from its base profile
+type ProfileOverride struct {
+	Path  string `json:"path"`
+	Base  string `json:"base"`
+	Value string `json:"value"`
+}
+
+// ProfileInheritance is the effective config of a profile and its differences with the base profile
+type ProfileInheritance struct {
+	Supervisor string            `json:"supervisor"`
+	Extends    string            `json:"extends"`
+	Chain      []string          `json:"chain"`
+	Effective  string            `json:"effective"`
+	Overrides  []ProfileOverride `json:"overrides"`
+}
+
+// readProfileNode reads the config.yaml of the profile as it is stored, without resolving the base profiles
+func readProfileNode(name string) (*yaml.Node, error) {
+	configPath := getAbsPath(filepath.Join("config", name, "config.yaml"))
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("error loading config.yaml: %w", err)
+	}
+
+	var doc yaml.Node
+	if err = yaml.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("error reading %s character config: %w", configPath, err)
+	}
+	if len(doc.Content) == 0 {
+		return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}, nil
+	}
+	if doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("error reading %s character config: root is not a mapping", configPath)
+	}
+
+	return doc.Content[0], nil
+}
+
+// resolveProfileNode reads the profile and merges it over its base profiles. The returned chain contains the profile
+// followed by its bases, nearest first.
+func resolveProfileNode(name string, visited []string) (*yaml.Node, []string, error) {
+	if slices.Contains(visited, name) {
+		return nil, nil, fmt.Errorf("extends: inheritance cycle %s -> %s", strings.Join(visited, " -> "), name)
+	}
+	visited = append(visited, name)
+
+	node, err := readProfileNode(name)
+	if err != nil {
+		if len(visited) > 1 {
+			return nil, nil, fmt.Errorf("extends: base profile %q: %w", name, err)
+		}
+		return nil, nil, err
+	}
+
+	base := mappingValue(node, "extends")
+	if base == nil || base.Value == "" {
+		return node, []string{name}, nil
+	}
+
+	baseNode, chain, err := resolveProfileNode(base.Value, visited)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// A profile extending an abstract one is runnable unless it's declared abstract too
+	if idx := mappingIndex(baseNode, "abstract"); idx >= 0 {
+		baseNode = &yaml.Node{Kind: baseNode.Kind, Tag: baseNode.Tag, Content: slices.Delete(slices.Clone(baseNode.Content), idx, idx+2)}
+	}
+
+	return mergeNodes(baseNode, node), append([]string{name}, chain...), nil
+}
+
+// mergeNodes returns the base with the override applied. Nested mappings are merged, lists are only merged when the
+// override list is tagged with !merge, and any other value is replaced.
+func mergeNodes(base, override *yaml.Node) *yaml.Node {
+	if override.Kind == yaml.SequenceNode {
+		merged := *override
+		merged.Tag = "!!seq"
+		if override.Tag != mergeTag || base.Kind != yaml.SequenceNode {
+			return &merged
+		}
+
+		merged.Style = base.Style
+		merged.Content = slices.Clone(base.Content)
+		for _, item := range override.Content {
+			if !containsNode(merged.Content, item) {
+				merged.Content = append(merged.Content, item)
+			}
+		}
+		return &merged
+	}
+	if base.Kind != yaml.MappingNode || override.Kind != yaml.MappingNode {
+		return override
+	}
+
+	merged := &yaml.Node{Kind: yaml.MappingNode, Tag: base.Tag, Content: slices.Clone(base.Content)}
+	for i := 0; i+1 < len(override.Content); i += 2 {
+		key, value := override.Content[i], override.Content[i+1]
+		idx := mappingIndex(merged, key.Value)
+		if idx < 0 {
+			merged.Content = append(merged.Content, key, value)
+			continue
+		}
+		merged.Content[idx+1] = mergeNodes(merged.Content[idx+1], value)
+	}
+
+	return merged
+}
+
+// diffNodes returns the parts of value that differ from base, changed is false when both are equal
+func diffNodes(base, value *yaml.Node) (diff *yaml.Node, changed bool) {
+	if base == nil {
+		return value, true
+	}
+	if base.Kind == yaml.SequenceNode && value.Kind == yaml.SequenceNode {
+		return diffSequences(base, value)
+	}
+	if base.Kind != yaml.MappingNode || value.Kind != yaml.MappingNode {
+		return value, !nodesEqual(base, value)
+	}
+
+	diff = &yaml.Node{Kind: yaml.MappingNode, Tag: value.Tag}
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		key := value.Content[i]
+		if d, changed := diffNodes(mappingValue(base, key.Value), value.Content[i+1]); changed {
+			diff.Content = append(diff.Content, key, d)
+		}
+	}
+
+	return diff, len(diff.Content) > 0
+}
+
+// diffSequences returns the elements of value missing in the base list tagged with !merge, or the whole list when
+// merging them with the base list doesn't give the same list
+func diffSequences(base, value *yaml.Node) (*yaml.Node, bool) {
+	if nodesEqual(base, value) {
+		return value, false
+	}
+
+	added := &yaml.Node{Kind: yaml.SequenceNode, Tag: mergeTag, Style: value.Style}
+	for _, item := range value.Content {
+		if !containsNode(base.Content, item) {
+			added.Content = append(added.Content, item)
+		}
+	}
+	if len(added.Content) > 0 && nodesEqual(mergeNodes(base, added), value) {
+		return added, true
+	}
+
+	return value, true
+}
+
+func containsNode(nodes []*yaml.Node, node *yaml.Node) bool {
+	return slices.ContainsFunc(nodes, func(n *yaml.Node) bool { return nodesEqual(n, node) })
+}
+
+func nodesEqual(a, b *yaml.Node) bool {
+	if a.Kind != b.Kind || a.Tag != b.Tag || a.Value != b.Value || len(a.Content) != len(b.Content) {
+		return false
+	}
+	for i := range a.Content {
+		if !nodesEqual(a.Content[i], b.Content[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func mappingIndex(node *yaml.Node, key string) int {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	if idx := mappingIndex(node, key); idx >= 0 {
+		return node.Content[idx+1]
+	}
+
+	return nil
+}
+
+// decodeProfile decodes the resolved profile settings and applies the defaults
+func decodeProfile(node *yaml.Node, cfg *CharacterCfg) error {
+	if err := node.Decode(cfg); err != nil {
+		return err
+	}
+	if cfg.Game.MaxFailedMenuAttempts == 0 {
+		cfg.Game.MaxFailedMenuAttempts = 10
+	}
+
+	return nil
+}
+
+// effectiveProfileNode returns every setting of the profile with its bases applied, encoded the same way the profile
+// is saved so it can be compared with other profiles
+func effectiveProfileNode(name string, visited []string) (*yaml.Node, error) {
+	node, _, err := resolveProfileNode(name, visited)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &CharacterCfg{}
+	if err = decodeProfile(node, cfg); err != nil {
+		return nil, fmt.Errorf("extends: base profile %q: %w", name, err)
+	}
+	cfg.Validate()
+
+	return encodeProfile(cfg)
+}
+
+func encodeProfile(cfg *CharacterCfg) (*yaml.Node, error) {
+	var node yaml.Node
+	if err := node.Encode(cfg); err != nil {
+		return nil, err
+	}
+
+	return &node, nil
+}
+
+// profileOverridesNode returns the settings of the profile that differ from its base profile, the ones to persist
+func profileOverridesNode(supervisorName string, cfg *CharacterCfg) (*yaml.Node, error) {
+	node, err := encodeProfile(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	base, err := effectiveProfileNode(cfg.Extends, []string{supervisorName})
+	if err != nil {
+		return nil, err
+	}
+
+	overrides, _ := diffNodes(base, node)
+	if overrides.Kind != yaml.MappingNode {
+		return node, nil
+	}
+
+	// The schema version and the base are always stored first, they are needed to read the rest of the file
+	for _, key := range []string{"schemaVersion", "extends"} {
+		if idx := mappingIndex(overrides, key); idx >= 0 {
+			overrides.Content = slices.Delete(overrides.Content, idx, idx+2)
+		}
+	}
+	header := []*yaml.Node{
+		{Kind: yaml.ScalarNode, Tag: "!!str", Value: "schemaVersion"},
+		{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(cfg.SchemaVersion)},
+		{Kind: yaml.ScalarNode, Tag: "!!str", Value: "extends"},
+		{Kind: yaml.ScalarNode, Tag: "!!str", Value: cfg.Extends},
+	}
+	overrides.Content = append(header, overrides.Content...)
+
+	return overrides, nil
+}
+
+// GetProfileInheritance returns the effective config of the profile and the settings overridden from its base profile
+func GetProfileInheritance(supervisorName string) (ProfileInheritance, error) {
+	cfg, found := GetCharacter(supervisorName)
+	if !found {
+		return ProfileInheritance{}, fmt.Errorf("profile %s not found", supervisorName)
+	}
+
+	effective, err := effectiveProfileNode(supervisorName, nil)
+	if err != nil {
+		return ProfileInheritance{}, err
+	}
+	content, err := yaml.Marshal(effective)
+	if err != nil {
+		return ProfileInheritance{}, err
+	}
+
+	_, chain, err := resolveProfileNode(supervisorName, nil)
+	if err != nil {
+		return ProfileInheritance{}, err
+	}
+
+	inheritance := ProfileInheritance{
+		Supervisor: supervisorName,
+		Extends:    cfg.Extends,
+		Chain:      chain,
+		Effective:  secrets.Redact(string(content)),
+		Overrides:  make([]ProfileOverride, 0),
+	}
+	if cfg.Extends == "" {
+		return inheritance, nil
+	}
+
+	base, err := effectiveProfileNode(cfg.Extends, []string{supervisorName})
+	if err != nil {
+		return ProfileInheritance{}, err
+	}
+	diff, _ := diffNodes(base, effective)
+	inheritance.Overrides = collectOverrides(diff, base, effective, "", inheritance.Overrides)
+
+	return inheritance, nil
+}
+
+// collectOverrides flattens the diff to one entry per overridden setting, with the dotted path to the setting and its
+// effective value, merged lists are shown with every element
+func collectOverrides(diff, base, effective *yaml.Node, path string, overrides []ProfileOverride) []ProfileOverride {
+	if diff.Kind == yaml.MappingNode && (base == nil || base.Kind == yaml.MappingNode) {
+		for i := 0; i+1 < len(diff.Content); i += 2 {
+			key := diff.Content[i].Value
+			if path == "" && (key == "extends" || key == "schemaVersion") {
+				continue
+			}
+			var baseValue *yaml.Node
+			if base != nil {
+				baseValue = mappingValue(base, key)
+			}
+			overrides = collectOverrides(diff.Content[i+1], baseValue, mappingValue(effective, key), strings.TrimPrefix(path+"."+key, "."), overrides)
+		}
+
+		return overrides
+	}
+
+	return append(overrides, ProfileOverride{Path: path, Base: secrets.Redact(nodeString(base)), Value: secrets.Redact(nodeString(effective))})
+}
+
+func nodeString(node *yaml.Node) string {
+	if node == nil {
+		return ""
+	}
+	if node.Kind == yaml.ScalarNode {
+		return node.Value
+	}
+
+	content, err := yaml.Marshal(node)
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(content))
+}
@@ -0,0 +1,303 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestMergeNodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     string
+		override string
+		want     string
+	}{
+		{
+			name:     "mappings are merged",
+			base:     "a: 1\nb:\n  c: 2\n  d: 3\n",
+			override: "b:\n  d: 4\ne: 5\n",
+			want:     "a: 1\nb:\n  c: 2\n  d: 4\ne: 5\n",
+		},
+		{
+			name:     "lists replace the base list",
+			base:     "runs: [a, b]\n",
+			override: "runs: [b, c]\n",
+			want:     "runs: [b, c]\n",
+		},
+		{
+			name:     "lists tagged with merge are merged",
+			base:     "runs: [a, b]\n",
+			override: "runs: !merge [b, c]\n",
+			want:     "runs: [a, b, c]\n",
+		},
+		{
+			name:     "lists of mappings are merged",
+			base:     "rules:\n  - name: a\n",
+			override: "rules: !merge\n  - name: a\n  - name: b\n",
+			want:     "rules:\n  - name: a\n  - name: b\n",
+		},
+		{
+			name:     "lists tagged with replace by older versions",
+			base:     "runs: [a, b]\n",
+			override: "runs: !replace [c]\n",
+			want:     "runs: [c]\n",
+		},
+		{
+			name:     "empty list",
+			base:     "runs: [a, b]\n",
+			override: "runs: []\n",
+			want:     "runs: []\n",
+		},
+		{
+			name:     "belt columns keep their positions",
+			base:     "health:\n  beltColumns: [healing, healing, mana, rejuvenation]\n",
+			override: "health:\n  beltColumns: [mana, mana, mana, rejuvenation]\n",
+			want:     "health:\n  beltColumns: [mana, mana, mana, rejuvenation]\n",
+		},
+		{
+			name:     "inventory lock keeps its rows",
+			base:     "inventory:\n  inventoryLock:\n    - [1, 1]\n    - [1, 1]\n    - [0, 0]\n    - [0, 0]\n",
+			override: "inventory:\n  inventoryLock:\n    - [1, 1]\n    - [1, 1]\n    - [1, 0]\n    - [0, 0]\n",
+			want:     "inventory:\n  inventoryLock:\n    - [1, 1]\n    - [1, 1]\n    - [1, 0]\n    - [0, 0]\n",
+		},
+		{
+			name:     "scheduler days are not duplicated",
+			base:     "scheduler:\n  days:\n    - day: 0\n      timeRanges: [{start: \"10:00\", end: \"12:00\"}]\n    - day: 1\n      timeRanges: []\n",
+			override: "scheduler:\n  days:\n    - day: 0\n      timeRanges: [{start: \"14:00\", end: \"16:00\"}]\n    - day: 1\n      timeRanges: []\n",
+			want:     "scheduler:\n  days:\n    - day: 0\n      timeRanges: [{start: \"14:00\", end: \"16:00\"}]\n    - day: 1\n      timeRanges: []\n",
+		},
+		{
+			name:     "other values are replaced",
+			base:     "runs: [a]\nname: base\n",
+			override: "runs: pit\nname: profile\n",
+			want:     "runs: pit\nname: profile\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mergeNodes(testNode(t, tt.base), testNode(t, tt.override))
+			if got, want := plainYAML(t, got), plainYAML(t, testNode(t, tt.want)); got != want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestDiffNodes(t *testing.T) {
+	tests := []struct {
+		name    string
+		base    string
+		value   string
+		want    string
+		changed bool
+	}{
+		{
+			name:  "equal",
+			base:  "a: 1\nruns: [a]\n",
+			value: "a: 1\nruns: [a]\n",
+		},
+		{
+			name:    "changed scalar",
+			base:    "a: 1\nb: 2\n",
+			value:   "a: 1\nb: 3\n",
+			want:    "b: 3\n",
+			changed: true,
+		},
+		{
+			name:    "changed nested setting",
+			base:    "m:\n  x: 1\n  y: 2\n",
+			value:   "m:\n  x: 1\n  y: 5\n",
+			want:    "m:\n  y: 5\n",
+			changed: true,
+		},
+		{
+			name:    "added list elements",
+			base:    "runs: [a]\n",
+			value:   "runs: [a, b]\n",
+			want:    "runs: !merge [b]\n",
+			changed: true,
+		},
+		{
+			name:    "removed list elements",
+			base:    "runs: [a, b]\n",
+			value:   "runs: [a]\n",
+			want:    "runs: [a]\n",
+			changed: true,
+		},
+		{
+			name:    "reordered list",
+			base:    "runs: [a, b]\n",
+			value:   "runs: [b, a]\n",
+			want:    "runs: [b, a]\n",
+			changed: true,
+		},
+		{
+			name:    "changed belt column",
+			base:    "beltColumns: [healing, healing, mana, rejuvenation]\n",
+			value:   "beltColumns: [mana, healing, mana, rejuvenation]\n",
+			want:    "beltColumns: [mana, healing, mana, rejuvenation]\n",
+			changed: true,
+		},
+		{
+			name:    "new setting",
+			base:    "a: 1\n",
+			value:   "a: 1\nb: [x]\n",
+			want:    "b: [x]\n",
+			changed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base, value := testNode(t, tt.base), testNode(t, tt.value)
+			diff, changed := diffNodes(base, value)
+			if changed != tt.changed {
+				t.Fatalf("got changed %v, want %v", changed, tt.changed)
+			}
+			if !changed {
+				return
+			}
+			if got, want := plainYAML(t, diff), plainYAML(t, testNode(t, tt.want)); got != want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, want)
+			}
+
+			// The stored overrides applied to the base must give the same settings
+			if got, want := plainYAML(t, mergeNodes(base, diff)), plainYAML(t, value); got != want {
+				t.Errorf("merged overrides:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestCollectOverrides(t *testing.T) {
+	base := testNode(t, "a: 1\nm:\n  x: 1\n  y: 2\nruns: [a]\n")
+	effective := testNode(t, "extends: base\na: 1\nm:\n  x: 1\n  y: 5\nruns: [a, b]\nnew: true\n")
+
+	diff, _ := diffNodes(base, effective)
+	got := collectOverrides(diff, base, effective, "", nil)
+	want := []ProfileOverride{
+		{Path: "m.y", Base: "2", Value: "5"},
+		// Merged lists are shown with every element
+		{Path: "runs", Base: "[a]", Value: "[a, b]"},
+		{Path: "new", Base: "", Value: "true"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestResolveProfileNode(t *testing.T) {
+	writeTestProfiles(t, map[string]string{
+		"base":     "abstract: true\nmaxGameLength: 100\ngame:\n  runs: [a, b]\n",
+		"merged":   "extends: base\ngame:\n  runs: !merge [c]\n",
+		"replaced": "extends: base\nmaxGameLength: 200\ngame:\n  runs: [c]\n",
+		"nested":   "extends: merged\nabstract: true\n",
+		"cycle_a":  "extends: cycle_b\n",
+		"cycle_b":  "extends: cycle_a\n",
+	})
+
+	tests := []struct {
+		name          string
+		maxGameLength int
+		runs          []string
+		chain         []string
+		abstract      bool
+	}{
+		{name: "base", maxGameLength: 100, runs: []string{"a", "b"}, chain: []string{"base"}, abstract: true},
+		{name: "merged", maxGameLength: 100, runs: []string{"a", "b", "c"}, chain: []string{"merged", "base"}},
+		{name: "replaced", maxGameLength: 200, runs: []string{"c"}, chain: []string{"replaced", "base"}},
+		{name: "nested", maxGameLength: 100, runs: []string{"a", "b", "c"}, chain: []string{"nested", "merged", "base"}, abstract: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, chain, err := resolveProfileNode(tt.name, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(chain, tt.chain) {
+				t.Errorf("got chain %v, want %v", chain, tt.chain)
+			}
+
+			var got struct {
+				Abstract      bool `yaml:"abstract"`
+				MaxGameLength int  `yaml:"maxGameLength"`
+				Game          struct {
+					Runs []string `yaml:"runs"`
+				} `yaml:"game"`
+			}
+			if err = node.Decode(&got); err != nil {
+				t.Fatal(err)
+			}
+			if got.Abstract != tt.abstract {
+				t.Errorf("got abstract %v, want %v", got.Abstract, tt.abstract)
+			}
+			if got.MaxGameLength != tt.maxGameLength || !slices.Equal(got.Game.Runs, tt.runs) {
+				t.Errorf("got maxGameLength %d and runs %v, want %d and %v", got.MaxGameLength, got.Game.Runs, tt.maxGameLength, tt.runs)
+			}
+		})
+	}
+
+	if _, _, err := resolveProfileNode("cycle_a", nil); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("got %v, want an inheritance cycle error", err)
+	}
+	if _, _, err := resolveProfileNode("missing", nil); err == nil {
+		t.Error("expected an error for a missing profile")
+	}
+}
+
+// writeTestProfiles writes the config.yaml of every profile in a temp koolo folder, used as working dir by the test
+func writeTestProfiles(t *testing.T, profiles map[string]string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range profiles {
+		profileDir := filepath.Join(dir, "config", name)
+		if err := os.MkdirAll(profileDir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(profileDir, "config.yaml"), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = os.Chdir(wd)
+	})
+}
+
+// plainYAML encodes the node without the formatting styles, so nodes can be compared by content and tags
+func plainYAML(t *testing.T, node *yaml.Node) string {
+	t.Helper()
+
+	content, err := yaml.Marshal(withoutStyle(node))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(content)
+}
+
+func withoutStyle(node *yaml.Node) *yaml.Node {
+	plain := *node
+	plain.Style = 0
+	plain.Content = make([]*yaml.Node, 0, len(node.Content))
+	for _, child := range node.Content {
+		plain.Content = append(plain.Content, withoutStyle(child))
+	}
+
+	return &plain
+}
@@ -1,5 +1,238 @@
 package config
 
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hectorgimenez/d2go/pkg/data/skill"
+	"github.com/hectorgimenez/d2go/pkg/data/stat"
+)
+
+// LevelingBuildConfig is the content of config/template/builds_leveling/<class>.json. Everything but the nips is optional,
+// when a section is present it replaces the plan hardcoded for the leveling class.
 type LevelingBuildConfig struct {
 	Nips []string `json:"nips"`
+	// Stats are assigned in order, same as LevelingCharacter.StatPoints
+	Stats []LevelingStatTarget `json:"stats,omitempty"`
+	// Skills contains the skill point plans, the one with the highest minLevel reached by the character is used
+	Skills   []LevelingSkillPlan    `json:"skills,omitempty"`
+	Bindings []LevelingSkillBinding `json:"bindings,omitempty"`
+	Respec   []LevelingRespec       `json:"respec,omitempty"`
+}
+
+type LevelingStatTarget struct {
+	Stat   BuildStat `json:"stat"`
+	Points int       `json:"points"`
+}
+
+type LevelingSkillPlan struct {
+	MinLevel int `json:"minLevel"`
+	// Points is the ordered list of skill points, repeat the skill to assign more than one point
+	Points []BuildSkill `json:"points"`
+}
+
+type LevelingSkillBinding struct {
+	Skill    BuildSkill `json:"skill"`
+	MinLevel int        `json:"minLevel,omitempty"`
+	// Main binds the skill to the left click, the last main skill available is used
+	Main bool `json:"main,omitempty"`
+}
+
+// LevelingRespec resets the skills and stats when the character reaches the level, only if the required skills have at
+// least the given level (so it doesn't happen again after the respec)
+type LevelingRespec struct {
+	Level          int                `json:"level"`
+	RequiredSkills map[BuildSkill]int `json:"requiredSkills,omitempty"`
+}
+
+func (b *LevelingBuildConfig) HasStats() bool {
+	return b != nil && len(b.Stats) > 0
+}
+
+func (b *LevelingBuildConfig) HasSkills() bool {
+	return b != nil && len(b.Skills) > 0
+}
+
+func (b *LevelingBuildConfig) HasBindings() bool {
+	return b != nil && len(b.Bindings) > 0
+}
+
+func (b *LevelingBuildConfig) HasRespec() bool {
+	return b != nil && len(b.Respec) > 0
+}
+
+// SkillPlan returns the skill points to assign at the given character level
+func (b *LevelingBuildConfig) SkillPlan(level int) []skill.ID {
+	var plan *LevelingSkillPlan
+	for i, p := range b.Skills {
+		if p.MinLevel <= level && (plan == nil || p.MinLevel >= plan.MinLevel) {
+			plan = &b.Skills[i]
+		}
+	}
+	if plan == nil {
+		return nil
+	}
+
+	skills := make([]skill.ID, 0, len(plan.Points))
+	for _, sk := range plan.Points {
+		skills = append(skills, skill.ID(sk))
+	}
+
+	return skills
+}
+
+// SkillBindings returns the main skill and the skills to bind at the given level, available filters out the skills
+// the character can't use yet (not learned, no tome in the inventory...)
+func (b *LevelingBuildConfig) SkillBindings(level int, available func(skill.ID) bool) (skill.ID, []skill.ID) {
+	mainSkill := skill.AttackSkill
+	skills := make([]skill.ID, 0, len(b.Bindings))
+	for _, binding := range b.Bindings {
+		sk := skill.ID(binding.Skill)
+		if binding.MinLevel > level || !available(sk) {
+			continue
+		}
+		if binding.Main {
+			mainSkill = sk
+			continue
+		}
+		skills = append(skills, sk)
+	}
+
+	return mainSkill, skills
+}
+
+// ShouldRespec returns true if any of the respec points matches the current level and skills
+func (b *LevelingBuildConfig) ShouldRespec(level int, skillLevel func(skill.ID) int) bool {
+	for _, r := range b.Respec {
+		if r.Level != level {
+			continue
+		}
+
+		matches := true
+		for sk, lvl := range r.RequiredSkills {
+			if skillLevel(skill.ID(sk)) < lvl {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+
+	return false
+}
+
+// BuildSkill is a skill.ID that can be written in the build files by name ("FireBolt", "Fire Bolt") or by numeric ID
+type BuildSkill skill.ID
+
+// buildSkillIDs are the skills by normalized name, a name shared by several skills has all of them sorted by ID
+var buildSkillIDs = indexBuildSkills(skill.SkillNames)
+
+// MarshalText writes the skill name, or the numeric ID when the skill has no name or its name is ambiguous
+func (s BuildSkill) MarshalText() ([]byte, error) {
+	if name, found := skill.SkillNames[skill.ID(s)]; found && len(buildSkillIDs[normalizeBuildName(name)]) == 1 {
+		return []byte(name), nil
+	}
+
+	return []byte(strconv.Itoa(int(s))), nil
+}
+
+func (s *BuildSkill) UnmarshalText(text []byte) error {
+	id, err := ParseBuildSkill(string(text))
+	if err != nil {
+		return err
+	}
+	*s = BuildSkill(id)
+
+	return nil
+}
+
+// UnmarshalJSON also accepts numeric IDs without quotes
+func (s *BuildSkill) UnmarshalJSON(b []byte) error {
+	var id int
+	if err := json.Unmarshal(b, &id); err == nil {
+		*s = BuildSkill(id)
+		return nil
+	}
+
+	var name string
+	if err := json.Unmarshal(b, &name); err != nil {
+		return fmt.Errorf("invalid skill %s", string(b))
+	}
+
+	return s.UnmarshalText([]byte(name))
+}
+
+// ParseBuildSkill returns the skill with the numeric ID or the name, names shared by several skills are rejected
+func ParseBuildSkill(name string) (skill.ID, error) {
+	if id, err := strconv.Atoi(name); err == nil {
+		return skill.ID(id), nil
+	}
+
+	return findBuildSkill(buildSkillIDs, name)
+}
+
+func findBuildSkill(index map[string][]skill.ID, name string) (skill.ID, error) {
+	ids := index[normalizeBuildName(name)]
+	switch len(ids) {
+	case 0:
+		return 0, fmt.Errorf("unknown skill %q", name)
+	case 1:
+		return ids[0], nil
+	}
+
+	return 0, fmt.Errorf("ambiguous skill %q, it's the name of the skills %v, use the numeric ID instead", name, ids)
+}
+
+func indexBuildSkills(names map[skill.ID]string) map[string][]skill.ID {
+	index := make(map[string][]skill.ID, len(names))
+	for id, name := range names {
+		key := normalizeBuildName(name)
+		index[key] = append(index[key], id)
+	}
+	for _, ids := range index {
+		slices.Sort(ids)
+	}
+
+	return index
+}
+
+// buildStats are the stats that can be assigned using stat points
+var buildStats = map[string]stat.ID{
+	"strength":  stat.Strength,
+	"energy":    stat.Energy,
+	"dexterity": stat.Dexterity,
+	"vitality":  stat.Vitality,
+}
+
+// BuildStat is one of the stats that can be assigned with stat points, written by name in the build files
+type BuildStat stat.ID
+
+func (s BuildStat) MarshalText() ([]byte, error) {
+	for name, id := range buildStats {
+		if id == stat.ID(s) {
+			return []byte(name), nil
+		}
+	}
+
+	return nil, fmt.Errorf("stat %d can't be assigned", s)
+}
+
+func (s *BuildStat) UnmarshalText(text []byte) error {
+	id, found := buildStats[normalizeBuildName(string(text))]
+	if !found {
+		return fmt.Errorf("unknown stat %q, valid stats are strength, dexterity, vitality and energy", string(text))
+	}
+	*s = BuildStat(id)
+
+	return nil
+}
+
+func normalizeBuildName(name string) string {
+	name = strings.ToLower(name)
+	return strings.NewReplacer(" ", "", "_", "", "-", "", "'", "").Replace(name)
 }
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hectorgimenez/d2go/pkg/data/skill"
+	"github.com/hectorgimenez/d2go/pkg/data/stat"
+)
+
+func TestParseBuildSkill(t *testing.T) {
+	name := skill.SkillNames[skill.FireBolt]
+
+	tests := []struct {
+		name    string
+		input   string
+		want    skill.ID
+		wantErr bool
+	}{
+		{name: "skill name", input: name, want: skill.FireBolt},
+		{name: "upper case", input: strings.ToUpper(name), want: skill.FireBolt},
+		{name: "without spaces", input: strings.ReplaceAll(name, " ", ""), want: skill.FireBolt},
+		{name: "numeric ID", input: strconv.Itoa(int(skill.FireBolt)), want: skill.FireBolt},
+		{name: "unknown skill", input: "Fire Boltz", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseBuildSkill(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got skill %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindBuildSkillAmbiguous(t *testing.T) {
+	index := indexBuildSkills(map[skill.ID]string{
+		1: "Fire Bolt",
+		7: "Teleport",
+		3: "teleport",
+	})
+
+	if id, err := findBuildSkill(index, "fire_bolt"); err != nil || id != 1 {
+		t.Errorf("got %d (%v), want 1", id, err)
+	}
+	if ids := index["teleport"]; !slices.Equal(ids, []skill.ID{3, 7}) {
+		t.Errorf("got ids %v, want them sorted", ids)
+	}
+	// Every call must give the same result, whatever the map order
+	for i := 0; i < 20; i++ {
+		if _, err := findBuildSkill(index, "Teleport"); err == nil || !strings.Contains(err.Error(), "ambiguous") {
+			t.Fatalf("got %v, want an ambiguous skill error", err)
+		}
+	}
+}
+
+func TestBuildSkillText(t *testing.T) {
+	text, err := BuildSkill(skill.FireBolt).MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got BuildSkill
+	if err = got.UnmarshalText(text); err != nil || got != BuildSkill(skill.FireBolt) {
+		t.Errorf("got %d (%v) reading %q back, want %d", got, err, text, skill.FireBolt)
+	}
+
+	// Skills without name are written by ID
+	if text, err = BuildSkill(9999).MarshalText(); err != nil || string(text) != "9999" {
+		t.Errorf("got %q (%v), want 9999", text, err)
+	}
+}
+
+func TestBuildStatText(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    BuildStat
+		wantErr bool
+	}{
+		{name: "strength", input: "strength", want: BuildStat(stat.Strength)},
+		{name: "upper case", input: "Dexterity", want: BuildStat(stat.Dexterity)},
+		{name: "vitality", input: "VITALITY", want: BuildStat(stat.Vitality)},
+		{name: "energy", input: "energy", want: BuildStat(stat.Energy)},
+		{name: "abbreviation", input: "vit", wantErr: true},
+		{name: "not assignable", input: "life", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got BuildStat
+			err := got.UnmarshalText([]byte(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got != tt.want {
+				t.Errorf("got stat %d, want %d", got, tt.want)
+			}
+
+			text, err := got.MarshalText()
+			if err != nil || string(text) != strings.ToLower(tt.input) {
+				t.Errorf("got %q (%v) writing it back, want %q", text, err, strings.ToLower(tt.input))
+			}
+		})
+	}
+
+	if _, err := BuildStat(stat.Life).MarshalText(); err == nil {
+		t.Error("expected an error writing a stat that can't be assigned")
+	}
+}
+
+func TestDecodeLevelingBuild(t *testing.T) {
+	fireBolt := skill.SkillNames[skill.FireBolt]
+	content := `{
+		"nips": ["sorceress_leveling.nip"],
+		"stats": [{"stat": "vitality", "points": 50}, {"stat": "Strength", "points": 30}],
+		"skills": [
+			{"minLevel": 1, "points": ["` + fireBolt + `", ` + strconv.Itoa(int(skill.FireBolt)) + `]},
+			{"minLevel": 30, "points": ["` + fireBolt + `"]}
+		],
+		"bindings": [{"skill": "` + fireBolt + `", "main": true}, {"skill": ` + strconv.Itoa(int(skill.FireBolt)) + `, "minLevel": 6}],
+		"respec": [{"level": 30, "requiredSkills": {"` + fireBolt + `": 5}}]
+	}`
+
+	var build LevelingBuildConfig
+	if err := json.Unmarshal([]byte(content), &build); err != nil {
+		t.Fatal(err)
+	}
+
+	wantStats := []LevelingStatTarget{{Stat: BuildStat(stat.Vitality), Points: 50}, {Stat: BuildStat(stat.Strength), Points: 30}}
+	if !slices.Equal(build.Stats, wantStats) {
+		t.Errorf("got stats %+v, want %+v", build.Stats, wantStats)
+	}
+	if plan := build.SkillPlan(10); !slices.Equal(plan, []skill.ID{skill.FireBolt, skill.FireBolt}) {
+		t.Errorf("got level 10 plan %v", plan)
+	}
+	if plan := build.SkillPlan(30); !slices.Equal(plan, []skill.ID{skill.FireBolt}) {
+		t.Errorf("got level 30 plan %v", plan)
+	}
+	available := func(skill.ID) bool { return true }
+	if main, bound := build.SkillBindings(5, available); main != skill.FireBolt || len(bound) != 0 {
+		t.Errorf("got main skill %d and bindings %v at level 5", main, bound)
+	}
+	if !build.ShouldRespec(30, func(skill.ID) int { return 5 }) || build.ShouldRespec(30, func(skill.ID) int { return 4 }) {
+		t.Error("respec must only happen with the required skill levels")
+	}
+
+	invalid := []string{
+		`{"stats": [{"stat": "life", "points": 10}]}`,
+		`{"skills": [{"minLevel": 1, "points": ["Not A Skill"]}]}`,
+		`{"bindings": [{"skill": true}]}`,
+	}
+	for _, content := range invalid {
+		if err := json.Unmarshal([]byte(content), &LevelingBuildConfig{}); err == nil {
+			t.Errorf("expected an error decoding %s", content)
+		}
+	}
+}
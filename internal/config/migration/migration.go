@@ -0,0 +1,227 @@
+// Package migration upgrades the koolo.yaml and character config.yaml files written by older versions to the current
+// config schema. Every file stores its schemaVersion, files without it are version 0.
+package migration
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const SchemaVersionKey = "schemaVersion"
+
+type Kind string
+
+const (
+	KindKoolo     Kind = "koolo"
+	KindCharacter Kind = "character"
+)
+
+// Migration upgrades a config document from Version-1 to Version. Bases are the root mappings of the profiles the
+// document extends, nearest first, as they are stored: migrations only read them to know the inherited settings.
+type Migration struct {
+	Version     int
+	Description string
+	Apply       func(root *yaml.Node, bases []*yaml.Node) error
+}
+
+// Result describes the migrations applied to a file
+type Result struct {
+	Path    string
+	From    int
+	To      int
+	Applied []string
+	Backup  string
+}
+
+var ErrNewerVersion = errors.New("config was written by a newer koolo version")
+
+// LatestVersion returns the schema version written by this koolo version
+func LatestVersion(kind Kind) int {
+	migrations := registry[kind]
+	if len(migrations) == 0 {
+		return 0
+	}
+
+	return migrations[len(migrations)-1].Version
+}
+
+// SchemaVersion returns the schema version stored in the document, 0 when it doesn't have one
+func SchemaVersion(root *yaml.Node) (int, error) {
+	value := mappingValue(root, SchemaVersionKey)
+	if value == nil {
+		return 0, nil
+	}
+
+	version, err := strconv.Atoi(value.Value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", SchemaVersionKey, value.Value, err)
+	}
+
+	return version, nil
+}
+
+// Migrate applies every pending migration to the document root mapping, returns the applied ones in order. Bases are
+// the profiles the document extends, nearest first, nil when it doesn't extend any.
+func Migrate(kind Kind, root *yaml.Node, bases []*yaml.Node) ([]Migration, error) {
+	if root.Kind != yaml.MappingNode {
+		return nil, errors.New("config root is not a mapping")
+	}
+
+	version, err := SchemaVersion(root)
+	if err != nil {
+		return nil, err
+	}
+	if latest := LatestVersion(kind); version > latest {
+		return nil, fmt.Errorf("%w: schema version %d, supported up to %d", ErrNewerVersion, version, latest)
+	}
+
+	applied := make([]Migration, 0)
+	for _, m := range registry[kind] {
+		if m.Version <= version {
+			continue
+		}
+		if err = m.Apply(root, bases); err != nil {
+			return applied, fmt.Errorf("migration to schema version %d (%s): %w", m.Version, m.Description, err)
+		}
+		setSchemaVersion(root, m.Version)
+		applied = append(applied, m)
+	}
+
+	return applied, nil
+}
+
+// MigrateFile upgrades the file in place when it's outdated. The original file is kept next to it as
+// <file>.v<version>.bak and every applied migration is appended to logPath. Character profiles extending another one
+// read their base profiles from the sibling folders.
+func MigrateFile(kind Kind, path, logPath string) (Result, error) {
+	result := Result{Path: path}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return result, err
+	}
+
+	var doc yaml.Node
+	if err = yaml.Unmarshal(content, &doc); err != nil {
+		return result, err
+	}
+	// Empty files are left as they are, there is nothing to migrate
+	if len(doc.Content) == 0 {
+		return result, nil
+	}
+
+	root := doc.Content[0]
+	if result.From, err = SchemaVersion(root); err != nil {
+		return result, err
+	}
+	result.To = result.From
+
+	var bases []*yaml.Node
+	if kind == KindCharacter {
+		bases = readBases(path, root)
+	}
+
+	applied, err := Migrate(kind, root, bases)
+	if err != nil || len(applied) == 0 {
+		return result, err
+	}
+	result.To = applied[len(applied)-1].Version
+	for _, m := range applied {
+		result.Applied = append(result.Applied, m.Description)
+	}
+
+	result.Backup = fmt.Sprintf("%s.v%d.bak", path, result.From)
+	if err = os.WriteFile(result.Backup, content, 0644); err != nil {
+		return result, fmt.Errorf("error writing config backup: %w", err)
+	}
+
+	migrated, err := encode(&doc)
+	if err != nil {
+		return result, err
+	}
+	if err = os.WriteFile(path, migrated, 0644); err != nil {
+		return result, fmt.Errorf("error writing migrated config: %w", err)
+	}
+
+	return result, appendLog(logPath, result, applied)
+}
+
+// readBases follows the extends chain of the profile stored at path, nearest base first. The chain stops at the first
+// base that can't be read or at a cycle, loading the profile reports those errors.
+func readBases(path string, root *yaml.Node) []*yaml.Node {
+	configDir := filepath.Dir(filepath.Dir(path))
+	visited := []string{filepath.Base(filepath.Dir(path))}
+
+	var bases []*yaml.Node
+	for node := root; ; {
+		extends := mappingValue(node, "extends")
+		if extends == nil || extends.Value == "" || slices.Contains(visited, extends.Value) {
+			return bases
+		}
+		visited = append(visited, extends.Value)
+
+		content, err := os.ReadFile(filepath.Join(configDir, extends.Value, "config.yaml"))
+		if err != nil {
+			return bases
+		}
+		var doc yaml.Node
+		if err = yaml.Unmarshal(content, &doc); err != nil || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+			return bases
+		}
+		node = doc.Content[0]
+		bases = append(bases, node)
+	}
+}
+
+func encode(doc *yaml.Node) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	encoder := yaml.NewEncoder(buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func appendLog(logPath string, result Result, applied []Migration) error {
+	f, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("error writing migration log: %w", err)
+	}
+	defer f.Close()
+
+	now := time.Now().Format(time.RFC3339)
+	for _, m := range applied {
+		if _, err = fmt.Fprintf(f, "%s %s: schema version %d: %s (backup %s)\n", now, result.Path, m.Version, m.Description, result.Backup); err != nil {
+			return fmt.Errorf("error writing migration log: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func setSchemaVersion(root *yaml.Node, version int) {
+	value := strconv.Itoa(version)
+	if node := mappingValue(root, SchemaVersionKey); node != nil {
+		node.Value = value
+		return
+	}
+
+	// The version is added as the first key, it's the first thing to check when reading a config by hand
+	root.Content = append([]*yaml.Node{
+		{Kind: yaml.ScalarNode, Tag: "!!str", Value: SchemaVersionKey},
+		{Kind: yaml.ScalarNode, Tag: "!!int", Value: value},
+	}, root.Content...)
+}
@@ -0,0 +1,191 @@
+package migration
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+// TestMigrationSteps applies every migration step to testdata/<kind>/v<version>.input.yaml and compares the result
+// with v<version>.golden.yaml
+func TestMigrationSteps(t *testing.T) {
+	for kind, migrations := range registry {
+		for _, m := range migrations {
+			t.Run(fmt.Sprintf("%s/v%d", kind, m.Version), func(t *testing.T) {
+				base := filepath.Join("testdata", string(kind), fmt.Sprintf("v%d", m.Version))
+				root := readRoot(t, base+".input.yaml")
+
+				if err := m.Apply(root, nil); err != nil {
+					t.Fatalf("migration failed: %v", err)
+				}
+				setSchemaVersion(root, m.Version)
+
+				got, err := encode(root)
+				if err != nil {
+					t.Fatalf("error encoding migrated config: %v", err)
+				}
+
+				goldenPath := base + ".golden.yaml"
+				if *update {
+					if err = os.WriteFile(goldenPath, got, 0644); err != nil {
+						t.Fatal(err)
+					}
+				}
+				want, err := os.ReadFile(goldenPath)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(got) != string(want) {
+					t.Errorf("migrated config doesn't match %s\ngot:\n%s\nwant:\n%s", goldenPath, got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	root := readRoot(t, filepath.Join("testdata", "character", "v1.input.yaml"))
+
+	applied, err := Migrate(KindCharacter, root, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != len(registry[KindCharacter]) {
+		t.Errorf("expected %d migrations applied, got %d", len(registry[KindCharacter]), len(applied))
+	}
+	if version, _ := SchemaVersion(root); version != LatestVersion(KindCharacter) {
+		t.Errorf("expected schema version %d, got %d", LatestVersion(KindCharacter), version)
+	}
+
+	// An up to date config is not migrated again
+	if applied, err = Migrate(KindCharacter, root, nil); err != nil || len(applied) != 0 {
+		t.Errorf("expected no migrations, got %d (%v)", len(applied), err)
+	}
+
+	setSchemaVersion(root, LatestVersion(KindCharacter)+1)
+	if _, err = Migrate(KindCharacter, root, nil); err == nil {
+		t.Error("expected an error migrating a config from a newer version")
+	}
+}
+
+func TestCopyWarcryBarbSettingsInherited(t *testing.T) {
+	tests := []struct {
+		name  string
+		root  string
+		bases []string
+		want  string
+	}{
+		{
+			name:  "class inherited from the base",
+			root:  "character:\n  berserker_barb:\n    use_howl: true\n",
+			bases: []string{"character:\n  class: warcry_barb\n"},
+			want:  "character:\n  berserker_barb:\n    use_howl: true\n  warcry_barb:\n    use_howl: true\n",
+		},
+		{
+			name:  "settings inherited from the bases, nearest first",
+			root:  "extends: base\n",
+			bases: []string{"character:\n  class: warcry_barb\n  berserker_barb:\n    howl_cooldown: 4\n", "character:\n  berserker_barb:\n    howl_cooldown: 6\n    use_battlecry: true\n"},
+			want:  "extends: base\ncharacter:\n  warcry_barb:\n    howl_cooldown: 4\n    use_battlecry: true\n",
+		},
+		{
+			name:  "inherited warcry settings are kept",
+			root:  "character:\n  class: warcry_barb\n  berserker_barb:\n    use_howl: true\n    howl_cooldown: 6\n",
+			bases: []string{"character:\n  warcry_barb:\n    howl_cooldown: 8\n"},
+			want:  "character:\n  class: warcry_barb\n  berserker_barb:\n    use_howl: true\n    howl_cooldown: 6\n  warcry_barb:\n    use_howl: true\n",
+		},
+		{
+			name:  "class overridden by the profile",
+			root:  "character:\n  class: berserker\n",
+			bases: []string{"character:\n  class: warcry_barb\n  berserker_barb:\n    use_howl: true\n"},
+			want:  "character:\n  class: berserker\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := parseRoot(t, tt.root)
+			bases := make([]*yaml.Node, 0, len(tt.bases))
+			for _, base := range tt.bases {
+				bases = append(bases, parseRoot(t, base))
+			}
+
+			if err := copyWarcryBarbSettings(root, bases); err != nil {
+				t.Fatal(err)
+			}
+			got, err := encode(root)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMigrateFileReadsBases(t *testing.T) {
+	dir := t.TempDir()
+	writeProfile(t, dir, "base", "schemaVersion: 2\nextends: child\ncharacter:\n  class: warcry_barb\n  berserker_barb:\n    use_howl: true\n")
+	writeProfile(t, dir, "child", "schemaVersion: 1\nextends: base\ncharacter:\n  berserker_barb:\n    howl_cooldown: 4\n")
+
+	path := filepath.Join(dir, "child", "config.yaml")
+	result, err := MigrateFile(KindCharacter, path, filepath.Join(dir, "migrations.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.From != 1 || result.To != LatestVersion(KindCharacter) {
+		t.Errorf("expected migration from 1 to %d, got %d to %d", LatestVersion(KindCharacter), result.From, result.To)
+	}
+
+	got := readRoot(t, path)
+	warcry := mappingValue(mappingValue(got, "character"), "warcry_barb")
+	if value := mappingValue(warcry, "use_howl"); value == nil || value.Value != "true" {
+		t.Errorf("use_howl not copied from the base profile: %v", value)
+	}
+	if value := mappingValue(warcry, "howl_cooldown"); value == nil || value.Value != "4" {
+		t.Errorf("howl_cooldown not copied from the profile: %v", value)
+	}
+}
+
+func readRoot(t *testing.T, path string) *yaml.Node {
+	t.Helper()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc yaml.Node
+	if err = yaml.Unmarshal(content, &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	return doc.Content[0]
+}
+
+func parseRoot(t *testing.T, content string) *yaml.Node {
+	t.Helper()
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	return doc.Content[0]
+}
+
+func writeProfile(t *testing.T, dir, name, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Join(dir, name), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name, "config.yaml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
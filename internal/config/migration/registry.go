@@ -0,0 +1,136 @@
+package migration
+
+import (
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// registry contains the migrations of every config kind, sorted by version. New migrations are appended with the
+// next version, released ones must never change since files already migrated won't run them again.
+var registry = map[Kind][]Migration{
+	KindKoolo: {
+		{Version: 1, Description: "add schema version", Apply: func(*yaml.Node, []*yaml.Node) error { return nil }},
+	},
+	KindCharacter: {
+		{Version: 1, Description: "move muling.switchToMule to muling.muleProfiles", Apply: moveSwitchToMule},
+		{Version: 2, Description: "copy berserker_barb settings to warcry_barb for warcry barbarians", Apply: copyWarcryBarbSettings},
+	},
+}
+
+// moveSwitchToMule converts the single mule profile to the mule profiles list, it's only used when the list is empty
+func moveSwitchToMule(root *yaml.Node, _ []*yaml.Node) error {
+	muling := mappingValue(root, "muling")
+	if muling == nil || muling.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	switchToMule := mappingValue(muling, "switchToMule")
+	removeKey(muling, "switchToMule")
+	if switchToMule == nil || switchToMule.Value == "" {
+		return nil
+	}
+
+	profiles := mappingValue(muling, "muleProfiles")
+	if profiles != nil && len(profiles.Content) > 0 {
+		return nil
+	}
+
+	list := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: []*yaml.Node{
+		{Kind: yaml.ScalarNode, Tag: "!!str", Value: switchToMule.Value},
+	}}
+	setKey(muling, "muleProfiles", list)
+
+	return nil
+}
+
+// warcryBarbSharedKeys are the berserker_barb settings warcry barbarians read from warcry_barb
+var warcryBarbSharedKeys = []string{
+	"find_item_switch",
+	"skip_potion_pickup_in_travincal",
+	"use_howl",
+	"howl_cooldown",
+	"howl_min_monsters",
+	"use_battlecry",
+	"battlecry_cooldown",
+	"battlecry_min_monsters",
+}
+
+// copyWarcryBarbSettings fills the warcry_barb section of warcry barbarians configured through berserker_barb, the
+// settings already in warcry_barb are kept. The class and both sections can be inherited from the base profiles, the
+// copied settings are written to the profile itself.
+func copyWarcryBarbSettings(root *yaml.Node, bases []*yaml.Node) error {
+	profiles := append([]*yaml.Node{root}, bases...)
+	class := inheritedValue(profiles, "character", "class")
+	if class == nil || class.Value != "warcry_barb" {
+		return nil
+	}
+
+	var character, warcry *yaml.Node
+	for _, key := range warcryBarbSharedKeys {
+		value := inheritedValue(profiles, "character", "berserker_barb", key)
+		if value == nil || value.Kind != yaml.ScalarNode || inheritedValue(profiles, "character", "warcry_barb", key) != nil {
+			continue
+		}
+
+		if character == nil {
+			if character = mappingValue(root, "character"); character == nil || character.Kind != yaml.MappingNode {
+				character = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+				setKey(root, "character", character)
+			}
+			if warcry = mappingValue(character, "warcry_barb"); warcry == nil || warcry.Kind != yaml.MappingNode {
+				warcry = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+				setKey(character, "warcry_barb", warcry)
+			}
+		}
+		setKey(warcry, key, &yaml.Node{Kind: yaml.ScalarNode, Tag: value.Tag, Value: value.Value})
+	}
+
+	return nil
+}
+
+// inheritedValue returns the value at path of the first profile setting it, profiles are sorted nearest first
+func inheritedValue(profiles []*yaml.Node, path ...string) *yaml.Node {
+	for _, node := range profiles {
+		for _, key := range path {
+			node = mappingValue(node, key)
+		}
+		if node != nil {
+			return node
+		}
+	}
+
+	return nil
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+func setKey(node *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content[i+1] = value
+			return
+		}
+	}
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+}
+
+func removeKey(node *yaml.Node, key string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content = slices.Delete(node.Content, i, i+2)
+			return
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package config
+
+const (
+	NotificationActionSend   = "send"
+	NotificationActionDigest = "digest"
+	NotificationActionMute   = "mute"
+)
+
+// NotificationsCfg controls how events are routed to the remote notifiers (Discord, Telegram).
+// When disabled, the legacy per-notifier behaviour is kept (Discord boolean toggles, Telegram sends everything).
+type NotificationsCfg struct {
+	Enabled       bool                   `yaml:"enabled"`
+	DefaultAction string                 `yaml:"defaultAction"` // action for events not matching any rule, defaults to mute
+	Rules         []NotificationRule     `yaml:"rules"`
+	QuietHours    NotificationQuietHours `yaml:"quietHours"`
+	RateLimit     NotificationRateLimit  `yaml:"rateLimit"`
+	Digest        NotificationDigest     `yaml:"digest"`
+}
+
+// NotificationRule matches events, all the non-empty filters must match. First matching rule wins.
+type NotificationRule struct {
+	Name          string   `yaml:"name"`
+	Events        []string `yaml:"events"`        // game_created, game_finished, run_started, run_finished, item_stashed, item_blacklisted, game_paused, level_up, quest_completed, difficulty_changed, merc_died, player_died, screenshot
+	Channels      []string `yaml:"channels"`      // discord, telegram
+	Supervisors   []string `yaml:"supervisors"`   // supervisor (config folder) names
+	Reasons       []string `yaml:"reasons"`       // finish reasons: ok, death, chicken, merc chicken, error
+	DropQualities []string `yaml:"dropQualities"` // item qualities: unique, set, rare, magic, crafted...
+	NipMatch      string   `yaml:"nipMatch"`      // case-insensitive substring matched against the NIP rule or rule file
+	Action        string   `yaml:"action"`        // send, digest or mute
+}
+
+// NotificationQuietHours delays (digest) or drops notifications during the given time range, in local time.
+type NotificationQuietHours struct {
+	Enabled bool   `yaml:"enabled"`
+	Start   string `yaml:"start"` // HH:MM
+	End     string `yaml:"end"`   // HH:MM, can be lower than start to cross midnight
+}
+
+// NotificationRateLimit is applied per channel, messages over the limit are moved to the digest (if enabled).
+type NotificationRateLimit struct {
+	MaxMessages   int `yaml:"maxMessages"`
+	PeriodSeconds int `yaml:"periodSeconds"`
+}
+
+type NotificationDigest struct {
+	Enabled         bool `yaml:"enabled"`
+	IntervalMinutes int  `yaml:"intervalMinutes"`
+	MaxLines        int  `yaml:"maxLines"`
+}
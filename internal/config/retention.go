@@ -0,0 +1,83 @@
+package config
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/hectorgimenez/koolo/internal/retention"
+)
+
+// RetentionCfg are the retention policies of the folders koolo writes to while running. It's disabled unless
+// configured, so existing logs are never removed without asking.
+type RetentionCfg struct {
+	Enabled         bool            `yaml:"enabled"`
+	IntervalMinutes int             `yaml:"intervalMinutes"`
+	Logs            RetentionPolicy `yaml:"logs"`
+	Screenshots     RetentionPolicy `yaml:"screenshots"`
+	Droplogs        RetentionPolicy `yaml:"droplogs"`
+}
+
+// RetentionPolicy rules are disabled when zero
+type RetentionPolicy struct {
+	MaxAgeDays        int `yaml:"maxAgeDays"`
+	MaxSizeMB         int `yaml:"maxSizeMB"`
+	CompressAfterDays int `yaml:"compressAfterDays"`
+	KeepNewest        int `yaml:"keepNewest"`
+}
+
+func (p RetentionPolicy) policy() retention.Policy {
+	return retention.Policy{
+		MaxAge:        time.Duration(p.MaxAgeDays) * 24 * time.Hour,
+		MaxTotalSize:  int64(p.MaxSizeMB) * 1024 * 1024,
+		CompressAfter: time.Duration(p.CompressAfterDays) * 24 * time.Hour,
+		KeepNewest:    p.KeepNewest,
+	}
+}
+
+// LogDirectory returns the folder of the log files, the droplogs are in its droplogs subfolder
+func LogDirectory() string {
+	if Koolo == nil || Koolo.LogSaveDirectory == "" {
+		return "logs"
+	}
+
+	return Koolo.LogSaveDirectory
+}
+
+// RetentionSettings returns the retention of the logs, the error screenshots and the droplogs with the current config
+func RetentionSettings() retention.Settings {
+	cfgMux.RLock()
+	defer cfgMux.RUnlock()
+
+	var cfg RetentionCfg
+	if Koolo != nil {
+		cfg = Koolo.Retention
+	}
+
+	return retention.Settings{
+		Enabled:  cfg.Enabled,
+		Interval: time.Duration(cfg.IntervalMinutes) * time.Minute,
+		Targets: []retention.Target{
+			{
+				Name:     "logs",
+				Dir:      LogDirectory(),
+				Patterns: []string{"*.txt"},
+				Compress: true,
+				Policy:   cfg.Logs.policy(),
+			},
+			{
+				// Written by the event listener when debug screenshots are enabled
+				Name:     "screenshots",
+				Dir:      "screenshots",
+				Patterns: []string{"*.jpeg", "*.jpg", "*.png"},
+				Policy:   cfg.Screenshots.policy(),
+			},
+			{
+				Name:     "droplogs",
+				Dir:      filepath.Join(LogDirectory(), "droplogs"),
+				Patterns: []string{"*.jsonl"},
+				Compress: true,
+				Policy:   cfg.Droplogs.policy(),
+			},
+		},
+	}
+}
@@ -0,0 +1,80 @@
+package secrets
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// PassphraseEnv contains the master passphrase, used when there is no keyfile
+	PassphraseEnv = "KOOLO_SECRETS_PASSPHRASE"
+	// KeyfileEnv overrides the keyfile path
+	KeyfileEnv = "KOOLO_SECRETS_KEYFILE"
+)
+
+var ErrLocked = errors.New("secrets store is locked, create a keyfile or set the " + PassphraseEnv + " environment variable")
+
+// MasterKey returns the master key from the keyfile, or from the passphrase environment variable when there is no
+// keyfile. The keyfile is read from KOOLO_SECRETS_KEYFILE, defaultKeyfile is used when it's not set.
+func MasterKey(defaultKeyfile string) ([]byte, error) {
+	keyfile := KeyfilePath(defaultKeyfile)
+
+	content, err := os.ReadFile(keyfile)
+	if err == nil {
+		key := []byte(strings.TrimSpace(string(content)))
+		if len(key) == 0 {
+			return nil, fmt.Errorf("keyfile %s is empty", keyfile)
+		}
+		return key, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("error reading keyfile %s: %w", keyfile, err)
+	}
+
+	if passphrase := os.Getenv(PassphraseEnv); passphrase != "" {
+		return []byte(passphrase), nil
+	}
+
+	return nil, ErrLocked
+}
+
+// KeyfilePath returns the keyfile set in KOOLO_SECRETS_KEYFILE, or defaultKeyfile when it's not set
+func KeyfilePath(defaultKeyfile string) string {
+	if keyfile := os.Getenv(KeyfileEnv); keyfile != "" {
+		return keyfile
+	}
+
+	return defaultKeyfile
+}
+
+// GenerateKeyfile writes a random keyfile, its folder is created when missing and existing keyfiles are never
+// overwritten
+func GenerateKeyfile(path string) ([]byte, error) {
+	raw := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, raw); err != nil {
+		return nil, err
+	}
+	key := []byte(hex.EncodeToString(raw))
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("error creating keyfile folder: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("error creating keyfile: %w", err)
+	}
+	defer f.Close()
+
+	if _, err = f.Write(key); err != nil {
+		return nil, fmt.Errorf("error writing keyfile: %w", err)
+	}
+
+	return key, nil
+}
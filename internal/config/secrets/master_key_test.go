@@ -0,0 +1,60 @@
+package secrets
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMasterKey(t *testing.T) {
+	dir := t.TempDir()
+	keyfile := filepath.Join(dir, "secrets.key")
+	t.Setenv(KeyfileEnv, "")
+	t.Setenv(PassphraseEnv, "")
+
+	if _, err := MasterKey(keyfile); !errors.Is(err, ErrLocked) {
+		t.Errorf("got %v without keyfile nor passphrase, want ErrLocked", err)
+	}
+
+	t.Setenv(PassphraseEnv, "passphrase")
+	if key, err := MasterKey(keyfile); err != nil || string(key) != "passphrase" {
+		t.Errorf("got %q, %v, want the passphrase", key, err)
+	}
+
+	// The keyfile wins over the passphrase
+	if err := os.WriteFile(keyfile, []byte("file key\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if key, err := MasterKey(keyfile); err != nil || string(key) != "file key" {
+		t.Errorf("got %q, %v, want the keyfile content", key, err)
+	}
+
+	envKeyfile := filepath.Join(dir, "env.key")
+	if err := os.WriteFile(envKeyfile, []byte("env key"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(KeyfileEnv, envKeyfile)
+	if key, err := MasterKey(keyfile); err != nil || string(key) != "env key" {
+		t.Errorf("got %q, %v, want the %s content", key, err, KeyfileEnv)
+	}
+}
+
+func TestGenerateKeyfile(t *testing.T) {
+	keyfile := filepath.Join(t.TempDir(), "koolo", "secrets.key")
+
+	key, err := GenerateKeyfile(keyfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(key) != 64 {
+		t.Errorf("got a key of %d characters, want 64", len(key))
+	}
+	if content, _ := os.ReadFile(keyfile); string(content) != string(key) {
+		t.Errorf("keyfile content %q is not the key", content)
+	}
+
+	if _, err = GenerateKeyfile(keyfile); err == nil {
+		t.Error("existing keyfile overwritten")
+	}
+}
@@ -0,0 +1,56 @@
+package secrets
+
+import (
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Redacted replaces the secret values wherever they are displayed
+const Redacted = "********"
+
+// Values shorter than this are not redacted, they would hide unrelated text
+const minRedactLength = 4
+
+var (
+	knownMu     sync.RWMutex
+	knownValues []string
+)
+
+// Register adds values to redact from logs and any other output
+func Register(values ...string) {
+	knownMu.Lock()
+	defer knownMu.Unlock()
+
+	for _, value := range values {
+		if len(value) < minRedactLength || IsReference(value) || slices.Contains(knownValues, value) {
+			continue
+		}
+		knownValues = append(knownValues, value)
+	}
+	// Longer values first, a secret containing another one is redacted as a whole
+	slices.SortFunc(knownValues, func(a, b string) int {
+		return len(b) - len(a)
+	})
+}
+
+// Redact replaces every registered secret value in the text
+func Redact(text string) string {
+	knownMu.RLock()
+	defer knownMu.RUnlock()
+
+	for _, value := range knownValues {
+		text = strings.ReplaceAll(text, value, Redacted)
+	}
+
+	return text
+}
+
+// Unredact returns the current value when the submitted one is the redacted placeholder shown to the user
+func Unredact(submitted, current string) string {
+	if submitted == Redacted {
+		return current
+	}
+
+	return submitted
+}
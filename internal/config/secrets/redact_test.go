@@ -0,0 +1,35 @@
+package secrets
+
+import "testing"
+
+func TestRedact(t *testing.T) {
+	Register("hunter22", "abc", "secret://password", "hunter22-long")
+
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"login with hunter22", "login with " + Redacted},
+		{"token hunter22-long", "token " + Redacted},
+		// Too short to be redacted without hiding unrelated text
+		{"abc", "abc"},
+		// References are not secret
+		{"password: secret://password", "password: secret://password"},
+		{"nothing to hide", "nothing to hide"},
+	}
+
+	for _, tt := range tests {
+		if got := Redact(tt.text); got != tt.want {
+			t.Errorf("Redact(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestUnredact(t *testing.T) {
+	if got := Unredact(Redacted, "current"); got != "current" {
+		t.Errorf("got %q, want current", got)
+	}
+	if got := Unredact("new", "current"); got != "new" {
+		t.Errorf("got %q, want new", got)
+	}
+}
@@ -0,0 +1,278 @@
+// Package secrets stores the credentials referenced from the config files, encrypted with a master passphrase or
+// keyfile. Config values like "secret://name" are resolved from the store when the config is loaded.
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	ReferencePrefix = "secret://"
+	storeVersion    = 1
+	checkValue      = "koolo-secrets"
+	saltSize        = 16
+	keySize         = 32
+)
+
+// scrypt parameters, recommended for interactive logins
+const (
+	scryptN = 32768
+	scryptR = 8
+	scryptP = 1
+)
+
+var (
+	ErrWrongKey = errors.New("wrong master passphrase or keyfile")
+	ErrNotFound = errors.New("secret not found")
+)
+
+type storeFile struct {
+	Version int               `json:"version"`
+	Salt    []byte            `json:"salt"`
+	Check   []byte            `json:"check"`
+	Secrets map[string][]byte `json:"secrets"`
+}
+
+// Store is the encrypted secrets file, values are decrypted on demand and never written in plain text
+type Store struct {
+	mu   sync.Mutex
+	path string
+	aead cipher.AEAD
+	file storeFile
+}
+
+// Open opens the store with the master key, the store is created on the first write when the file doesn't exist
+func Open(path string, masterKey []byte) (*Store, error) {
+	s := &Store{path: path}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		if err = s.init(masterKey); err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading secrets store: %w", err)
+	}
+
+	if err = json.Unmarshal(content, &s.file); err != nil {
+		return nil, fmt.Errorf("error reading secrets store: %w", err)
+	}
+	if s.file.Version != storeVersion {
+		return nil, fmt.Errorf("unsupported secrets store version %d", s.file.Version)
+	}
+	if s.aead, err = newAEAD(masterKey, s.file.Salt); err != nil {
+		return nil, err
+	}
+	if value, err := s.open("", s.file.Check); err != nil || value != checkValue {
+		return nil, ErrWrongKey
+	}
+	if s.file.Secrets == nil {
+		s.file.Secrets = make(map[string][]byte)
+	}
+
+	return s, nil
+}
+
+// init prepares an empty store encrypted with the master key
+func (s *Store) init(masterKey []byte) error {
+	salt := make([]byte, saltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return err
+	}
+
+	aead, err := newAEAD(masterKey, salt)
+	if err != nil {
+		return err
+	}
+
+	s.aead = aead
+	s.file = storeFile{Version: storeVersion, Salt: salt, Secrets: make(map[string][]byte)}
+	s.file.Check, err = s.seal("", checkValue)
+
+	return err
+}
+
+// Get returns the secret value
+func (s *Store) Get(name string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sealed, found := s.file.Secrets[name]
+	if !found {
+		return "", fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+
+	value, err := s.open(name, sealed)
+	if err != nil {
+		return "", fmt.Errorf("error decrypting secret %s: %w", name, err)
+	}
+	Register(value)
+
+	return value, nil
+}
+
+// Set encrypts and stores the secret, replacing the previous value
+func (s *Store) Set(name, value string) error {
+	if name == "" || strings.ContainsAny(name, " /\\") {
+		return fmt.Errorf("invalid secret name %q", name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sealed, err := s.seal(name, value)
+	if err != nil {
+		return err
+	}
+	s.file.Secrets[name] = sealed
+	Register(value)
+
+	return s.save()
+}
+
+// Delete removes the secret, config values referencing it will fail to load
+func (s *Store) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, found := s.file.Secrets[name]; !found {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	delete(s.file.Secrets, name)
+
+	return s.save()
+}
+
+// Names returns the stored secret names, sorted
+func (s *Store) Names() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	names := make([]string, 0, len(s.file.Secrets))
+	for name := range s.file.Secrets {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// RotateKey encrypts every secret again with a new master key and salt
+func (s *Store) RotateKey(newMasterKey []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	values := make(map[string]string, len(s.file.Secrets))
+	for name, sealed := range s.file.Secrets {
+		value, err := s.open(name, sealed)
+		if err != nil {
+			return fmt.Errorf("error decrypting secret %s: %w", name, err)
+		}
+		values[name] = value
+	}
+
+	rotated := &Store{path: s.path}
+	if err := rotated.init(newMasterKey); err != nil {
+		return err
+	}
+	for name, value := range values {
+		sealed, err := rotated.seal(name, value)
+		if err != nil {
+			return err
+		}
+		rotated.file.Secrets[name] = sealed
+	}
+
+	s.aead = rotated.aead
+	s.file = rotated.file
+
+	return s.save()
+}
+
+func (s *Store) save() error {
+	content, err := json.MarshalIndent(s.file, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Written to a temporary file first, a failed write must never corrupt the store
+	tmp := s.path + ".tmp"
+	if err = os.WriteFile(tmp, content, 0600); err != nil {
+		return fmt.Errorf("error writing secrets store: %w", err)
+	}
+	if err = os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("error writing secrets store: %w", err)
+	}
+
+	return nil
+}
+
+// seal encrypts the value bound to the secret name, so values can't be swapped between names
+func (s *Store) seal(name, value string) ([]byte, error) {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return s.aead.Seal(nonce, nonce, []byte(value), []byte(name)), nil
+}
+
+func (s *Store) open(name string, sealed []byte) (string, error) {
+	if len(sealed) < s.aead.NonceSize() {
+		return "", errors.New("invalid encrypted value")
+	}
+
+	nonce, ciphertext := sealed[:s.aead.NonceSize()], sealed[s.aead.NonceSize():]
+	value, err := s.aead.Open(nil, nonce, ciphertext, []byte(name))
+	if err != nil {
+		return "", err
+	}
+
+	return string(value), nil
+}
+
+func newAEAD(masterKey, salt []byte) (cipher.AEAD, error) {
+	if len(masterKey) == 0 {
+		return nil, errors.New("empty master key")
+	}
+
+	key, err := scrypt.Key(masterKey, salt, scryptN, scryptR, scryptP, keySize)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// IsReference returns true when the config value is a reference to a stored secret
+func IsReference(value string) bool {
+	return strings.HasPrefix(value, ReferencePrefix)
+}
+
+// Reference returns the config value referencing the secret
+func Reference(name string) string {
+	return ReferencePrefix + name
+}
+
+// ReferenceName returns the secret name of a reference
+func ReferenceName(reference string) string {
+	return strings.TrimPrefix(reference, ReferencePrefix)
+}
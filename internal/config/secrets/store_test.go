@@ -0,0 +1,196 @@
+package secrets
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestStoreRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.json")
+	key := []byte("master key")
+
+	store, err := Open(path, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = store.Set("koolo.password", "hunter22"); err != nil {
+		t.Fatal(err)
+	}
+	if err = store.Set("discord.token", "token value"); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(content), "hunter22") || strings.Contains(string(content), "token value") {
+		t.Fatal("secret values are written in plain text")
+	}
+
+	reopened, err := Open(path, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reopened.Names(); !slices.Equal(got, []string{"discord.token", "koolo.password"}) {
+		t.Errorf("got names %v", got)
+	}
+	if value, err := reopened.Get("koolo.password"); err != nil || value != "hunter22" {
+		t.Errorf("got %q, %v, want hunter22", value, err)
+	}
+
+	if err = reopened.Delete("discord.token"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = reopened.Get("discord.token"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("got %v, want ErrNotFound", err)
+	}
+}
+
+func TestStoreWrongKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.json")
+
+	store, err := Open(path, []byte("master key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = store.Set("password", "hunter22"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Open(path, []byte("other key")); !errors.Is(err, ErrWrongKey) {
+		t.Errorf("got %v, want ErrWrongKey", err)
+	}
+}
+
+func TestStoreTampered(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.json")
+	key := []byte("master key")
+
+	store, err := Open(path, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = store.Set("password", "hunter22"); err != nil {
+		t.Fatal(err)
+	}
+	if err = store.Set("token", "token value"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		tamper func(f *storeFile)
+	}{
+		{
+			name: "flipped ciphertext bit",
+			tamper: func(f *storeFile) {
+				f.Secrets["password"][len(f.Secrets["password"])-1] ^= 1
+			},
+		},
+		{
+			name: "swapped values",
+			tamper: func(f *storeFile) {
+				f.Secrets["password"], f.Secrets["token"] = f.Secrets["token"], f.Secrets["password"]
+			},
+		},
+		{
+			name: "truncated value",
+			tamper: func(f *storeFile) {
+				f.Secrets["password"] = f.Secrets["password"][:4]
+			},
+		},
+	}
+
+	original, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var f storeFile
+			if err := json.Unmarshal(original, &f); err != nil {
+				t.Fatal(err)
+			}
+			tt.tamper(&f)
+			writeStoreFile(t, path, f)
+
+			tampered, err := Open(path, key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if value, err := tampered.Get("password"); err == nil {
+				t.Errorf("tampered secret decrypted as %q", value)
+			}
+		})
+	}
+
+	t.Run("tampered check value", func(t *testing.T) {
+		var f storeFile
+		if err := json.Unmarshal(original, &f); err != nil {
+			t.Fatal(err)
+		}
+		f.Check[len(f.Check)-1] ^= 1
+		writeStoreFile(t, path, f)
+
+		if _, err := Open(path, key); !errors.Is(err, ErrWrongKey) {
+			t.Errorf("got %v, want ErrWrongKey", err)
+		}
+	})
+}
+
+func TestStoreRotateKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.json")
+
+	store, err := Open(path, []byte("old key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = store.Set("password", "hunter22"); err != nil {
+		t.Fatal(err)
+	}
+	if err = store.RotateKey([]byte("new key")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Open(path, []byte("old key")); !errors.Is(err, ErrWrongKey) {
+		t.Errorf("got %v with the old key, want ErrWrongKey", err)
+	}
+	rotated, err := Open(path, []byte("new key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value, err := rotated.Get("password"); err != nil || value != "hunter22" {
+		t.Errorf("got %q, %v, want hunter22", value, err)
+	}
+}
+
+func TestStoreInvalidName(t *testing.T) {
+	store, err := Open(filepath.Join(t.TempDir(), "secrets.json"), []byte("master key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"", "with space", "../password", `a\b`} {
+		if err = store.Set(name, "value"); err == nil {
+			t.Errorf("secret name %q accepted", name)
+		}
+	}
+}
+
+func writeStoreFile(t *testing.T, path string, f storeFile) {
+	t.Helper()
+
+	content, err := json.Marshal(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(path, content, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	inventoryLockRows    = 4
+	inventoryLockColumns = 10
+)
+
+var beltColumnTypes = []string{"healing", "mana", "rejuvenation"}
+
+// Disabled returns true when the profile has validation errors, disabled profiles can't be started
+func (c *CharacterCfg) Disabled() bool {
+	return len(c.Runtime.ValidationErrors) > 0
+}
+
+// validateProfile collects every problem of the profile settings, an empty result means the profile is valid
+func validateProfile(c *CharacterCfg) []error {
+	var errs []error
+
+	for _, r := range c.Game.Runs {
+		if !isKnownRun(r) {
+			errs = append(errs, fmt.Errorf("game.runs: unknown run %q", r))
+		}
+	}
+
+	for name, value := range map[string]int{
+		"healingPotionAt":     c.Health.HealingPotionAt,
+		"manaPotionAt":        c.Health.ManaPotionAt,
+		"rejuvPotionAtLife":   c.Health.RejuvPotionAtLife,
+		"rejuvPotionAtMana":   c.Health.RejuvPotionAtMana,
+		"mercHealingPotionAt": c.Health.MercHealingPotionAt,
+		"mercRejuvPotionAt":   c.Health.MercRejuvPotionAt,
+		"chickenAt":           c.Health.ChickenAt,
+		"townChickenAt":       c.Health.TownChickenAt,
+		"mercChickenAt":       c.Health.MercChickenAt,
+	} {
+		if value < 0 || value > 100 {
+			errs = append(errs, fmt.Errorf("health.%s: %d is not a percentage between 0 and 100", name, value))
+		}
+	}
+
+	errs = append(errs, validateInventoryLock(c.Inventory.InventoryLock)...)
+
+	for i, column := range c.Inventory.BeltColumns {
+		if !slices.Contains(beltColumnTypes, strings.ToLower(column)) {
+			errs = append(errs, fmt.Errorf("inventory.beltColumns[%d]: %q is not one of %s", i, column, strings.Join(beltColumnTypes, ", ")))
+		}
+	}
+
+	errs = append(errs, validateScheduler(c.Scheduler)...)
+
+	// Errors are sorted since health percentages are checked iterating a map
+	slices.SortFunc(errs, func(a, b error) int {
+		return strings.Compare(a.Error(), b.Error())
+	})
+
+	return errs
+}
+
+func isKnownRun(r Run) bool {
+	if _, found := AvailableRuns[r]; found {
+		return true
+	}
+
+	return slices.Contains(SequencerRuns, r)
+}
+
+// validateInventoryLock checks the lock is empty or has the inventory shape with 0 (locked) and 1 (unlocked) values
+func validateInventoryLock(lock [][]int) []error {
+	if len(lock) == 0 {
+		return nil
+	}
+
+	var errs []error
+	if len(lock) != inventoryLockRows {
+		errs = append(errs, fmt.Errorf("inventory.inventoryLock: expected %d rows, got %d", inventoryLockRows, len(lock)))
+	}
+	for y, row := range lock {
+		if len(row) != inventoryLockColumns {
+			errs = append(errs, fmt.Errorf("inventory.inventoryLock[%d]: expected %d columns, got %d", y, inventoryLockColumns, len(row)))
+		}
+		for x, value := range row {
+			if value != 0 && value != 1 {
+				errs = append(errs, fmt.Errorf("inventory.inventoryLock[%d][%d]: %d is not 0 (locked) or 1 (unlocked)", y, x, value))
+			}
+		}
+	}
+
+	return errs
+}
+
+func validateScheduler(scheduler Scheduler) []error {
+	if !scheduler.Enabled {
+		return nil
+	}
+
+	var errs []error
+	for i, day := range scheduler.Days {
+		if day.DayOfWeek < 0 || day.DayOfWeek > 6 {
+			errs = append(errs, fmt.Errorf("scheduler.days[%d].dayOfWeek: %d is not between 0 (sunday) and 6 (saturday)", i, day.DayOfWeek))
+		}
+		for j, timeRange := range day.TimeRanges {
+			start := timeRange.Start.Hour()*60 + timeRange.Start.Minute()
+			end := timeRange.End.Hour()*60 + timeRange.End.Minute()
+			if start >= end {
+				errs = append(errs, fmt.Errorf("scheduler.days[%d].timeRange[%d]: start %s is not before end %s", i, j, timeRange.Start.Format("15:04"), timeRange.End.Format("15:04")))
+			}
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,202 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidateProfile(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(cfg *CharacterCfg)
+		want   []string
+	}{
+		{
+			name:   "valid profile",
+			change: func(cfg *CharacterCfg) {},
+		},
+		{
+			name:   "unknown run",
+			change: func(cfg *CharacterCfg) { cfg.Game.Runs = []Run{"pit", "cowz"} },
+			want:   []string{`game.runs: unknown run "cowz"`},
+		},
+		{
+			name: "health percentages",
+			change: func(cfg *CharacterCfg) {
+				cfg.Health.ChickenAt = -1
+				cfg.Health.HealingPotionAt = 101
+				cfg.Health.ManaPotionAt = 100
+			},
+			want: []string{
+				"health.chickenAt: -1 is not a percentage between 0 and 100",
+				"health.healingPotionAt: 101 is not a percentage between 0 and 100",
+			},
+		},
+		{
+			name: "belt columns are case insensitive",
+			change: func(cfg *CharacterCfg) {
+				cfg.Inventory.BeltColumns = BeltColumns{"Healing", "MANA", "rejuvenation", "healing"}
+			},
+		},
+		{
+			name:   "unknown belt column",
+			change: func(cfg *CharacterCfg) { cfg.Inventory.BeltColumns[3] = "stamina" },
+			want:   []string{`inventory.beltColumns[3]: "stamina" is not one of healing, mana, rejuvenation`},
+		},
+		{
+			name: "every problem is reported sorted",
+			change: func(cfg *CharacterCfg) {
+				cfg.Game.Runs = []Run{"unknown"}
+				cfg.Health.MercChickenAt = 200
+				cfg.Inventory.InventoryLock = [][]int{{2}}
+			},
+			want: []string{
+				`game.runs: unknown run "unknown"`,
+				"health.mercChickenAt: 200 is not a percentage between 0 and 100",
+				"inventory.inventoryLock: expected 4 rows, got 1",
+				"inventory.inventoryLock[0]: expected 10 columns, got 1",
+				"inventory.inventoryLock[0][0]: 2 is not 0 (locked) or 1 (unlocked)",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validTestProfile()
+			tt.change(cfg)
+
+			assertErrors(t, validateProfile(cfg), tt.want)
+		})
+	}
+}
+
+func TestValidateInventoryLock(t *testing.T) {
+	tests := []struct {
+		name string
+		lock [][]int
+		want []string
+	}{
+		{name: "empty lock", lock: nil},
+		{name: "valid lock", lock: testInventoryLock()},
+		{
+			name: "missing row",
+			lock: testInventoryLock()[:3],
+			want: []string{"inventory.inventoryLock: expected 4 rows, got 3"},
+		},
+		{
+			name: "short row",
+			lock: func() [][]int {
+				lock := testInventoryLock()
+				lock[2] = lock[2][:9]
+				return lock
+			}(),
+			want: []string{"inventory.inventoryLock[2]: expected 10 columns, got 9"},
+		},
+		{
+			name: "invalid values",
+			lock: func() [][]int {
+				lock := testInventoryLock()
+				lock[0][4] = 2
+				lock[3][9] = -1
+				return lock
+			}(),
+			want: []string{
+				"inventory.inventoryLock[0][4]: 2 is not 0 (locked) or 1 (unlocked)",
+				"inventory.inventoryLock[3][9]: -1 is not 0 (locked) or 1 (unlocked)",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertErrors(t, validateInventoryLock(tt.lock), tt.want)
+		})
+	}
+}
+
+func TestValidateScheduler(t *testing.T) {
+	tests := []struct {
+		name      string
+		scheduler Scheduler
+		want      []string
+	}{
+		{
+			name:      "disabled scheduler is not checked",
+			scheduler: Scheduler{Days: []Day{{DayOfWeek: 9}}},
+		},
+		{
+			name: "valid days",
+			scheduler: Scheduler{Enabled: true, Days: []Day{
+				{DayOfWeek: 0, TimeRanges: []TimeRange{testTimeRange("08:00", "12:30"), testTimeRange("14:00", "23:59")}},
+				{DayOfWeek: 6},
+			}},
+		},
+		{
+			name:      "invalid day of week",
+			scheduler: Scheduler{Enabled: true, Days: []Day{{DayOfWeek: -1}, {DayOfWeek: 7}}},
+			want: []string{
+				"scheduler.days[0].dayOfWeek: -1 is not between 0 (sunday) and 6 (saturday)",
+				"scheduler.days[1].dayOfWeek: 7 is not between 0 (sunday) and 6 (saturday)",
+			},
+		},
+		{
+			name: "time range not ending after its start",
+			scheduler: Scheduler{Enabled: true, Days: []Day{
+				{DayOfWeek: 1, TimeRanges: []TimeRange{testTimeRange("10:00", "11:00"), testTimeRange("22:00", "06:00"), testTimeRange("09:15", "09:15")}},
+			}},
+			want: []string{
+				"scheduler.days[0].timeRange[1]: start 22:00 is not before end 06:00",
+				"scheduler.days[0].timeRange[2]: start 09:15 is not before end 09:15",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertErrors(t, validateScheduler(tt.scheduler), tt.want)
+		})
+	}
+}
+
+func assertErrors(t *testing.T, errs []error, want []string) {
+	t.Helper()
+
+	if len(errs) != len(want) {
+		t.Fatalf("got errors %v, want %v", errs, want)
+	}
+	for i, err := range errs {
+		if err.Error() != want[i] {
+			t.Errorf("error %d: got %q, want %q", i, err.Error(), want[i])
+		}
+	}
+}
+
+func validTestProfile() *CharacterCfg {
+	cfg := &CharacterCfg{}
+	cfg.Game.Runs = []Run{"pit"}
+	cfg.Health.HealingPotionAt = 40
+	cfg.Health.ChickenAt = 20
+	cfg.Inventory.BeltColumns = BeltColumns{"healing", "healing", "mana", "mana"}
+	cfg.Inventory.InventoryLock = testInventoryLock()
+
+	return cfg
+}
+
+func testInventoryLock() [][]int {
+	lock := make([][]int, inventoryLockRows)
+	for y := range lock {
+		lock[y] = make([]int, inventoryLockColumns)
+		for x := range lock[y] {
+			lock[y][x] = 1
+		}
+	}
+
+	return lock
+}
+
+func testTimeRange(start, end string) TimeRange {
+	s, _ := time.Parse("15:04", start)
+	e, _ := time.Parse("15:04", end)
+
+	return TimeRange{Start: s, End: e}
+}
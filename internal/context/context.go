@@ -66,6 +66,8 @@ type Context struct {
 	ManualModeActive      bool      // Manual play mode: stops after character selection
 	LastPortalTick        time.Time // NEW FIELD: Tracks last portal creation for spam prevention
 	IsBossEquipmentActive bool      // flag for barb leveling
+	// dataMu guards the Data refreshes against DataSnapshot, the bot goroutine reads Data without it
+	dataMu sync.RWMutex
 }
 
 type Debug struct {
@@ -168,17 +170,33 @@ func getGoroutineID() uint64 {
 }
 
 func (ctx *Context) RefreshGameData() {
-	*ctx.Data = ctx.GameReader.GetData()
+	gameData := ctx.GameReader.GetData()
+
+	ctx.dataMu.Lock()
+	defer ctx.dataMu.Unlock()
+	*ctx.Data = gameData
 	if ctx.IsLevelingCharacter == nil {
 		_, isLevelingCharacter := ctx.Char.(LevelingCharacter)
 		ctx.IsLevelingCharacter = &isLevelingCharacter
 	}
 	ctx.Data.IsLevelingCharacter = *ctx.IsLevelingCharacter
-
 }
 
 func (ctx *Context) RefreshInventory() {
-	ctx.Data.Inventory = ctx.GameReader.GetInventory()
+	inventory := ctx.GameReader.GetInventory()
+
+	ctx.dataMu.Lock()
+	defer ctx.dataMu.Unlock()
+	ctx.Data.Inventory = inventory
+}
+
+// DataSnapshot returns a copy of the game data for goroutines other than the bot one, Data is refreshed by the bot
+// while they read it otherwise
+func (ctx *Context) DataSnapshot() game.Data {
+	ctx.dataMu.RLock()
+	defer ctx.dataMu.RUnlock()
+
+	return *ctx.Data
 }
 
 func (ctx *Context) Detach() {
@@ -2,6 +2,10 @@ package event
 
 import (
 	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/d2go/pkg/data/area"
+	"github.com/hectorgimenez/d2go/pkg/data/difficulty"
+	"github.com/hectorgimenez/d2go/pkg/data/npc"
+	"github.com/hectorgimenez/d2go/pkg/data/quest"
 )
 
 const (
@@ -181,3 +185,82 @@ func ResetCompanionGameInfo(be BaseEvent, leader string) ResetCompanionGameInfoE
 		Leader:    leader,
 	}
 }
+
+type LevelUpEvent struct {
+	BaseEvent
+	Level      int
+	Difficulty difficulty.Difficulty
+}
+
+func LevelUp(be BaseEvent, level int, diff difficulty.Difficulty) LevelUpEvent {
+	return LevelUpEvent{
+		BaseEvent:  be,
+		Level:      level,
+		Difficulty: diff,
+	}
+}
+
+type QuestCompletedEvent struct {
+	BaseEvent
+	Quest      quest.Quest
+	QuestName  string
+	Difficulty difficulty.Difficulty
+}
+
+func QuestCompleted(be BaseEvent, q quest.Quest, questName string, diff difficulty.Difficulty) QuestCompletedEvent {
+	return QuestCompletedEvent{
+		BaseEvent:  be,
+		Quest:      q,
+		QuestName:  questName,
+		Difficulty: diff,
+	}
+}
+
+type DifficultyChangedEvent struct {
+	BaseEvent
+	From difficulty.Difficulty
+	To   difficulty.Difficulty
+}
+
+func DifficultyChanged(be BaseEvent, from, to difficulty.Difficulty) DifficultyChangedEvent {
+	return DifficultyChangedEvent{
+		BaseEvent: be,
+		From:      from,
+		To:        to,
+	}
+}
+
+type MercDiedEvent struct {
+	BaseEvent
+	Area area.ID
+}
+
+func MercDied(be BaseEvent, areaID area.ID) MercDiedEvent {
+	return MercDiedEvent{
+		BaseEvent: be,
+		Area:      areaID,
+	}
+}
+
+// NearbyMonster is a monster close to the player when it died
+type NearbyMonster struct {
+	Name     npc.ID           `json:"name"`
+	Type     data.MonsterType `json:"type"`
+	Distance int              `json:"distance"`
+}
+
+type PlayerDiedEvent struct {
+	BaseEvent
+	Area           area.ID
+	NearbyMonsters []NearbyMonster
+	LastAction     string
+}
+
+func PlayerDied(be BaseEvent, areaID area.ID, nearbyMonsters []NearbyMonster, lastAction string) PlayerDiedEvent {
+	return PlayerDiedEvent{
+		BaseEvent:      be,
+		Area:           areaID,
+		NearbyMonsters: nearbyMonsters,
+		LastAction:     lastAction,
+	}
+}
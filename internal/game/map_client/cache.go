@@ -0,0 +1,319 @@
+package map_client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hectorgimenez/d2go/pkg/data/difficulty"
+	"github.com/hectorgimenez/koolo/internal/config"
+)
+
+// cacheVersion must be increased every time serverLevel or the way it's produced changes, older entries are discarded
+const cacheVersion = 1
+
+const (
+	defaultCacheDir       = "cache/maps"
+	defaultCacheMaxSizeMB = 512
+	cacheFileExt          = ".mapcache"
+)
+
+var (
+	ErrCacheMiss      = errors.New("map data not found in cache")
+	ErrCacheCorrupted = errors.New("map data cache entry is corrupted")
+	ErrCacheOutdated  = errors.New("map data cache entry version is outdated")
+
+	defaultCacheMux sync.Mutex
+	defaultCache    *Cache
+)
+
+type cacheEnvelope struct {
+	Version    int             `json:"version"`
+	Seed       string          `json:"seed"`
+	Difficulty string          `json:"difficulty"`
+	CreatedAt  time.Time       `json:"createdAt"`
+	Checksum   string          `json:"checksum"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// Cache is a persistent, size bounded storage of MapData keyed by seed and difficulty.
+// Entries are gzip compressed JSON files including a version and a checksum of the map data.
+type Cache struct {
+	mu       sync.Mutex
+	dir      string
+	maxBytes int64
+}
+
+func NewCache(dir string, maxBytes int64) *Cache {
+	return &Cache{
+		dir:      dir,
+		maxBytes: maxBytes,
+	}
+}
+
+// DefaultCache returns the cache shared by all the supervisors, nil if the cache is disabled
+func DefaultCache() *Cache {
+	if config.Koolo == nil || !config.Koolo.MapCache.Enabled {
+		return nil
+	}
+
+	dir := config.Koolo.MapCache.Directory
+	if dir == "" {
+		dir = defaultCacheDir
+	}
+	maxSizeMB := config.Koolo.MapCache.MaxSizeMB
+	if maxSizeMB <= 0 {
+		maxSizeMB = defaultCacheMaxSizeMB
+	}
+
+	defaultCacheMux.Lock()
+	defer defaultCacheMux.Unlock()
+	if defaultCache == nil || defaultCache.dir != dir {
+		defaultCache = NewCache(dir, int64(maxSizeMB)*1024*1024)
+	} else {
+		defaultCache.maxBytes = int64(maxSizeMB) * 1024 * 1024
+	}
+
+	return defaultCache
+}
+
+func (c *Cache) Get(seed string, df difficulty.Difficulty) (MapData, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	path := c.entryPath(seed, df)
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrCacheMiss
+		}
+		return nil, err
+	}
+
+	mapData, err := readEntry(f, seed, df)
+	_ = f.Close()
+	if err != nil {
+		// Broken or outdated entries are removed, they will be regenerated on next Put
+		_ = os.Remove(path)
+		return nil, err
+	}
+
+	// Refresh the modification time, it's used as LRU when the cache is trimmed
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+
+	return mapData, nil
+}
+
+func (c *Cache) Put(seed string, df difficulty.Difficulty, mapData MapData) error {
+	payload, err := json.Marshal(mapData)
+	if err != nil {
+		return fmt.Errorf("error encoding map data: %w", err)
+	}
+
+	checksum := sha256.Sum256(payload)
+	envelope, err := json.Marshal(cacheEnvelope{
+		Version:    cacheVersion,
+		Seed:       seed,
+		Difficulty: string(df),
+		CreatedAt:  time.Now(),
+		Checksum:   hex.EncodeToString(checksum[:]),
+		Payload:    payload,
+	})
+	if err != nil {
+		return fmt.Errorf("error encoding map cache entry: %w", err)
+	}
+
+	buf := new(bytes.Buffer)
+	gz := gzip.NewWriter(buf)
+	if _, err = gz.Write(envelope); err != nil {
+		return err
+	}
+	if err = gz.Close(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err = os.MkdirAll(c.dir, os.ModePerm); err != nil {
+		return fmt.Errorf("error creating map cache directory: %w", err)
+	}
+
+	// Write to a temporary file first, so other supervisors never read a partially written entry
+	path := c.entryPath(seed, df)
+	tmpFile, err := os.CreateTemp(c.dir, "tmp-*")
+	if err != nil {
+		return fmt.Errorf("error creating map cache entry: %w", err)
+	}
+	if _, err = tmpFile.Write(buf.Bytes()); err != nil {
+		_ = tmpFile.Close()
+		_ = os.Remove(tmpFile.Name())
+		return fmt.Errorf("error writing map cache entry: %w", err)
+	}
+	_ = tmpFile.Close()
+
+	if err = os.Rename(tmpFile.Name(), path); err != nil {
+		_ = os.Remove(tmpFile.Name())
+		return fmt.Errorf("error writing map cache entry: %w", err)
+	}
+
+	return c.trim()
+}
+
+// trim removes the least recently used entries until the cache fits into the max size
+func (c *Cache) trim() error {
+	if c.maxBytes <= 0 {
+		return nil
+	}
+
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return err
+	}
+
+	type cacheFile struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+
+	var files []cacheFile
+	var total int64
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), cacheFileExt) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, cacheFile{path: filepath.Join(c.dir, entry.Name()), size: info.Size(), modTime: info.ModTime()})
+		total += info.Size()
+	}
+
+	if total <= c.maxBytes {
+		return nil
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.Before(files[j].modTime)
+	})
+
+	for _, f := range files {
+		if total <= c.maxBytes {
+			break
+		}
+		if err = os.Remove(f.path); err == nil {
+			total -= f.size
+		}
+	}
+
+	return nil
+}
+
+func (c *Cache) entryPath(seed string, df difficulty.Difficulty) string {
+	return filepath.Join(c.dir, fmt.Sprintf("%s-%s%s", seed, getDifficultyAsNum(df), cacheFileExt))
+}
+
+func readEntry(r io.Reader, seed string, df difficulty.Difficulty) (MapData, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCacheCorrupted, err)
+	}
+	defer gz.Close()
+
+	content, err := io.ReadAll(gz)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCacheCorrupted, err)
+	}
+
+	var envelope cacheEnvelope
+	if err = json.Unmarshal(content, &envelope); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCacheCorrupted, err)
+	}
+
+	if envelope.Version != cacheVersion {
+		return nil, ErrCacheOutdated
+	}
+
+	if envelope.Seed != seed || envelope.Difficulty != string(df) {
+		return nil, fmt.Errorf("%w: entry belongs to seed %s (%s)", ErrCacheCorrupted, envelope.Seed, envelope.Difficulty)
+	}
+
+	checksum := sha256.Sum256(envelope.Payload)
+	if hex.EncodeToString(checksum[:]) != envelope.Checksum {
+		return nil, fmt.Errorf("%w: checksum mismatch", ErrCacheCorrupted)
+	}
+
+	var mapData MapData
+	if err = json.Unmarshal(envelope.Payload, &mapData); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCacheCorrupted, err)
+	}
+
+	return mapData, nil
+}
+
+// Loader resolves map data from the cache, falling back to the external koolo-map process when there is no entry
+type Loader struct {
+	cache  *Cache
+	fetch  func(seed string, df difficulty.Difficulty) (MapData, error)
+	logger *slog.Logger
+}
+
+func NewLoader(cache *Cache, logger *slog.Logger) *Loader {
+	return &Loader{
+		cache:  cache,
+		fetch:  GetMapData,
+		logger: logger,
+	}
+}
+
+// NewOfflineLoader only reads from the cache, useful for tests and tools without access to the game files
+func NewOfflineLoader(cache *Cache, logger *slog.Logger) *Loader {
+	return &Loader{
+		cache: cache,
+		fetch: func(seed string, _ difficulty.Difficulty) (MapData, error) {
+			return nil, fmt.Errorf("%w: seed %s", ErrCacheMiss, seed)
+		},
+		logger: logger,
+	}
+}
+
+// Load returns the map data and true if it was served from the cache
+func (l *Loader) Load(seed string, df difficulty.Difficulty) (MapData, bool, error) {
+	if l.cache != nil {
+		mapData, err := l.cache.Get(seed, df)
+		if err == nil {
+			return mapData, true, nil
+		}
+		if !errors.Is(err, ErrCacheMiss) {
+			l.logger.Warn("Map cache entry discarded", slog.String("seed", seed), slog.Any("error", err))
+		}
+	}
+
+	mapData, err := l.fetch(seed, df)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if l.cache != nil {
+		if err = l.cache.Put(seed, df, mapData); err != nil {
+			l.logger.Warn("Map data could not be cached", slog.String("seed", seed), slog.Any("error", err))
+		}
+	}
+
+	return mapData, false, nil
+}
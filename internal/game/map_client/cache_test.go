@@ -0,0 +1,232 @@
+package map_client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hectorgimenez/d2go/pkg/data/difficulty"
+)
+
+func TestCacheGetPut(t *testing.T) {
+	cache := NewCache(t.TempDir(), 0)
+	mapData := testMapData("Blood Moor")
+
+	if _, err := cache.Get("123", difficulty.Normal); !errors.Is(err, ErrCacheMiss) {
+		t.Fatalf("got %v, want a cache miss", err)
+	}
+
+	if err := cache.Put("123", difficulty.Normal, mapData); err != nil {
+		t.Fatal(err)
+	}
+	got, err := cache.Get("123", difficulty.Normal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, mapData) {
+		t.Errorf("got %+v, want %+v", got, mapData)
+	}
+
+	// Entries are keyed by seed and difficulty
+	for _, key := range []struct {
+		seed string
+		df   difficulty.Difficulty
+	}{{"124", difficulty.Normal}, {"123", difficulty.Hell}} {
+		if _, err = cache.Get(key.seed, key.df); !errors.Is(err, ErrCacheMiss) {
+			t.Errorf("seed %s (%s): got %v, want a cache miss", key.seed, key.df, err)
+		}
+	}
+}
+
+func TestCacheInvalidEntries(t *testing.T) {
+	tests := []struct {
+		name    string
+		content func(t *testing.T) []byte
+		wantErr error
+	}{
+		{
+			name:    "not compressed",
+			content: func(t *testing.T) []byte { return []byte("not a cache entry") },
+			wantErr: ErrCacheCorrupted,
+		},
+		{
+			name: "outdated version",
+			content: func(t *testing.T) []byte {
+				return testEntry(t, func(e *cacheEnvelope) { e.Version = cacheVersion - 1 })
+			},
+			wantErr: ErrCacheOutdated,
+		},
+		{
+			name: "checksum mismatch",
+			content: func(t *testing.T) []byte {
+				return testEntry(t, func(e *cacheEnvelope) { e.Checksum = "0000" })
+			},
+			wantErr: ErrCacheCorrupted,
+		},
+		{
+			name: "entry of another seed",
+			content: func(t *testing.T) []byte {
+				return testEntry(t, func(e *cacheEnvelope) { e.Seed = "999" })
+			},
+			wantErr: ErrCacheCorrupted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := NewCache(t.TempDir(), 0)
+			path := cache.entryPath("123", difficulty.Normal)
+			if err := os.WriteFile(path, tt.content(t), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := cache.Get("123", difficulty.Normal); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+			// Invalid entries are removed, the next read is a miss
+			if _, err := os.Stat(path); !os.IsNotExist(err) {
+				t.Error("invalid entry not removed")
+			}
+			if _, err := cache.Get("123", difficulty.Normal); !errors.Is(err, ErrCacheMiss) {
+				t.Errorf("got %v, want a cache miss", err)
+			}
+		})
+	}
+}
+
+func TestCacheTrim(t *testing.T) {
+	cache := NewCache(t.TempDir(), 0)
+	for _, seed := range []string{"1", "2", "3"} {
+		if err := cache.Put(seed, difficulty.Normal, testMapData("level "+seed)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Seed 1 is the oldest entry, but it's read after the others so seed 2 is the least recently used
+	old := time.Now().Add(-time.Hour)
+	for i, seed := range []string{"1", "2", "3"} {
+		modTime := old.Add(time.Duration(i) * time.Minute)
+		if err := os.Chtimes(cache.entryPath(seed, difficulty.Normal), modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := cache.Get("1", difficulty.Normal); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(cache.entryPath("3", difficulty.Normal))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Room for two entries, the new one and the most recently used
+	cache.maxBytes = 2*info.Size() + info.Size()/2
+	if err = cache.Put("4", difficulty.Normal, testMapData("level 4")); err != nil {
+		t.Fatal(err)
+	}
+
+	for seed, wantFound := range map[string]bool{"1": true, "2": false, "3": false, "4": true} {
+		_, err = os.Stat(cache.entryPath(seed, difficulty.Normal))
+		if found := err == nil; found != wantFound {
+			t.Errorf("seed %s: found %v, want %v", seed, found, wantFound)
+		}
+	}
+}
+
+func TestLoader(t *testing.T) {
+	cache := NewCache(t.TempDir(), 0)
+	fetched := 0
+	loader := &Loader{
+		cache: cache,
+		fetch: func(seed string, _ difficulty.Difficulty) (MapData, error) {
+			fetched++
+			if seed == "broken" {
+				return nil, fmt.Errorf("koolo-map failed")
+			}
+			return testMapData("seed " + seed), nil
+		},
+		logger: slog.Default(),
+	}
+
+	for i, wantCached := range []bool{false, true} {
+		mapData, cached, err := loader.Load("123", difficulty.Normal)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cached != wantCached || mapData[0].Name != "seed 123" {
+			t.Errorf("load %d: got cached %v and %s", i, cached, mapData[0].Name)
+		}
+	}
+	if fetched != 1 {
+		t.Errorf("map data fetched %d times, want 1", fetched)
+	}
+
+	if _, _, err := loader.Load("broken", difficulty.Normal); err == nil {
+		t.Error("expected the fetch error")
+	}
+	if _, err := cache.Get("broken", difficulty.Normal); !errors.Is(err, ErrCacheMiss) {
+		t.Errorf("failed fetches must not be cached, got %v", err)
+	}
+
+	offline := NewOfflineLoader(cache, slog.Default())
+	if _, cached, err := offline.Load("123", difficulty.Normal); err != nil || !cached {
+		t.Errorf("offline loader: got cached %v, %v", cached, err)
+	}
+	if _, _, err := offline.Load("456", difficulty.Normal); !errors.Is(err, ErrCacheMiss) {
+		t.Errorf("offline loader: got %v, want a cache miss", err)
+	}
+}
+
+func testMapData(name string) MapData {
+	lvl := serverLevel{Type: "map", ID: 2, Name: name, Map: [][]int{{0, 1}, {1, 0}}}
+	lvl.Size.Width = 2
+	lvl.Size.Height = 2
+	lvl.Rooms = []serverRoom{{serverPosition: serverPosition{X: 10, Y: 20}, Width: 2, Height: 2}}
+
+	return MapData{lvl}
+}
+
+// testEntry returns a cache entry of seed 123 in normal, change modifies the envelope before it's written
+func testEntry(t *testing.T, change func(e *cacheEnvelope)) []byte {
+	t.Helper()
+
+	cache := NewCache(t.TempDir(), 0)
+	if err := cache.Put("123", difficulty.Normal, testMapData("Blood Moor")); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(cache.entryPath("123", difficulty.Normal))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var envelope cacheEnvelope
+	if err = json.NewDecoder(gz).Decode(&envelope); err != nil {
+		t.Fatal(err)
+	}
+	change(&envelope)
+
+	encoded, err := json.Marshal(envelope)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := new(bytes.Buffer)
+	zw := gzip.NewWriter(buf)
+	if _, err = zw.Write(encoded); err != nil {
+		t.Fatal(err)
+	}
+	if err = zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
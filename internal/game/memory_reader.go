@@ -29,6 +29,7 @@ type MemoryReader struct {
 	GameAreaSizeY  int
 	supervisorName string
 	cachedMapData  map[area.ID]AreaData
+	mapLoader      *map_client.Loader
 	logger         *slog.Logger
 }
 
@@ -43,6 +44,7 @@ func NewGameReader(cfg *config.CharacterCfg, supervisorName string, pid uint32,
 		HWND:           window,
 		supervisorName: supervisorName,
 		cfg:            cfg,
+		mapLoader:      map_client.NewLoader(map_client.DefaultCache(), logger),
 		logger:         logger,
 	}
 
@@ -62,7 +64,7 @@ func (gd *MemoryReader) FetchMapData() error {
 	cfg, _ := config.GetCharacter(gd.supervisorName)
 	gd.logger.Debug("Fetching map data...", slog.Uint64("seed", uint64(gd.mapSeed)), slog.String("difficulty", string(cfg.Game.Difficulty)))
 
-	mapData, err := map_client.GetMapData(strconv.Itoa(int(gd.mapSeed)), cfg.Game.Difficulty)
+	mapData, fromCache, err := gd.mapLoader.Load(strconv.Itoa(int(gd.mapSeed)), cfg.Game.Difficulty)
 	if err != nil {
 		return fmt.Errorf("error fetching map data: %w", err)
 	}
@@ -113,7 +115,7 @@ func (gd *MemoryReader) FetchMapData() error {
 	_ = g.Wait()
 
 	gd.cachedMapData = areas
-	gd.logger.Debug("Fetch completed", slog.Int64("ms", time.Since(t).Milliseconds()))
+	gd.logger.Debug("Fetch completed", slog.Int64("ms", time.Since(t).Milliseconds()), slog.Bool("fromCache", fromCache))
 
 	return nil
 }
@@ -0,0 +1,110 @@
+package logstream
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/hectorgimenez/koolo/internal/config/secrets"
+)
+
+// Handler publishes the records handled by the wrapped handler to a hub
+type Handler struct {
+	next       slog.Handler
+	hub        *Hub
+	supervisor string
+	// attrs are the attributes added with WithAttrs, already formatted
+	attrs  string
+	groups []string
+}
+
+// NewHandler wraps next, the records it handles are published to the hub as records of the supervisor
+func NewHandler(next slog.Handler, hub *Hub, supervisor string) *Handler {
+	if supervisor == "" {
+		supervisor = AppSource
+	}
+
+	return &Handler{next: next, hub: hub, supervisor: supervisor}
+}
+
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	sb := &strings.Builder{}
+	sb.WriteString(h.attrs)
+	r.Attrs(func(a slog.Attr) bool {
+		writeAttr(sb, h.groups, a)
+		return true
+	})
+
+	h.hub.Publish(Record{
+		Time:       r.Time,
+		Level:      r.Level,
+		Supervisor: h.supervisor,
+		Message:    secrets.Redact(r.Message),
+		Attrs:      secrets.Redact(strings.TrimSpace(sb.String())),
+	})
+
+	return h.next.Handle(ctx, r)
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	sb := &strings.Builder{}
+	sb.WriteString(h.attrs)
+	for _, a := range attrs {
+		writeAttr(sb, h.groups, a)
+	}
+
+	clone := *h
+	clone.next = h.next.WithAttrs(attrs)
+	clone.attrs = sb.String()
+
+	return &clone
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	clone := *h
+	clone.next = h.next.WithGroup(name)
+	clone.groups = append(append([]string{}, h.groups...), name)
+
+	return &clone
+}
+
+// writeAttr writes the attribute as key=value, like the text handler writing the log files
+func writeAttr(sb *strings.Builder, groups []string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			groups = append(append([]string{}, groups...), a.Key)
+		}
+		for _, ga := range a.Value.Group() {
+			writeAttr(sb, groups, ga)
+		}
+		return
+	}
+
+	sb.WriteByte(' ')
+	for _, g := range groups {
+		sb.WriteString(g)
+		sb.WriteByte('.')
+	}
+	sb.WriteString(a.Key)
+	sb.WriteByte('=')
+
+	value := a.Value.String()
+	if value == "" || strings.ContainsAny(value, " =\"") {
+		sb.WriteString(`"` + strings.ReplaceAll(value, `"`, `\"`) + `"`)
+		return
+	}
+	sb.WriteString(value)
+}
@@ -0,0 +1,222 @@
+// Package logstream keeps the recent log records of koolo and of every supervisor in memory, so they can be searched
+// and followed live from the web UI without opening the log files on the bot host.
+package logstream
+
+import (
+	"cmp"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultBufferSize is the number of records kept per supervisor
+	DefaultBufferSize = 2000
+	// AppSource is the supervisor name of the records logged by koolo itself
+	AppSource = "koolo"
+)
+
+// Default is the hub the koolo loggers publish to
+var Default = NewHub(DefaultBufferSize)
+
+// Record is a log record as shown in the web UI, Attrs are the record attributes in the log file format
+type Record struct {
+	ID         uint64     `json:"id"`
+	Time       time.Time  `json:"time"`
+	Level      slog.Level `json:"level"`
+	Supervisor string     `json:"supervisor"`
+	Message    string     `json:"message"`
+	Attrs      string     `json:"attrs,omitempty"`
+}
+
+// Filter selects records, the zero value matches every record of level info or higher
+type Filter struct {
+	// Supervisor matches the records of a single supervisor, empty matches all of them
+	Supervisor string
+	MinLevel   slog.Level
+	// Text matches the records with the text in the message or the attributes, case insensitive
+	Text string
+}
+
+func (f Filter) Match(rec Record) bool {
+	if f.Supervisor != "" && f.Supervisor != rec.Supervisor {
+		return false
+	}
+	if rec.Level < f.MinLevel {
+		return false
+	}
+	if f.Text == "" {
+		return true
+	}
+
+	text := strings.ToLower(f.Text)
+	return strings.Contains(strings.ToLower(rec.Message), text) || strings.Contains(strings.ToLower(rec.Attrs), text)
+}
+
+// Hub keeps a ring buffer of records per supervisor and sends the new records to the subscribers
+type Hub struct {
+	mu          sync.RWMutex
+	size        int
+	nextID      uint64
+	buffers     map[string]*ring
+	subscribers map[*Subscription]struct{}
+}
+
+func NewHub(size int) *Hub {
+	return &Hub{
+		size:        max(size, 1),
+		nextID:      1,
+		buffers:     make(map[string]*ring),
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Publish stores the record and sends it to the subscribers matching it, the ID is assigned here
+func (h *Hub) Publish(rec Record) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	rec.ID = h.nextID
+	h.nextID++
+
+	buf, found := h.buffers[rec.Supervisor]
+	if !found {
+		buf = newRing(h.size)
+		h.buffers[rec.Supervisor] = buf
+	}
+	buf.add(rec)
+
+	for sub := range h.subscribers {
+		sub.send(rec)
+	}
+}
+
+// Search returns the newest records matching the filter, up to limit, oldest first
+func (h *Hub) Search(filter Filter, limit int) []Record {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	records := make([]Record, 0)
+	for supervisor, buf := range h.buffers {
+		if filter.Supervisor != "" && filter.Supervisor != supervisor {
+			continue
+		}
+		buf.each(func(rec Record) {
+			if filter.Match(rec) {
+				records = append(records, rec)
+			}
+		})
+	}
+	slices.SortFunc(records, func(a, b Record) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	if limit > 0 && len(records) > limit {
+		records = records[len(records)-limit:]
+	}
+
+	return records
+}
+
+// Supervisors returns the supervisors with records, sorted by name
+func (h *Hub) Supervisors() []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	supervisors := make([]string, 0, len(h.buffers))
+	for supervisor := range h.buffers {
+		supervisors = append(supervisors, supervisor)
+	}
+	slices.Sort(supervisors)
+
+	return supervisors
+}
+
+// Subscribe returns a subscription receiving the new records matching the filter, buffer is the number of records
+// waiting to be read before the next ones are dropped. The subscription must be closed when it's no longer used.
+func (h *Hub) Subscribe(filter Filter, buffer int) *Subscription {
+	sub := &Subscription{hub: h, filter: filter, records: make(chan Record, max(buffer, 1))}
+
+	h.mu.Lock()
+	h.subscribers[sub] = struct{}{}
+	h.mu.Unlock()
+
+	return sub
+}
+
+// Subscription receives the records published after it was created
+type Subscription struct {
+	hub     *Hub
+	filter  Filter
+	records chan Record
+	// dropped is only changed with the hub lock held
+	dropped uint64
+	closed  bool
+}
+
+// Records returns the channel receiving the records, it's closed when the subscription is closed
+func (s *Subscription) Records() <-chan Record {
+	return s.records
+}
+
+// Dropped returns the number of records not sent because the subscriber was too slow reading them
+func (s *Subscription) Dropped() uint64 {
+	s.hub.mu.RLock()
+	defer s.hub.mu.RUnlock()
+
+	return s.dropped
+}
+
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+	s.closed = true
+	delete(s.hub.subscribers, s)
+	close(s.records)
+}
+
+// send never blocks, logging must not wait for a slow web client
+func (s *Subscription) send(rec Record) {
+	if !s.filter.Match(rec) {
+		return
+	}
+
+	select {
+	case s.records <- rec:
+	default:
+		s.dropped++
+	}
+}
+
+type ring struct {
+	records []Record
+	start   int
+	count   int
+}
+
+func newRing(size int) *ring {
+	return &ring{records: make([]Record, size)}
+}
+
+func (r *ring) add(rec Record) {
+	idx := (r.start + r.count) % len(r.records)
+	r.records[idx] = rec
+	if r.count < len(r.records) {
+		r.count++
+		return
+	}
+	r.start = (r.start + 1) % len(r.records)
+}
+
+// each calls fn with the records, oldest first
+func (r *ring) each(fn func(Record)) {
+	for i := 0; i < r.count; i++ {
+		fn(r.records[(r.start+i)%len(r.records)])
+	}
+}
@@ -0,0 +1,160 @@
+package logstream
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"testing"
+)
+
+func TestHubBufferWraparound(t *testing.T) {
+	h := NewHub(3)
+	for i := 1; i <= 5; i++ {
+		h.Publish(Record{Supervisor: "koza", Message: fmt.Sprintf("message %d", i)})
+	}
+	h.Publish(Record{Supervisor: "other", Message: "other message"})
+
+	// Every supervisor has its own buffer, the oldest records are dropped
+	got := messages(h.Search(Filter{Supervisor: "koza"}, 0))
+	if want := []string{"message 3", "message 4", "message 5"}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got = messages(h.Search(Filter{Supervisor: "other"}, 0)); !slices.Equal(got, []string{"other message"}) {
+		t.Errorf("got %v for the other supervisor", got)
+	}
+
+	// Records of every supervisor are sorted by ID
+	records := h.Search(Filter{}, 0)
+	if got = messages(records); !slices.Equal(got, []string{"message 3", "message 4", "message 5", "other message"}) {
+		t.Errorf("got %v", got)
+	}
+	if records[0].ID != 3 || records[len(records)-1].ID != 6 {
+		t.Errorf("unexpected IDs %d and %d", records[0].ID, records[len(records)-1].ID)
+	}
+
+	if got = messages(h.Search(Filter{}, 2)); !slices.Equal(got, []string{"message 5", "other message"}) {
+		t.Errorf("limit must keep the newest records, got %v", got)
+	}
+	if got := h.Supervisors(); !slices.Equal(got, []string{"koza", "other"}) {
+		t.Errorf("got supervisors %v", got)
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	rec := Record{Supervisor: "koza", Level: slog.LevelWarn, Message: "Failed to pick up item", Attrs: `item="Shako"`}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   bool
+	}{
+		{"zero value", Filter{}, true},
+		{"supervisor", Filter{Supervisor: "koza"}, true},
+		{"other supervisor", Filter{Supervisor: "other"}, false},
+		{"min level", Filter{MinLevel: slog.LevelWarn}, true},
+		{"level too low", Filter{MinLevel: slog.LevelError}, false},
+		{"text in message", Filter{Text: "pick up"}, true},
+		{"text in attributes", Filter{Text: "shako"}, true},
+		{"text case insensitive", Filter{Text: "FAILED"}, true},
+		{"text not found", Filter{Text: "chicken"}, false},
+		{"every filter", Filter{Supervisor: "koza", MinLevel: slog.LevelInfo, Text: "item"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Match(rec); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Debug records are hidden by the zero value
+	if (Filter{}).Match(Record{Level: slog.LevelDebug}) {
+		t.Error("debug records must not match the zero value filter")
+	}
+}
+
+func TestHubSearchFilter(t *testing.T) {
+	h := NewHub(10)
+	h.Publish(Record{Supervisor: "koza", Level: slog.LevelDebug, Message: "debug"})
+	h.Publish(Record{Supervisor: "koza", Level: slog.LevelError, Message: "error"})
+	h.Publish(Record{Supervisor: "other", Level: slog.LevelError, Message: "other error"})
+
+	got := messages(h.Search(Filter{Supervisor: "koza", MinLevel: slog.LevelDebug}, 0))
+	if want := []string{"debug", "error"}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got = messages(h.Search(Filter{Text: "error"}, 0)); !slices.Equal(got, []string{"error", "other error"}) {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestHubSubscribers(t *testing.T) {
+	h := NewHub(10)
+	all := h.Subscribe(Filter{}, 10)
+	koza := h.Subscribe(Filter{Supervisor: "koza"}, 10)
+	errorSub := h.Subscribe(Filter{MinLevel: slog.LevelError}, 10)
+	defer all.Close()
+	defer koza.Close()
+
+	h.Publish(Record{Supervisor: "koza", Message: "koza info"})
+	h.Publish(Record{Supervisor: "other", Level: slog.LevelError, Message: "other error"})
+
+	if got := messages(received(all)); !slices.Equal(got, []string{"koza info", "other error"}) {
+		t.Errorf("every record subscriber got %v", got)
+	}
+	if got := messages(received(koza)); !slices.Equal(got, []string{"koza info"}) {
+		t.Errorf("supervisor subscriber got %v", got)
+	}
+	if got := messages(received(errorSub)); !slices.Equal(got, []string{"other error"}) {
+		t.Errorf("error subscriber got %v", got)
+	}
+
+	// Closed subscriptions don't get records anymore, closing them twice is fine
+	errorSub.Close()
+	errorSub.Close()
+	h.Publish(Record{Supervisor: "koza", Level: slog.LevelError, Message: "after close"})
+	if _, ok := <-errorSub.Records(); ok {
+		t.Error("closed subscription got a record")
+	}
+	if got := messages(received(all)); !slices.Equal(got, []string{"after close"}) {
+		t.Errorf("open subscriber got %v", got)
+	}
+}
+
+func TestHubSlowSubscriber(t *testing.T) {
+	h := NewHub(10)
+	sub := h.Subscribe(Filter{}, 2)
+	defer sub.Close()
+
+	// Publishing never blocks, the records not fitting in the subscriber buffer are dropped
+	for i := 1; i <= 5; i++ {
+		h.Publish(Record{Supervisor: "koza", Message: fmt.Sprintf("message %d", i)})
+	}
+
+	if got := messages(received(sub)); !slices.Equal(got, []string{"message 1", "message 2"}) {
+		t.Errorf("got %v", got)
+	}
+	if dropped := sub.Dropped(); dropped != 3 {
+		t.Errorf("got %d dropped records, want 3", dropped)
+	}
+}
+
+// received returns the records waiting in the subscription
+func received(sub *Subscription) []Record {
+	records := make([]Record, 0)
+	for len(sub.Records()) > 0 {
+		records = append(records, <-sub.Records())
+	}
+
+	return records
+}
+
+func messages(records []Record) []string {
+	result := make([]string, 0, len(records))
+	for _, rec := range records {
+		result = append(result, rec.Message)
+	}
+
+	return result
+}
@@ -1,7 +1,6 @@
 package astar
 
 import (
-	"container/heap"
 	"math"
 
 	"github.com/hectorgimenez/d2go/pkg/data"
@@ -27,6 +26,23 @@ type Node struct {
 	TpStreak int
 }
 
+// Mode selects the search strategy used by CalculatePathWithOptions
+type Mode int
+
+const (
+	// ModeAStar expands every tile, it's the reference implementation and always returns the optimal path
+	ModeAStar Mode = iota
+	// ModeJumpPoint uses jump point search over uniform cost (walkable) tiles and falls back to regular A* expansion
+	// around tiles with a different cost (objects, monsters, low priority...). It's much faster on big open areas, on narrow
+	// levels it performs close to ModeAStar. Paths may differ slightly from ModeAStar ones.
+	ModeJumpPoint
+)
+
+type Options struct {
+	CanTeleport bool
+	Mode        Mode
+}
+
 func direction(from, to data.Position) (dx, dy int) {
 	dx = to.X - from.X
 	dy = to.Y - from.Y
@@ -35,54 +51,49 @@ func direction(from, to data.Position) (dx, dy int) {
 
 const MaxConsecutiveTeleportOver = 12
 
+// CalculatePath returns the path from start to goal using A*, positions are relative to the grid
 func CalculatePath(g *game.Grid, start, goal data.Position, canTeleport bool) ([]data.Position, int, bool) {
-	pq := make(PriorityQueue, 0)
-	heap.Init(&pq)
-
-	// Use a 2D slice to store the cost of each node
-	costSoFar := make([][]int, g.Width)
-	cameFrom := make([][]data.Position, g.Width)
-	for i := range costSoFar {
-		costSoFar[i] = make([]int, g.Height)
-		cameFrom[i] = make([]data.Position, g.Height)
-		for j := range costSoFar[i] {
-			costSoFar[i][j] = math.MaxInt32
-		}
+	return CalculatePathWithOptions(g, start, goal, Options{CanTeleport: canTeleport})
+}
+
+func CalculatePathWithOptions(g *game.Grid, start, goal data.Position, opts Options) ([]data.Position, int, bool) {
+	if !inBounds(g, start) || !inBounds(g, goal) {
+		return nil, 0, false
+	}
+
+	s := acquireSearch(g.Width, g.Height)
+	defer releaseSearch(s)
+
+	if opts.Mode == ModeJumpPoint {
+		return s.jumpPointSearch(g, start, goal, opts.CanTeleport)
 	}
 
-	startNode := &Node{Position: start, Cost: 0, Priority: heuristic(start, goal)}
-	heap.Push(&pq, startNode)
-	costSoFar[start.X][start.Y] = 0
+	return s.aStar(g, start, goal, opts.CanTeleport)
+}
 
-	neighbors := make([]data.Position, 0, 8)
+func (s *search) aStar(g *game.Grid, start, goal data.Position, canTeleport bool) ([]data.Position, int, bool) {
+	s.push(Node{Position: start, Cost: 0, Priority: heuristic(start, goal)})
+	s.setCost(s.index(start), 0)
 
-	for pq.Len() > 0 {
-		current := heap.Pop(&pq).(*Node)
+	for len(s.open) > 0 {
+		current := s.pop()
 
 		// Let's build the path if we reached the goal
 		if current.Position == goal {
-			var path []data.Position
-			for p := goal; p != start; p = cameFrom[p.X][p.Y] {
-				if g.CollisionGrid[p.Y][p.X] == game.CollisionTypeTeleportOver {
-					continue
-				}
-				path = append([]data.Position{p}, path...)
-			}
-			path = append([]data.Position{start}, path...)
+			path := s.buildPath(g, start, goal, false)
 			return path, len(path), true
 		}
 
-		updateNeighbors(g, current, &neighbors, canTeleport)
+		updateNeighbors(g, &current, &s.neighbors, canTeleport)
 
-		for _, neighbor := range neighbors {
+		currentCost := s.costOf(s.index(current.Position))
+		for _, neighbor := range s.neighbors {
 			tileType := g.CollisionGrid[neighbor.Y][neighbor.X]
 
 			// Determine teleport streak
 			teleportStreak := 0
 			if tileType == game.CollisionTypeTeleportOver {
 				teleportStreak = current.TpStreak + 1
-			} else {
-				teleportStreak = 0
 			}
 
 			// Skip if exceeds allowed consecutive teleport tiles
@@ -90,20 +101,14 @@ func CalculatePath(g *game.Grid, start, goal data.Position, canTeleport bool) ([
 				continue
 			}
 
-			newCost := costSoFar[current.X][current.Y] + getCost(tileType, canTeleport)
-
-			// Handicap for changing direction, this prevents zig-zagging around obstacles
-			//curDirX, curDirY := direction(cameFrom[current.X][current.Y], current.Position)
-			//newDirX, newDirY := direction(current.Position, neighbor)
-			//if curDirX != newDirX || curDirY != newDirY {
-			//	newCost++
-			//}
+			newCost := currentCost + getCost(tileType, canTeleport)
 
-			if newCost < costSoFar[neighbor.X][neighbor.Y] {
-				costSoFar[neighbor.X][neighbor.Y] = newCost
+			idx := s.index(neighbor)
+			if newCost < s.costOf(idx) {
+				s.setCost(idx, newCost)
 				priority := newCost + int(0.5*float64(heuristic(neighbor, goal)))
-				heap.Push(&pq, &Node{Position: neighbor, Cost: newCost, Priority: priority, TpStreak: teleportStreak})
-				cameFrom[neighbor.X][neighbor.Y] = current.Position
+				s.push(Node{Position: neighbor, Cost: newCost, Priority: priority, TpStreak: teleportStreak})
+				s.cameFrom[idx] = int32(s.index(current.Position))
 			}
 		}
 	}
@@ -116,28 +121,16 @@ func updateNeighbors(grid *game.Grid, node *Node, neighbors *[]data.Position, ca
 	*neighbors = (*neighbors)[:0]
 
 	x, y := node.X, node.Y
-	gridWidth, gridHeight := grid.Width, grid.Height
-
-	isBlocked := func(px, py int) bool {
-		if px < 0 || px >= gridWidth || py < 0 || py >= gridHeight {
-			return true
-		}
-		collisionType := grid.CollisionGrid[py][px]
-		return collisionType == game.CollisionTypeNonWalkable || (!canTeleport && collisionType == game.CollisionTypeTeleportOver)
-	}
 
 	for _, d := range directions {
 		newX, newY := x+d.X, y+d.Y
 
-		if isBlocked(newX, newY) {
+		if isBlocked(grid, newX, newY, canTeleport) {
 			continue
 		}
 
 		if d.X != 0 && d.Y != 0 {
-			adj1X, adj1Y := x+d.X, y
-			adj2X, adj2Y := x, y+d.Y
-
-			if isBlocked(adj1X, adj1Y) || isBlocked(adj2X, adj2Y) {
+			if isBlocked(grid, x+d.X, y, canTeleport) || isBlocked(grid, x, y+d.Y, canTeleport) {
 				continue
 			}
 		}
@@ -146,6 +139,18 @@ func updateNeighbors(grid *game.Grid, node *Node, neighbors *[]data.Position, ca
 	}
 }
 
+func isBlocked(grid *game.Grid, px, py int, canTeleport bool) bool {
+	if px < 0 || px >= grid.Width || py < 0 || py >= grid.Height {
+		return true
+	}
+	collisionType := grid.CollisionGrid[py][px]
+	return collisionType == game.CollisionTypeNonWalkable || (!canTeleport && collisionType == game.CollisionTypeTeleportOver)
+}
+
+func inBounds(g *game.Grid, p data.Position) bool {
+	return p.X >= 0 && p.X < g.Width && p.Y >= 0 && p.Y < g.Height
+}
+
 func getCost(tileType game.CollisionType, canTeleport bool) int {
 	switch tileType {
 	case game.CollisionTypeWalkable:
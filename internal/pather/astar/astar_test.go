@@ -2,7 +2,9 @@ package astar
 
 import (
 	"encoding/gob"
+	"math/rand"
 	"os"
+	"slices"
 	"testing"
 
 	"github.com/hectorgimenez/d2go/pkg/data"
@@ -21,6 +23,30 @@ func BenchmarkAstar(b *testing.B) {
 	}
 }
 
+func BenchmarkJumpPoint(b *testing.B) {
+	grid := loadGrid()
+
+	start := data.Position{X: 336, Y: 701}
+	goal := data.Position{X: 11, Y: 330}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		CalculatePathWithOptions(grid, start, goal, Options{Mode: ModeJumpPoint})
+	}
+}
+
+func BenchmarkReferenceAstar(b *testing.B) {
+	grid := loadGrid()
+
+	start := data.Position{X: 336, Y: 701}
+	goal := data.Position{X: 11, Y: 330}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		referenceCalculatePath(grid, start, goal, false)
+	}
+}
+
 func TestAstar(t *testing.T) {
 	grid := loadGrid()
 
@@ -39,6 +65,61 @@ func TestAstar(t *testing.T) {
 	}
 }
 
+func TestAstarMatchesReference(t *testing.T) {
+	grid := loadGrid()
+	positions := randomWalkablePositions(grid, 40)
+
+	for i := 0; i+1 < len(positions); i += 2 {
+		start, goal := positions[i], positions[i+1]
+		for _, canTeleport := range []bool{false, true} {
+			expectedPath, expectedDist, expectedFound := referenceCalculatePath(grid, start, goal, canTeleport)
+			path, dist, found := CalculatePath(grid, start, goal, canTeleport)
+			if found != expectedFound || dist != expectedDist || !slices.Equal(path, expectedPath) {
+				t.Fatalf("Path from %v to %v (teleport: %t) differs from reference: got %d tiles, expected %d", start, goal, canTeleport, dist, expectedDist)
+			}
+		}
+	}
+}
+
+func TestJumpPoint(t *testing.T) {
+	grid := loadGrid()
+	positions := randomWalkablePositions(grid, 40)
+
+	for i := 0; i+1 < len(positions); i += 2 {
+		start, goal := positions[i], positions[i+1]
+		_, _, expectedFound := CalculatePath(grid, start, goal, false)
+		path, dist, found := CalculatePathWithOptions(grid, start, goal, Options{Mode: ModeJumpPoint})
+		if found != expectedFound {
+			t.Fatalf("Path from %v to %v: found %t, expected %t", start, goal, found, expectedFound)
+		}
+		if !found {
+			continue
+		}
+		if dist != len(path) || path[0] != start || path[len(path)-1] != goal {
+			t.Fatalf("Path from %v to %v is not valid", start, goal)
+		}
+		for j := 1; j < len(path); j++ {
+			dx, dy := path[j].X-path[j-1].X, path[j].Y-path[j-1].Y
+			if dx < -1 || dx > 1 || dy < -1 || dy > 1 || isBlocked(grid, path[j].X, path[j].Y, false) {
+				t.Fatalf("Path from %v to %v has an invalid step %v -> %v", start, goal, path[j-1], path[j])
+			}
+		}
+	}
+}
+
+func randomWalkablePositions(grid *game.Grid, count int) []data.Position {
+	r := rand.New(rand.NewSource(1))
+	positions := make([]data.Position, 0, count)
+	for len(positions) < count {
+		p := data.Position{X: r.Intn(grid.Width), Y: r.Intn(grid.Height)}
+		if grid.CollisionGrid[p.Y][p.X] == game.CollisionTypeWalkable {
+			positions = append(positions, p)
+		}
+	}
+
+	return positions
+}
+
 func loadGrid() *game.Grid {
 	var grid game.Grid
 	file, err := os.Open("durance_of_hate_grid.bin")
@@ -54,3 +135,72 @@ func loadGrid() *game.Grid {
 
 	return &grid
 }
+
+func TestPlanner(t *testing.T) {
+	grid := loadGrid()
+	positions := randomWalkablePositions(grid, 20)
+	planner := NewPlanner()
+
+	for i := 0; i+1 < len(positions); i += 2 {
+		start, goal := positions[i], positions[i+1]
+		planner.Reset(grid, goal)
+
+		expected, _, expectedFound := CalculatePath(grid, start, goal, false)
+		path, _, found := planner.Path(start)
+		if found != expectedFound {
+			t.Fatalf("Path from %v to %v: found %t, expected %t", start, goal, found, expectedFound)
+		}
+		if !found {
+			continue
+		}
+		if pathCost(grid, path) != pathCost(grid, expected) {
+			t.Fatalf("Path from %v to %v costs %d, expected %d", start, goal, pathCost(grid, path), pathCost(grid, expected))
+		}
+
+		// Move a few steps and block the rest of the path with monsters, the planner should repair the previous search
+		start = path[min(5, len(path)-1)]
+		var changed []data.Position
+		for j := len(path) / 3; j < len(path)/2; j++ {
+			if path[j] != goal && path[j] != start {
+				changed = append(changed, path[j])
+			}
+		}
+		modified := grid.Copy()
+		for _, c := range changed {
+			modified.CollisionGrid[c.Y][c.X] = game.CollisionTypeMonster
+		}
+		planner.grid = modified
+		planner.UpdateCells(changed)
+
+		expected, _, _ = CalculatePath(modified, start, goal, false)
+		path, _, found = planner.Path(start)
+		if !found || pathCost(modified, path) != pathCost(modified, expected) {
+			t.Fatalf("Replanned path from %v to %v costs %d, expected %d", start, goal, pathCost(modified, path), pathCost(modified, expected))
+		}
+	}
+}
+
+func pathCost(grid *game.Grid, path []data.Position) int {
+	cost := 0
+	for _, p := range path[1:] {
+		cost += getCost(grid.CollisionGrid[p.Y][p.X], false)
+	}
+
+	return cost
+}
+
+func TestStepDistances(t *testing.T) {
+	grid := loadGrid()
+	positions := randomWalkablePositions(grid, 10)
+
+	distances := StepDistances(grid, positions[0], positions[1:], false)
+	for i, target := range positions[1:] {
+		path, _, found := CalculatePathWithOptions(grid, positions[0], target, Options{Mode: ModeJumpPoint})
+		if found != (distances[i] != -1) {
+			t.Fatalf("Distance to %v: got %d, path found %t", target, distances[i], found)
+		}
+		if found && distances[i] > len(path)-1 {
+			t.Fatalf("Distance to %v is %d, longer than a valid path of %d steps", target, distances[i], len(path)-1)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package astar
+
+import (
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/koolo/internal/game"
+)
+
+// StepDistances returns the number of steps needed to reach every target from the given position, -1 if unreachable.
+// Movement rules are the same as CalculatePath but tile costs are ignored, every step counts as one, so it can be
+// computed for multiple targets at once with a single breadth first search. Positions are relative to the grid.
+func StepDistances(g *game.Grid, from data.Position, targets []data.Position, canTeleport bool) []int {
+	distances := make([]int, len(targets))
+	for i := range distances {
+		distances[i] = -1
+	}
+	if !inBounds(g, from) {
+		return distances
+	}
+
+	s := acquireSearch(g.Width, g.Height)
+	defer releaseSearch(s)
+
+	// Visited tiles store their distance + 1 as cost, pending targets are marked with cost 0 and cameFrom pointing to
+	// the first target on that tile
+	pending := 0
+	for i, t := range targets {
+		if !inBounds(g, t) {
+			continue
+		}
+		idx := s.index(t)
+		if s.costOf(idx) == 0 {
+			continue
+		}
+		s.setCost(idx, 0)
+		s.cameFrom[idx] = int32(i)
+		pending++
+	}
+
+	queue := s.queue[:0]
+	visit := func(idx, dist int) {
+		// Tile used as target marker, resolve every target sharing this position
+		if s.costOf(idx) == 0 {
+			target := targets[s.cameFrom[idx]]
+			for i, t := range targets {
+				if t == target && distances[i] == -1 {
+					distances[i] = dist
+				}
+			}
+			pending--
+		}
+		s.setCost(idx, dist+1)
+		queue = append(queue, int32(idx))
+	}
+
+	visit(s.index(from), 0)
+	for head := 0; head < len(queue) && pending > 0; head++ {
+		idx := int(queue[head])
+		current := Node{Position: s.position(idx)}
+		dist := s.costOf(idx) - 1
+
+		updateNeighbors(g, &current, &s.neighbors, canTeleport)
+		for _, n := range s.neighbors {
+			nIdx := s.index(n)
+			if c := s.costOf(nIdx); c == 0 || c == unreachable {
+				visit(nIdx, dist+1)
+			}
+		}
+	}
+	s.queue = queue
+
+	return distances
+}
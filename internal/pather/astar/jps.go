@@ -0,0 +1,340 @@
+package astar
+
+import (
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/koolo/internal/game"
+)
+
+const (
+	tileOpen uint8 = iota
+	tileBlocked
+	// tileCostly is passable but with a cost different from a walkable tile
+	tileCostly
+	// tileNearCostly is a walkable tile next to a costly one, jumps stop here so the costly tile can be evaluated
+	tileNearCostly
+)
+
+const (
+	rayUnknown  int16 = -2
+	rayNotFound int16 = -1
+)
+
+// jumpPointSearch is a hybrid jump point search: jumps only travel over CollisionTypeWalkable tiles (uniform cost), any other
+// passable tile (objects, monsters, low priority, teleport over) and the tiles around it stop the jump and are expanded
+// one step at a time like regular A*. Diagonal moves never cut corners, same rule as updateNeighbors.
+func (s *search) jumpPointSearch(g *game.Grid, start, goal data.Position, canTeleport bool) ([]data.Position, int, bool) {
+	s.prepareJumpBuffers()
+
+	startIdx := s.index(start)
+	s.push(Node{Position: start, Cost: 0, Priority: chebyshev(start, goal)})
+	s.setCost(startIdx, 0)
+
+	for len(s.open) > 0 {
+		current := s.pop()
+		currentIdx := s.index(current.Position)
+		currentCost := s.costOf(currentIdx)
+
+		// Stale entry, this node was already reached with a lower cost
+		if current.Cost > currentCost {
+			continue
+		}
+
+		if current.Position == goal {
+			path := s.buildPath(g, start, goal, true)
+			return path, len(path), true
+		}
+
+		// Tiles around costly ones are expanded one step at a time like regular A*, jumps only start from uniform cost regions
+		canJump := s.tileClass(g, current.X, current.Y, canTeleport) == tileOpen
+		if canJump {
+			s.jpsNeighbors(g, current, currentIdx == startIdx, canTeleport)
+		} else {
+			updateNeighbors(g, &current, &s.neighbors, canTeleport)
+		}
+
+		for _, neighbor := range s.neighbors {
+			jumpPoint, steps, found := neighbor, 1, true
+			if canJump {
+				dx, dy := sign(neighbor.X-current.X), sign(neighbor.Y-current.Y)
+				jumpPoint, steps, found = s.jump(g, neighbor, dx, dy, goal, canTeleport)
+				if !found {
+					continue
+				}
+			}
+
+			tileType := g.CollisionGrid[jumpPoint.Y][jumpPoint.X]
+			teleportStreak := 0
+			if tileType == game.CollisionTypeTeleportOver {
+				// Tiles in between are always walkable, so the streak only continues on single steps
+				if steps == 1 {
+					teleportStreak = current.TpStreak
+				}
+				teleportStreak++
+			}
+			if teleportStreak > MaxConsecutiveTeleportOver {
+				continue
+			}
+
+			newCost := currentCost + steps - 1 + getCost(tileType, canTeleport)
+
+			idx := s.index(jumpPoint)
+			if newCost < s.costOf(idx) {
+				s.setCost(idx, newCost)
+				priority := newCost + chebyshev(jumpPoint, goal)
+				s.push(Node{Position: jumpPoint, Cost: newCost, Priority: priority, TpStreak: teleportStreak})
+				s.cameFrom[idx] = int32(currentIdx)
+			}
+		}
+	}
+
+	return nil, 0, false
+}
+
+// jpsNeighbors fills s.neighbors with the directions to explore from an uniform cost node, pruned by the direction we came from
+func (s *search) jpsNeighbors(g *game.Grid, node Node, isStart, canTeleport bool) {
+	x, y := node.X, node.Y
+	if isStart {
+		updateNeighbors(g, &node, &s.neighbors, canTeleport)
+		return
+	}
+
+	parent := s.position(int(s.cameFrom[s.index(node.Position)]))
+	dx, dy := sign(x-parent.X), sign(y-parent.Y)
+
+	s.neighbors = s.neighbors[:0]
+	walkable := func(px, py int) bool {
+		return !isBlocked(g, px, py, canTeleport)
+	}
+	add := func(px, py int) {
+		s.neighbors = append(s.neighbors, data.Position{X: px, Y: py})
+	}
+
+	switch {
+	case dx != 0 && dy != 0:
+		nextY, nextX := walkable(x, y+dy), walkable(x+dx, y)
+		if nextY {
+			add(x, y+dy)
+		}
+		if nextX {
+			add(x+dx, y)
+		}
+		if nextX && nextY && walkable(x+dx, y+dy) {
+			add(x+dx, y+dy)
+		}
+	case dx != 0:
+		next, down, up := walkable(x+dx, y), walkable(x, y+1), walkable(x, y-1)
+		if next {
+			add(x+dx, y)
+			if down && walkable(x+dx, y+1) {
+				add(x+dx, y+1)
+			}
+			if up && walkable(x+dx, y-1) {
+				add(x+dx, y-1)
+			}
+		}
+		if down {
+			add(x, y+1)
+		}
+		if up {
+			add(x, y-1)
+		}
+	default:
+		next, right, left := walkable(x, y+dy), walkable(x+1, y), walkable(x-1, y)
+		if next {
+			add(x, y+dy)
+			if right && walkable(x+1, y+dy) {
+				add(x+1, y+dy)
+			}
+			if left && walkable(x-1, y+dy) {
+				add(x-1, y+dy)
+			}
+		}
+		if right {
+			add(x+1, y)
+		}
+		if left {
+			add(x-1, y)
+		}
+	}
+}
+
+// jump travels from p in the given direction until it finds a jump point, returning it and the number of steps taken
+func (s *search) jump(g *game.Grid, p data.Position, dx, dy int, goal data.Position, canTeleport bool) (data.Position, int, bool) {
+	if dx == 0 || dy == 0 {
+		jumpPoint, found := s.jumpStraight(g, p.X, p.Y, dx, dy, goal, canTeleport)
+		return jumpPoint, max(abs(jumpPoint.X-p.X), abs(jumpPoint.Y-p.Y)) + 1, found
+	}
+
+	steps := 1
+	x, y := p.X, p.Y
+	for {
+		class := s.tileClass(g, x, y, canTeleport)
+		if class == tileBlocked {
+			return data.Position{}, 0, false
+		}
+
+		pos := data.Position{X: x, Y: y}
+		if pos == goal || class != tileOpen {
+			return pos, steps, true
+		}
+
+		if s.hasJumpPoint(g, x+dx, y, dx, 0, goal, canTeleport) || s.hasJumpPoint(g, x, y+dy, 0, dy, goal, canTeleport) {
+			return pos, steps, true
+		}
+
+		// Diagonal moves are not allowed to cut corners
+		if isBlocked(g, x+dx, y, canTeleport) || isBlocked(g, x, y+dy, canTeleport) {
+			return data.Position{}, 0, false
+		}
+
+		x += dx
+		y += dy
+		steps++
+	}
+}
+
+// jumpStraight follows an horizontal or vertical ray. The result is stored for every tile crossed, diagonal jumps check
+// the same rays again and again.
+func (s *search) jumpStraight(g *game.Grid, x, y, dx, dy int, goal data.Position, canTeleport bool) (data.Position, bool) {
+	ray := rayIndex(dx, dy)
+	startX, startY := x, y
+	jumpPoint, found := data.Position{}, false
+
+	for {
+		class := s.tileClass(g, x, y, canTeleport)
+		if class == tileBlocked {
+			break
+		}
+
+		idx := y*s.width + x
+		if s.rayStamp[idx] == s.gen && s.rays[idx][ray] != rayUnknown {
+			if dist := s.rays[idx][ray]; dist != rayNotFound {
+				jumpPoint, found = data.Position{X: x + dx*int(dist), Y: y + dy*int(dist)}, true
+			}
+			break
+		}
+
+		pos := data.Position{X: x, Y: y}
+		if pos == goal || class != tileOpen {
+			jumpPoint, found = pos, true
+			break
+		}
+
+		// Forced neighbors
+		if dx != 0 {
+			if (!isBlocked(g, x, y-1, canTeleport) && isBlocked(g, x-dx, y-1, canTeleport)) ||
+				(!isBlocked(g, x, y+1, canTeleport) && isBlocked(g, x-dx, y+1, canTeleport)) {
+				jumpPoint, found = pos, true
+				break
+			}
+		} else if (!isBlocked(g, x-1, y, canTeleport) && isBlocked(g, x-1, y-dy, canTeleport)) ||
+			(!isBlocked(g, x+1, y, canTeleport) && isBlocked(g, x+1, y-dy, canTeleport)) {
+			jumpPoint, found = pos, true
+			break
+		}
+
+		x += dx
+		y += dy
+	}
+
+	for cx, cy := startX, startY; cx != x || cy != y; cx, cy = cx+dx, cy+dy {
+		idx := cy*s.width + cx
+		if s.rayStamp[idx] != s.gen {
+			s.rayStamp[idx] = s.gen
+			s.rays[idx] = [4]int16{rayUnknown, rayUnknown, rayUnknown, rayUnknown}
+		}
+		s.rays[idx][ray] = rayNotFound
+		if found {
+			s.rays[idx][ray] = int16(max(abs(jumpPoint.X-cx), abs(jumpPoint.Y-cy)))
+		}
+	}
+
+	return jumpPoint, found
+}
+
+// hasJumpPoint checks the straight rays followed while jumping diagonally. Rays ending at a costly tile are ignored, walls
+// are usually surrounded by low priority tiles and otherwise every diagonal step would become a jump point. Those tiles are
+// still reached by straight jumps and by the one step expansion around costly tiles.
+func (s *search) hasJumpPoint(g *game.Grid, x, y, dx, dy int, goal data.Position, canTeleport bool) bool {
+	p, found := s.jumpStraight(g, x, y, dx, dy, goal, canTeleport)
+	if !found {
+		return false
+	}
+
+	return p == goal || s.tileClass(g, p.X, p.Y, canTeleport) == tileOpen
+}
+
+func rayIndex(dx, dy int) int {
+	switch {
+	case dx > 0:
+		return 0
+	case dx < 0:
+		return 1
+	case dy > 0:
+		return 2
+	}
+
+	return 3
+}
+
+// tileClass classifies the tile for jumping, results are memoized for the current search since rays cross the same tiles often
+func (s *search) tileClass(g *game.Grid, x, y int, canTeleport bool) uint8 {
+	if x < 0 || x >= g.Width || y < 0 || y >= g.Height {
+		return tileBlocked
+	}
+
+	idx := y*s.width + x
+	if s.classStamp[idx] == s.gen {
+		return s.class[idx]
+	}
+
+	class := tileOpen
+	switch {
+	case isBlocked(g, x, y, canTeleport):
+		class = tileBlocked
+	case g.CollisionGrid[y][x] != game.CollisionTypeWalkable:
+		class = tileCostly
+	case hasCostlyNeighbor(g, x, y, canTeleport):
+		class = tileNearCostly
+	}
+	s.classStamp[idx] = s.gen
+	s.class[idx] = class
+
+	return class
+}
+
+// hasCostlyNeighbor returns true if any surrounding tile is passable but with a cost different from a walkable tile
+func hasCostlyNeighbor(g *game.Grid, x, y int, canTeleport bool) bool {
+	for _, d := range directions {
+		nx, ny := x+d.X, y+d.Y
+		if nx < 0 || nx >= g.Width || ny < 0 || ny >= g.Height {
+			continue
+		}
+		switch g.CollisionGrid[ny][nx] {
+		case game.CollisionTypeNonWalkable, game.CollisionTypeWalkable:
+			continue
+		case game.CollisionTypeTeleportOver:
+			if !canTeleport {
+				continue
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// chebyshev is the exact distance on an empty grid where diagonal moves cost the same as straight ones. It's a stronger
+// heuristic than the one used by A* mode, jump point search only pays off when the search is well directed to the goal.
+func chebyshev(a, b data.Position) int {
+	return max(abs(a.X-b.X), abs(a.Y-b.Y))
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+
+	return v
+}
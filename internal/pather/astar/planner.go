@@ -0,0 +1,327 @@
+package astar
+
+import (
+	"math"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/koolo/internal/game"
+)
+
+const unreachable = math.MaxInt32
+
+type plannerEntry struct {
+	idx    int32
+	k1, k2 int
+}
+
+// Planner incrementally calculates paths to a fixed goal (D* Lite). The search runs backwards from the goal, so when the
+// start moves or some tiles change (monsters, objects...) only the affected part of the previous search is repaired.
+// Teleport over tiles are never crossed, the consecutive teleport limit can't be tracked on a backwards search.
+type Planner struct {
+	grid      *game.Grid
+	goal      data.Position
+	last      data.Position
+	started   bool
+	km        int
+	width     int
+	gen       uint32
+	stamp     []uint32
+	g         []int32
+	rhs       []int32
+	open      []plannerEntry
+	neighbors []data.Position
+}
+
+func NewPlanner() *Planner {
+	return &Planner{}
+}
+
+// Reset prepares the planner for a new grid or goal, previous buffers are reused
+func (p *Planner) Reset(g *game.Grid, goal data.Position) {
+	size := g.Width * g.Height
+	if cap(p.stamp) < size {
+		p.stamp = make([]uint32, size)
+		p.g = make([]int32, size)
+		p.rhs = make([]int32, size)
+	}
+	p.stamp = p.stamp[:size]
+	p.g = p.g[:size]
+	p.rhs = p.rhs[:size]
+
+	p.gen++
+	if p.gen == 0 {
+		clear(p.stamp)
+		p.gen = 1
+	}
+
+	p.grid = g
+	p.goal = goal
+	p.width = g.Width
+	p.started = false
+	p.km = 0
+	p.open = p.open[:0]
+
+	if !inBounds(g, goal) {
+		return
+	}
+
+	idx := p.index(goal)
+	p.touch(idx)
+	p.rhs[idx] = 0
+	k1, k2 := p.calculateKey(idx, goal)
+	p.push(idx, k1, k2)
+}
+
+func (p *Planner) Grid() *game.Grid {
+	return p.grid
+}
+
+func (p *Planner) Goal() data.Position {
+	return p.goal
+}
+
+// UpdateCells must be called after changing the collision type of the given tiles (grid relative positions)
+func (p *Planner) UpdateCells(cells []data.Position) {
+	if p.grid == nil || !p.started {
+		return
+	}
+
+	for _, c := range cells {
+		if !inBounds(p.grid, c) {
+			continue
+		}
+		p.updateVertex(c)
+		for _, d := range directions {
+			n := data.Position{X: c.X + d.X, Y: c.Y + d.Y}
+			if inBounds(p.grid, n) {
+				p.updateVertex(n)
+			}
+		}
+	}
+}
+
+// Path returns the path from start to the goal, results have the same format as CalculatePath
+func (p *Planner) Path(start data.Position) ([]data.Position, int, bool) {
+	if p.grid == nil || !inBounds(p.grid, start) || !inBounds(p.grid, p.goal) {
+		return nil, 0, false
+	}
+
+	if p.started {
+		p.km += chebyshev(p.last, start)
+	}
+	p.started = true
+	p.last = start
+
+	p.computeShortestPath(start)
+
+	if p.gOf(p.index(start)) == unreachable {
+		return nil, 0, false
+	}
+
+	path := []data.Position{start}
+	maxSteps := p.grid.Width * p.grid.Height
+	for current := start; current != p.goal; {
+		updateNeighbors(p.grid, &Node{Position: current}, &p.neighbors, false)
+
+		best, bestCost := data.Position{}, unreachable
+		for _, n := range p.neighbors {
+			g := p.gOf(p.index(n))
+			if g == unreachable {
+				continue
+			}
+			if cost := g + getCost(p.grid.CollisionGrid[n.Y][n.X], false); cost < bestCost {
+				best, bestCost = n, cost
+			}
+		}
+
+		if bestCost == unreachable || len(path) > maxSteps {
+			return nil, 0, false
+		}
+		path = append(path, best)
+		current = best
+	}
+
+	return path, len(path), true
+}
+
+func (p *Planner) computeShortestPath(start data.Position) {
+	startIdx := p.index(start)
+
+	for len(p.open) > 0 {
+		top := p.open[0]
+		u := int(top.idx)
+
+		// Lazy deletion, consistent tiles are not in the queue anymore
+		if p.gOf(u) == p.rhsOf(u) {
+			p.pop()
+			continue
+		}
+
+		sk1, sk2 := p.calculateKey(startIdx, start)
+		if !keyLess(top.k1, top.k2, sk1, sk2) && p.gOf(startIdx) == p.rhsOf(startIdx) {
+			return
+		}
+
+		p.pop()
+		k1, k2 := p.calculateKey(u, start)
+		if keyLess(top.k1, top.k2, k1, k2) {
+			p.push(u, k1, k2)
+			continue
+		}
+		// Outdated entry, the tile was pushed again with a lower key
+		if keyLess(k1, k2, top.k1, top.k2) {
+			continue
+		}
+
+		pos := p.position(u)
+		if p.gOf(u) > p.rhsOf(u) {
+			// Overconsistent, the tile got cheaper, only predecessors going through it can improve
+			p.g[u] = p.rhs[u]
+			for _, d := range directions {
+				n := data.Position{X: pos.X + d.X, Y: pos.Y + d.Y}
+				cost := p.edgeCost(n, pos)
+				if cost == unreachable || n == p.goal {
+					continue
+				}
+				ni := p.index(n)
+				p.touch(ni)
+				if newRhs := int(p.g[u]) + cost; newRhs < int(p.rhs[ni]) {
+					p.rhs[ni] = int32(newRhs)
+					p.pushIfInconsistent(ni)
+				}
+			}
+			continue
+		}
+
+		// Underconsistent, predecessors depending on this tile must be recalculated
+		oldG := p.gOf(u)
+		p.g[u] = unreachable
+		p.updateVertex(pos)
+		for _, d := range directions {
+			n := data.Position{X: pos.X + d.X, Y: pos.Y + d.Y}
+			if cost := p.edgeCost(n, pos); cost != unreachable && p.rhsOf(p.index(n)) == oldG+cost {
+				p.updateVertex(n)
+			}
+		}
+	}
+}
+
+func (p *Planner) updateVertex(pos data.Position) {
+	idx := p.index(pos)
+	p.touch(idx)
+
+	if pos != p.goal {
+		best := unreachable
+		updateNeighbors(p.grid, &Node{Position: pos}, &p.neighbors, false)
+		for _, n := range p.neighbors {
+			g := p.gOf(p.index(n))
+			if g == unreachable {
+				continue
+			}
+			best = min(best, g+getCost(p.grid.CollisionGrid[n.Y][n.X], false))
+		}
+		p.rhs[idx] = int32(best)
+	}
+
+	p.pushIfInconsistent(idx)
+}
+
+func (p *Planner) pushIfInconsistent(idx int) {
+	if p.g[idx] != p.rhs[idx] {
+		k1, k2 := p.calculateKey(idx, p.last)
+		p.push(idx, k1, k2)
+	}
+}
+
+// edgeCost returns the cost of moving between two adjacent tiles, using the same rules as updateNeighbors
+func (p *Planner) edgeCost(from, to data.Position) int {
+	if !inBounds(p.grid, from) || isBlocked(p.grid, to.X, to.Y, false) {
+		return unreachable
+	}
+	dx, dy := to.X-from.X, to.Y-from.Y
+	if dx != 0 && dy != 0 && (isBlocked(p.grid, from.X+dx, from.Y, false) || isBlocked(p.grid, from.X, from.Y+dy, false)) {
+		return unreachable
+	}
+
+	return getCost(p.grid.CollisionGrid[to.Y][to.X], false)
+}
+
+func (p *Planner) calculateKey(idx int, start data.Position) (int, int) {
+	m := min(p.gOf(idx), p.rhsOf(idx))
+	if m == unreachable {
+		return unreachable, unreachable
+	}
+
+	return m + chebyshev(start, p.position(idx)) + p.km, m
+}
+
+func (p *Planner) touch(idx int) {
+	if p.stamp[idx] != p.gen {
+		p.stamp[idx] = p.gen
+		p.g[idx] = unreachable
+		p.rhs[idx] = unreachable
+	}
+}
+
+func (p *Planner) gOf(idx int) int {
+	if p.stamp[idx] != p.gen {
+		return unreachable
+	}
+
+	return int(p.g[idx])
+}
+
+func (p *Planner) rhsOf(idx int) int {
+	if p.stamp[idx] != p.gen {
+		return unreachable
+	}
+
+	return int(p.rhs[idx])
+}
+
+func (p *Planner) index(pos data.Position) int {
+	return pos.Y*p.width + pos.X
+}
+
+func (p *Planner) position(idx int) data.Position {
+	return data.Position{X: idx % p.width, Y: idx / p.width}
+}
+
+func (p *Planner) push(idx, k1, k2 int) {
+	p.open = append(p.open, plannerEntry{idx: int32(idx), k1: k1, k2: k2})
+	j := len(p.open) - 1
+	for j > 0 {
+		i := (j - 1) / 2
+		if !keyLess(p.open[j].k1, p.open[j].k2, p.open[i].k1, p.open[i].k2) {
+			break
+		}
+		p.open[i], p.open[j] = p.open[j], p.open[i]
+		j = i
+	}
+}
+
+func (p *Planner) pop() {
+	n := len(p.open) - 1
+	p.open[0] = p.open[n]
+	p.open = p.open[:n]
+
+	i := 0
+	for {
+		j := 2*i + 1
+		if j >= n {
+			break
+		}
+		if j2 := j + 1; j2 < n && keyLess(p.open[j2].k1, p.open[j2].k2, p.open[j].k1, p.open[j].k2) {
+			j = j2
+		}
+		if !keyLess(p.open[j].k1, p.open[j].k2, p.open[i].k1, p.open[i].k2) {
+			break
+		}
+		p.open[i], p.open[j] = p.open[j], p.open[i]
+		i = j
+	}
+}
+
+func keyLess(a1, a2, b1, b2 int) bool {
+	return a1 < b1 || (a1 == b1 && a2 < b2)
+}
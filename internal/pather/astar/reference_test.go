@@ -0,0 +1,71 @@
+package astar
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/koolo/internal/game"
+)
+
+// referenceCalculatePath is the previous CalculatePath implementation, kept to ensure the optimized A* returns the same paths
+func referenceCalculatePath(g *game.Grid, start, goal data.Position, canTeleport bool) ([]data.Position, int, bool) {
+	pq := make(PriorityQueue, 0)
+	heap.Init(&pq)
+
+	costSoFar := make([][]int, g.Width)
+	cameFrom := make([][]data.Position, g.Width)
+	for i := range costSoFar {
+		costSoFar[i] = make([]int, g.Height)
+		cameFrom[i] = make([]data.Position, g.Height)
+		for j := range costSoFar[i] {
+			costSoFar[i][j] = math.MaxInt32
+		}
+	}
+
+	startNode := &Node{Position: start, Cost: 0, Priority: heuristic(start, goal)}
+	heap.Push(&pq, startNode)
+	costSoFar[start.X][start.Y] = 0
+
+	neighbors := make([]data.Position, 0, 8)
+
+	for pq.Len() > 0 {
+		current := heap.Pop(&pq).(*Node)
+
+		if current.Position == goal {
+			var path []data.Position
+			for p := goal; p != start; p = cameFrom[p.X][p.Y] {
+				if g.CollisionGrid[p.Y][p.X] == game.CollisionTypeTeleportOver {
+					continue
+				}
+				path = append([]data.Position{p}, path...)
+			}
+			path = append([]data.Position{start}, path...)
+			return path, len(path), true
+		}
+
+		updateNeighbors(g, current, &neighbors, canTeleport)
+
+		for _, neighbor := range neighbors {
+			tileType := g.CollisionGrid[neighbor.Y][neighbor.X]
+
+			teleportStreak := 0
+			if tileType == game.CollisionTypeTeleportOver {
+				teleportStreak = current.TpStreak + 1
+			}
+			if teleportStreak > MaxConsecutiveTeleportOver {
+				continue
+			}
+
+			newCost := costSoFar[current.X][current.Y] + getCost(tileType, canTeleport)
+			if newCost < costSoFar[neighbor.X][neighbor.Y] {
+				costSoFar[neighbor.X][neighbor.Y] = newCost
+				priority := newCost + int(0.5*float64(heuristic(neighbor, goal)))
+				heap.Push(&pq, &Node{Position: neighbor, Cost: newCost, Priority: priority, TpStreak: teleportStreak})
+				cameFrom[neighbor.X][neighbor.Y] = current.Position
+			}
+		}
+	}
+
+	return nil, 0, false
+}
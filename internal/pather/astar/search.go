@@ -0,0 +1,188 @@
+package astar
+
+import (
+	"math"
+	"slices"
+	"sync"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/koolo/internal/game"
+)
+
+// search holds all the buffers needed by a single path calculation. Buffers are flat (y*width+x) and reused across calls,
+// instead of clearing them on every call we use a generation counter: a tile cost is only valid if its stamp matches.
+type search struct {
+	width    int
+	height   int
+	gen      uint32
+	stamp    []uint32
+	cost     []int32
+	cameFrom []int32
+	// Jump point search only, tile classes and straight ray results are memoized using their own stamps
+	classStamp []uint32
+	class      []uint8
+	rayStamp   []uint32
+	rays       [][4]int16
+	open       []Node
+	queue      []int32
+	neighbors  []data.Position
+}
+
+var searchPool = sync.Pool{
+	New: func() any {
+		return &search{}
+	},
+}
+
+func acquireSearch(width, height int) *search {
+	s := searchPool.Get().(*search)
+	s.reset(width, height)
+
+	return s
+}
+
+func releaseSearch(s *search) {
+	searchPool.Put(s)
+}
+
+func (s *search) reset(width, height int) {
+	size := width * height
+	if cap(s.stamp) < size {
+		s.stamp = make([]uint32, size)
+		s.cost = make([]int32, size)
+		s.cameFrom = make([]int32, size)
+	}
+	s.stamp = s.stamp[:size]
+	s.cost = s.cost[:size]
+	s.cameFrom = s.cameFrom[:size]
+	s.width = width
+	s.height = height
+	s.open = s.open[:0]
+
+	s.gen++
+	// On overflow stamps from older generations could collide with the new one
+	if s.gen == 0 {
+		clear(s.stamp)
+		clear(s.classStamp)
+		clear(s.rayStamp)
+		s.gen = 1
+	}
+}
+
+// prepareJumpBuffers allocates the buffers only needed by jump point search, so regular A* searches don't pay for them
+func (s *search) prepareJumpBuffers() {
+	size := len(s.stamp)
+	if cap(s.classStamp) < size {
+		s.classStamp = make([]uint32, size)
+		s.class = make([]uint8, size)
+		s.rayStamp = make([]uint32, size)
+		s.rays = make([][4]int16, size)
+	}
+	s.classStamp = s.classStamp[:size]
+	s.class = s.class[:size]
+	s.rayStamp = s.rayStamp[:size]
+	s.rays = s.rays[:size]
+}
+
+func (s *search) index(p data.Position) int {
+	return p.Y*s.width + p.X
+}
+
+func (s *search) position(idx int) data.Position {
+	return data.Position{X: idx % s.width, Y: idx / s.width}
+}
+
+func (s *search) costOf(idx int) int {
+	if s.stamp[idx] != s.gen {
+		return math.MaxInt32
+	}
+
+	return int(s.cost[idx])
+}
+
+func (s *search) setCost(idx, cost int) {
+	s.stamp[idx] = s.gen
+	s.cost[idx] = int32(cost)
+}
+
+// buildPath walks back from goal to start, when interpolate is set the gaps between jump points are filled
+func (s *search) buildPath(g *game.Grid, start, goal data.Position, interpolate bool) []data.Position {
+	startIdx := s.index(start)
+	path := make([]data.Position, 0, 64)
+
+	for idx := s.index(goal); idx != startIdx; idx = int(s.cameFrom[idx]) {
+		p := s.position(idx)
+		if !interpolate {
+			if g.CollisionGrid[p.Y][p.X] != game.CollisionTypeTeleportOver {
+				path = append(path, p)
+			}
+			continue
+		}
+
+		// Add every tile between this jump point and its parent, parent excluded
+		parent := s.position(int(s.cameFrom[idx]))
+		dx, dy := sign(parent.X-p.X), sign(parent.Y-p.Y)
+		for step := p; step != parent; step = (data.Position{X: step.X + dx, Y: step.Y + dy}) {
+			if g.CollisionGrid[step.Y][step.X] != game.CollisionTypeTeleportOver {
+				path = append(path, step)
+			}
+		}
+	}
+	path = append(path, start)
+	slices.Reverse(path)
+
+	return path
+}
+
+// push and pop replicate container/heap ordering, so results are the same as the original implementation,
+// but without boxing every node into an interface
+func (s *search) push(n Node) {
+	s.open = append(s.open, n)
+	j := len(s.open) - 1
+	for {
+		i := (j - 1) / 2 // parent
+		if i == j || s.open[j].Priority >= s.open[i].Priority {
+			break
+		}
+		s.open[i], s.open[j] = s.open[j], s.open[i]
+		j = i
+	}
+}
+
+func (s *search) pop() Node {
+	n := len(s.open) - 1
+	s.open[0], s.open[n] = s.open[n], s.open[0]
+
+	i := 0
+	for {
+		j1 := 2*i + 1
+		if j1 >= n || j1 < 0 {
+			break
+		}
+		j := j1
+		if j2 := j1 + 1; j2 < n && s.open[j2].Priority < s.open[j1].Priority {
+			j = j2
+		}
+		if s.open[j].Priority >= s.open[i].Priority {
+			break
+		}
+		s.open[i], s.open[j] = s.open[j], s.open[i]
+		i = j
+	}
+
+	node := s.open[n]
+	s.open = s.open[:n]
+
+	return node
+}
+
+func sign(v int) int {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+
+	return 0
+}
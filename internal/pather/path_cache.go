@@ -0,0 +1,288 @@
+package pather
+
+import (
+	"fmt"
+	"hash/fnv"
+	"slices"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/d2go/pkg/data/area"
+	"github.com/hectorgimenez/d2go/pkg/data/npc"
+	"github.com/hectorgimenez/koolo/internal/game"
+	"github.com/hectorgimenez/koolo/internal/pather/astar"
+)
+
+// maxCachedGrids is the number of prepared grids kept in memory, usually the current area and the merged one with the next area
+const maxCachedGrids = 3
+
+// gridKey identifies a prepared grid, source is the area grid pointer which is different on every game
+type gridKey struct {
+	source      *game.Grid
+	area        area.ID
+	mergedWith  area.ID
+	canTeleport bool
+	arcane      bool
+}
+
+// cachedGrid is a copy of the area collision grid (or merged grids) kept between path calculations. Dynamic obstacles
+// (objects, monsters, barricade towers) are updated in place, so we don't need to copy the whole grid on every call.
+type cachedGrid struct {
+	key      gridKey
+	base     *game.Grid // static collision grid, without obstacles, never modified
+	grid     *game.Grid // base grid with the obstacles applied, used by the path finding algorithms
+	applied  map[int]game.CollisionType
+	planner  *astar.Planner
+	lastUsed uint64
+
+	// Last calculated path, reused while we keep moving over it and nothing changes close to it
+	path      Path
+	pathTo    data.Position
+	pathTiles map[int]struct{}
+	doors     uint64
+}
+
+func (pf *PathFinder) cachedGridFor(to data.Position, canTeleport bool) (*cachedGrid, error) {
+	a := pf.data.AreaData
+	key := gridKey{source: a.Grid, area: a.Area, canTeleport: canTeleport}
+
+	var destination game.AreaData
+	if !a.IsInside(to) {
+		found := false
+		for _, lvl := range a.AdjacentLevels {
+			if dst := pf.data.Areas[lvl.Area]; dst.IsInside(to) {
+				destination, found = dst, true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("destination grid not found")
+		}
+		key.mergedWith = destination.Area
+	} else {
+		// Special handling for Arcane Sanctuary (to allow pathing with platforms)
+		key.arcane = pf.data.PlayerUnit.Area == area.ArcaneSanctuary && canTeleport
+	}
+
+	pf.gridsUsage++
+	for _, cg := range pf.grids {
+		if cg.key == key {
+			cg.lastUsed = pf.gridsUsage
+			return cg, nil
+		}
+	}
+
+	var base *game.Grid
+	if key.mergedWith != 0 {
+		base = mergeGrids(a, destination, canTeleport)
+	} else {
+		base = a.Grid.Copy()
+		if key.arcane {
+			// Make all non-walkable tiles into low priority tiles for teleport pathing
+			for y := 0; y < len(base.CollisionGrid); y++ {
+				for x := 0; x < len(base.CollisionGrid[y]); x++ {
+					if base.CollisionGrid[y][x] == game.CollisionTypeNonWalkable {
+						base.CollisionGrid[y][x] = game.CollisionTypeLowPriority
+					}
+				}
+			}
+		}
+	}
+
+	cg := &cachedGrid{
+		key:      key,
+		base:     base,
+		grid:     base.Copy(),
+		applied:  make(map[int]game.CollisionType),
+		planner:  astar.NewPlanner(),
+		lastUsed: pf.gridsUsage,
+	}
+
+	// Grids from previous games are useless, otherwise drop the least recently used one
+	pf.grids = slices.DeleteFunc(pf.grids, func(c *cachedGrid) bool {
+		return pf.data.Areas[c.key.area].Grid != c.key.source
+	})
+	if len(pf.grids) >= maxCachedGrids {
+		oldest := 0
+		for i, c := range pf.grids {
+			if c.lastUsed < pf.grids[oldest].lastUsed {
+				oldest = i
+			}
+		}
+		pf.grids = slices.Delete(pf.grids, oldest, oldest+1)
+	}
+	pf.grids = append(pf.grids, cg)
+
+	return cg, nil
+}
+
+// obstacles returns the collision type for every tile affected by objects, monsters and barricade towers, same rules as
+// they were applied over a fresh copy of the base grid
+func (pf *PathFinder) obstacles(cg *cachedGrid) map[int]game.CollisionType {
+	base := cg.base
+	obstacles := make(map[int]game.CollisionType)
+	get := func(x, y int) game.CollisionType {
+		if ct, found := obstacles[y*base.Width+x]; found {
+			return ct
+		}
+		return base.CollisionGrid[y][x]
+	}
+	set := func(x, y int, ct game.CollisionType) {
+		obstacles[y*base.Width+x] = ct
+	}
+	isWalkable := func(p data.Position) bool {
+		p = base.RelativePosition(p)
+		if p.X < 0 || p.X >= base.Width || p.Y < 0 || p.Y >= base.Height {
+			return false
+		}
+		ct := get(p.X, p.Y)
+		return ct != game.CollisionTypeNonWalkable && ct != game.CollisionTypeTeleportOver
+	}
+
+	// Add objects to the collision grid as obstacles
+	for _, o := range pf.data.AreaData.Objects {
+		if !isWalkable(o.Position) {
+			continue
+		}
+		relativePos := base.RelativePosition(o.Position)
+		set(relativePos.X, relativePos.Y, game.CollisionTypeObject)
+		for i := -2; i <= 2; i++ {
+			for j := -2; j <= 2; j++ {
+				if i == 0 && j == 0 {
+					continue
+				}
+				y, x := relativePos.Y+i, relativePos.X+j
+				if y < 0 || y >= base.Height || x < 0 || x >= base.Width {
+					continue
+				}
+				if get(x, y) == game.CollisionTypeWalkable {
+					set(x, y, game.CollisionTypeLowPriority)
+				}
+			}
+		}
+	}
+
+	// Add monsters to the collision grid as obstacles
+	for _, m := range pf.data.Monsters {
+		if !isWalkable(m.Position) {
+			continue
+		}
+		relativePos := base.RelativePosition(m.Position)
+		set(relativePos.X, relativePos.Y, game.CollisionTypeMonster)
+	}
+
+	// set barricade tower as non walkable in act 5
+	a := pf.data.AreaData.Area
+	if a == area.FrigidHighlands || a == area.FrozenTundra || a == area.ArreatPlateau {
+		for _, n := range pf.data.NPCs {
+			if n.ID != npc.BarricadeTower || len(n.Positions) == 0 {
+				continue
+			}
+			relativePos := base.RelativePosition(n.Positions[0])
+
+			// Set a 5x5 area around the barricade tower as non-walkable
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					towerY := relativePos.Y + dy
+					towerX := relativePos.X + dx
+					if towerY >= 0 && towerY < base.Height && towerX >= 0 && towerX < base.Width {
+						set(towerX, towerY, game.CollisionTypeNonWalkable)
+					}
+				}
+			}
+		}
+	}
+
+	return obstacles
+}
+
+// applyObstacles updates the grid in place and returns the tiles (grid relative) that changed since the previous call
+func (cg *cachedGrid) applyObstacles(obstacles map[int]game.CollisionType) []data.Position {
+	var changed []data.Position
+	width := cg.grid.Width
+
+	for idx := range cg.applied {
+		if _, found := obstacles[idx]; found {
+			continue
+		}
+		x, y := idx%width, idx/width
+		if cg.grid.CollisionGrid[y][x] != cg.base.CollisionGrid[y][x] {
+			cg.grid.CollisionGrid[y][x] = cg.base.CollisionGrid[y][x]
+			changed = append(changed, data.Position{X: x, Y: y})
+		}
+	}
+
+	for idx, ct := range obstacles {
+		x, y := idx%width, idx/width
+		if cg.grid.CollisionGrid[y][x] != ct {
+			cg.grid.CollisionGrid[y][x] = ct
+			changed = append(changed, data.Position{X: x, Y: y})
+		}
+	}
+	cg.applied = obstacles
+
+	return changed
+}
+
+// cachedPath returns the remaining part of the last calculated path if we are still walking over it, the destination is
+// the same and nothing changed around it
+func (cg *cachedGrid) cachedPath(from, to data.Position, changed []data.Position, doors uint64) (Path, bool) {
+	if cg.path == nil {
+		return nil, false
+	}
+
+	if cg.pathTo != to || cg.doors != doors || cg.intersects(changed) {
+		cg.path = nil
+		return nil, false
+	}
+
+	idx := slices.Index(cg.path, from)
+	if idx == -1 {
+		return nil, false
+	}
+
+	return slices.Clone(cg.path[idx:]), true
+}
+
+func (cg *cachedGrid) storePath(path Path, to data.Position, doors uint64) {
+	cg.path = slices.Clone(path)
+	cg.pathTo = to
+	cg.doors = doors
+	cg.pathTiles = make(map[int]struct{}, len(path))
+	for _, p := range path {
+		cg.pathTiles[p.Y*cg.grid.Width+p.X] = struct{}{}
+	}
+}
+
+// intersects returns true if any of the tiles is part of the cached path or next to it
+func (cg *cachedGrid) intersects(tiles []data.Position) bool {
+	for _, t := range tiles {
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				x, y := t.X+dx, t.Y+dy
+				if x < 0 || x >= cg.grid.Width || y < 0 || y >= cg.grid.Height {
+					continue
+				}
+				if _, found := cg.pathTiles[y*cg.grid.Width+x]; found {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// doorsSignature changes every time a door is opened or closed
+func (pf *PathFinder) doorsSignature() uint64 {
+	var signature uint64
+	for _, o := range pf.data.Objects {
+		if !o.IsDoor() || !o.Selectable {
+			continue
+		}
+		h := fnv.New64a()
+		_, _ = fmt.Fprintf(h, "%d,%d", o.Position.X, o.Position.Y)
+		signature ^= h.Sum64()
+	}
+
+	return signature
+}
@@ -1,12 +1,11 @@
 package pather
 
 import (
-	"fmt"
 	"math"
+	"sync"
 
 	"github.com/hectorgimenez/d2go/pkg/data"
 	"github.com/hectorgimenez/d2go/pkg/data/area"
-	"github.com/hectorgimenez/d2go/pkg/data/npc"
 	"github.com/hectorgimenez/koolo/internal/config"
 	"github.com/hectorgimenez/koolo/internal/game"
 	"github.com/hectorgimenez/koolo/internal/pather/astar"
@@ -17,6 +16,10 @@ type PathFinder struct {
 	data *game.Data
 	hid  *game.HID
 	cfg  *config.CharacterCfg
+
+	mu         sync.Mutex
+	grids      []*cachedGrid
+	gridsUsage uint64
 }
 
 func NewPathFinder(gr *game.MemoryReader, data *game.Data, hid *game.HID, cfg *config.CharacterCfg) *PathFinder {
@@ -48,152 +51,92 @@ func (pf *PathFinder) GetPath(to data.Position) (Path, int, bool) {
 }
 
 func (pf *PathFinder) GetPathFrom(from, to data.Position) (Path, int, bool) {
+	pf.mu.Lock()
+	defer pf.mu.Unlock()
+
 	a := pf.data.AreaData
 	canTeleport := pf.data.CanTeleport()
 
-	// We don't want to modify the original grid
-	grid := a.Grid.Copy()
-
-	// Special handling for Arcane Sanctuary (to allow pathing with platforms)
-	if pf.data.PlayerUnit.Area == area.ArcaneSanctuary && pf.data.CanTeleport() {
-		// Make all non-walkable tiles into low priority tiles for teleport pathing
-		for y := 0; y < len(grid.CollisionGrid); y++ {
-			for x := 0; x < len(grid.CollisionGrid[y]); x++ {
-				if grid.CollisionGrid[y][x] == game.CollisionTypeNonWalkable {
-					grid.CollisionGrid[y][x] = game.CollisionTypeLowPriority
-				}
-			}
-		}
-	}
 	// Lut Gholein map is a bit bugged, we should close this fake path to avoid pathing issues
 	if a.Area == area.LutGholein {
 		a.CollisionGrid[13][210] = game.CollisionTypeNonWalkable
 	}
 
-	if !a.IsInside(to) {
-		expandedGrid, err := pf.mergeGrids(to, canTeleport)
-		if err != nil {
-			return nil, 0, false
-		}
-		grid = expandedGrid
+	// Grids are kept between calls, we don't want to copy (or merge) the whole area grid every time
+	cg, err := pf.cachedGridFor(to, canTeleport)
+	if err != nil {
+		return nil, 0, false
 	}
 
-	if !grid.IsWalkable(to) {
-		if walkableTo, found := pf.findNearbyWalkablePositionInGrid(grid, to); found {
+	if !cg.base.IsWalkable(to) {
+		if walkableTo, found := pf.findNearbyWalkablePositionInGrid(cg.base, to); found {
 			to = walkableTo
 		}
 	}
-	from = grid.RelativePosition(from)
-	to = grid.RelativePosition(to)
+	from = cg.grid.RelativePosition(from)
+	to = cg.grid.RelativePosition(to)
 
-	// Add objects to the collision grid as obstacles
-	for _, o := range pf.data.AreaData.Objects {
-		if !grid.IsWalkable(o.Position) {
-			continue
-		}
-		relativePos := grid.RelativePosition(o.Position)
-		grid.CollisionGrid[relativePos.Y][relativePos.X] = game.CollisionTypeObject
-		for i := -2; i <= 2; i++ {
-			for j := -2; j <= 2; j++ {
-				if i == 0 && j == 0 {
-					continue
-				}
-				if relativePos.Y+i < 0 || relativePos.Y+i >= len(grid.CollisionGrid) || relativePos.X+j < 0 || relativePos.X+j >= len(grid.CollisionGrid[relativePos.Y]) {
-					continue
-				}
-				if grid.CollisionGrid[relativePos.Y+i][relativePos.X+j] == game.CollisionTypeWalkable {
-					grid.CollisionGrid[relativePos.Y+i][relativePos.X+j] = game.CollisionTypeLowPriority
+	changed := cg.applyObstacles(pf.obstacles(cg))
+	doors := pf.doorsSignature()
 
-				}
-			}
-		}
+	// When walking, repeated calls to the same destination repair the previous search instead of starting from scratch.
+	// Changes are always sent to the planner, even if the cached path is still valid.
+	plannerReady := !canTeleport && cg.planner.Grid() == cg.grid && cg.planner.Goal() == to
+	if plannerReady {
+		cg.planner.UpdateCells(changed)
 	}
 
-	// Add monsters to the collision grid as obstacles
-	for _, m := range pf.data.Monsters {
-		if !grid.IsWalkable(m.Position) {
-			continue
-		}
-		relativePos := grid.RelativePosition(m.Position)
-		grid.CollisionGrid[relativePos.Y][relativePos.X] = game.CollisionTypeMonster
+	if path, found := cg.cachedPath(from, to, changed, doors); found {
+		return path, len(path), true
 	}
 
-	// set barricade tower as non walkable in act 5
-	if a.Area == area.FrigidHighlands || a.Area == area.FrozenTundra || a.Area == area.ArreatPlateau {
-		towerCount := 0
-		for _, n := range pf.data.NPCs {
-			if n.ID != npc.BarricadeTower {
-				continue
-			}
-			if len(n.Positions) == 0 {
-				continue
-			}
-			npcPos := n.Positions[0]
-			relativePos := grid.RelativePosition(npcPos)
-			towerCount++
-
-			// Set a 5x5 area around the barricade tower as non-walkable
-			blockedCells := 0
-			for dy := -2; dy <= 2; dy++ {
-				for dx := -2; dx <= 2; dx++ {
-					towerY := relativePos.Y + dy
-					towerX := relativePos.X + dx
-
-					// Bounds checking to prevent array index out of bounds
-					if towerY >= 0 && towerY < len(grid.CollisionGrid) &&
-						towerX >= 0 && towerX < len(grid.CollisionGrid[towerY]) {
-						grid.CollisionGrid[towerY][towerX] = game.CollisionTypeNonWalkable
-						blockedCells++
-					}
-				}
-			}
+	var path Path
+	var distance int
+	var found bool
+	if canTeleport {
+		path, distance, found = astar.CalculatePath(cg.grid, from, to, canTeleport)
+	} else {
+		if !plannerReady {
+			cg.planner.Reset(cg.grid, to)
 		}
+		path, distance, found = cg.planner.Path(from)
 	}
 
-	path, distance, found := astar.CalculatePath(grid, from, to, canTeleport)
+	if found {
+		cg.storePath(path, to, doors)
+	}
 
 	if config.Koolo.Debug.RenderMap {
-		pf.renderMap(grid, from, to, path)
+		pf.renderMap(cg.grid, from, to, path)
 	}
 
 	return path, distance, found
 }
 
-func (pf *PathFinder) mergeGrids(to data.Position, canTeleport bool) (*game.Grid, error) {
-	for _, a := range pf.data.AreaData.AdjacentLevels {
-		destination := pf.data.Areas[a.Area]
-		if destination.IsInside(to) {
-			origin := pf.data.AreaData
+func mergeGrids(origin, destination game.AreaData, canTeleport bool) *game.Grid {
+	endX1 := origin.OffsetX + len(origin.Grid.CollisionGrid[0])
+	endY1 := origin.OffsetY + len(origin.Grid.CollisionGrid)
+	endX2 := destination.OffsetX + len(destination.Grid.CollisionGrid[0])
+	endY2 := destination.OffsetY + len(destination.Grid.CollisionGrid)
 
-			endX1 := origin.OffsetX + len(origin.Grid.CollisionGrid[0])
-			endY1 := origin.OffsetY + len(origin.Grid.CollisionGrid)
-			endX2 := destination.OffsetX + len(destination.Grid.CollisionGrid[0])
-			endY2 := destination.OffsetY + len(destination.Grid.CollisionGrid)
+	minX := min(origin.OffsetX, destination.OffsetX)
+	minY := min(origin.OffsetY, destination.OffsetY)
+	maxX := max(endX1, endX2)
+	maxY := max(endY1, endY2)
 
-			minX := min(origin.OffsetX, destination.OffsetX)
-			minY := min(origin.OffsetY, destination.OffsetY)
-			maxX := max(endX1, endX2)
-			maxY := max(endY1, endY2)
+	width := maxX - minX
+	height := maxY - minY
 
-			width := maxX - minX
-			height := maxY - minY
-
-			resultGrid := make([][]game.CollisionType, height)
-			for i := range resultGrid {
-				resultGrid[i] = make([]game.CollisionType, width)
-			}
-
-			// Let's copy both grids into the result grid
-			copyGrid(resultGrid, origin.CollisionGrid, origin.OffsetX-minX, origin.OffsetY-minY)
-			copyGrid(resultGrid, destination.CollisionGrid, destination.OffsetX-minX, destination.OffsetY-minY)
-
-			grid := game.NewGrid(resultGrid, minX, minY, canTeleport)
-
-			return grid, nil
-		}
+	resultGrid := make([][]game.CollisionType, height)
+	for i := range resultGrid {
+		resultGrid[i] = make([]game.CollisionType, width)
 	}
 
-	return nil, fmt.Errorf("destination grid not found")
+	// Let's copy both grids into the result grid
+	copyGrid(resultGrid, origin.CollisionGrid, origin.OffsetX-minX, origin.OffsetY-minY)
+	copyGrid(resultGrid, destination.CollisionGrid, destination.OffsetX-minX, destination.OffsetY-minY)
+
+	return game.NewGrid(resultGrid, minX, minY, canTeleport)
 }
 
 func copyGrid(dest [][]game.CollisionType, src [][]game.CollisionType, offsetX, offsetY int) {
@@ -0,0 +1,309 @@
+package pather
+
+import (
+	"math"
+	"slices"
+	"sync"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/koolo/internal/game"
+	"github.com/hectorgimenez/koolo/internal/pather/astar"
+)
+
+const (
+	// maxTraversalImprovements bounds the local search, tours are usually optimized after a few passes
+	maxTraversalImprovements = 1000
+	// maxAnchorRadius is the max distance from the room center where we look for a walkable tile
+	maxAnchorRadius = 10
+	// traversalWorkers is the number of distance searches running at the same time, every one of them uses a full grid buffer
+	traversalWorkers = 4
+)
+
+type TraversalOptions struct {
+	CanTeleport bool
+	// EndNear makes the tour finish as close as possible to this position (usually the exit to the next area)
+	EndNear *data.Position
+}
+
+// PlanRoomsTraversal returns the order to visit all the rooms starting from the given position. Distances between rooms
+// are the real walking distances over the grid (rooms behind a wall are not considered close), the tour is built using
+// nearest neighbour and improved with 2-opt and Or-opt moves. All the positions are absolute game coordinates.
+func PlanRoomsTraversal(g *game.Grid, rooms []data.Room, start data.Position, opts TraversalOptions) []data.Room {
+	if len(rooms) < 2 {
+		return rooms
+	}
+
+	// Node 0 is the start position, 1..n the rooms and n+1 the (optional) end position
+	points := make([]data.Position, 0, len(rooms)+2)
+	points = append(points, g.RelativePosition(start))
+	for _, r := range rooms {
+		points = append(points, roomAnchor(g, r))
+	}
+	if opts.EndNear != nil {
+		points = append(points, g.RelativePosition(*opts.EndNear))
+	}
+
+	dist := traversalDistances(g, points, opts.CanTeleport)
+	tour := planTour(dist, len(rooms), opts.EndNear != nil)
+
+	result := make([]data.Room, 0, len(rooms))
+	for _, node := range tour[1:] {
+		result = append(result, rooms[node-1])
+	}
+
+	return result
+}
+
+// planTour returns the cheapest tour found over the distance matrix, starting at node 0 and visiting the rooms 1..rooms.
+// When hasEnd is set the last node of the matrix is the position where the tour should finish.
+func planTour(dist [][]int, rooms int, hasEnd bool) []int {
+	endCost := func(node int) int {
+		if !hasEnd {
+			return 0
+		}
+		return dist[node][rooms+1]
+	}
+
+	tours := [][]int{nearestNeighbourTour(dist, rooms)}
+	if hasEnd {
+		// Built backwards from the end position, the forward tour often leaves the rooms close to the end for the middle
+		backwards := nearestNeighbourOrder(dist, rooms, rooms+1)
+		slices.Reverse(backwards)
+		tours = append(tours, append([]int{0}, backwards...))
+	}
+
+	// Every tour is improved, the cheapest one is used
+	var best []int
+	bestCost := math.MaxInt
+	for _, tour := range tours {
+		improveTour(tour, dist, endCost)
+		if cost := tourCost(tour, dist, endCost); cost < bestCost {
+			best, bestCost = tour, cost
+		}
+	}
+
+	return best
+}
+
+// roomAnchor returns the room center, or the closest walkable tile inside the room if the center is not walkable
+func roomAnchor(g *game.Grid, r data.Room) data.Position {
+	center := r.GetCenter()
+	if g.IsWalkable(center) {
+		return g.RelativePosition(center)
+	}
+
+	for radius := 1; radius <= maxAnchorRadius; radius++ {
+		for dy := -radius; dy <= radius; dy++ {
+			for dx := -radius; dx <= radius; dx++ {
+				if abs(dx) != radius && abs(dy) != radius {
+					continue
+				}
+				p := data.Position{X: center.X + dx, Y: center.Y + dy}
+				if r.IsInside(p) && g.IsWalkable(p) {
+					return g.RelativePosition(p)
+				}
+			}
+		}
+	}
+
+	return g.RelativePosition(center)
+}
+
+// traversalDistances builds the (symmetric) distance matrix, unreachable pairs fall back to a penalized straight distance,
+// they may still be reachable through other areas or once a door is opened
+func traversalDistances(g *game.Grid, points []data.Position, canTeleport bool) [][]int {
+	dist := make([][]int, len(points))
+	for i := range dist {
+		dist[i] = make([]int, len(points))
+	}
+
+	// Every search writes its own row, so they can run in parallel
+	wg := sync.WaitGroup{}
+	sem := make(chan struct{}, traversalWorkers)
+	for i := 0; i < len(points)-1; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			distances := astar.StepDistances(g, points[i], points[i+1:], canTeleport)
+			for k, d := range distances {
+				j := i + 1 + k
+				if d == -1 {
+					d = DistanceFromPoint(points[i], points[j])*4 + 100
+				}
+				dist[i][j] = d
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i := range dist {
+		for j := i + 1; j < len(dist); j++ {
+			dist[j][i] = dist[i][j]
+		}
+	}
+
+	return dist
+}
+
+// nearestNeighbourTour starts at the start position (node 0) and goes to the closest room not visited yet
+func nearestNeighbourTour(dist [][]int, rooms int) []int {
+	return append([]int{0}, nearestNeighbourOrder(dist, rooms, 0)...)
+}
+
+// nearestNeighbourOrder returns the rooms (nodes 1..rooms) in the order they are visited going from the given node to
+// the closest room not visited yet
+func nearestNeighbourOrder(dist [][]int, rooms int, from int) []int {
+	order := make([]int, 0, rooms)
+	visited := make([]bool, rooms+1)
+
+	current := from
+	for len(order) < rooms {
+		next, best := -1, math.MaxInt
+		for node := 1; node <= rooms; node++ {
+			if !visited[node] && dist[current][node] < best {
+				next, best = node, dist[current][node]
+			}
+		}
+		order = append(order, next)
+		visited[next] = true
+		current = next
+	}
+
+	return order
+}
+
+// improveTour applies 2-opt and Or-opt moves while they shorten the tour, the first node (start position) never moves
+func improveTour(tour []int, dist [][]int, endCost func(node int) int) {
+	// cost of the edge leaving position i, the last node is connected to the end position
+	edge := func(t []int, i int) int {
+		if i == len(t)-1 {
+			return endCost(t[i])
+		}
+		return dist[t[i]][t[i+1]]
+	}
+
+	cost := tourCost(tour, dist, endCost)
+	previous := make([]int, len(tour))
+	for iteration := 0; iteration < maxTraversalImprovements; iteration++ {
+		copy(previous, tour)
+		if !twoOpt(tour, dist, endCost, edge) && !orOpt(tour, dist, endCost) {
+			return
+		}
+
+		// Moves are chosen by their local gain, the whole tour must still get cheaper or the search could cycle
+		improved := tourCost(tour, dist, endCost)
+		if improved >= cost {
+			copy(tour, previous)
+			return
+		}
+		cost = improved
+	}
+}
+
+// twoOpt reverses the first segment that makes the tour shorter
+func twoOpt(tour []int, dist [][]int, endCost func(node int) int, edge func(t []int, i int) int) bool {
+	n := len(tour)
+	for i := 1; i < n-1; i++ {
+		for j := i + 1; j < n; j++ {
+			before := dist[tour[i-1]][tour[i]] + edge(tour, j)
+			after := dist[tour[i-1]][tour[j]]
+			if j == n-1 {
+				after += endCost(tour[i])
+			} else {
+				after += dist[tour[i]][tour[j+1]]
+			}
+
+			if after < before {
+				for l, r := i, j; l < r; l, r = l+1, r-1 {
+					tour[l], tour[r] = tour[r], tour[l]
+				}
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// orOpt moves the first segment of up to 3 nodes (in any direction) to a position where the tour gets shorter
+func orOpt(tour []int, dist [][]int, endCost func(node int) int) bool {
+	// cost between two nodes, -1 is the end of the tour
+	cost := func(a, b int) int {
+		if b == -1 {
+			return endCost(a)
+		}
+		return dist[a][b]
+	}
+	nodeAt := func(t []int, i int) int {
+		if i >= len(t) {
+			return -1
+		}
+		return t[i]
+	}
+
+	n := len(tour)
+	for length := 1; length <= 3; length++ {
+		for i := 1; i+length <= n; i++ {
+			first, last := tour[i], tour[i+length-1]
+			prev, next := tour[i-1], nodeAt(tour, i+length)
+			removeGain := dist[prev][first] + cost(last, next) - cost(prev, next)
+
+			// Insertion points are between tour[j-1] and tour[j], outside of the segment
+			for j := 1; j <= n; j++ {
+				if j >= i && j <= i+length {
+					continue
+				}
+				a, b := tour[j-1], nodeAt(tour, j)
+				for _, reversed := range []bool{false, true} {
+					head, tail := first, last
+					if reversed {
+						head, tail = last, first
+					}
+					if dist[a][head]+cost(tail, b)-cost(a, b) < removeGain {
+						moveSegment(tour, i, length, j, reversed)
+						return true
+					}
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// moveSegment moves tour[i:i+length] before the element at position j (j can be len(tour))
+func moveSegment(tour []int, i, length, j int, reversed bool) {
+	segment := slices.Clone(tour[i : i+length])
+	if reversed {
+		slices.Reverse(segment)
+	}
+
+	rest := slices.Delete(slices.Clone(tour), i, i+length)
+	if j > i {
+		j -= length
+	}
+	result := slices.Insert(rest, j, segment...)
+	copy(tour, result)
+}
+
+// tourCost is the distance walked visiting the nodes in the tour order, plus the distance to the end position
+func tourCost(tour []int, dist [][]int, endCost func(node int) int) int {
+	cost := endCost(tour[len(tour)-1])
+	for i := 0; i < len(tour)-1; i++ {
+		cost += dist[tour[i]][tour[i+1]]
+	}
+
+	return cost
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+
+	return v
+}
@@ -0,0 +1,161 @@
+package pather
+
+import (
+	"encoding/gob"
+	"os"
+	"testing"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/koolo/internal/game"
+)
+
+const roomSize = 40
+
+func BenchmarkPlanRoomsTraversal(b *testing.B) {
+	grid, rooms := loadLevel()
+	start := rooms[0].GetCenter()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		PlanRoomsTraversal(grid, rooms, start, TraversalOptions{})
+	}
+}
+
+func TestPlanRoomsTraversal(t *testing.T) {
+	grid, rooms := loadLevel()
+	start := rooms[0].GetCenter()
+	end := rooms[len(rooms)/2].GetCenter()
+
+	for _, opts := range []TraversalOptions{{}, {EndNear: &end}} {
+		order := PlanRoomsTraversal(grid, rooms, start, opts)
+		if len(order) != len(rooms) {
+			t.Fatalf("Expected %d rooms, got %d", len(rooms), len(order))
+		}
+
+		seen := make(map[data.Room]bool)
+		for _, r := range order {
+			if seen[r] {
+				t.Fatalf("Room %v visited twice", r.Position)
+			}
+			seen[r] = true
+		}
+
+		// Local search starts from the nearest neighbour tour, it can never be worse
+		points := []data.Position{grid.RelativePosition(start)}
+		for _, r := range rooms {
+			points = append(points, roomAnchor(grid, r))
+		}
+		if opts.EndNear != nil {
+			points = append(points, grid.RelativePosition(end))
+		}
+		dist := traversalDistances(grid, points, false)
+		endCost := func(node int) int {
+			if opts.EndNear == nil {
+				return 0
+			}
+			return dist[node][len(points)-1]
+		}
+
+		index := make(map[data.Room]int)
+		for i, r := range rooms {
+			index[r] = i + 1
+		}
+		tour := []int{0}
+		for _, r := range order {
+			tour = append(tour, index[r])
+		}
+
+		greedy := tourCost(nearestNeighbourTour(dist, len(rooms)), dist, endCost)
+		if cost := tourCost(tour, dist, endCost); cost > greedy {
+			t.Errorf("Planned tour costs %d, nearest neighbour tour costs %d", cost, greedy)
+		}
+	}
+}
+
+func TestPlanTour(t *testing.T) {
+	// Start at 0 and the exit at 10, the closest room to the start is the one behind it
+	line := []int{0, -1, 2, 4, 6, 8, 10}
+	tests := []struct {
+		name     string
+		points   []int
+		hasEnd   bool
+		wantCost int
+	}{
+		{"without end", line[:len(line)-1], false, 10},
+		{"ending near the exit", line, true, 12},
+		{"unordered rooms", []int{0, 7, -3, 5, 1, -2, 3}, false, 13},
+		{"unordered rooms ending near the exit", []int{0, 7, -3, 5, 1, -2, 3, -4}, true, 18},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dist := lineDistances(tt.points)
+			rooms := len(tt.points) - 1
+			if tt.hasEnd {
+				rooms--
+			}
+			endCost := func(node int) int {
+				if !tt.hasEnd {
+					return 0
+				}
+				return dist[node][rooms+1]
+			}
+
+			tour := planTour(dist, rooms, tt.hasEnd)
+			if len(tour) != rooms+1 || tour[0] != 0 {
+				t.Fatalf("invalid tour %v", tour)
+			}
+			seen := make(map[int]bool)
+			for _, node := range tour[1:] {
+				if node < 1 || node > rooms || seen[node] {
+					t.Fatalf("invalid tour %v", tour)
+				}
+				seen[node] = true
+			}
+
+			if cost := tourCost(tour, dist, endCost); cost != tt.wantCost {
+				t.Errorf("tour %v costs %d, want %d", tour, cost, tt.wantCost)
+			}
+		})
+	}
+}
+
+// lineDistances returns the distance matrix of points placed on a line
+func lineDistances(points []int) [][]int {
+	dist := make([][]int, len(points))
+	for i := range points {
+		dist[i] = make([]int, len(points))
+		for j := range points {
+			dist[i][j] = abs(points[i] - points[j])
+		}
+	}
+
+	return dist
+}
+
+// loadLevel splits the Durance of Hate fixture into rooms, like the game does, keeping the ones with walkable tiles
+func loadLevel() (*game.Grid, []data.Room) {
+	var grid game.Grid
+	file, err := os.Open("astar/durance_of_hate_grid.bin")
+	if err != nil {
+		panic(err)
+	}
+	defer file.Close()
+
+	if err = gob.NewDecoder(file).Decode(&grid); err != nil {
+		panic(err)
+	}
+
+	var rooms []data.Room
+	for y := 0; y+roomSize <= grid.Height; y += roomSize {
+		for x := 0; x+roomSize <= grid.Width; x += roomSize {
+			r := data.Room{Position: data.Position{X: grid.OffsetX + x, Y: grid.OffsetY + y}, Width: roomSize, Height: roomSize}
+			anchor := roomAnchor(&grid, r)
+			if grid.IsWalkable(data.Position{X: grid.OffsetX + anchor.X, Y: grid.OffsetY + anchor.Y}) {
+				rooms = append(rooms, r)
+			}
+		}
+	}
+
+	return &grid, rooms
+}
@@ -26,51 +26,19 @@ func (pf *PathFinder) DistanceFromMe(p data.Position) int {
 	return DistanceFromPoint(pf.data.PlayerUnit.Position, p)
 }
 
+// OptimizeRoomsTraverseOrder returns the current area rooms in the order they should be visited to walk as less as possible
 func (pf *PathFinder) OptimizeRoomsTraverseOrder() []data.Room {
-	distanceMatrix := make(map[data.Room]map[data.Room]int)
-
-	for _, room1 := range pf.data.Rooms {
-		distanceMatrix[room1] = make(map[data.Room]int)
-		for _, room2 := range pf.data.Rooms {
-			if room1 != room2 {
-				distance := DistanceFromPoint(room1.GetCenter(), room2.GetCenter())
-				distanceMatrix[room1][room2] = distance
-			} else {
-				distanceMatrix[room1][room2] = 0
-			}
-		}
-	}
-
-	currentRoom := data.Room{}
-	for _, r := range pf.data.Rooms {
-		if r.IsInside(pf.data.PlayerUnit.Position) {
-			currentRoom = r
-		}
-	}
-
-	visited := make(map[data.Room]bool)
-	order := []data.Room{currentRoom}
-	visited[currentRoom] = true
-
-	for len(order) < len(pf.data.Rooms) {
-		nextRoom := data.Room{}
-		minDistance := math.MaxInt
-
-		// Find the nearest unvisited room
-		for _, room := range pf.data.Rooms {
-			if !visited[room] && distanceMatrix[currentRoom][room] < minDistance {
-				nextRoom = room
-				minDistance = distanceMatrix[currentRoom][room]
-			}
-		}
-
-		// Add the next room to the order of visit
-		order = append(order, nextRoom)
-		visited[nextRoom] = true
-		currentRoom = nextRoom
-	}
+	return PlanRoomsTraversal(pf.data.AreaData.Grid, pf.data.Rooms, pf.data.PlayerUnit.Position, TraversalOptions{
+		CanTeleport: pf.data.CanTeleport(),
+	})
+}
 
-	return order
+// OptimizeRoomsTraverseOrderEndingNear works like OptimizeRoomsTraverseOrder, but the last room is close to the given position
+func (pf *PathFinder) OptimizeRoomsTraverseOrderEndingNear(end data.Position) []data.Room {
+	return PlanRoomsTraversal(pf.data.AreaData.Grid, pf.data.Rooms, pf.data.PlayerUnit.Position, TraversalOptions{
+		CanTeleport: pf.data.CanTeleport(),
+		EndNear:     &end,
+	})
 }
 
 func (pf *PathFinder) MoveThroughPath(p Path, walkDuration time.Duration) {
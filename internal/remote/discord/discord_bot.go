@@ -83,6 +83,10 @@ func (b *Bot) onMessageCreated(s *discordgo.Session, m *discordgo.MessageCreate)
 		b.handleHelpRequest(s, m)
 	case "!drops":
 		b.handleDropsRequest(s, m)
+	case "!history":
+		b.handleHistoryRequest(s, m)
+	case "!rollback":
+		b.handleRollbackRequest(s, m)
 	default:
 		// Unknown command - send help
 		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Unknown command: `%s`. Type `!help` for available commands.", prefix))
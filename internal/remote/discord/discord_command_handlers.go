@@ -2,12 +2,16 @@ package discord
 
 import (
 	"fmt"
+	"path/filepath"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/hectorgimenez/koolo/internal/bot"
+	"github.com/hectorgimenez/koolo/internal/config"
+	"github.com/hectorgimenez/koolo/internal/config/history"
 )
 
 func (b *Bot) supervisorExists(supervisor string) bool {
@@ -30,7 +34,10 @@ func (b *Bot) handleStartRequest(s *discordgo.Session, m *discordgo.MessageCreat
 			}
 
 			// Attempt to start the specified supervisor
-			b.manager.Start(supervisor, false, false)
+			if err := b.manager.Start(supervisor, false, false); err != nil {
+				s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Supervisor '%s' could not be started: %v", supervisor, err))
+				continue
+			}
 
 			// Wait for the supervisor to start
 			time.Sleep(1 * time.Second)
@@ -261,6 +268,16 @@ func (b *Bot) handleHelpRequest(s *discordgo.Session, m *discordgo.MessageCreate
 				Value:  "Show recent drops for a supervisor\nExample: `!drops Koza 10`\nDefault count: 5",
 				Inline: false,
 			},
+			{
+				Name:   "!history <supervisor> [count]",
+				Value:  "Show the latest config changes of a supervisor\nExample: `!history Koza`\nDefault count: 5",
+				Inline: false,
+			},
+			{
+				Name:   "!rollback <supervisor> [version]",
+				Value:  "Restore a previous config version, the last one saved from the web UI or the API when no version is given\nExample: `!rollback Koza 12`",
+				Inline: false,
+			},
 			{
 				Name:   "!help",
 				Value:  "Show this help message",
@@ -375,3 +392,128 @@ func (b *Bot) handleDropsRequest(s *discordgo.Session, m *discordgo.MessageCreat
 
 	s.ChannelMessageSendEmbed(m.ChannelID, embed)
 }
+
+func (b *Bot) handleHistoryRequest(s *discordgo.Session, m *discordgo.MessageCreate) {
+	words := strings.Fields(m.Content)
+
+	if len(words) < 2 {
+		s.ChannelMessageSend(m.ChannelID, "Usage: !history <supervisor> [count]\nExample: `!history Koza 10`")
+		return
+	}
+
+	supervisor := words[1]
+
+	if !b.supervisorExists(supervisor) {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Supervisor '%s' not found.", supervisor))
+		return
+	}
+
+	// Default count is 5, max is 20
+	count := 5
+	if len(words) > 2 {
+		fmt.Sscanf(words[2], "%d", &count)
+		if count < 1 {
+			count = 5
+		}
+		if count > 20 {
+			count = 20
+		}
+	}
+
+	versions, err := supervisorConfigVersions(supervisor)
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Error reading the config history: %v", err))
+		return
+	}
+	if len(versions) == 0 {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("No config changes recorded for '%s' yet.", supervisor))
+		return
+	}
+
+	var description strings.Builder
+	for i, version := range versions[:min(count, len(versions))] {
+		description.WriteString(fmt.Sprintf("`#%d` %s - %s", version.ID, version.Timestamp.Format("2006-01-02 15:04"), version.Source))
+		if version.Note != "" {
+			description.WriteString(" - " + version.Note)
+		}
+		if i == 0 {
+			description.WriteString(" (current)")
+		}
+		description.WriteString("\n")
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("🕒 Config history of %s", supervisor),
+		Description: description.String(),
+		Color:       0x5865F2,
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "Use !rollback <supervisor> [version] to restore a version",
+		},
+	}
+
+	s.ChannelMessageSendEmbed(m.ChannelID, embed)
+}
+
+func (b *Bot) handleRollbackRequest(s *discordgo.Session, m *discordgo.MessageCreate) {
+	words := strings.Fields(m.Content)
+
+	if len(words) < 2 {
+		s.ChannelMessageSend(m.ChannelID, "Usage: !rollback <supervisor> [version]\nExample: `!rollback Koza 12`")
+		return
+	}
+
+	supervisor := words[1]
+
+	if !b.supervisorExists(supervisor) {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Supervisor '%s' not found.", supervisor))
+		return
+	}
+
+	versions, err := supervisorConfigVersions(supervisor)
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Error reading the config history: %v", err))
+		return
+	}
+
+	// Without version the config goes back to the last change made by the user, the bot saves its progress much more
+	// often and restoring one of those would rarely undo anything
+	var target history.Version
+	if len(words) > 2 {
+		id, err := strconv.ParseInt(strings.TrimPrefix(words[2], "#"), 10, 64)
+		if err != nil {
+			s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Invalid version '%s'.", words[2]))
+			return
+		}
+		idx := slices.IndexFunc(versions, func(v history.Version) bool { return v.ID == id })
+		if idx < 0 {
+			s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Version #%d not found in the config history of '%s'.", id, supervisor))
+			return
+		}
+		target = versions[idx]
+	} else {
+		idx := slices.IndexFunc(versions, func(v history.Version) bool {
+			return (v.Source == history.SourceUI || v.Source == history.SourceAPI) && v.Hash != versions[0].Hash
+		})
+		if idx < 0 {
+			s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("No previous config version saved from the web UI or the API for '%s'.", supervisor))
+			return
+		}
+		target = versions[idx]
+	}
+
+	if _, err = config.RollbackConfigFile(target.ID, history.SourceDiscord); err != nil {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Error rolling back '%s': %v", supervisor, err))
+		return
+	}
+
+	s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Config of '%s' restored to version #%d. Running supervisors apply the safe changes at the next game, the rest after a restart.", supervisor, target.ID))
+}
+
+func supervisorConfigVersions(supervisor string) ([]history.Version, error) {
+	store, err := config.ConfigHistory()
+	if err != nil {
+		return nil, err
+	}
+
+	return store.Versions(config.HistoryArtifact(filepath.Join("config", supervisor, "config.yaml"))), nil
+}
@@ -11,48 +11,63 @@ import (
 	"github.com/hectorgimenez/koolo/internal/event"
 )
 
-func (b *Bot) Handle(_ context.Context, e event.Event) error {
+func (b *Bot) Handle(ctx context.Context, e event.Event) error {
 	if b.shouldPublish(e) {
+		return b.Deliver(ctx, e)
+	}
 
-		switch evt := e.(type) {
-		case event.GameCreatedEvent:
-			message := fmt.Sprintf("**[%s]** %s\nGame: %s\nPassword: %s", evt.Supervisor(), evt.Message(), evt.Name, evt.Password)
-			_, err := b.discordSession.ChannelMessageSend(b.channelID, message)
-			return err
-		case event.GameFinishedEvent:
-			message := fmt.Sprintf("**[%s]** %s", evt.Supervisor(), evt.Message())
-			_, err := b.discordSession.ChannelMessageSend(b.channelID, message)
-			return err
-		case event.RunStartedEvent:
-			message := fmt.Sprintf("**[%s]** started a new run: **%s**", evt.Supervisor(), evt.RunName)
-			_, err := b.discordSession.ChannelMessageSend(b.channelID, message)
-			return err
-		case event.RunFinishedEvent:
-			message := fmt.Sprintf("**[%s]** finished run: **%s** (%s)", evt.Supervisor(), evt.RunName, evt.Reason)
-			_, err := b.discordSession.ChannelMessageSend(b.channelID, message)
-			return err
-		default:
-			break
-		}
+	return nil
+}
 
-		buf := new(bytes.Buffer)
-		err := jpeg.Encode(buf, e.Image(), &jpeg.Options{Quality: 80})
-		if err != nil {
-			return err
-		}
+// Deliver sends the event to the channel without any filtering, used by the notification router
+func (b *Bot) Deliver(_ context.Context, e event.Event) error {
+	switch evt := e.(type) {
+	case event.GameCreatedEvent:
+		message := fmt.Sprintf("**[%s]** %s\nGame: %s\nPassword: %s", evt.Supervisor(), evt.Message(), evt.Name, evt.Password)
+		_, err := b.discordSession.ChannelMessageSend(b.channelID, message)
+		return err
+	case event.GameFinishedEvent:
+		message := fmt.Sprintf("**[%s]** %s", evt.Supervisor(), evt.Message())
+		_, err := b.discordSession.ChannelMessageSend(b.channelID, message)
+		return err
+	case event.RunStartedEvent:
+		message := fmt.Sprintf("**[%s]** started a new run: **%s**", evt.Supervisor(), evt.RunName)
+		_, err := b.discordSession.ChannelMessageSend(b.channelID, message)
+		return err
+	case event.RunFinishedEvent:
+		message := fmt.Sprintf("**[%s]** finished run: **%s** (%s)", evt.Supervisor(), evt.RunName, evt.Reason)
+		_, err := b.discordSession.ChannelMessageSend(b.channelID, message)
+		return err
+	default:
+		break
+	}
 
-		// Add supervisor name to screenshot messages
-		message := fmt.Sprintf("**[%s]** %s", e.Supervisor(), e.Message())
+	// Add supervisor name to screenshot messages
+	message := fmt.Sprintf("**[%s]** %s", e.Supervisor(), e.Message())
 
-		_, err = b.discordSession.ChannelMessageSendComplex(b.channelID, &discordgo.MessageSend{
-			File:    &discordgo.File{Name: "Screenshot.jpeg", ContentType: "image/jpeg", Reader: buf},
-			Content: message,
-		})
+	if e.Image() == nil {
+		_, err := b.discordSession.ChannelMessageSend(b.channelID, message)
+		return err
+	}
 
+	buf := new(bytes.Buffer)
+	err := jpeg.Encode(buf, e.Image(), &jpeg.Options{Quality: 80})
+	if err != nil {
 		return err
 	}
 
-	return nil
+	_, err = b.discordSession.ChannelMessageSendComplex(b.channelID, &discordgo.MessageSend{
+		File:    &discordgo.File{Name: "Screenshot.jpeg", ContentType: "image/jpeg", Reader: buf},
+		Content: message,
+	})
+
+	return err
+}
+
+// SendText sends a plain message to the channel, used for notification digests
+func (b *Bot) SendText(_ context.Context, message string) error {
+	_, err := b.discordSession.ChannelMessageSend(b.channelID, message)
+	return err
 }
 
 func (b *Bot) shouldPublish(e event.Event) bool {
@@ -75,6 +90,10 @@ func (b *Bot) shouldPublish(e event.Event) bool {
 		return config.Koolo.Discord.EnableNewRunMessages
 	case event.RunFinishedEvent:
 		return config.Koolo.Discord.EnableRunFinishMessages
+	case event.PlayerDiedEvent, event.MercDiedEvent:
+		return config.Koolo.Discord.EnableDiscordChickenMessages
+	case event.LevelUpEvent, event.QuestCompletedEvent, event.DifficultyChangedEvent:
+		return config.Koolo.Discord.EnableProgressMessages
 	default:
 		break
 	}
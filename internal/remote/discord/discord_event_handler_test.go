@@ -0,0 +1,54 @@
+package discord
+
+import (
+	"testing"
+
+	"github.com/hectorgimenez/d2go/pkg/data/area"
+	"github.com/hectorgimenez/d2go/pkg/data/difficulty"
+	"github.com/hectorgimenez/d2go/pkg/data/quest"
+	"github.com/hectorgimenez/koolo/internal/config"
+	"github.com/hectorgimenez/koolo/internal/event"
+)
+
+func TestShouldPublish(t *testing.T) {
+	levelUp := event.LevelUp(event.Text("koza", "Reached level 30"), 30, difficulty.Normal)
+	questCompleted := event.QuestCompleted(event.Text("koza", "Quest completed"), quest.Act1DenOfEvil, "Den of Evil", difficulty.Normal)
+	difficultyChanged := event.DifficultyChanged(event.Text("koza", "Difficulty changed"), difficulty.Normal, difficulty.Nightmare)
+	playerDied := event.PlayerDied(event.Text("koza", "Died"), area.BloodMoor, nil, "")
+	mercDied := event.MercDied(event.Text("koza", "Mercenary died"), area.BloodMoor)
+
+	tests := []struct {
+		name     string
+		progress bool
+		chicken  bool
+		e        event.Event
+		want     bool
+	}{
+		{"level up enabled", true, false, levelUp, true},
+		{"level up disabled", false, true, levelUp, false},
+		{"quest completed enabled", true, false, questCompleted, true},
+		{"quest completed disabled", false, true, questCompleted, false},
+		{"difficulty changed enabled", true, false, difficultyChanged, true},
+		{"difficulty changed disabled", false, false, difficultyChanged, false},
+		{"player died enabled", false, true, playerDied, true},
+		{"player died disabled", true, false, playerDied, false},
+		{"merc died enabled", false, true, mercDied, true},
+		{"merc died disabled", true, false, mercDied, false},
+	}
+
+	previous := config.Koolo
+	t.Cleanup(func() { config.Koolo = previous })
+
+	b := &Bot{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.Koolo = &config.KooloCfg{}
+			config.Koolo.Discord.EnableProgressMessages = tt.progress
+			config.Koolo.Discord.EnableDiscordChickenMessages = tt.chicken
+
+			if got := b.shouldPublish(tt.e); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package droplog
 
 import (
 	"bufio"
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -35,92 +36,215 @@ func NewWriter(logDir string, logger *slog.Logger) *Writer {
 	return &Writer{logDir: logDir, logger: logger}
 }
 
-// Handle subscribes to the event bus and persists ItemStashedEvent to a daily JSONL file.
-func (w *Writer) Handle(_ context.Context, e event.Event) error {
-	ist, ok := e.(event.ItemStashedEvent)
-	if !ok {
-		return nil
-	}
+// EventRecord is the persisted representation of a leveling lifecycle event (level up, quest, deaths...).
+type EventRecord struct {
+	Time       time.Time `json:"time"`
+	Supervisor string    `json:"supervisor"`
+	Character  string    `json:"character"`
+	Profile    string    `json:"profile"`
+	Type       string    `json:"type"`
+	Message    string    `json:"message"`
+	Details    any       `json:"details,omitempty"`
+}
 
+// Handle subscribes to the event bus and persists ItemStashedEvent to a daily JSONL file, lifecycle events are
+// persisted to their own daily file.
+func (w *Writer) Handle(_ context.Context, e event.Event) error {
 	// Resolve metadata
 	sup := e.Supervisor()
 	charName := ""
 	profile := ""
-	if cfg, found := config.GetCharacter(sup); found && cfg != nil { 
+	if cfg, found := config.GetCharacter(sup); found && cfg != nil {
 		charName = cfg.CharacterName
 		profile = cfg.ConfigFolderName
 	}
 
-	rec := Record{
+	switch evt := e.(type) {
+	case event.ItemStashedEvent:
+		w.append("droplog", Record{
+			Time:       e.OccurredAt(),
+			Supervisor: sup,
+			Character:  charName,
+			Profile:    profile,
+			Drop:       evt.Item,
+		})
+	case event.LevelUpEvent:
+		w.append("events", w.eventRecord(e, charName, profile, "level_up", map[string]any{"level": evt.Level, "difficulty": evt.Difficulty}))
+	case event.QuestCompletedEvent:
+		w.append("events", w.eventRecord(e, charName, profile, "quest_completed", map[string]any{"quest": evt.QuestName, "difficulty": evt.Difficulty}))
+	case event.DifficultyChangedEvent:
+		w.append("events", w.eventRecord(e, charName, profile, "difficulty_changed", map[string]any{"from": evt.From, "to": evt.To}))
+	case event.MercDiedEvent:
+		w.append("events", w.eventRecord(e, charName, profile, "merc_died", map[string]any{"area": evt.Area.Area().Name}))
+	case event.PlayerDiedEvent:
+		w.append("events", w.eventRecord(e, charName, profile, "player_died", map[string]any{
+			"area":           evt.Area.Area().Name,
+			"nearbyMonsters": evt.NearbyMonsters,
+			"lastAction":     evt.LastAction,
+		}))
+	}
+
+	return nil
+}
+
+func (w *Writer) eventRecord(e event.Event, charName, profile, eventType string, details map[string]any) EventRecord {
+	return EventRecord{
 		Time:       e.OccurredAt(),
-		Supervisor: sup,
+		Supervisor: e.Supervisor(),
 		Character:  charName,
 		Profile:    profile,
-		Drop:       ist.Item,
+		Type:       eventType,
+		Message:    e.Message(),
+		Details:    details,
 	}
+}
 
+// append writes the record to the daily <prefix>-<date>.jsonl file, errors are only logged
+func (w *Writer) append(prefix string, rec any) {
 	// Ensure directory exists
 	if err := os.MkdirAll(w.logDir, 0o755); err != nil {
 		w.logger.Error("Failed to create droplog directory", slog.Any("error", err), slog.String("dir", w.logDir))
-		return nil // don't break the bot because of logging errors
+		return // don't break the bot because of logging errors
 	}
 
 	// Daily rotation by date
-	file := filepath.Join(w.logDir, fmt.Sprintf("droplog-%s.jsonl", time.Now().Format("2006-01-02")))
+	file := filepath.Join(w.logDir, fmt.Sprintf("%s-%s.jsonl", prefix, time.Now().Format("2006-01-02")))
 	f, err := os.OpenFile(file, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
 	if err != nil {
 		w.logger.Error("Failed to open droplog file", slog.Any("error", err), slog.String("file", file))
-		return nil
+		return
 	}
 	defer f.Close()
 
 	enc, err := json.Marshal(rec)
 	if err != nil {
 		w.logger.Error("Failed to encode droplog record", slog.Any("error", err))
-		return nil
+		return
 	}
 	if _, err = f.Write(append(enc, '\n')); err != nil {
 		w.logger.Error("Failed to write droplog record", slog.Any("error", err))
 	}
+}
+
+// StashSnapshot are the items a character had in its stash and inventory the last time it was saved, so they can be
+// read while the character is not running, like the mules.
+type StashSnapshot struct {
+	Time       time.Time   `json:"time"`
+	Supervisor string      `json:"supervisor"`
+	Items      []data.Item `json:"items"`
+}
+
+// WriteStash replaces the stash snapshot of the supervisor. It's written to a temp file first, so a snapshot being
+// written is never read half way.
+func WriteStash(logDir string, snapshot StashSnapshot) error {
+	if err := os.MkdirAll(logDir, 0o755); err != nil {
+		return err
+	}
+
+	enc, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	file := stashFile(logDir, snapshot.Supervisor)
+	tmp := file + ".tmp"
+	if err = os.WriteFile(tmp, enc, 0o644); err != nil {
+		return err
+	}
+	if err = os.Rename(tmp, file); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
 
 	return nil
 }
 
+// ReadStash returns the last stash snapshot of the supervisor, found is false when it was never written.
+func ReadStash(logDir, supervisor string) (snapshot StashSnapshot, found bool, err error) {
+	content, err := os.ReadFile(stashFile(logDir, supervisor))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return snapshot, false, nil
+		}
+		return snapshot, false, err
+	}
+
+	if err = json.Unmarshal(content, &snapshot); err != nil {
+		return snapshot, false, fmt.Errorf("error reading the stash of %s: %w", supervisor, err)
+	}
+
+	return snapshot, true, nil
+}
+
+func stashFile(logDir, supervisor string) string {
+	return filepath.Join(logDir, fmt.Sprintf("stash-%s.json", supervisor))
+}
+
 // ReadAll scans the log directory for droplog-*.jsonl files, parses them, and returns all records.
 func ReadAll(logDir string) ([]Record, error) {
-	files, err := filepath.Glob(filepath.Join(logDir, "droplog-*.jsonl"))
+	return readRecords[Record](logDir, "droplog")
+}
+
+// ReadEvents scans the log directory for events-*.jsonl files and returns all the lifecycle event records.
+func ReadEvents(logDir string) ([]EventRecord, error) {
+	return readRecords[EventRecord](logDir, "events")
+}
+
+func readRecords[T any](logDir, prefix string) ([]T, error) {
+	files, err := filepath.Glob(filepath.Join(logDir, prefix+"-*.jsonl"))
 	if err != nil {
 		return nil, err
 	}
+	// Older files are compressed by the retention policy
+	compressed, err := filepath.Glob(filepath.Join(logDir, prefix+"-*.jsonl.gz"))
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, compressed...)
 
 	// Sort by name (date) ascending
 	sortStrings(files)
 
-	var out []Record
+	var out []T
 	for _, fpath := range files {
-		f, err := os.Open(fpath)
+		out = append(out, readFile[T](fpath)...)
+	}
+	return out, nil
+}
+
+// readFile returns the records of a daily file, unreadable files and lines are skipped
+func readFile[T any](fpath string) []T {
+	f, err := os.Open(fpath)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var src io.Reader = f
+	if strings.HasSuffix(fpath, ".gz") {
+		zr, err := gzip.NewReader(f)
 		if err != nil {
-			continue
+			return nil
 		}
-		r := bufio.NewReader(f)
-		for {
-			line, err := r.ReadString('\n')
-			if len(line) > 0 {
-				var rec Record
-				if err := json.Unmarshal([]byte(strings.TrimSpace(line)), &rec); err == nil {
-					out = append(out, rec)
-				}
-			}
-			if err != nil {
-				if err == io.EOF {
-					break
-				}
-				break
+		defer zr.Close()
+		src = zr
+	}
+
+	var out []T
+	r := bufio.NewReader(src)
+	for {
+		line, err := r.ReadString('\n')
+		if len(line) > 0 {
+			var rec T
+			if err := json.Unmarshal([]byte(strings.TrimSpace(line)), &rec); err == nil {
+				out = append(out, rec)
 			}
 		}
-		f.Close()
+		if err != nil {
+			break
+		}
 	}
-	return out, nil
+	return out
 }
 
 // minimal local sort to avoid pulling extra deps
@@ -0,0 +1,424 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hectorgimenez/koolo/internal/config"
+	"github.com/hectorgimenez/koolo/internal/event"
+)
+
+type Channel string
+
+const (
+	ChannelDiscord  Channel = "discord"
+	ChannelTelegram Channel = "telegram"
+
+	defaultDigestInterval = 30 * time.Minute
+	defaultDigestMaxLines = 20
+	// maxDigestEntries is the max number of entries waiting per channel, the oldest ones are dropped first
+	maxDigestEntries = 500
+	// digestFlushTimeout limits the time sending the pending digests when stopping
+	digestFlushTimeout = 10 * time.Second
+)
+
+// Notifier is implemented by every remote integration able to deliver events
+type Notifier interface {
+	Deliver(ctx context.Context, e event.Event) error
+	SendText(ctx context.Context, message string) error
+}
+
+type digestEntry struct {
+	occurredAt time.Time
+	supervisor string
+	eventType  string
+	message    string
+}
+
+type channelState struct {
+	notifier Notifier
+	sentAt   []time.Time
+	digest   []digestEntry
+	// dropped is the number of digest entries removed since the last flush, the digest is full
+	dropped   int
+	lastFlush time.Time
+}
+
+// Router decides, per channel, if an event should be sent right away, batched into a digest or muted.
+type Router struct {
+	mu       sync.Mutex
+	channels map[Channel]*channelState
+	cfg      func() config.NotificationsCfg
+	now      func() time.Time
+	logger   *slog.Logger
+}
+
+func NewRouter(logger *slog.Logger) *Router {
+	return &Router{
+		channels: make(map[Channel]*channelState),
+		cfg: func() config.NotificationsCfg {
+			return config.Koolo.Notifications
+		},
+		now:    time.Now,
+		logger: logger,
+	}
+}
+
+func (r *Router) Register(ch Channel, n Notifier) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.channels[ch] = &channelState{notifier: n, lastFlush: r.now()}
+}
+
+// Handle subscribes to the event bus and dispatches the event to every registered channel
+func (r *Router) Handle(ctx context.Context, e event.Event) error {
+	eventType := EventType(e)
+	if eventType == "" {
+		return nil
+	}
+
+	cfg := r.cfg()
+	var toDeliver []Notifier
+
+	r.mu.Lock()
+	for ch, state := range r.channels {
+		action := Decide(cfg, ch, e)
+		if action == config.NotificationActionMute {
+			continue
+		}
+
+		now := r.now()
+		if action == config.NotificationActionSend && InQuietHours(cfg.QuietHours, now) {
+			action = config.NotificationActionDigest
+		}
+
+		if action == config.NotificationActionSend && !state.allow(cfg.RateLimit, now) {
+			r.logger.Debug("Notification rate limit reached", slog.String("channel", string(ch)), slog.String("event", eventType))
+			action = config.NotificationActionDigest
+		}
+
+		if action == config.NotificationActionDigest {
+			if cfg.Digest.Enabled {
+				state.addDigestEntry(digestEntry{
+					occurredAt: e.OccurredAt(),
+					supervisor: e.Supervisor(),
+					eventType:  eventType,
+					message:    e.Message(),
+				})
+			}
+			continue
+		}
+
+		toDeliver = append(toDeliver, state.notifier)
+	}
+	r.mu.Unlock()
+
+	// Delivery is done outside the lock, remote APIs can be slow
+	var lastErr error
+	for _, n := range toDeliver {
+		if err := n.Deliver(ctx, e); err != nil {
+			lastErr = err
+		}
+	}
+
+	return lastErr
+}
+
+// Start periodically flushes the pending digests until the context is cancelled, then the remaining ones are sent
+func (r *Router) Start(ctx context.Context) error {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// The pending digests are sent before stopping, ctx is already cancelled
+			flushCtx, cancel := context.WithTimeout(context.Background(), digestFlushTimeout)
+			r.flushDigests(flushCtx, true)
+			cancel()
+			return nil
+		case <-ticker.C:
+			r.flushDigests(ctx, false)
+		}
+	}
+}
+
+func (r *Router) flushDigests(ctx context.Context, force bool) {
+	cfg := r.cfg()
+	now := r.now()
+	if !force && InQuietHours(cfg.QuietHours, now) {
+		return
+	}
+
+	interval := defaultDigestInterval
+	if cfg.Digest.IntervalMinutes > 0 {
+		interval = time.Duration(cfg.Digest.IntervalMinutes) * time.Minute
+	}
+
+	type pending struct {
+		ch       Channel
+		notifier Notifier
+		message  string
+	}
+	var toSend []pending
+
+	r.mu.Lock()
+	for ch, state := range r.channels {
+		if !force && now.Sub(state.lastFlush) < interval {
+			continue
+		}
+		state.lastFlush = now
+		if len(state.digest) == 0 {
+			continue
+		}
+		toSend = append(toSend, pending{ch: ch, notifier: state.notifier, message: formatDigest(state.digest, state.dropped, cfg.Digest.MaxLines)})
+		state.digest = nil
+		state.dropped = 0
+	}
+	r.mu.Unlock()
+
+	for _, p := range toSend {
+		if err := p.notifier.SendText(ctx, p.message); err != nil {
+			r.logger.Error("Failed to send notification digest", slog.String("channel", string(p.ch)), slog.Any("error", err))
+		}
+	}
+}
+
+// addDigestEntry queues the entry for the next digest, the oldest entry is dropped when the digest is full
+func (s *channelState) addDigestEntry(entry digestEntry) {
+	if len(s.digest) >= maxDigestEntries {
+		s.digest = slices.Delete(s.digest, 0, 1)
+		s.dropped++
+	}
+	s.digest = append(s.digest, entry)
+}
+
+// allow applies the sliding window rate limit, recording the message when allowed
+func (s *channelState) allow(rl config.NotificationRateLimit, now time.Time) bool {
+	if rl.MaxMessages <= 0 || rl.PeriodSeconds <= 0 {
+		return true
+	}
+
+	windowStart := now.Add(-time.Duration(rl.PeriodSeconds) * time.Second)
+	kept := s.sentAt[:0]
+	for _, t := range s.sentAt {
+		if t.After(windowStart) {
+			kept = append(kept, t)
+		}
+	}
+	s.sentAt = kept
+
+	if len(s.sentAt) >= rl.MaxMessages {
+		return false
+	}
+	s.sentAt = append(s.sentAt, now)
+
+	return true
+}
+
+// Decide returns the action for the given event and channel, first matching rule wins
+func Decide(cfg config.NotificationsCfg, ch Channel, e event.Event) string {
+	for _, rule := range cfg.Rules {
+		if ruleMatches(rule, ch, e) {
+			return normalizeAction(rule.Action, config.NotificationActionSend)
+		}
+	}
+
+	return normalizeAction(cfg.DefaultAction, config.NotificationActionMute)
+}
+
+func normalizeAction(action, fallback string) string {
+	switch strings.ToLower(strings.TrimSpace(action)) {
+	case config.NotificationActionSend:
+		return config.NotificationActionSend
+	case config.NotificationActionDigest:
+		return config.NotificationActionDigest
+	case config.NotificationActionMute:
+		return config.NotificationActionMute
+	}
+
+	return fallback
+}
+
+func ruleMatches(rule config.NotificationRule, ch Channel, e event.Event) bool {
+	if len(rule.Channels) > 0 && !containsFold(rule.Channels, string(ch)) {
+		return false
+	}
+	if len(rule.Events) > 0 && !containsFold(rule.Events, EventType(e)) {
+		return false
+	}
+	if len(rule.Supervisors) > 0 && !containsFold(rule.Supervisors, e.Supervisor()) {
+		return false
+	}
+	if len(rule.Reasons) > 0 {
+		reason, ok := finishReason(e)
+		if !ok || !containsFold(rule.Reasons, string(reason)) {
+			return false
+		}
+	}
+
+	if len(rule.DropQualities) > 0 || rule.NipMatch != "" {
+		var quality, nipRule, nipFile string
+		switch evt := e.(type) {
+		case event.ItemStashedEvent:
+			quality, nipRule, nipFile = evt.Item.Item.Quality.ToString(), evt.Item.Rule, evt.Item.RuleFile
+		case event.ItemBlackListedEvent:
+			quality, nipRule, nipFile = evt.Item.Item.Quality.ToString(), evt.Item.Rule, evt.Item.RuleFile
+		default:
+			return false
+		}
+
+		if len(rule.DropQualities) > 0 && !containsFold(rule.DropQualities, quality) {
+			return false
+		}
+		if rule.NipMatch != "" {
+			needle := strings.ToLower(rule.NipMatch)
+			if !strings.Contains(strings.ToLower(nipRule), needle) && !strings.Contains(strings.ToLower(nipFile), needle) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// EventType returns the routing name of the event, empty for internal events that are never notified
+func EventType(e event.Event) string {
+	switch e.(type) {
+	case event.GameCreatedEvent:
+		return "game_created"
+	case event.GameFinishedEvent:
+		return "game_finished"
+	case event.RunStartedEvent:
+		return "run_started"
+	case event.RunFinishedEvent:
+		return "run_finished"
+	case event.ItemStashedEvent:
+		return "item_stashed"
+	case event.ItemBlackListedEvent:
+		return "item_blacklisted"
+	case event.GamePausedEvent:
+		return "game_paused"
+	case event.LevelUpEvent:
+		return "level_up"
+	case event.QuestCompletedEvent:
+		return "quest_completed"
+	case event.DifficultyChangedEvent:
+		return "difficulty_changed"
+	case event.MercDiedEvent:
+		return "merc_died"
+	case event.PlayerDiedEvent:
+		return "player_died"
+	case event.UsedPotionEvent, event.InteractedToEvent, event.CompanionLeaderAttackEvent, event.CompanionRequestedTPEvent,
+		event.RequestCompanionJoinGameEvent, event.ResetCompanionGameInfoEvent, event.CharacterSwitchEvent:
+		return ""
+	}
+
+	if e.Image() != nil {
+		return "screenshot"
+	}
+
+	return ""
+}
+
+func finishReason(e event.Event) (event.FinishReason, bool) {
+	switch evt := e.(type) {
+	case event.GameFinishedEvent:
+		return evt.Reason, true
+	case event.RunFinishedEvent:
+		return evt.Reason, true
+	}
+
+	return "", false
+}
+
+// InQuietHours returns true if the given time is inside the configured quiet hours range
+func InQuietHours(qh config.NotificationQuietHours, now time.Time) bool {
+	if !qh.Enabled {
+		return false
+	}
+
+	start, errStart := time.Parse("15:04", qh.Start)
+	end, errEnd := time.Parse("15:04", qh.End)
+	if errStart != nil || errEnd != nil {
+		return false
+	}
+
+	current := now.Hour()*60 + now.Minute()
+	startMin := start.Hour()*60 + start.Minute()
+	endMin := end.Hour()*60 + end.Minute()
+
+	if startMin == endMin {
+		return false
+	}
+	if startMin < endMin {
+		return current >= startMin && current < endMin
+	}
+
+	// Range crosses midnight
+	return current >= startMin || current < endMin
+}
+
+// formatDigest builds a summary message with event counters per supervisor followed by the latest entries, dropped
+// is the number of entries removed because the digest was full
+func formatDigest(entries []digestEntry, dropped, maxLines int) string {
+	if maxLines <= 0 {
+		maxLines = defaultDigestMaxLines
+	}
+
+	counters := make(map[string]map[string]int)
+	for _, entry := range entries {
+		if counters[entry.supervisor] == nil {
+			counters[entry.supervisor] = make(map[string]int)
+		}
+		counters[entry.supervisor][entry.eventType]++
+	}
+
+	supervisors := make([]string, 0, len(counters))
+	for sup := range counters {
+		supervisors = append(supervisors, sup)
+	}
+	sort.Strings(supervisors)
+
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("Summary: %d notifications since %s\n", len(entries), entries[0].occurredAt.Format("15:04")))
+	if dropped > 0 {
+		sb.WriteString(fmt.Sprintf("%d older notifications dropped, too many to keep\n", dropped))
+	}
+	for _, sup := range supervisors {
+		types := make([]string, 0, len(counters[sup]))
+		for t := range counters[sup] {
+			types = append(types, t)
+		}
+		sort.Strings(types)
+
+		parts := make([]string, 0, len(types))
+		for _, t := range types {
+			parts = append(parts, fmt.Sprintf("%s: %d", t, counters[sup][t]))
+		}
+		sb.WriteString(fmt.Sprintf("[%s] %s\n", sup, strings.Join(parts, ", ")))
+	}
+
+	start := 0
+	if len(entries) > maxLines {
+		start = len(entries) - maxLines
+		sb.WriteString(fmt.Sprintf("... %d older entries omitted\n", start))
+	}
+	for _, entry := range entries[start:] {
+		sb.WriteString(fmt.Sprintf("%s [%s] %s\n", entry.occurredAt.Format("15:04:05"), entry.supervisor, entry.message))
+	}
+
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func containsFold(values []string, value string) bool {
+	return slices.ContainsFunc(values, func(v string) bool {
+		return strings.EqualFold(strings.TrimSpace(v), value)
+	})
+}
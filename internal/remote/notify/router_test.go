@@ -0,0 +1,258 @@
+package notify
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/koolo/internal/config"
+	"github.com/hectorgimenez/koolo/internal/event"
+)
+
+func TestDecide(t *testing.T) {
+	gameFinished := event.GameFinished(event.Text("koza", "Game finished"), event.FinishedDied)
+	runStarted := event.RunStarted(event.Text("koza", "Run started"), "pit")
+
+	cfg := config.NotificationsCfg{
+		DefaultAction: config.NotificationActionDigest,
+		Rules: []config.NotificationRule{
+			{Events: []string{"game_finished"}, Reasons: []string{"death"}, Action: "send"},
+			{Events: []string{"game_finished"}, Action: "mute"},
+			{Channels: []string{"telegram"}, Events: []string{"run_started"}, Action: "mute"},
+			// Invalid actions fall back to send
+			{Supervisors: []string{"other"}, Action: "unknown"},
+		},
+	}
+
+	tests := []struct {
+		name string
+		cfg  config.NotificationsCfg
+		ch   Channel
+		e    event.Event
+		want string
+	}{
+		{"first matching rule wins", cfg, ChannelDiscord, gameFinished, config.NotificationActionSend},
+		{"reason not matching", cfg, ChannelDiscord, event.GameFinished(event.Text("koza", ""), event.FinishedOK), config.NotificationActionMute},
+		{"channel rule", cfg, ChannelTelegram, runStarted, config.NotificationActionMute},
+		{"default action", cfg, ChannelDiscord, runStarted, config.NotificationActionDigest},
+		{"invalid rule action", cfg, ChannelDiscord, event.RunStarted(event.Text("other", ""), "pit"), config.NotificationActionSend},
+		{"muted without default action", config.NotificationsCfg{}, ChannelDiscord, runStarted, config.NotificationActionMute},
+		{"invalid default action", config.NotificationsCfg{DefaultAction: "typo"}, ChannelDiscord, runStarted, config.NotificationActionMute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Decide(tt.cfg, tt.ch, tt.e); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRuleMatches(t *testing.T) {
+	stashed := event.ItemStashed(event.Text("koza", "Item stashed"), data.Drop{Rule: "[type] == ring && [quality] == unique", RuleFile: "rings.nip"})
+	runFinished := event.RunFinished(event.Text("koza", "Run finished"), "pit", event.FinishedChicken)
+
+	tests := []struct {
+		name string
+		rule config.NotificationRule
+		ch   Channel
+		e    event.Event
+		want bool
+	}{
+		{"empty rule matches everything", config.NotificationRule{}, ChannelDiscord, stashed, true},
+		{"channel is case insensitive", config.NotificationRule{Channels: []string{" Discord "}}, ChannelDiscord, stashed, true},
+		{"other channel", config.NotificationRule{Channels: []string{"telegram"}}, ChannelDiscord, stashed, false},
+		{"event type", config.NotificationRule{Events: []string{"item_stashed"}}, ChannelDiscord, stashed, true},
+		{"other event type", config.NotificationRule{Events: []string{"run_finished"}}, ChannelDiscord, stashed, false},
+		{"supervisor", config.NotificationRule{Supervisors: []string{"KOZA"}}, ChannelDiscord, stashed, true},
+		{"other supervisor", config.NotificationRule{Supervisors: []string{"other"}}, ChannelDiscord, stashed, false},
+		{"reason", config.NotificationRule{Reasons: []string{"chicken"}}, ChannelDiscord, runFinished, true},
+		{"other reason", config.NotificationRule{Reasons: []string{"death"}}, ChannelDiscord, runFinished, false},
+		{"reason of an event without reason", config.NotificationRule{Reasons: []string{"ok"}}, ChannelDiscord, stashed, false},
+		{"nip rule", config.NotificationRule{NipMatch: "RING"}, ChannelDiscord, stashed, true},
+		{"nip rule file", config.NotificationRule{NipMatch: "rings.nip"}, ChannelDiscord, stashed, true},
+		{"other nip rule", config.NotificationRule{NipMatch: "amulet"}, ChannelDiscord, stashed, false},
+		{"nip match of an event without item", config.NotificationRule{NipMatch: "ring"}, ChannelDiscord, runFinished, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ruleMatches(tt.rule, tt.ch, tt.e); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInQuietHours(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, 1, 1, hour, minute, 0, 0, time.Local)
+	}
+	night := config.NotificationQuietHours{Enabled: true, Start: "22:00", End: "06:00"}
+	day := config.NotificationQuietHours{Enabled: true, Start: "09:30", End: "17:00"}
+
+	tests := []struct {
+		name string
+		qh   config.NotificationQuietHours
+		now  time.Time
+		want bool
+	}{
+		{"disabled", config.NotificationQuietHours{Start: "00:00", End: "23:59"}, at(12, 0), false},
+		{"same day inside", day, at(12, 0), true},
+		{"same day start is included", day, at(9, 30), true},
+		{"same day end is excluded", day, at(17, 0), false},
+		{"same day before", day, at(9, 29), false},
+		{"midnight wrap before midnight", night, at(23, 30), true},
+		{"midnight wrap start", night, at(22, 0), true},
+		{"midnight wrap after midnight", night, at(0, 15), true},
+		{"midnight wrap last minute", night, at(5, 59), true},
+		{"midnight wrap end is excluded", night, at(6, 0), false},
+		{"midnight wrap outside", night, at(12, 0), false},
+		{"empty range", config.NotificationQuietHours{Enabled: true, Start: "10:00", End: "10:00"}, at(10, 0), false},
+		{"invalid time", config.NotificationQuietHours{Enabled: true, Start: "10pm", End: "06:00"}, at(23, 0), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InQuietHours(tt.qh, tt.now); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatDigest(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	entries := []digestEntry{
+		{occurredAt: start, supervisor: "koza", eventType: "item_stashed", message: "Ring"},
+		{occurredAt: start.Add(time.Minute), supervisor: "alpha", eventType: "game_finished", message: "Game finished"},
+		{occurredAt: start.Add(2 * time.Minute), supervisor: "koza", eventType: "item_stashed", message: "Amulet"},
+		{occurredAt: start.Add(3 * time.Minute), supervisor: "koza", eventType: "level_up", message: "Level 20"},
+	}
+
+	tests := []struct {
+		name     string
+		dropped  int
+		maxLines int
+		want     []string
+	}{
+		{
+			name: "every entry",
+			want: []string{
+				"Summary: 4 notifications since 10:00",
+				"[alpha] game_finished: 1",
+				"[koza] item_stashed: 2, level_up: 1",
+				"10:00:00 [koza] Ring",
+				"10:01:00 [alpha] Game finished",
+				"10:02:00 [koza] Amulet",
+				"10:03:00 [koza] Level 20",
+			},
+		},
+		{
+			name:     "latest entries",
+			maxLines: 2,
+			want: []string{
+				"Summary: 4 notifications since 10:00",
+				"[alpha] game_finished: 1",
+				"[koza] item_stashed: 2, level_up: 1",
+				"... 2 older entries omitted",
+				"10:02:00 [koza] Amulet",
+				"10:03:00 [koza] Level 20",
+			},
+		},
+		{
+			name:     "dropped entries",
+			dropped:  7,
+			maxLines: 1,
+			want: []string{
+				"Summary: 4 notifications since 10:00",
+				"7 older notifications dropped, too many to keep",
+				"[alpha] game_finished: 1",
+				"[koza] item_stashed: 2, level_up: 1",
+				"... 3 older entries omitted",
+				"10:03:00 [koza] Level 20",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, want := formatDigest(entries, tt.dropped, tt.maxLines), strings.Join(tt.want, "\n"); got != want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestDigestIsCapped(t *testing.T) {
+	state := &channelState{}
+	for i := 0; i < maxDigestEntries+10; i++ {
+		state.addDigestEntry(digestEntry{message: string(rune('a' + i%26))})
+	}
+
+	if len(state.digest) != maxDigestEntries || state.dropped != 10 {
+		t.Fatalf("got %d entries and %d dropped, want %d and 10", len(state.digest), state.dropped, maxDigestEntries)
+	}
+	// The oldest entries are the dropped ones
+	if got := state.digest[0].message; got != string(rune('a'+10)) {
+		t.Errorf("got oldest entry %q, want %q", got, string(rune('a'+10)))
+	}
+}
+
+func TestStartFlushesDigestsWhenStopping(t *testing.T) {
+	notifier := &testNotifier{}
+	r := &Router{
+		channels: make(map[Channel]*channelState),
+		cfg: func() config.NotificationsCfg {
+			return config.NotificationsCfg{
+				DefaultAction: config.NotificationActionDigest,
+				Digest:        config.NotificationDigest{Enabled: true},
+			}
+		},
+		now: time.Now,
+	}
+	r.Register(ChannelDiscord, notifier)
+
+	if err := r.Handle(context.Background(), event.RunStarted(event.Text("koza", "Run started"), "pit")); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := r.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	notifier.mu.Lock()
+	defer notifier.mu.Unlock()
+	if len(notifier.texts) != 1 || !strings.Contains(notifier.texts[0], "[koza] Run started") {
+		t.Fatalf("got digests %v", notifier.texts)
+	}
+	if notifier.ctxErr != nil {
+		t.Errorf("digest sent with a done context: %v", notifier.ctxErr)
+	}
+}
+
+type testNotifier struct {
+	mu     sync.Mutex
+	texts  []string
+	ctxErr error
+}
+
+func (n *testNotifier) Deliver(context.Context, event.Event) error {
+	return nil
+}
+
+func (n *testNotifier) SendText(ctx context.Context, message string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.texts = append(n.texts, message)
+	n.ctxErr = ctx.Err()
+
+	return nil
+}
@@ -9,7 +9,12 @@ import (
     "github.com/hectorgimenez/koolo/internal/event"
 )
 
-func (b *Bot) Handle(_ context.Context, e event.Event) error {
+func (b *Bot) Handle(ctx context.Context, e event.Event) error {
+    return b.Deliver(ctx, e)
+}
+
+// Deliver sends the event to the chat, used directly by the notification router
+func (b *Bot) Deliver(_ context.Context, e event.Event) error {
     if e.Image() != nil {
         buf := new(bytes.Buffer)
         if err := jpeg.Encode(buf, e.Image(), &jpeg.Options{Quality: 90}); err != nil {
@@ -24,3 +29,9 @@ func (b *Bot) Handle(_ context.Context, e event.Event) error {
     _, err := b.bot.Send(tgbotapi.NewMessage(b.chatID, e.Message()))
     return err
 }
+
+// SendText sends a plain message to the chat, used for notification digests
+func (b *Bot) SendText(_ context.Context, message string) error {
+    _, err := b.bot.Send(tgbotapi.NewMessage(b.chatID, message))
+    return err
+}
@@ -0,0 +1,270 @@
+// Package retention removes and compresses the old files written by koolo, like the logs, the error screenshots and
+// the droplogs, so they don't grow without bound.
+package retention
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const compressedExt = ".gz"
+
+// Policy is what is kept of a folder, zero values disable each rule
+type Policy struct {
+	// MaxAge removes the files not modified for longer
+	MaxAge time.Duration `json:"maxAge"`
+	// MaxTotalSize removes the oldest files until the folder uses less bytes
+	MaxTotalSize int64 `json:"maxTotalSize"`
+	// CompressAfter compresses the files not modified for longer
+	CompressAfter time.Duration `json:"compressAfter"`
+	// KeepNewest files are never removed nor compressed, whatever the other rules
+	KeepNewest int `json:"keepNewest"`
+}
+
+// Target is a folder managed by a policy, only the files matching the patterns are managed and the subfolders are
+// ignored. Compressed files are matched by the patterns with the ".gz" extension added.
+type Target struct {
+	Name     string   `json:"name"`
+	Dir      string   `json:"dir"`
+	Patterns []string `json:"patterns"`
+	// Compress is false for files already compressed, like images
+	Compress bool   `json:"compress"`
+	Policy   Policy `json:"policy"`
+}
+
+// Result is what a run removed and compressed in a target, files that could not be changed, usually because they
+// are still open, are listed in Errors and retried in the next run
+type Result struct {
+	Target     string   `json:"target"`
+	Removed    int      `json:"removed"`
+	Compressed int      `json:"compressed"`
+	FreedBytes int64    `json:"freedBytes"`
+	Errors     []string `json:"errors,omitempty"`
+}
+
+// Usage is the disk used by the files of a target
+type Usage struct {
+	Target          string    `json:"target"`
+	Dir             string    `json:"dir"`
+	Files           int       `json:"files"`
+	Bytes           int64     `json:"bytes"`
+	CompressedFiles int       `json:"compressedFiles"`
+	Oldest          time.Time `json:"oldest,omitempty"`
+	Newest          time.Time `json:"newest,omitempty"`
+}
+
+type file struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// Apply enforces the target policy, now is the time the file ages are computed from
+func Apply(target Target, now time.Time) (Result, error) {
+	result := Result{Target: target.Name}
+
+	files, err := list(target)
+	if err != nil {
+		return result, err
+	}
+
+	policy := target.Policy
+	kept := make([]file, 0, len(files))
+	for i, f := range files {
+		if i < policy.KeepNewest {
+			kept = append(kept, f)
+			continue
+		}
+
+		age := now.Sub(f.modTime)
+		if policy.MaxAge > 0 && age > policy.MaxAge {
+			if err = os.Remove(f.path); err != nil {
+				result.Errors = append(result.Errors, err.Error())
+				kept = append(kept, f)
+				continue
+			}
+			result.Removed++
+			result.FreedBytes += f.size
+			continue
+		}
+
+		if target.Compress && policy.CompressAfter > 0 && age > policy.CompressAfter && !isCompressed(f.path) {
+			compressed, err := compress(f)
+			if err != nil {
+				result.Errors = append(result.Errors, err.Error())
+				kept = append(kept, f)
+				continue
+			}
+			result.Compressed++
+			result.FreedBytes += f.size - compressed.size
+			f = compressed
+		}
+		kept = append(kept, f)
+	}
+
+	if policy.MaxTotalSize <= 0 {
+		return result, nil
+	}
+
+	var total int64
+	for _, f := range kept {
+		total += f.size
+	}
+	// Files are sorted newest first, the oldest ones are removed first
+	for i := len(kept) - 1; i >= policy.KeepNewest && total > policy.MaxTotalSize; i-- {
+		if err = os.Remove(kept[i].path); err != nil {
+			result.Errors = append(result.Errors, err.Error())
+			continue
+		}
+		result.Removed++
+		result.FreedBytes += kept[i].size
+		total -= kept[i].size
+	}
+
+	return result, nil
+}
+
+// DiskUsage returns the files and bytes used by the target
+func DiskUsage(target Target) (Usage, error) {
+	usage := Usage{Target: target.Name, Dir: target.Dir}
+
+	files, err := list(target)
+	if err != nil {
+		return usage, err
+	}
+
+	for _, f := range files {
+		usage.Files++
+		usage.Bytes += f.size
+		if isCompressed(f.path) {
+			usage.CompressedFiles++
+		}
+	}
+	if len(files) > 0 {
+		usage.Newest = files[0].modTime
+		usage.Oldest = files[len(files)-1].modTime
+	}
+
+	return usage, nil
+}
+
+// list returns the files of the target, newest first. A missing folder has no files.
+func list(target Target) ([]file, error) {
+	entries, err := os.ReadDir(target.Dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading %s: %w", target.Dir, err)
+	}
+
+	files := make([]file, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !matches(target.Patterns, entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			// Removed since the folder was read
+			continue
+		}
+		files = append(files, file{path: filepath.Join(target.Dir, entry.Name()), size: info.Size(), modTime: info.ModTime()})
+	}
+	slices.SortFunc(files, func(a, b file) int {
+		return b.modTime.Compare(a.modTime)
+	})
+
+	return files, nil
+}
+
+func matches(patterns []string, name string) bool {
+	name = strings.ToLower(name)
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(pattern)
+		for _, p := range []string{pattern, pattern + compressedExt} {
+			if ok, _ := filepath.Match(p, name); ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func isCompressed(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), compressedExt)
+}
+
+// compress replaces the file with its gzip compressed copy, the modification time is kept so the file keeps its age
+func compress(f file) (file, error) {
+	out, err := createCompressed(f.path)
+	if err != nil {
+		return f, fmt.Errorf("error compressing %s: %w", f.path, err)
+	}
+	dst := out.Name()
+
+	if err = writeCompressed(f, out); err != nil {
+		_ = os.Remove(dst)
+		return f, fmt.Errorf("error compressing %s: %w", f.path, err)
+	}
+
+	if err = os.Remove(f.path); err != nil {
+		// Still open, it's compressed again in the next run
+		_ = os.Remove(dst)
+		return f, fmt.Errorf("error compressing %s: %w", f.path, err)
+	}
+
+	info, err := os.Stat(dst)
+	if err != nil {
+		return f, fmt.Errorf("error compressing %s: %w", f.path, err)
+	}
+
+	return file{path: dst, size: info.Size(), modTime: f.modTime}, nil
+}
+
+// createCompressed creates the compressed copy of the file, an existing copy is never overwritten: when a file with
+// the same name was compressed before, a counter is added before its extension, like "koolo-log.1.txt.gz"
+func createCompressed(path string) (*os.File, error) {
+	ext := filepath.Ext(path)
+	name := strings.TrimSuffix(path, ext)
+	dst := path + compressedExt
+	for i := 1; ; i++ {
+		out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if !os.IsExist(err) {
+			return out, err
+		}
+		dst = fmt.Sprintf("%s.%d%s%s", name, i, ext, compressedExt)
+	}
+}
+
+func writeCompressed(f file, out *os.File) error {
+	src, err := os.Open(f.path)
+	if err != nil {
+		out.Close()
+		return err
+	}
+	defer src.Close()
+
+	zw := gzip.NewWriter(out)
+	zw.Name = filepath.Base(f.path)
+	zw.ModTime = f.modTime
+	if _, err = io.Copy(zw, src); err != nil {
+		out.Close()
+		return err
+	}
+	if err = zw.Close(); err != nil {
+		out.Close()
+		return err
+	}
+	if err = out.Close(); err != nil {
+		return err
+	}
+
+	return os.Chtimes(out.Name(), f.modTime, f.modTime)
+}
@@ -0,0 +1,193 @@
+package retention
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+var now = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     Policy
+		files      []testFile
+		want       []string
+		removed    int
+		compressed int
+	}{
+		{
+			name:   "disabled rules keep everything",
+			policy: Policy{},
+			files:  []testFile{{"a.txt", 1, 10}, {"b.txt", 100, 10}},
+			want:   []string{"a.txt", "b.txt"},
+		},
+		{
+			name:    "max age",
+			policy:  Policy{MaxAge: 10 * day},
+			files:   []testFile{{"a.txt", 1, 10}, {"b.txt", 9, 10}, {"c.txt", 11, 10}, {"d.txt", 30, 10}},
+			want:    []string{"a.txt", "b.txt"},
+			removed: 2,
+		},
+		{
+			name:    "keep newest wins over max age",
+			policy:  Policy{MaxAge: 10 * day, KeepNewest: 3},
+			files:   []testFile{{"a.txt", 20, 10}, {"b.txt", 30, 10}, {"c.txt", 40, 10}, {"d.txt", 50, 10}},
+			want:    []string{"a.txt", "b.txt", "c.txt"},
+			removed: 1,
+		},
+		{
+			name:    "max total size removes the oldest",
+			policy:  Policy{MaxTotalSize: 25},
+			files:   []testFile{{"a.txt", 1, 10}, {"b.txt", 2, 10}, {"c.txt", 3, 10}, {"d.txt", 4, 10}},
+			want:    []string{"a.txt", "b.txt"},
+			removed: 2,
+		},
+		{
+			name:    "keep newest wins over max total size",
+			policy:  Policy{MaxTotalSize: 5, KeepNewest: 1},
+			files:   []testFile{{"a.txt", 1, 10}, {"b.txt", 2, 10}},
+			want:    []string{"a.txt"},
+			removed: 1,
+		},
+		{
+			name:       "compress old files",
+			policy:     Policy{CompressAfter: 2 * day},
+			files:      []testFile{{"a.txt", 1, 100}, {"b.txt", 3, 100}, {"c.txt.gz", 5, 10}},
+			want:       []string{"a.txt", "b.txt.gz", "c.txt.gz"},
+			compressed: 1,
+		},
+		{
+			name:       "expired files are removed instead of compressed",
+			policy:     Policy{MaxAge: 10 * day, CompressAfter: 2 * day},
+			files:      []testFile{{"a.txt", 1, 100}, {"b.txt", 5, 100}, {"c.txt", 20, 100}},
+			want:       []string{"a.txt", "b.txt.gz"},
+			removed:    1,
+			compressed: 1,
+		},
+		{
+			name:   "size is checked after compressing",
+			policy: Policy{CompressAfter: 2 * day, MaxTotalSize: 150},
+			// Repeated content compresses well below the max size
+			files:      []testFile{{"a.txt", 1, 100}, {"b.txt", 3, 1000}},
+			want:       []string{"a.txt", "b.txt.gz"},
+			compressed: 1,
+		},
+		{
+			name:       "existing compressed files are not overwritten",
+			policy:     Policy{CompressAfter: 2 * day},
+			files:      []testFile{{"a.txt", 3, 100}, {"a.txt.gz", 4, 10}},
+			want:       []string{"a.1.txt.gz", "a.txt.gz"},
+			compressed: 1,
+		},
+		{
+			name:    "files not matching the patterns are ignored",
+			policy:  Policy{MaxAge: day},
+			files:   []testFile{{"a.json", 10, 10}, {"b.txt", 10, 10}},
+			want:    []string{"a.json"},
+			removed: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, f := range tt.files {
+				f.create(t, dir)
+			}
+
+			target := Target{Name: "logs", Dir: dir, Patterns: []string{"*.txt"}, Compress: true, Policy: tt.policy}
+			result, err := Apply(target, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(result.Errors) > 0 {
+				t.Fatalf("unexpected errors: %v", result.Errors)
+			}
+			if result.Removed != tt.removed || result.Compressed != tt.compressed {
+				t.Errorf("got %d removed and %d compressed, want %d and %d", result.Removed, result.Compressed, tt.removed, tt.compressed)
+			}
+
+			if got := fileNames(t, dir); !slices.Equal(got, tt.want) {
+				t.Errorf("got files %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyKeepsCompressedFileAge(t *testing.T) {
+	dir := t.TempDir()
+	testFile{"a.txt", 5, 100}.create(t, dir)
+
+	target := Target{Dir: dir, Patterns: []string{"*.txt"}, Compress: true, Policy: Policy{CompressAfter: day}}
+	if _, err := Apply(target, now); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(filepath.Join(dir, "a.txt.gz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := now.Add(-5 * day); !info.ModTime().Equal(want) {
+		t.Errorf("got modification time %s, want %s", info.ModTime(), want)
+	}
+
+	// Already compressed, so it's removed by the max age in the next run
+	target.Policy.MaxAge = 4 * day
+	result, err := Apply(target, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Removed != 1 || result.Compressed != 0 {
+		t.Errorf("got %d removed and %d compressed, want 1 and 0", result.Removed, result.Compressed)
+	}
+}
+
+func TestApplyMissingFolder(t *testing.T) {
+	target := Target{Dir: filepath.Join(t.TempDir(), "missing"), Patterns: []string{"*.txt"}, Policy: Policy{MaxAge: day}}
+	if _, err := Apply(target, now); err != nil {
+		t.Errorf("a missing folder must have nothing to apply, got %v", err)
+	}
+}
+
+const day = 24 * time.Hour
+
+type testFile struct {
+	name    string
+	ageDays int
+	size    int
+}
+
+func (f testFile) create(t *testing.T, dir string) {
+	t.Helper()
+
+	path := filepath.Join(dir, f.name)
+	if err := os.WriteFile(path, []byte(strings.Repeat("x", f.size)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	modTime := now.Add(-time.Duration(f.ageDays) * day)
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func fileNames(t *testing.T, dir string) []string {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	slices.Sort(names)
+
+	return names
+}
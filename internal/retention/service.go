@@ -0,0 +1,154 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const defaultInterval = 6 * time.Hour
+
+var ErrDisabled = errors.New("retention is disabled")
+
+// Settings are the retention settings, read again before every run so config changes apply without restarting
+type Settings struct {
+	Enabled bool
+	// Interval between runs, the default is used when it's zero
+	Interval time.Duration
+	Targets  []Target
+}
+
+// Status is the disk usage of the targets and the result of the last run
+type Status struct {
+	Enabled  bool          `json:"enabled"`
+	Interval time.Duration `json:"interval"`
+	LastRun  time.Time     `json:"lastRun,omitempty"`
+	Results  []Result      `json:"results"`
+	Targets  []Target      `json:"targets"`
+	Usage    []Usage       `json:"usage"`
+}
+
+// Service applies the retention policies periodically
+type Service struct {
+	logger   *slog.Logger
+	settings func() Settings
+	now      func() time.Time
+
+	// runMu makes the periodic and the manual runs wait for each other
+	runMu   sync.Mutex
+	mu      sync.Mutex
+	lastRun time.Time
+	results []Result
+}
+
+func NewService(logger *slog.Logger, settings func() Settings) *Service {
+	return &Service{logger: logger, settings: settings, now: time.Now, results: make([]Result, 0)}
+}
+
+// Start runs the retention when koolo starts and then every interval, until the context is done
+func (s *Service) Start(ctx context.Context) error {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		if s.due() {
+			if _, err := s.Run(); err != nil && !errors.Is(err, ErrDisabled) {
+				s.logger.Error("Retention run failed", slog.Any("error", err))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Run applies the policies of every target now, returns ErrDisabled when the retention is disabled
+func (s *Service) Run() ([]Result, error) {
+	settings := s.settings()
+	if !settings.Enabled {
+		return nil, ErrDisabled
+	}
+
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+
+	now := s.now()
+	results := make([]Result, 0, len(settings.Targets))
+	var errs []error
+	for _, target := range settings.Targets {
+		result, err := Apply(target, now)
+		if err != nil {
+			errs = append(errs, err)
+			result.Errors = append(result.Errors, err.Error())
+		}
+		results = append(results, result)
+
+		if result.Removed > 0 || result.Compressed > 0 || len(result.Errors) > 0 {
+			s.logger.Info("Retention applied",
+				slog.String("target", target.Name),
+				slog.Int("removed", result.Removed),
+				slog.Int("compressed", result.Compressed),
+				slog.Int64("freedBytes", result.FreedBytes),
+				slog.Int("errors", len(result.Errors)),
+			)
+		}
+	}
+
+	s.mu.Lock()
+	s.lastRun = now
+	s.results = results
+	s.mu.Unlock()
+
+	return results, errors.Join(errs...)
+}
+
+// Status returns the current disk usage of the targets and the last run
+func (s *Service) Status() Status {
+	settings := s.settings()
+
+	s.mu.Lock()
+	status := Status{
+		Enabled:  settings.Enabled,
+		Interval: interval(settings),
+		LastRun:  s.lastRun,
+		Results:  s.results,
+		Targets:  settings.Targets,
+		Usage:    make([]Usage, 0, len(settings.Targets)),
+	}
+	s.mu.Unlock()
+
+	for _, target := range settings.Targets {
+		usage, err := DiskUsage(target)
+		if err != nil {
+			s.logger.Warn("Error reading disk usage", slog.String("target", target.Name), slog.Any("error", err))
+		}
+		status.Usage = append(status.Usage, usage)
+	}
+
+	return status
+}
+
+func (s *Service) due() bool {
+	settings := s.settings()
+	if !settings.Enabled {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lastRun.IsZero() || s.now().Sub(s.lastRun) >= interval(settings)
+}
+
+func interval(settings Settings) time.Duration {
+	if settings.Interval > 0 {
+		return settings.Interval
+	}
+
+	return defaultInterval
+}
@@ -71,7 +71,7 @@ func (s *Baal) Run(parameters *RunParameters) error {
 	}
 
 	if s.ctx.CharacterCfg.Game.Baal.ClearFloors || s.clearMonsterFilter != nil {
-		action.ClearCurrentLevel(false, filter)
+		action.ClearCurrentLevelTo(false, filter, area.TheWorldStoneKeepLevel3)
 	}
 
 	err = action.MoveToArea(area.TheWorldStoneKeepLevel3)
@@ -80,7 +80,7 @@ func (s *Baal) Run(parameters *RunParameters) error {
 	}
 
 	if s.ctx.CharacterCfg.Game.Baal.ClearFloors || s.clearMonsterFilter != nil {
-		action.ClearCurrentLevel(false, filter)
+		action.ClearCurrentLevelTo(false, filter, area.ThroneOfDestruction)
 	}
 
 	err = action.MoveToArea(area.ThroneOfDestruction)
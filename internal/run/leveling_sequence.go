@@ -48,6 +48,8 @@ type SequenceSettings struct {
 	ExitGame         bool   `json:"exitGame,omitempty"`
 	StopIfCheckFails bool   `json:"stopIfCheckFails,omitempty"`
 	Parameters       string `json:"parameters,omitempty"`
+	// Condition is an optional expression, the run is only executed if it evaluates to true (see ConditionEnv)
+	Condition string `json:"condition,omitempty"`
 }
 
 type DifficultyConditionsSettings struct {
@@ -58,11 +60,16 @@ type DifficultyConditionsSettings struct {
 	PoisonRes          *int `json:"poisonRes,omitempty"`
 	AboveLowGold       bool `json:"aboveLowGold,omitempty"`
 	AboveGoldThreshold bool `json:"aboveGoldThreshold,omitempty"`
+	// Condition is an optional expression checked along with the rest of conditions (see ConditionEnv)
+	Condition string `json:"condition,omitempty"`
 }
 
 type ConfigLevelingSettings struct {
 	Level          *int                    `json:"level,omitempty"`
 	HealthSettings *HealthLevelingSettings `json:"healthSettings,omitempty"`
+	// Overrides is a partial character config applied after HealthSettings. Entries are applied in order, normal ones
+	// first, then nightmare and hell ones once that difficulty is reached, so later entries win.
+	Overrides ConfigOverrides `json:"overrides,omitempty"`
 }
 
 type HealthLevelingSettings struct {
@@ -248,6 +255,10 @@ func (ls LevelingSequence) CheckSequenceRequirements(run Run, sequenceSettings S
 		return false, nil
 	}
 
+	if !ls.checkCondition(sequenceSettings.Run, sequenceSettings.Condition) {
+		return false, nil
+	}
+
 	return true, nil
 }
 
@@ -298,6 +309,16 @@ func (ls *LevelingSequence) LoadSettings() error {
 		return err
 	}
 
+	if err = ValidateConditions(&sequenceSettings); err != nil {
+		ls.ctx.Logger.Error("invalid sequence conditions ", "file name", fileName)
+		return err
+	}
+
+	if err = ValidateConfigOverrides(&sequenceSettings); err != nil {
+		ls.ctx.Logger.Error("invalid sequence config overrides ", "file name", fileName)
+		return err
+	}
+
 	ls.Settings = &sequenceSettings
 	return nil
 }
@@ -314,24 +335,47 @@ func (ls LevelingSequence) AdjustHealthConfig() error {
 
 	playerLevel := lvl.Value
 
+	// The settings are applied every game, the config is only saved when they changed something
+	changes, err := ls.applyLevelingConfig(playerLevel)
+	if err != nil {
+		return fmt.Errorf("adjust health config: %w", err)
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	for _, change := range changes {
+		ls.ctx.Logger.Info("Leveling config setting changed", "setting", change.Key, "from", change.Old, "to", change.New, "level", playerLevel)
+	}
+
+	return config.SaveSupervisorConfig(ls.ctx.CharacterCfg.ConfigFolderName, ls.ctx.CharacterCfg)
+}
+
+// applyLevelingConfig applies the config settings of the current and previous difficulties, returns the settings they
+// changed in the character config
+func (ls LevelingSequence) applyLevelingConfig(playerLevel int) ([]ConfigChange, error) {
+	before, err := flattenConfig(ls.ctx.CharacterCfg)
+	if err != nil {
+		return nil, err
+	}
+
 	currentDifficulty := ls.ctx.CharacterCfg.Game.Difficulty
-	settingsApplied := ls.ApplyConfigSettings(ls.Settings.Normal.ConfigSettings, playerLevel)
+	ls.ApplyConfigSettings(ls.Settings.Normal.ConfigSettings, playerLevel)
 
 	if currentDifficulty == difficulty.Nightmare || currentDifficulty == difficulty.Hell {
-		settingsApplied = ls.ApplyConfigSettings(ls.Settings.Nightmare.ConfigSettings, playerLevel) || settingsApplied
+		ls.ApplyConfigSettings(ls.Settings.Nightmare.ConfigSettings, playerLevel)
 	}
 
 	if currentDifficulty == difficulty.Hell {
-		settingsApplied = ls.ApplyConfigSettings(ls.Settings.Hell.ConfigSettings, playerLevel) || settingsApplied
+		ls.ApplyConfigSettings(ls.Settings.Hell.ConfigSettings, playerLevel)
 	}
 
-	if settingsApplied {
-		if err := config.SaveSupervisorConfig(ls.ctx.CharacterCfg.ConfigFolderName, ls.ctx.CharacterCfg); err != nil {
-			return err
-		}
+	after, err := flattenConfig(ls.ctx.CharacterCfg)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	return configChanges(before, after), nil
 }
 
 func (ls LevelingSequence) ApplyConfigSettings(configSettings []ConfigLevelingSettings, playerLevel int) bool {
@@ -355,6 +399,13 @@ func (ls LevelingSequence) ApplyConfigSettings(configSettings []ConfigLevelingSe
 				ls.ApplyHealthSetting(*configSetting.HealthSettings)
 				settingsApplied = true
 			}
+			if len(configSetting.Overrides) > 0 {
+				if err := configSetting.Overrides.applyTo(ls.ctx.CharacterCfg); err != nil {
+					ls.ctx.Logger.Error("failed to apply leveling config overrides", "level", configSetting.Level, "error", err)
+					continue
+				}
+				settingsApplied = true
+			}
 		}
 	}
 
@@ -546,17 +597,7 @@ func (ls LevelingSequence) CheckDifficultyConditions(conditions *DifficultyCondi
 		return true
 	}
 
-	resPenalty := 0
-	switch targetDifficulty {
-	case difficulty.Nightmare:
-		resPenalty = 40
-		//TODO Classic
-		//resPenalty = 20
-	case difficulty.Hell:
-		resPenalty = 100
-		//TODO Classic
-		//resPenalty = 50
-	}
+	resPenalty := resistPenalty(targetDifficulty)
 
 	if !ls.CheckResCondition(stat.LightningResist, conditions.LightRes, resPenalty) {
 		return false
@@ -582,9 +623,28 @@ func (ls LevelingSequence) CheckDifficultyConditions(conditions *DifficultyCondi
 		return false
 	}
 
+	if !ls.checkCondition(string(targetDifficulty)+" difficulty conditions", conditions.Condition) {
+		return false
+	}
+
 	return true
 }
 
+func resistPenalty(targetDifficulty difficulty.Difficulty) int {
+	switch targetDifficulty {
+	case difficulty.Nightmare:
+		//TODO Classic
+		//return 20
+		return 40
+	case difficulty.Hell:
+		//TODO Classic
+		//return 50
+		return 100
+	}
+
+	return 0
+}
+
 func (ls LevelingSequence) CheckResCondition(resType stat.ID, resTarget *int, resPenalty int) bool {
 
 	if resTarget != nil {
@@ -0,0 +1,220 @@
+package run
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/d2go/pkg/data/quest"
+	"github.com/hectorgimenez/d2go/pkg/data/stat"
+	"github.com/hectorgimenez/koolo/internal/config"
+)
+
+// ConditionEnv is the environment available to the `condition` expressions of sequence entries and difficulty
+// conditions, the expression must return a boolean. Examples:
+//
+//	level >= 20 && fireRes >= 30
+//	quests.radament && !hasItem("Spirit")
+//	difficulty == "nightmare" && mercAlive && gold > 50000
+//
+// Resistances are the values without the difficulty penalty, quests contains every quest run from the sequencer
+// catalog (den, andariel, radament...) with true if it's completed in the current difficulty. Items are counted by
+// name, unique/set name and runeword name, equipped or in the inventory, stash or cube. hasItem(name), itemCount(name)
+// and completed(run) are shortcuts for the items and quests maps.
+type ConditionEnv struct {
+	Level      int                    `expr:"level"`
+	Difficulty string                 `expr:"difficulty"`
+	FireRes    int                    `expr:"fireRes"`
+	ColdRes    int                    `expr:"coldRes"`
+	LightRes   int                    `expr:"lightRes"`
+	PoisonRes  int                    `expr:"poisonRes"`
+	Gold       int                    `expr:"gold"`
+	Quests     map[string]bool        `expr:"quests"`
+	MercAlive  bool                   `expr:"mercAlive"`
+	Items      map[string]int         `expr:"items"`
+	HasItem    func(name string) bool `expr:"hasItem"`
+	Completed  func(run string) bool  `expr:"completed"`
+	ItemCount  func(name string) int  `expr:"itemCount"`
+}
+
+// questByRun are the quests completed by each sequencer quest run
+var questByRun = map[config.Run]quest.Quest{
+	config.DenRun:            quest.Act1DenOfEvil,
+	config.BloodravenRun:     quest.Act1SistersBurialGrounds,
+	config.RescueCainRun:     quest.Act1TheSearchForCain,
+	config.CountessRun:       quest.Act1TheForgottenTower,
+	config.RetrieveHammerRun: quest.Act1ToolsOfTheTrade,
+	config.AndarielRun:       quest.Act1SistersToTheSlaughter,
+	config.RadamentRun:       quest.Act2RadamentsLair,
+	config.StaffRun:          quest.Act2TheHoradricStaff,
+	config.AmuletRun:         quest.Act2TaintedSun,
+	config.SummonerRun:       quest.Act2TheSummoner,
+	config.DurielRun:         quest.Act2TheSevenTombs,
+	config.JadeFigurineRun:   quest.Act3TheGoldenBird,
+	config.KhalimsEyeRun:     quest.Act3KhalimsWill,
+	config.KhalimsBrainRun:   quest.Act3KhalimsWill,
+	config.KhalimsHeartRun:   quest.Act3KhalimsWill,
+	config.GidbinnRun:        quest.Act3BladeOfTheOldReligion,
+	config.LamEsenRun:        quest.Act3LamEsensTome,
+	config.TravincalRun:      quest.Act3TheBlackenedTemple,
+	config.MephistoRun:       quest.Act3TheGuardian,
+	config.IzualRun:          quest.Act4TheFallenAngel,
+	config.HellforgeRun:      quest.Act4HellForge,
+	config.DiabloRun:         quest.Act4TerrorsEnd,
+	config.ShenkRun:          quest.Act5SiegeOnHarrogath,
+	config.RescueBarbsRun:    quest.Act5RescueOnMountArreat,
+	config.AnyaRun:           quest.Act5PrisonOfIce,
+	config.NihlathakRun:      quest.Act5BetrayalOfHarrogath,
+	config.AncientsRun:       quest.Act5RiteOfPassage,
+	config.BaalRun:           quest.Act5EveOfDestruction,
+}
+
+var conditionPrograms sync.Map
+
+// compileCondition compiles the expression once, programs are cached since conditions are checked on every game
+func compileCondition(condition string) (*vm.Program, error) {
+	if program, found := conditionPrograms.Load(condition); found {
+		return program.(*vm.Program), nil
+	}
+
+	program, err := expr.Compile(condition, expr.Env(ConditionEnv{}), expr.AsBool())
+	if err != nil {
+		return nil, err
+	}
+	conditionPrograms.Store(condition, program)
+
+	return program, nil
+}
+
+// EvaluateCondition returns true if the condition is empty or the expression evaluates to true
+func EvaluateCondition(condition string, env ConditionEnv) (bool, error) {
+	if strings.TrimSpace(condition) == "" {
+		return true, nil
+	}
+
+	program, err := compileCondition(condition)
+	if err != nil {
+		return false, err
+	}
+
+	if env.HasItem == nil {
+		env.HasItem = func(name string) bool { return env.Items[name] > 0 }
+	}
+	if env.ItemCount == nil {
+		env.ItemCount = func(name string) int { return env.Items[name] }
+	}
+	if env.Completed == nil {
+		env.Completed = func(run string) bool { return env.Quests[run] }
+	}
+
+	result, err := expr.Run(program, env)
+	if err != nil {
+		return false, err
+	}
+
+	return result.(bool), nil
+}
+
+// ValidateConditions compiles every condition in the sequence, the error contains all the invalid ones
+func ValidateConditions(settings *LevelingSequenceSettings) error {
+	var errs []error
+	check := func(location, condition string) {
+		if strings.TrimSpace(condition) == "" {
+			return
+		}
+		if _, err := compileCondition(condition); err != nil {
+			errs = append(errs, fmt.Errorf("%s: invalid condition %q: %w", location, condition, err))
+		}
+	}
+
+	for _, d := range []struct {
+		name     string
+		settings *DifficultyLevelingSettings
+	}{{"normal", &settings.Normal}, {"nightmare", &settings.Nightmare}, {"hell", &settings.Hell}} {
+		sections := map[string][]SequenceSettings{
+			"beforeQuests": d.settings.BeforeQuests,
+			"quests":       d.settings.Quests,
+			"afterQuests":  d.settings.AfterQuests,
+		}
+		for _, section := range []string{"beforeQuests", "quests", "afterQuests"} {
+			for i, sequence := range sections[section] {
+				check(fmt.Sprintf("%s.%s[%d] (%s)", d.name, section, i, sequence.Run), sequence.Condition)
+			}
+		}
+		if c := d.settings.NextDifficultyConditions; c != nil {
+			check(d.name+".nextDifficultyConditions", c.Condition)
+		}
+		if c := d.settings.StayDifficultyConditions; c != nil {
+			check(d.name+".stayDifficultyConditions", c.Condition)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// conditionEnv builds the environment from the current game data
+func (ls LevelingSequence) conditionEnv() ConditionEnv {
+	d := ls.ctx.Data
+	env := newConditionEnv(func(id stat.ID) int {
+		s, _ := d.PlayerUnit.FindStat(id, 0)
+		return s.Value
+	}, d.Inventory.AllItems)
+
+	env.Difficulty = string(ls.ctx.CharacterCfg.Game.Difficulty)
+	env.Gold = d.PlayerUnit.TotalPlayerGold()
+	env.MercAlive = d.MercHPPercent() > 0
+	for run, q := range questByRun {
+		env.Quests[string(run)] = d.Quests[q].Completed()
+	}
+
+	return env
+}
+
+// newConditionEnv fills the level, the resistances and the items of the environment. The resistances are the player
+// stats as they are, the difficulty penalty is never applied.
+func newConditionEnv(statValue func(id stat.ID) int, items []data.Item) ConditionEnv {
+	env := ConditionEnv{
+		Level:     statValue(stat.Level),
+		FireRes:   statValue(stat.FireResist),
+		ColdRes:   statValue(stat.ColdResist),
+		LightRes:  statValue(stat.LightningResist),
+		PoisonRes: statValue(stat.PoisonResist),
+		Quests:    make(map[string]bool, len(questByRun)),
+		Items:     make(map[string]int),
+	}
+
+	for _, itm := range items {
+		names := []string{string(itm.Name)}
+		if itm.IdentifiedName != "" {
+			names = append(names, itm.IdentifiedName)
+		}
+		if itm.RunewordName != "" {
+			names = append(names, string(itm.RunewordName))
+		}
+		for _, name := range slices.Compact(names) {
+			env.Items[name]++
+		}
+	}
+
+	return env
+}
+
+// checkCondition evaluates the condition using the current game data, errors are logged and the condition fails
+func (ls LevelingSequence) checkCondition(location, condition string) bool {
+	if strings.TrimSpace(condition) == "" {
+		return true
+	}
+
+	ok, err := EvaluateCondition(condition, ls.conditionEnv())
+	if err != nil {
+		ls.ctx.Logger.Error("failed to evaluate sequence condition", "location", location, "condition", condition, "error", err)
+		return false
+	}
+
+	return ok
+}
@@ -0,0 +1,146 @@
+package run
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/d2go/pkg/data/stat"
+)
+
+func TestEvaluateCondition(t *testing.T) {
+	env := ConditionEnv{
+		Level:      24,
+		Difficulty: "nightmare",
+		FireRes:    40,
+		Gold:       60000,
+		Quests:     map[string]bool{"radament": true, "andariel": true, "duriel": false},
+		MercAlive:  true,
+		Items:      map[string]int{"Ring": 2, "Spirit": 1},
+	}
+
+	tests := []struct {
+		name      string
+		condition string
+		want      bool
+		wantErr   bool
+	}{
+		{name: "empty condition", condition: "  ", want: true},
+		{name: "stats", condition: "level >= 20 && fireRes >= 30", want: true},
+		{name: "difficulty and gold", condition: `difficulty == "nightmare" && mercAlive && gold > 50000`, want: true},
+		{name: "quests map", condition: "quests.radament && !quests.duriel", want: true},
+		{name: "completed", condition: `completed("andariel") && !completed("duriel")`, want: true},
+		{name: "unknown quest is not completed", condition: `completed("baal")`, want: false},
+		{name: "hasItem", condition: `hasItem("Spirit") && !hasItem("Insight")`, want: true},
+		{name: "itemCount", condition: `itemCount("Ring") == 2 && itemCount("Amulet") == 0`, want: true},
+		{name: "false condition", condition: "level > 30", want: false},
+		{name: "non boolean result", condition: "level + 1", wantErr: true},
+		{name: "parse error", condition: "level >=", wantErr: true},
+		{name: "unknown variable", condition: "strength > 10", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EvaluateCondition(tt.condition, env)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateConditions(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings LevelingSequenceSettings
+		want     []string
+	}{
+		{
+			name: "valid conditions",
+			settings: LevelingSequenceSettings{
+				Normal: DifficultyLevelingSettings{
+					Quests:                   []SequenceSettings{{Run: "den", Condition: "level < 10"}, {Run: "andariel"}},
+					NextDifficultyConditions: &DifficultyConditionsSettings{Condition: `completed("baal")`},
+				},
+			},
+		},
+		{
+			name: "parse errors",
+			settings: LevelingSequenceSettings{
+				Normal:    DifficultyLevelingSettings{BeforeQuests: []SequenceSettings{{Run: "tristram", Condition: "level >="}}},
+				Nightmare: DifficultyLevelingSettings{StayDifficultyConditions: &DifficultyConditionsSettings{Condition: "fireRes >"}},
+			},
+			want: []string{"normal.beforeQuests[0] (tristram)", "nightmare.stayDifficultyConditions"},
+		},
+		{
+			name: "non boolean results",
+			settings: LevelingSequenceSettings{
+				Hell: DifficultyLevelingSettings{
+					AfterQuests:              []SequenceSettings{{Run: "pit", Condition: "gold"}},
+					NextDifficultyConditions: &DifficultyConditionsSettings{Condition: `"hell"`},
+				},
+			},
+			want: []string{"hell.afterQuests[0] (pit)", "hell.nextDifficultyConditions"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateConditions(&tt.settings)
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			for _, location := range tt.want {
+				if !strings.Contains(err.Error(), location) {
+					t.Errorf("error %q doesn't report %s", err, location)
+				}
+			}
+		})
+	}
+}
+
+func TestNewConditionEnv(t *testing.T) {
+	stats := map[stat.ID]int{
+		stat.Level:           30,
+		stat.FireResist:      75,
+		stat.ColdResist:      20,
+		stat.LightningResist: -10,
+		stat.PoisonResist:    5,
+	}
+	items := []data.Item{
+		{Name: "Ring", IdentifiedName: "Ring"},
+		{Name: "Ring", IdentifiedName: "Stone of Jordan"},
+		{Name: "Broad Sword", RunewordName: "Spirit"},
+	}
+
+	env := newConditionEnv(func(id stat.ID) int { return stats[id] }, items)
+
+	// The resistances are the stats as they are, without the -40/-100 penalty of nightmare and hell
+	if env.Level != 30 || env.FireRes != 75 || env.ColdRes != 20 || env.LightRes != -10 || env.PoisonRes != 5 {
+		t.Errorf("unexpected stats: %+v", env)
+	}
+
+	wantItems := map[string]int{"Ring": 2, "Stone of Jordan": 1, "Broad Sword": 1, "Spirit": 1}
+	if len(env.Items) != len(wantItems) {
+		t.Errorf("got items %v, want %v", env.Items, wantItems)
+	}
+	for name, count := range wantItems {
+		if env.Items[name] != count {
+			t.Errorf("got %d %s, want %d", env.Items[name], name, count)
+		}
+	}
+
+	ok, err := EvaluateCondition(`fireRes >= 75 && itemCount("Ring") == 2 && hasItem("Spirit")`, env)
+	if err != nil || !ok {
+		t.Errorf("got %v (%v), want true", ok, err)
+	}
+}
@@ -0,0 +1,351 @@
+package run
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/hectorgimenez/d2go/pkg/data/difficulty"
+	"github.com/hectorgimenez/koolo/internal/action"
+	"github.com/hectorgimenez/koolo/internal/config"
+)
+
+type DryRunOutcome string
+
+const (
+	DryRunExecute DryRunOutcome = "run"
+	DryRunSkip    DryRunOutcome = "skip"
+	// DryRunStop stops the current section (before quests, quests or after quests), next section is still evaluated
+	DryRunStop DryRunOutcome = "stop"
+	// DryRunEnd ends the sequence for this game, nothing else is evaluated
+	DryRunEnd   DryRunOutcome = "end"
+	DryRunError DryRunOutcome = "error"
+)
+
+// DryRunState is a hypothetical character state. Resistances are the values without difficulty penalty (as shown on
+// normal), CompletedQuests are the quest runs already completed in the current difficulty.
+type DryRunState struct {
+	Level           int                   `json:"level"`
+	Difficulty      difficulty.Difficulty `json:"difficulty"`
+	FireRes         int                   `json:"fireRes"`
+	ColdRes         int                   `json:"coldRes"`
+	LightRes        int                   `json:"lightRes"`
+	PoisonRes       int                   `json:"poisonRes"`
+	Gold            int                   `json:"gold"`
+	CompletedQuests []config.Run          `json:"completedQuests"`
+	MercAlive       bool                  `json:"mercAlive"`
+	// Items owned by the character, used by conditions
+	Items []string `json:"items,omitempty"`
+}
+
+type DryRunStep struct {
+	Section string        `json:"section"`
+	Index   int           `json:"index"`
+	Run     string        `json:"run"`
+	Outcome DryRunOutcome `json:"outcome"`
+	Reason  string        `json:"reason,omitempty"`
+}
+
+type DryRunResult struct {
+	Difficulty        difficulty.Difficulty `json:"difficulty"`
+	DifficultyChanged bool                  `json:"difficultyChanged"`
+	NewDifficulty     difficulty.Difficulty `json:"newDifficulty,omitempty"`
+	DifficultyReason  string                `json:"difficultyReason,omitempty"`
+	Steps             []DryRunStep          `json:"steps"`
+	Error             string                `json:"error,omitempty"`
+}
+
+type dryRun struct {
+	settings *LevelingSequenceSettings
+	state    DryRunState
+	doneRuns []string
+	result   DryRunResult
+}
+
+// DryRun evaluates the sequence for one game with the given character state, following the same decisions as
+// LevelingSequence.Run without playing. Run specific conditions (CheckConditions) need the game data, they are
+// approximated: quest runs are skipped if the quest is already completed, and a quest boss can't be farmed before its
+// quest is completed. Every executed run is assumed to succeed.
+func DryRun(settings LevelingSequenceSettings, state DryRunState) DryRunResult {
+	if state.Difficulty == "" {
+		state.Difficulty = difficulty.Normal
+	}
+	state.Level = max(state.Level, 1)
+
+	d := &dryRun{
+		settings: &settings,
+		state:    state,
+		result:   DryRunResult{Difficulty: state.Difficulty, Steps: make([]DryRunStep, 0)},
+	}
+
+	// Same as Run, when the difficulty changes the game is restarted with the new one
+	if newDifficulty, reason, changed := d.adjustDifficulty(); changed {
+		d.result.DifficultyChanged = true
+		d.result.NewDifficulty = newDifficulty
+		d.result.DifficultyReason = reason
+		return d.result
+	}
+
+	difficultySettings := d.difficultySettings(d.state.Difficulty)
+	if difficultySettings == nil {
+		d.result.Error = fmt.Sprintf("couldn't find leveling settings for difficulty %s", d.state.Difficulty)
+		return d.result
+	}
+
+	sections := []struct {
+		name      string
+		sequences []SequenceSettings
+		farming   bool
+	}{
+		{"beforeQuests", difficultySettings.BeforeQuests, true},
+		{"quests", difficultySettings.Quests, false},
+		{"afterQuests", difficultySettings.AfterQuests, true},
+	}
+	for _, section := range sections {
+		if !d.runSequences(section.name, section.sequences, section.farming) {
+			break
+		}
+	}
+
+	return d.result
+}
+
+// runSequences mirrors LevelingSequence.RunSequences, returns false if the next sections shouldn't be evaluated
+func (d *dryRun) runSequences(section string, sequences []SequenceSettings, farmSequence bool) bool {
+	for i, sequenceSettings := range sequences {
+		step := func(outcome DryRunOutcome, format string, args ...any) {
+			d.result.Steps = append(d.result.Steps, DryRunStep{
+				Section: section,
+				Index:   i,
+				Run:     sequenceSettings.Run,
+				Outcome: outcome,
+				Reason:  fmt.Sprintf(format, args...),
+			})
+		}
+
+		if !slices.Contains(config.SequencerRuns, config.Run(sequenceSettings.Run)) {
+			step(DryRunError, "couldn't build run %s", sequenceSettings.Run)
+			d.result.Error = fmt.Sprintf("error while running %s: unknown run %s", section, sequenceSettings.Run)
+			return false
+		}
+
+		if ok, reason := d.checkSequenceRequirements(sequenceSettings); !ok {
+			if sequenceSettings.StopIfCheckFails {
+				step(DryRunStop, "%s, stopIfCheckFails is set", reason)
+				return true
+			}
+			step(DryRunSkip, "%s", reason)
+			continue
+		}
+
+		if skip, reason := d.checkConditions(sequenceSettings, farmSequence); skip {
+			step(DryRunSkip, "%s", reason)
+			continue
+		}
+
+		if slices.Contains(d.doneRuns, sequenceSettings.Run) {
+			step(DryRunSkip, "already done in this game")
+			continue
+		}
+
+		d.doneRuns = append(d.doneRuns, sequenceSettings.Run)
+		if !farmSequence && d.isQuest(sequenceSettings.Run) {
+			d.state.CompletedQuests = append(d.state.CompletedQuests, config.Run(sequenceSettings.Run))
+		}
+
+		if sequenceSettings.ExitGame {
+			step(DryRunEnd, "exitGame is set, the game ends after this run")
+			return false
+		}
+		step(DryRunExecute, "")
+	}
+
+	return true
+}
+
+// checkSequenceRequirements mirrors LevelingSequence.CheckSequenceRequirements
+func (d *dryRun) checkSequenceRequirements(sequenceSettings SequenceSettings) (bool, string) {
+	if sequenceSettings.MinLevel != nil && d.state.Level < *sequenceSettings.MinLevel {
+		return false, fmt.Sprintf("level %d is below minLevel %d", d.state.Level, *sequenceSettings.MinLevel)
+	}
+
+	if sequenceSettings.MaxLevel != nil && d.state.Level >= *sequenceSettings.MaxLevel {
+		return false, fmt.Sprintf("level %d reached maxLevel %d", d.state.Level, *sequenceSettings.MaxLevel)
+	}
+
+	if sequenceSettings.LowGoldRun && !d.isLowGold() {
+		return false, fmt.Sprintf("lowGoldRun is set and gold is above %d", action.LowGoldThreshold(d.state.Level))
+	}
+
+	if ok, reason := d.checkCondition(sequenceSettings.Condition); !ok {
+		return false, reason
+	}
+
+	return true, ""
+}
+
+// checkConditions approximates the run CheckConditions using the completed quests
+func (d *dryRun) checkConditions(sequenceSettings SequenceSettings, farmSequence bool) (bool, string) {
+	if !d.isQuest(sequenceSettings.Run) {
+		return false, ""
+	}
+
+	completed := slices.Contains(d.state.CompletedQuests, config.Run(sequenceSettings.Run))
+	if !farmSequence && completed {
+		return true, "quest already completed"
+	}
+	if farmSequence && !completed {
+		return true, "quest not completed yet, it can't be farmed"
+	}
+
+	return false, ""
+}
+
+// adjustDifficulty mirrors LevelingSequence.AdjustDifficulty
+func (d *dryRun) adjustDifficulty() (difficulty.Difficulty, string, bool) {
+	current := d.state.Difficulty
+	difficultySettings := d.difficultySettings(current)
+	if difficultySettings == nil {
+		return current, "", false
+	}
+
+	for _, diff := range []difficulty.Difficulty{difficulty.Normal, difficulty.Nightmare} {
+		diffSettings := d.difficultySettings(diff)
+		if ok, reason := d.checkDifficultyConditions(diffSettings.NextDifficultyConditions, nextDifficulty(diff), true); !ok {
+			if diff != current {
+				return diff, fmt.Sprintf("%s nextDifficultyConditions not met: %s", diff, reason), true
+			}
+			break
+		}
+	}
+
+	if difficultySettings.StayDifficultyConditions != nil {
+		if ok, reason := d.checkDifficultyConditions(difficultySettings.StayDifficultyConditions, current, false); !ok {
+			previous := difficulty.Normal
+			if current == difficulty.Hell {
+				previous = difficulty.Nightmare
+			}
+			if previous != current {
+				return previous, fmt.Sprintf("stayDifficultyConditions not met: %s", reason), true
+			}
+		}
+	}
+
+	// Eve of Destruction
+	if difficultySettings.NextDifficultyConditions != nil && slices.Contains(d.state.CompletedQuests, config.BaalRun) {
+		next := nextDifficulty(current)
+		if next != current {
+			if ok, _ := d.checkDifficultyConditions(difficultySettings.NextDifficultyConditions, next, false); ok {
+				return next, "baal is completed and nextDifficultyConditions are met", true
+			}
+		}
+	}
+
+	return current, "", false
+}
+
+// checkDifficultyConditions mirrors LevelingSequence.CheckDifficultyConditions, returning the first failed condition
+func (d *dryRun) checkDifficultyConditions(conditions *DifficultyConditionsSettings, targetDifficulty difficulty.Difficulty, levelOnly bool) (bool, string) {
+	if conditions == nil {
+		return true, ""
+	}
+
+	if conditions.Level != nil && d.state.Level < *conditions.Level {
+		return false, fmt.Sprintf("level %d is below %d", d.state.Level, *conditions.Level)
+	}
+	if levelOnly {
+		return true, ""
+	}
+
+	penalty := resistPenalty(targetDifficulty)
+	resists := []struct {
+		name   string
+		value  int
+		target *int
+	}{
+		{"lightning resist", d.state.LightRes, conditions.LightRes},
+		{"cold resist", d.state.ColdRes, conditions.ColdRes},
+		{"fire resist", d.state.FireRes, conditions.FireRes},
+		{"poison resist", d.state.PoisonRes, conditions.PoisonRes},
+	}
+	for _, res := range resists {
+		if res.target != nil && res.value < *res.target-penalty {
+			return false, fmt.Sprintf("%s %d is below %d on %s", res.name, res.value-penalty, *res.target, targetDifficulty)
+		}
+	}
+
+	if conditions.AboveLowGold && d.isLowGold() {
+		return false, fmt.Sprintf("gold is below %d", action.LowGoldThreshold(d.state.Level))
+	}
+
+	if conditions.AboveGoldThreshold && d.state.Gold < action.GoldPickupThreshold(d.state.Level) {
+		return false, fmt.Sprintf("gold is below %d", action.GoldPickupThreshold(d.state.Level))
+	}
+
+	if ok, reason := d.checkCondition(conditions.Condition); !ok {
+		return false, reason
+	}
+
+	return true, ""
+}
+
+func (d *dryRun) checkCondition(condition string) (bool, string) {
+	env := ConditionEnv{
+		Level:      d.state.Level,
+		Difficulty: string(d.state.Difficulty),
+		FireRes:    d.state.FireRes,
+		ColdRes:    d.state.ColdRes,
+		LightRes:   d.state.LightRes,
+		PoisonRes:  d.state.PoisonRes,
+		Gold:       d.state.Gold,
+		Quests:     make(map[string]bool, len(questByRun)),
+		MercAlive:  d.state.MercAlive,
+		Items:      make(map[string]int, len(d.state.Items)),
+	}
+	for run := range questByRun {
+		env.Quests[string(run)] = slices.Contains(d.state.CompletedQuests, run)
+	}
+	for _, name := range d.state.Items {
+		env.Items[name]++
+	}
+
+	ok, err := EvaluateCondition(condition, env)
+	if err != nil {
+		return false, fmt.Sprintf("condition error: %v", err)
+	}
+	if !ok {
+		return false, fmt.Sprintf("condition %q is false", condition)
+	}
+
+	return true, ""
+}
+
+func (d *dryRun) difficultySettings(diff difficulty.Difficulty) *DifficultyLevelingSettings {
+	switch diff {
+	case difficulty.Normal:
+		return &d.settings.Normal
+	case difficulty.Nightmare:
+		return &d.settings.Nightmare
+	case difficulty.Hell:
+		return &d.settings.Hell
+	}
+
+	return nil
+}
+
+func (d *dryRun) isLowGold() bool {
+	return d.state.Gold < action.LowGoldThreshold(d.state.Level)
+}
+
+func (d *dryRun) isQuest(run string) bool {
+	return slices.ContainsFunc(config.SequencerQuests, func(q config.LevelingRunInfo) bool {
+		return string(q.Run) == run
+	})
+}
+
+func nextDifficulty(diff difficulty.Difficulty) difficulty.Difficulty {
+	if diff == difficulty.Normal {
+		return difficulty.Nightmare
+	}
+
+	return difficulty.Hell
+}
@@ -0,0 +1,154 @@
+package run
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/hectorgimenez/koolo/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigOverrides is a partial character config, using the same keys as the character config.yaml:
+//
+//	{"character": {"useTeleport": true, "clearPathDist": 15}, "backtotown": {"noMpPotions": false}}
+//
+// Objects are merged key by key, any other value (numbers, strings, lists...) replaces the current one.
+type ConfigOverrides map[string]any
+
+// UnmarshalJSON keeps integers as integers, otherwise big numbers would be encoded as 1e+06 and wouldn't fit int fields
+func (o *ConfigOverrides) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var raw map[string]any
+	if err := decoder.Decode(&raw); err != nil {
+		return err
+	}
+	*o = normalizeJSONNumbers(raw).(map[string]any)
+
+	return nil
+}
+
+func normalizeJSONNumbers(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, nested := range v {
+			v[key] = normalizeJSONNumbers(nested)
+		}
+	case []any:
+		for i, nested := range v {
+			v[i] = normalizeJSONNumbers(nested)
+		}
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	}
+
+	return value
+}
+
+// Validate checks that every key exists in the character config and the values have the right type
+func (o ConfigOverrides) Validate() error {
+	data, err := yaml.Marshal(map[string]any(o))
+	if err != nil {
+		return err
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	return decoder.Decode(&config.CharacterCfg{})
+}
+
+// applyTo merges the overrides into the character config
+func (o ConfigOverrides) applyTo(cfg *config.CharacterCfg) error {
+	if len(o) == 0 {
+		return nil
+	}
+
+	data, err := yaml.Marshal(map[string]any(o))
+	if err != nil {
+		return err
+	}
+
+	return yaml.Unmarshal(data, cfg)
+}
+
+// ConfigChange is a single setting modified by the leveling config settings, Key is the yaml path (character.useTeleport)
+type ConfigChange struct {
+	Key string
+	Old any
+	New any
+}
+
+// flattenConfig returns every setting of the character config by its yaml path
+func flattenConfig(cfg *config.CharacterCfg) (map[string]any, error) {
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var tree map[string]any
+	if err = yaml.Unmarshal(data, &tree); err != nil {
+		return nil, err
+	}
+
+	flat := make(map[string]any)
+	var walk func(prefix string, value any)
+	walk = func(prefix string, value any) {
+		nested, ok := value.(map[string]any)
+		if !ok || len(nested) == 0 {
+			flat[prefix] = value
+			return
+		}
+		for key, v := range nested {
+			walk(strings.TrimPrefix(prefix+"."+key, "."), v)
+		}
+	}
+	walk("", tree)
+
+	return flat, nil
+}
+
+// configChanges compares two flattened configs, changes are sorted by key so they are always logged in the same order
+func configChanges(before, after map[string]any) []ConfigChange {
+	changes := make([]ConfigChange, 0)
+	for key, value := range after {
+		if old, found := before[key]; !found || !reflect.DeepEqual(old, value) {
+			changes = append(changes, ConfigChange{Key: key, Old: before[key], New: value})
+		}
+	}
+	for key, old := range before {
+		if _, found := after[key]; !found {
+			changes = append(changes, ConfigChange{Key: key, Old: old})
+		}
+	}
+	slices.SortFunc(changes, func(a, b ConfigChange) int { return strings.Compare(a.Key, b.Key) })
+
+	return changes
+}
+
+// ValidateConfigOverrides checks the overrides of every config settings entry, the error contains all the invalid ones
+func ValidateConfigOverrides(settings *LevelingSequenceSettings) error {
+	var errs []error
+	for _, d := range []struct {
+		name     string
+		settings *DifficultyLevelingSettings
+	}{{"normal", &settings.Normal}, {"nightmare", &settings.Nightmare}, {"hell", &settings.Hell}} {
+		for i, configSetting := range d.settings.ConfigSettings {
+			if err := configSetting.Overrides.Validate(); err != nil {
+				errs = append(errs, fmt.Errorf("%s.configSettings[%d]: invalid overrides: %w", d.name, i, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
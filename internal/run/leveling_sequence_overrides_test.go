@@ -0,0 +1,156 @@
+package run
+
+import (
+	"encoding/json"
+	"log/slog"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/hectorgimenez/d2go/pkg/data/difficulty"
+	"github.com/hectorgimenez/koolo/internal/config"
+	"github.com/hectorgimenez/koolo/internal/context"
+)
+
+func TestConfigOverridesValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides ConfigOverrides
+		wantErr   bool
+	}{
+		{name: "empty", overrides: ConfigOverrides{}},
+		{name: "known settings", overrides: ConfigOverrides{"character": map[string]any{"useTeleport": true, "clearPathDist": 15}}},
+		{name: "list", overrides: ConfigOverrides{"game": map[string]any{"runs": []any{"pit", "cows"}}}},
+		{name: "unknown section", overrides: ConfigOverrides{"teleport": true}, wantErr: true},
+		{name: "unknown nested key", overrides: ConfigOverrides{"character": map[string]any{"teleport": true}}, wantErr: true},
+		{name: "string for a number", overrides: ConfigOverrides{"character": map[string]any{"clearPathDist": "far"}}, wantErr: true},
+		{name: "bool for a number", overrides: ConfigOverrides{"health": map[string]any{"healingPotionAt": true}}, wantErr: true},
+		{name: "value for a section", overrides: ConfigOverrides{"backtotown": 1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.overrides.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("got error %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigOverridesApplyTo(t *testing.T) {
+	var overrides ConfigOverrides
+	content := `{"character": {"useTeleport": true}, "game": {"runs": ["andariel"]}, "health": {"healingPotionAt": 1000000}}`
+	if err := json.Unmarshal([]byte(content), &overrides); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := testLevelingCfg()
+	if err := overrides.applyTo(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if !cfg.Character.UseTeleport || cfg.Character.ClearPathDist != 10 {
+		t.Errorf("nested settings not merged: useTeleport %v, clearPathDist %d", cfg.Character.UseTeleport, cfg.Character.ClearPathDist)
+	}
+	if !slices.Equal(cfg.Game.Runs, []config.Run{"andariel"}) {
+		t.Errorf("lists must be replaced, got runs %v", cfg.Game.Runs)
+	}
+	if cfg.Health.HealingPotionAt != 1000000 {
+		t.Errorf("big integers must be kept, got %d", cfg.Health.HealingPotionAt)
+	}
+	if cfg.Health.ManaPotionAt != 20 {
+		t.Errorf("settings not overridden must be kept, got manaPotionAt %d", cfg.Health.ManaPotionAt)
+	}
+}
+
+func TestConfigChanges(t *testing.T) {
+	before := map[string]any{"a": 1, "list": []any{"x"}, "removed": true, "same": "s"}
+	after := map[string]any{"a": 2, "list": []any{"x", "y"}, "added": "new", "same": "s"}
+
+	want := []ConfigChange{
+		{Key: "a", Old: 1, New: 2},
+		{Key: "added", New: "new"},
+		{Key: "list", Old: []any{"x"}, New: []any{"x", "y"}},
+		{Key: "removed", Old: true},
+	}
+	// Maps are iterated in random order, the changes must always be sorted the same way
+	for i := 0; i < 20; i++ {
+		if got := configChanges(before, after); !reflect.DeepEqual(got, want) {
+			t.Fatalf("got %+v, want %+v", got, want)
+		}
+	}
+
+	if got := configChanges(before, before); len(got) != 0 {
+		t.Errorf("expected no changes, got %+v", got)
+	}
+}
+
+func TestApplyLevelingConfig(t *testing.T) {
+	level := func(l int) *int { return &l }
+	settings := &LevelingSequenceSettings{
+		Normal: DifficultyLevelingSettings{ConfigSettings: []ConfigLevelingSettings{
+			{HealthSettings: &HealthLevelingSettings{HealingPotionAt: level(60)}},
+			{Level: level(10), Overrides: ConfigOverrides{"character": map[string]any{"useTeleport": true}}},
+		}},
+		Nightmare: DifficultyLevelingSettings{ConfigSettings: []ConfigLevelingSettings{
+			{Overrides: ConfigOverrides{"health": map[string]any{"healingPotionAt": 70}}},
+		}},
+	}
+
+	tests := []struct {
+		name       string
+		difficulty difficulty.Difficulty
+		level      int
+		want       []ConfigChange
+	}{
+		{
+			name:       "below the level of the second entry",
+			difficulty: difficulty.Normal,
+			level:      5,
+			want:       []ConfigChange{{Key: "health.healingPotionAt", Old: 40, New: 60}},
+		},
+		{
+			name:       "later difficulties win",
+			difficulty: difficulty.Nightmare,
+			level:      12,
+			want: []ConfigChange{
+				{Key: "character.useTeleport", Old: false, New: true},
+				{Key: "health.healingPotionAt", Old: 40, New: 70},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testLevelingCfg()
+			cfg.Game.Difficulty = tt.difficulty
+			ls := LevelingSequence{
+				ctx:      &context.Status{Context: &context.Context{CharacterCfg: cfg, Logger: slog.Default()}},
+				Settings: settings,
+			}
+
+			changes, err := ls.applyLevelingConfig(tt.level)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(changes, tt.want) {
+				t.Errorf("got %+v, want %+v", changes, tt.want)
+			}
+
+			// Applied again every game, the config is only saved when something changed
+			if changes, err = ls.applyLevelingConfig(tt.level); err != nil || len(changes) != 0 {
+				t.Errorf("expected no changes applying the same settings again, got %+v (%v)", changes, err)
+			}
+		})
+	}
+}
+
+func testLevelingCfg() *config.CharacterCfg {
+	cfg := &config.CharacterCfg{}
+	cfg.Character.ClearPathDist = 10
+	cfg.Game.Runs = []config.Run{"pit", "cows"}
+	cfg.Health.HealingPotionAt = 40
+	cfg.Health.ManaPotionAt = 20
+
+	return cfg
+}
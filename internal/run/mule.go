@@ -3,6 +3,8 @@ package run
 import (
 	"errors"
 	"fmt"
+	"path/filepath"
+	"time"
 
 	"github.com/hectorgimenez/d2go/pkg/data"
 	"github.com/hectorgimenez/d2go/pkg/data/item"
@@ -10,6 +12,7 @@ import (
 	"github.com/hectorgimenez/koolo/internal/config"
 	"github.com/hectorgimenez/koolo/internal/context"
 	"github.com/hectorgimenez/koolo/internal/game"
+	"github.com/hectorgimenez/koolo/internal/remote/droplog"
 	"github.com/hectorgimenez/koolo/internal/ui"
 	"github.com/hectorgimenez/koolo/internal/utils"
 )
@@ -170,6 +173,9 @@ func (m Mule) Run(parameters *RunParameters) error {
 		}
 	}
 
+	ctx.RefreshGameData()
+	saveStashSnapshot(ctx)
+
 	ctx.Logger.Info("Preparing to switch character",
 		"from", ctx.Name,
 		"to", ctx.CurrentGame.SwitchToCharacter)
@@ -186,6 +192,23 @@ func (m Mule) Run(parameters *RunParameters) error {
 	return nil
 }
 
+// saveStashSnapshot saves the mule items, so they can be suggested as upgrades for the farming character while the
+// mule is not running
+func saveStashSnapshot(ctx *context.Status) {
+	items := make([]data.Item, 0)
+	for _, itm := range ctx.Data.Inventory.AllItems {
+		// The shared stash is read from the farming character
+		if source, ok := action.AdvisorSource(itm); ok && source != action.UpgradeSourceSharedStash {
+			items = append(items, itm)
+		}
+	}
+
+	snapshot := droplog.StashSnapshot{Time: time.Now(), Supervisor: ctx.Name, Items: items}
+	if err := droplog.WriteStash(filepath.Join(config.LogDirectory(), "droplogs"), snapshot); err != nil {
+		ctx.Logger.Warn("Failed to save the mule stash", "error", err)
+	}
+}
+
 // findStashSpace finds the top-left grid coordinates for a free spot in the personal stash.
 func findStashSpace(ctx *context.Status, itm data.Item) (data.Position, bool) {
 	stash := ctx.Data.Inventory.ByLocation(item.LocationStash)
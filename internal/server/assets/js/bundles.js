@@ -0,0 +1,138 @@
+const exportProfile = document.getElementById('export-profile');
+const exportBtn = document.getElementById('export-btn');
+const importFile = document.getElementById('import-file');
+const previewBtn = document.getElementById('preview-btn');
+const importBtn = document.getElementById('import-btn');
+const importName = document.getElementById('import-name');
+const overwriteBuild = document.getElementById('import-overwrite-build');
+const statusElement = document.getElementById('import-status');
+const previewElement = document.getElementById('import-preview');
+const manifestElement = document.getElementById('preview-manifest');
+const errorsElement = document.getElementById('preview-errors');
+const warningsElement = document.getElementById('preview-warnings');
+const filesElement = document.getElementById('preview-files');
+
+const urlParams = new URLSearchParams(window.location.search);
+const characterName = urlParams.get('characterName');
+if (characterName) {
+    exportProfile.value = characterName;
+}
+
+const ACTION_LABELS = {
+    create: 'Create',
+    reuse: 'Already installed, reused',
+    rename: 'Renamed, a different file with the same name exists',
+    keep: 'Skipped, the installed build is kept',
+    overwrite: 'Overwrites the installed build',
+};
+
+function createElement(tag, className, text) {
+    const element = document.createElement(tag);
+    if (className) {
+        element.className = className;
+    }
+    if (text !== undefined) {
+        element.textContent = text;
+    }
+    return element;
+}
+
+function fillList(element, items) {
+    element.innerHTML = '';
+    items.forEach((item) => element.appendChild(createElement('li', '', item)));
+}
+
+function bundleForm() {
+    const form = new FormData();
+    form.append('bundle', importFile.files[0]);
+    form.append('name', importName.value.trim());
+    form.append('overwriteBuild', overwriteBuild.checked ? 'true' : 'false');
+    return form;
+}
+
+function postBundle(url) {
+    return fetch(url, { method: 'POST', body: bundleForm() }).then((response) => {
+        if (!response.ok) {
+            return response.text().then((text) => {
+                throw new Error(text);
+            });
+        }
+        return response.json();
+    });
+}
+
+function renderPreview(preview) {
+    previewElement.style.display = '';
+    const manifest = preview.manifest;
+    manifestElement.textContent = manifest.profile
+        ? `${manifest.profile} (${manifest.class}), exported with koolo ${manifest.kooloVersion} on ${new Date(manifest.createdAt).toLocaleString()}`
+        : '';
+
+    if (preview.nameTaken && importName.value.trim() !== preview.suggestedName) {
+        preview.errors.push(`Suggested free name: ${preview.suggestedName}`);
+    }
+    fillList(errorsElement, preview.errors);
+    fillList(warningsElement, preview.warnings);
+    if (!importName.value.trim()) {
+        importName.value = preview.nameTaken ? preview.suggestedName : preview.name;
+    }
+
+    filesElement.innerHTML = '';
+    preview.files.forEach((file) => {
+        const row = document.createElement('tr');
+        row.append(
+            createElement('td', '', file.path),
+            createElement('td', `action-${file.action}`, ACTION_LABELS[file.action] || file.action),
+            createElement('td', '', file.target),
+        );
+        filesElement.appendChild(row);
+    });
+
+    importBtn.disabled = preview.errors.length > 0;
+}
+
+function preview() {
+    if (!importFile.files.length) {
+        statusElement.textContent = 'Select a bundle file first';
+        return;
+    }
+    statusElement.textContent = 'Checking bundle...';
+    postBundle('/api/bundles/preview')
+        .then((result) => {
+            statusElement.textContent = '';
+            renderPreview(result);
+        })
+        .catch((error) => {
+            statusElement.textContent = `Error: ${error.message}`;
+        });
+}
+
+exportBtn.addEventListener('click', () => {
+    if (!exportProfile.value) {
+        return;
+    }
+    window.location.href = `/api/bundles/export?supervisor=${encodeURIComponent(exportProfile.value)}`;
+});
+
+importFile.addEventListener('change', () => {
+    importName.value = '';
+    previewElement.style.display = 'none';
+    preview();
+});
+previewBtn.addEventListener('click', preview);
+importName.addEventListener('change', preview);
+overwriteBuild.addEventListener('change', preview);
+
+importBtn.addEventListener('click', () => {
+    importBtn.disabled = true;
+    statusElement.textContent = 'Importing...';
+    postBundle('/api/bundles/import')
+        .then((result) => {
+            previewElement.style.display = 'none';
+            statusElement.textContent = `Profile ${result.profile} imported, ${result.files.length} files`;
+        })
+        .catch((error) => {
+            importBtn.disabled = false;
+            statusElement.textContent = `Error: ${error.message}`;
+        });
+});
@@ -0,0 +1,196 @@
+const artifactSelect = document.getElementById('artifact-select');
+const refreshBtn = document.getElementById('refresh-btn');
+const statusElement = document.getElementById('history-status');
+const versionsElement = document.getElementById('versions');
+const diffTitle = document.getElementById('diff-title');
+const diffElement = document.getElementById('diff');
+
+const urlParams = new URLSearchParams(window.location.search);
+
+// Unchanged lines shown around every change, the rest are collapsed
+const DIFF_CONTEXT = 3;
+
+const SOURCE_LABELS = {
+    ui: 'Web UI',
+    api: 'API',
+    discord: 'Discord',
+    bot: 'Bot',
+    initial: 'Before first tracked change',
+};
+
+function createElement(tag, className, text) {
+    const element = document.createElement(tag);
+    if (className) {
+        element.className = className;
+    }
+    if (text !== undefined) {
+        element.textContent = text;
+    }
+    return element;
+}
+
+function fetchJSON(url, options) {
+    return fetch(url, options).then((response) => {
+        if (!response.ok) {
+            return response.text().then((text) => {
+                throw new Error(text);
+            });
+        }
+        return response.json();
+    });
+}
+
+function loadArtifacts() {
+    const selected = artifactSelect.value || urlParams.get('artifact');
+    return fetchJSON('/api/config-history').then((result) => {
+        artifactSelect.innerHTML = '';
+        if (result.artifacts.length === 0) {
+            statusElement.textContent = 'No changes recorded yet, files are added to the history the first time they are saved';
+            return;
+        }
+        result.artifacts.forEach((artifact) => {
+            artifactSelect.appendChild(createElement('option', '', artifact));
+        });
+        if (selected && result.artifacts.includes(selected)) {
+            artifactSelect.value = selected;
+        }
+        return loadVersions();
+    });
+}
+
+function loadVersions() {
+    const artifact = artifactSelect.value;
+    if (!artifact) {
+        return Promise.resolve();
+    }
+    statusElement.textContent = '';
+    diffTitle.textContent = '';
+    diffElement.innerHTML = '';
+
+    return fetchJSON(`/api/config-history?artifact=${encodeURIComponent(artifact)}`).then((result) => {
+        versionsElement.innerHTML = '';
+        result.versions.forEach((version, index) => {
+            const previous = result.versions[index + 1];
+            const row = document.createElement('tr');
+            row.dataset.id = version.id;
+            row.append(
+                createElement('td', '', `#${version.id}${index === 0 ? ' (latest)' : ''}`),
+                createElement('td', '', new Date(version.timestamp).toLocaleString()),
+                createElement('td', '', SOURCE_LABELS[version.source] || version.source),
+                createElement('td', '', version.note || ''),
+                createElement('td', '', `${version.size} B`),
+            );
+
+            const actions = document.createElement('td');
+            if (previous) {
+                const changesBtn = createElement('button', '', 'Changes');
+                changesBtn.title = `Compare with version #${previous.id}`;
+                changesBtn.addEventListener('click', () => showDiff(row, previous.id, version.id));
+                actions.appendChild(changesBtn);
+            }
+            const currentBtn = createElement('button', '', 'Compare with current');
+            currentBtn.addEventListener('click', () => showDiff(row, version.id));
+            actions.appendChild(currentBtn);
+            if (index > 0) {
+                const rollbackBtn = createElement('button', '', 'Roll back');
+                rollbackBtn.addEventListener('click', () => rollback(version));
+                actions.appendChild(rollbackBtn);
+            }
+            row.appendChild(actions);
+            versionsElement.appendChild(row);
+        });
+    });
+}
+
+function showDiff(row, from, to) {
+    versionsElement.querySelectorAll('tr').forEach((r) => r.classList.remove('selected'));
+    row.classList.add('selected');
+
+    const query = to ? `from=${from}&to=${to}` : `from=${from}`;
+    fetchJSON(`/api/config-history/diff?${query}`)
+        .then((result) => {
+            diffTitle.textContent = to
+                ? `Changes from version #${from} to #${to}`
+                : `Changes from version #${from} to the current file`;
+            renderDiff(result.lines);
+        })
+        .catch((error) => {
+            statusElement.textContent = `Error: ${error.message}`;
+        });
+}
+
+function renderDiff(lines) {
+    diffElement.innerHTML = '';
+    if (!lines.some((line) => line.kind !== 'equal')) {
+        diffElement.appendChild(createElement('div', 'skipped', 'No differences'));
+        return;
+    }
+
+    // Lines close to a change are shown, longer unchanged blocks are collapsed
+    const visible = lines.map(() => false);
+    lines.forEach((line, index) => {
+        if (line.kind === 'equal') {
+            return;
+        }
+        for (let i = Math.max(0, index - DIFF_CONTEXT); i <= Math.min(lines.length - 1, index + DIFF_CONTEXT); i++) {
+            visible[i] = true;
+        }
+    });
+
+    let skipped = 0;
+    const flushSkipped = () => {
+        if (skipped > 0) {
+            diffElement.appendChild(createElement('div', 'skipped', `… ${skipped} unchanged lines`));
+            skipped = 0;
+        }
+    };
+    lines.forEach((line, index) => {
+        if (!visible[index]) {
+            skipped++;
+            return;
+        }
+        flushSkipped();
+        const element = createElement('div', `line ${line.kind}`);
+        const marker = line.kind === 'added' ? '+' : line.kind === 'removed' ? '-' : ' ';
+        element.append(
+            createElement('span', 'line-number', line.oldLine || ''),
+            createElement('span', 'line-number', line.newLine || ''),
+            createElement('span', '', `${marker} ${line.text}`),
+        );
+        diffElement.appendChild(element);
+    });
+    flushSkipped();
+}
+
+function rollback(version) {
+    if (!confirm(`Restore ${version.artifact} to version #${version.id}? The current content stays in the history.`)) {
+        return;
+    }
+    fetchJSON('/api/config-history/rollback', {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ id: version.id }),
+    })
+        .then(() => loadVersions())
+        .then(() => {
+            statusElement.textContent = `${version.artifact} restored to version #${version.id}`;
+        })
+        .catch((error) => {
+            statusElement.textContent = `Error: ${error.message}`;
+        });
+}
+
+artifactSelect.addEventListener('change', () => {
+    loadVersions().catch((error) => {
+        statusElement.textContent = `Error: ${error.message}`;
+    });
+});
+refreshBtn.addEventListener('click', () => {
+    loadArtifacts().catch((error) => {
+        statusElement.textContent = `Error: ${error.message}`;
+    });
+});
+
+loadArtifacts().catch((error) => {
+    statusElement.textContent = `Error: ${error.message}`;
+});
@@ -85,6 +85,9 @@ function createCharacterCard(key) {
                   <div class="character-info">
                     <span>${key}</span>
                      <div class="status-indicator"></div>
+                     <i class="bi bi-exclamation-octagon-fill config-errors" style="display:none;"></i>
+                     <i class="bi bi-hourglass-split config-pending" style="display:none;"></i>
+                     <i class="bi bi-arrow-repeat config-restart" style="display:none;"></i>
                      <div class="co-line co-line-with-stats">
                       <div class="co-info-left">
                         <span class="co-classlevel">Class/Level (Exp)</span>
@@ -180,6 +183,24 @@ function createCharacterCard(key) {
                     <button class="btn btn-outline" onclick="location.href='/debug?characterName=${key}'" title="Open Debug Page">
                         <i class="bi bi-bug"></i>
                     </button>
+                    <button class="btn btn-outline" onclick="location.href='/gear-advisor?characterName=${key}'" title="Open Gear Advisor">
+                        <i class="bi bi-shield-check"></i>
+                    </button>
+                    <button class="btn btn-outline" onclick="location.href='/forensics?characterName=${key}'" title="Open Death and Chicken Reports">
+                        <i class="bi bi-activity"></i>
+                    </button>
+                    <button class="btn btn-outline" onclick="location.href='/progress?characterName=${key}'" title="Open Leveling Progress">
+                        <i class="bi bi-graph-up"></i>
+                    </button>
+                    <button class="btn btn-outline" onclick="location.href='/bundles?characterName=${key}'" title="Export Profile Bundle">
+                        <i class="bi bi-box-arrow-down"></i>
+                    </button>
+                    <button class="btn btn-outline" onclick="location.href='/config-history?artifact=config/${key}/config.yaml'" title="Open Config History">
+                        <i class="bi bi-clock-history"></i>
+                    </button>
+                    <button class="btn btn-outline" onclick="location.href='/logs?supervisor=${key}'" title="Open Logs">
+                        <i class="bi bi-journal-text"></i>
+                    </button>
                 </div>
                 <div class="run-stats"></div>
             </div>
@@ -315,8 +336,11 @@ function updateCharacterCard(card, key, value, dropCount) {
 
   if (startPauseBtn && stopBtn && attachBtn && manualPlayBtn) {
     updateButtons(startPauseBtn, stopBtn, attachBtn, manualPlayBtn, value.SupervisorStatus, value.manualModeActive);
+    updateValidationErrors(card, startPauseBtn, attachBtn, manualPlayBtn, value.ValidationErrors, value.SupervisorStatus);
   }
 
+  updateConfigChanges(card, value.PendingConfigChanges, value.RestartRequiredChanges);
+
   // Update companion join button visibility
   if (companionJoinBtn) {
     const isCompanionFollower = value.IsCompanionFollower || false;
@@ -335,6 +359,7 @@ function updateCharacterCard(card, key, value, dropCount) {
   // Enrich with live character overview (support both UI and ui keys)
   const uiPayload = value.UI || value.ui || null;
   updateCharacterOverview(card, uiPayload, value.SupervisorStatus);
+  updateProgress(card, value.Progress);
 
   if (statusDetails) {
     updateStartedTime(statusDetails, value.StartedAt);
@@ -425,6 +450,43 @@ function updateButtons(startPauseBtn, stopBtn, attachBtn, manualPlayBtn, status,
   }
 }
 
+// updateValidationErrors shows the config problems and disables starting a broken profile
+function updateValidationErrors(card, startPauseBtn, attachBtn, manualPlayBtn, errors, status) {
+  const errorsEl = card.querySelector(".config-errors");
+  const hasErrors = Array.isArray(errors) && errors.length > 0;
+  if (errorsEl) {
+    errorsEl.style.display = hasErrors ? "inline-block" : "none";
+    errorsEl.title = hasErrors ? `Profile disabled, invalid config:\n${errors.join("\n")}` : "";
+  }
+
+  // A running supervisor keeps its previous settings, it can still be paused
+  const running = status === "In game" || status === "Starting" || status === "Paused";
+  const disabled = hasErrors && !running;
+  [startPauseBtn, attachBtn, manualPlayBtn].forEach((btn) => {
+    btn.disabled = disabled;
+  });
+  if (disabled) {
+    startPauseBtn.title = "Fix the config errors to start";
+  } else {
+    startPauseBtn.title = "Start";
+  }
+}
+
+// updateConfigChanges shows the reloaded settings waiting for the next game and the ones needing a restart
+function updateConfigChanges(card, pending, restartRequired) {
+  const indicators = [
+    [".config-pending", pending, "Config changes applied at the next game"],
+    [".config-restart", restartRequired, "Config changes that need a restart"],
+  ];
+  indicators.forEach(([selector, changes, text]) => {
+    const el = card.querySelector(selector);
+    if (!el) return;
+    const hasChanges = Array.isArray(changes) && changes.length > 0;
+    el.style.display = hasChanges ? "inline-block" : "none";
+    el.title = hasChanges ? `${text}:\n${changes.join("\n")}` : "";
+  });
+}
+
 function updateStats(card, key, games, dropCount) {
   const stats = calculateStats(games);
 
@@ -709,6 +771,37 @@ function updateCharacterOverview(card, ui, status) {
     resEl.innerHTML = `<span class="res-fr">FR: ${fr}</span> | <span class="res-cr">CR: ${cr}</span> | <span class="res-lr">LR: ${lr}</span> | <span class="res-pr">PR: ${pr}</span>`;
 }
 
+// updateProgress shows the XP rate and the projections in the XP bar tooltip
+function updateProgress(card, progress) {
+  const xpEl = card.querySelector(".co-xp");
+  if (!xpEl) return;
+  if (!progress || !progress.xpPerHour) {
+    xpEl.title = "";
+    return;
+  }
+
+  const lines = [`XP/h: ${formatNumber(Math.round(progress.xpPerHour))}`];
+  if (progress.timeToNextLevel) {
+    lines.push(`Next level in: ${formatDurationNs(progress.timeToNextLevel)}`);
+  }
+  if (progress.timeToTargetLevel) {
+    lines.push(
+      `Level ${progress.targetLevel} in: ${formatDurationNs(
+        progress.timeToTargetLevel
+      )}`
+    );
+  }
+  xpEl.title = lines.join("\n");
+}
+
+// formatDurationNs formats a Go time.Duration (nanoseconds)
+function formatDurationNs(ns) {
+  const totalMinutes = Math.round(ns / 6e10);
+  const hours = Math.floor(totalMinutes / 60);
+  const minutes = totalMinutes % 60;
+  return hours > 0 ? `${hours}h ${minutes}m` : `${minutes}m`;
+}
+
 // Helpers to prettify class/difficulty
 function titleCase(s) {
   if (!s) return s;
@@ -0,0 +1,119 @@
+const supervisorNameElement = document.getElementById('supervisor-name');
+const statusElement = document.getElementById('forensics-status');
+const summaryElement = document.getElementById('report-summary');
+const reportSelect = document.getElementById('report-select');
+const samplesBody = document.querySelector('#samples-table tbody');
+const refreshBtn = document.getElementById('refresh-btn');
+
+const urlParams = new URLSearchParams(window.location.search);
+const characterName = urlParams.get('characterName') || '';
+
+// Values under this percent are highlighted
+const LOW_PERCENT = 35;
+
+function createElement(tag, className, text) {
+    const element = document.createElement(tag);
+    if (className) {
+        element.className = className;
+    }
+    if (text !== undefined) {
+        element.textContent = text;
+    }
+    return element;
+}
+
+function fetchJSON(url) {
+    return fetch(url).then((response) => {
+        if (!response.ok) {
+            return response.text().then((text) => {
+                throw new Error(text);
+            });
+        }
+        return response.json();
+    });
+}
+
+function percentCell(value) {
+    return createElement('td', value < LOW_PERCENT ? 'low' : '', `${value}%`);
+}
+
+function monstersCell(monsters) {
+    const cell = createElement('td');
+    (monsters || []).forEach((monster) => {
+        const line = createElement('div', '', `#${monster.name} ${monster.type} (${monster.distance})`);
+        if (monster.states && monster.states.length) {
+            line.appendChild(document.createTextNode(` states: ${monster.states.join(', ')}`));
+        }
+        if (monster.enchantments && monster.enchantments.length) {
+            line.appendChild(document.createTextNode(` enchanted: ${monster.enchantments.join(', ')}`));
+        }
+        if (monster.immunities && monster.immunities.length) {
+            line.appendChild(createElement('span', 'immune', ` immune: ${monster.immunities.join(', ')}`));
+        }
+        cell.appendChild(line);
+    });
+    return cell;
+}
+
+function renderReport(report) {
+    summaryElement.textContent = `${report.reason} at ${new Date(report.time).toLocaleString()}: ${report.error}`;
+    samplesBody.innerHTML = '';
+
+    const end = new Date(report.time).getTime();
+    report.samples.forEach((sample) => {
+        const row = createElement('tr');
+        const offset = (new Date(sample.time).getTime() - end) / 1000;
+        row.appendChild(createElement('td', '', `${offset.toFixed(2)}s`));
+        row.appendChild(percentCell(sample.hpPercent));
+        row.appendChild(percentCell(sample.mpPercent));
+        row.appendChild(createElement('td', '', sample.mercHpPercent > 0 ? `${sample.mercHpPercent}%` : '-'));
+        row.appendChild(createElement('td', '', sample.area));
+        row.appendChild(createElement('td', '', `${sample.position.X}, ${sample.position.Y}`));
+        row.appendChild(createElement('td', '', (sample.states || []).join(', ')));
+        row.appendChild(monstersCell(sample.monsters));
+        row.appendChild(createElement('td', '', [sample.lastAction, sample.lastStep].filter(Boolean).join(' / ')));
+        samplesBody.appendChild(row);
+    });
+}
+
+function loadReport(id) {
+    if (!id) {
+        return;
+    }
+    fetchJSON(`/api/forensics/report?characterName=${encodeURIComponent(characterName)}&id=${encodeURIComponent(id)}`)
+        .then(renderReport)
+        .catch((error) => {
+            statusElement.textContent = `Error: ${error.message}`;
+        });
+}
+
+function loadReports() {
+    statusElement.textContent = 'Loading...';
+    fetchJSON(`/api/forensics/reports?characterName=${encodeURIComponent(characterName)}`)
+        .then((reports) => {
+            reportSelect.innerHTML = '';
+            summaryElement.textContent = '';
+            samplesBody.innerHTML = '';
+            if (reports.length === 0) {
+                statusElement.textContent = 'No deaths or chickens recorded yet';
+                return;
+            }
+
+            statusElement.textContent = '';
+            reports.forEach((report) => {
+                const option = createElement('option', '', `${new Date(report.time).toLocaleString()} - ${report.reason} (${report.area})`);
+                option.value = report.id;
+                reportSelect.appendChild(option);
+            });
+            loadReport(reports[0].id);
+        })
+        .catch((error) => {
+            statusElement.textContent = `Error: ${error.message}`;
+        });
+}
+
+supervisorNameElement.textContent = `Supervisor: ${characterName}`;
+refreshBtn.addEventListener('click', loadReports);
+reportSelect.addEventListener('change', () => loadReport(reportSelect.value));
+
+loadReports();
@@ -0,0 +1,90 @@
+// Shows the Settings.json changes of the profile overrides and the settings the bot doesn't work with
+document.addEventListener('DOMContentLoaded', () => {
+    const container = document.getElementById('game-settings');
+    if (!container) {
+        return;
+    }
+
+    const overridesInput = document.getElementById('game-settings-overrides');
+    const classicModeInput = document.getElementById('classic_mode');
+    const statusElement = document.getElementById('game-settings-status');
+    const problemsList = document.getElementById('game-settings-problems');
+    const changesBody = document.querySelector('#game-settings-changes tbody');
+    let timer = null;
+
+    function format(value) {
+        return value === null || value === undefined ? '(missing)' : JSON.stringify(value);
+    }
+
+    function cell(text) {
+        const td = document.createElement('td');
+        td.textContent = text;
+        return td;
+    }
+
+    function render(report) {
+        problemsList.innerHTML = '';
+        report.problems.forEach((problem) => {
+            const item = document.createElement('li');
+            item.textContent = `${problem.key}: ${problem.message}`;
+            problemsList.appendChild(item);
+        });
+        statusElement.textContent = report.problems.length === 0
+            ? 'The game settings are valid'
+            : `${report.problems.length} setting(s) the bot doesn't work with`;
+
+        changesBody.innerHTML = '';
+        if (report.changes.length === 0) {
+            const row = document.createElement('tr');
+            const td = cell('No settings overridden, the base Settings.json is used');
+            td.colSpan = 3;
+            row.appendChild(td);
+            changesBody.appendChild(row);
+        }
+        report.changes.forEach((change) => {
+            const row = document.createElement('tr');
+            row.append(cell(change.key), cell(format(change.base)), cell(format(change.value)));
+            changesBody.appendChild(row);
+        });
+    }
+
+    function check() {
+        if (!container.open) {
+            return;
+        }
+        statusElement.textContent = 'Checking...';
+        fetch('/api/game-settings/check', {
+            method: 'POST',
+            headers: {'Content-Type': 'application/json'},
+            body: JSON.stringify({
+                overrides: overridesInput.value,
+                classicMode: classicModeInput ? classicModeInput.checked : false,
+            }),
+        })
+            .then((response) => {
+                if (!response.ok) {
+                    return response.text().then((text) => {
+                        throw new Error(text);
+                    });
+                }
+                return response.json();
+            })
+            .then(render)
+            .catch((error) => {
+                problemsList.innerHTML = '';
+                changesBody.innerHTML = '';
+                statusElement.textContent = `Error: ${error.message}`;
+            });
+    }
+
+    function scheduleCheck() {
+        clearTimeout(timer);
+        timer = setTimeout(check, 500);
+    }
+
+    container.addEventListener('toggle', check);
+    overridesInput.addEventListener('input', scheduleCheck);
+    if (classicModeInput) {
+        classicModeInput.addEventListener('change', check);
+    }
+});
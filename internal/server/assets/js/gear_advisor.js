@@ -0,0 +1,159 @@
+const supervisorNameElement = document.getElementById('supervisor-name');
+const statusElement = document.getElementById('advisor-status');
+const playerSlots = document.getElementById('player-slots');
+const mercSlots = document.getElementById('merc-slots');
+const refreshBtn = document.getElementById('refresh-btn');
+const showBlocked = document.getElementById('show-blocked');
+
+const urlParams = new URLSearchParams(window.location.search);
+const characterName = urlParams.get('characterName') || '';
+
+let lastReport = null;
+
+function selectedSources() {
+    return Array.from(document.querySelectorAll('.source-toggle'))
+        .filter((input) => input.checked)
+        .map((input) => input.value);
+}
+
+function formatScore(value) {
+    return Number(value || 0).toFixed(1);
+}
+
+function createElement(tag, className, text) {
+    const element = document.createElement(tag);
+    if (className) {
+        element.className = className;
+    }
+    if (text !== undefined) {
+        element.textContent = text;
+    }
+    return element;
+}
+
+function renderBreakdown(explanation) {
+    const details = createElement('details');
+    details.appendChild(createElement('summary', '', 'Why?'));
+
+    const parts = explanation.merc
+        ? [
+            ['Meta item', explanation.metaItem ? 'yes' : 'no'],
+            ['Merc tier rule', explanation.tierRule],
+            ['Elemental damage', explanation.elementalDamage],
+            ['Chance to cast', explanation.chanceToCast],
+        ]
+        : [
+            ['Tier rule', explanation.tierRule],
+            ['General', explanation.general],
+            ['Unique override', explanation.uniqueOverride ? 'yes' : 'no'],
+            ['Per level', explanation.perLevel],
+            ['Resist (main / other)', `${formatScore(explanation.mainResist)} / ${formatScore(explanation.otherResist)}`],
+            ['Skill', explanation.skill],
+        ];
+
+    const table = createElement('table');
+    parts.forEach(([label, value]) => {
+        const row = createElement('tr');
+        row.appendChild(createElement('td', '', label));
+        row.appendChild(createElement('td', '', typeof value === 'number' ? formatScore(value) : value));
+        table.appendChild(row);
+    });
+    (explanation.stats || []).forEach((stat) => {
+        const row = createElement('tr');
+        row.appendChild(createElement('td', '', `${stat.section}: ${stat.stat} ${stat.value}`));
+        row.appendChild(createElement('td', '', `${formatScore(stat.score)} (x${stat.weight})`));
+        table.appendChild(row);
+    });
+    details.appendChild(table);
+
+    return details;
+}
+
+function renderSlots(container, slots) {
+    container.innerHTML = '';
+    if (!slots || slots.length === 0) {
+        container.appendChild(createElement('div', 'advisor-empty', 'Nothing to show'));
+        return;
+    }
+
+    slots.forEach((slot) => {
+        const card = createElement('div', 'advisor-slot');
+        card.appendChild(createElement('h3', '', slot.location));
+        card.appendChild(createElement('div', 'advisor-equipped',
+            slot.equipped ? `Equipped: ${slot.equipped} (${formatScore(slot.equippedScore)})` : 'Nothing equipped'));
+
+        const candidates = slot.candidates.filter((candidate) => showBlocked.checked || !candidate.blocked);
+        if (candidates.length === 0) {
+            card.appendChild(createElement('div', 'advisor-empty', 'No upgrades found'));
+        }
+
+        candidates.forEach((candidate) => {
+            const entry = createElement('div', candidate.blocked ? 'advisor-candidate blocked' : 'advisor-candidate');
+            entry.appendChild(createElement('span', 'gain', `+${formatScore(candidate.gain)}`));
+            const source = candidate.owner ? `${candidate.source}: ${candidate.owner}` : candidate.source;
+            entry.appendChild(createElement('div', '', `${candidate.item} [${source}] ${formatScore(candidate.score)}`));
+            if (candidate.blocked) {
+                entry.appendChild(createElement('div', 'reason', candidate.reason));
+            }
+            entry.appendChild(renderBreakdown(candidate.explanation));
+            card.appendChild(entry);
+        });
+
+        container.appendChild(card);
+    });
+}
+
+function render(report) {
+    renderSlots(playerSlots, report.player);
+    if (report.mercAlive) {
+        renderSlots(mercSlots, report.merc);
+    } else {
+        mercSlots.innerHTML = '';
+        mercSlots.appendChild(createElement('div', 'advisor-empty', 'The mercenary is not alive'));
+    }
+
+    const status = [];
+    Object.entries(report.muleSnapshots || {}).forEach(([mule, time]) => {
+        status.push(`${mule} is not running, using its items from ${new Date(time).toLocaleString()}`);
+    });
+    if (report.unavailableMules && report.unavailableMules.length) {
+        status.push(`Mules not running and never saved, their items are not included: ${report.unavailableMules.join(', ')}`);
+    }
+    statusElement.textContent = status.join('. ');
+}
+
+function loadReport() {
+    const sources = selectedSources().join(',');
+    if (!sources) {
+        statusElement.textContent = 'Select at least one source';
+        return;
+    }
+    statusElement.textContent = 'Loading...';
+    fetch(`/api/autoequip/upgrades?characterName=${encodeURIComponent(characterName)}&sources=${encodeURIComponent(sources)}`)
+        .then((response) => {
+            if (!response.ok) {
+                return response.text().then((text) => {
+                    throw new Error(text);
+                });
+            }
+            return response.json();
+        })
+        .then((report) => {
+            lastReport = report;
+            render(report);
+        })
+        .catch((error) => {
+            statusElement.textContent = `Error: ${error.message}`;
+        });
+}
+
+supervisorNameElement.textContent = `Supervisor: ${characterName}`;
+refreshBtn.addEventListener('click', loadReport);
+document.querySelectorAll('.source-toggle').forEach((input) => input.addEventListener('change', loadReport));
+showBlocked.addEventListener('change', () => {
+    if (lastReport) {
+        render(lastReport);
+    }
+});
+
+loadReport();
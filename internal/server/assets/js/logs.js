@@ -0,0 +1,150 @@
+const supervisorSelect = document.getElementById('supervisor-select');
+const levelSelect = document.getElementById('level-select');
+const textInput = document.getElementById('text-input');
+const liveToggle = document.getElementById('live-toggle');
+const scrollToggle = document.getElementById('scroll-toggle');
+const clearBtn = document.getElementById('clear-btn');
+const statusElement = document.getElementById('logs-status');
+const logsElement = document.getElementById('logs');
+
+const urlParams = new URLSearchParams(window.location.search);
+
+// Records kept on screen, the oldest ones are removed first
+const MAX_RECORDS = 2000;
+
+let socket = null;
+let dropped = 0;
+let searchTimer = null;
+
+function createElement(tag, className, text) {
+    const element = document.createElement(tag);
+    if (className) {
+        element.className = className;
+    }
+    if (text !== undefined) {
+        element.textContent = text;
+    }
+    return element;
+}
+
+function filterParams() {
+    const params = new URLSearchParams();
+    if (supervisorSelect.value) {
+        params.set('supervisor', supervisorSelect.value);
+    }
+    params.set('level', levelSelect.value);
+    if (textInput.value) {
+        params.set('q', textInput.value);
+    }
+    return params;
+}
+
+function appendRecords(records) {
+    records.forEach((record) => {
+        const row = createElement('div', 'record');
+        row.append(
+            createElement('span', 'time', new Date(record.time).toLocaleTimeString()),
+            createElement('span', `level level-${record.level}`, record.level),
+            createElement('span', 'supervisor', record.supervisor),
+            createElement('span', 'message', record.message),
+        );
+        if (record.attrs) {
+            row.appendChild(createElement('span', 'attrs', record.attrs));
+        }
+        logsElement.appendChild(row);
+    });
+
+    while (logsElement.childElementCount > MAX_RECORDS) {
+        logsElement.removeChild(logsElement.firstChild);
+    }
+    if (scrollToggle.checked) {
+        logsElement.scrollTop = logsElement.scrollHeight;
+    }
+}
+
+function updateStatus(text) {
+    statusElement.textContent = dropped > 0 ? `${text}, ${dropped} record(s) dropped, the page was too slow to receive them` : text;
+}
+
+function disconnect() {
+    if (socket) {
+        socket.onclose = null;
+        socket.close();
+        socket = null;
+    }
+}
+
+function connect() {
+    disconnect();
+    logsElement.innerHTML = '';
+    dropped = 0;
+    updateStatus('Connecting...');
+
+    const protocol = window.location.protocol === 'https:' ? 'wss:' : 'ws:';
+    socket = new WebSocket(`${protocol}//${window.location.host}/ws/logs?${filterParams()}`);
+    socket.onopen = () => updateStatus('Following new records');
+    socket.onmessage = (event) => {
+        const message = JSON.parse(event.data);
+        dropped += message.dropped || 0;
+        appendRecords(message.records || []);
+        updateStatus('Following new records');
+    };
+    socket.onclose = () => {
+        socket = null;
+        updateStatus('Disconnected, reconnecting...');
+        setTimeout(() => {
+            if (liveToggle.checked && !socket) {
+                connect();
+            }
+        }, 3000);
+    };
+}
+
+function search() {
+    disconnect();
+    logsElement.innerHTML = '';
+    dropped = 0;
+    updateStatus('Searching...');
+
+    fetch(`/api/logs/search?${filterParams()}`)
+        .then((response) => {
+            if (!response.ok) {
+                return response.text().then((text) => {
+                    throw new Error(text);
+                });
+            }
+            return response.json();
+        })
+        .then((result) => {
+            appendRecords(result.records);
+            updateStatus(`${result.records.length} record(s) found`);
+        })
+        .catch((error) => {
+            updateStatus(`Error: ${error.message}`);
+        });
+}
+
+function reload() {
+    if (liveToggle.checked) {
+        connect();
+    } else {
+        search();
+    }
+}
+
+if (urlParams.has('supervisor')) {
+    supervisorSelect.value = urlParams.get('supervisor');
+}
+
+supervisorSelect.addEventListener('change', reload);
+levelSelect.addEventListener('change', reload);
+liveToggle.addEventListener('change', reload);
+textInput.addEventListener('input', () => {
+    clearTimeout(searchTimer);
+    searchTimer = setTimeout(reload, 400);
+});
+clearBtn.addEventListener('click', () => {
+    logsElement.innerHTML = '';
+});
+
+reload();
@@ -0,0 +1,62 @@
+// Loads the effective config and the overridden settings of a profile extending a base profile
+document.addEventListener('DOMContentLoaded', () => {
+    const container = document.getElementById('profile-inheritance');
+    if (!container) {
+        return;
+    }
+
+    const chainElement = document.getElementById('inheritance-chain');
+    const overridesBody = document.querySelector('#inheritance-overrides tbody');
+    const effectiveElement = document.getElementById('inheritance-effective');
+    let loaded = false;
+
+    function cell(text) {
+        const td = document.createElement('td');
+        const pre = document.createElement('pre');
+        pre.textContent = text;
+        td.appendChild(pre);
+        return td;
+    }
+
+    function render(inheritance) {
+        chainElement.textContent = `Inheritance: ${inheritance.chain.join(' → ')}`;
+        overridesBody.innerHTML = '';
+        if (inheritance.overrides.length === 0) {
+            const row = document.createElement('tr');
+            const td = document.createElement('td');
+            td.colSpan = 3;
+            td.textContent = 'No settings overridden, the profile is equal to its base';
+            row.appendChild(td);
+            overridesBody.appendChild(row);
+        }
+        inheritance.overrides.forEach((override) => {
+            const row = document.createElement('tr');
+            row.append(cell(override.path), cell(override.base), cell(override.value));
+            overridesBody.appendChild(row);
+        });
+        effectiveElement.textContent = inheritance.effective;
+    }
+
+    container.addEventListener('toggle', () => {
+        if (!container.open || loaded) {
+            return;
+        }
+        chainElement.textContent = 'Loading...';
+        fetch(`/api/profiles/inheritance?supervisor=${encodeURIComponent(container.dataset.supervisor)}`)
+            .then((response) => {
+                if (!response.ok) {
+                    return response.text().then((text) => {
+                        throw new Error(text);
+                    });
+                }
+                return response.json();
+            })
+            .then((inheritance) => {
+                loaded = true;
+                render(inheritance);
+            })
+            .catch((error) => {
+                chainElement.textContent = `Error: ${error.message}`;
+            });
+    });
+});
@@ -0,0 +1,171 @@
+const supervisorNameElement = document.getElementById('supervisor-name');
+const statusElement = document.getElementById('progress-status');
+const summaryElement = document.getElementById('progress-summary');
+const experienceChart = document.getElementById('experience-chart');
+const runsChart = document.getElementById('runs-chart');
+const milestonesElement = document.getElementById('milestones');
+const refreshBtn = document.getElementById('refresh-btn');
+
+const urlParams = new URLSearchParams(window.location.search);
+const characterName = urlParams.get('characterName') || '';
+
+const SVG_NS = 'http://www.w3.org/2000/svg';
+const CHART_WIDTH = 800;
+const CHART_HEIGHT = 220;
+const CHART_PADDING = 40;
+
+function createElement(tag, className, text) {
+    const element = document.createElement(tag);
+    if (className) {
+        element.className = className;
+    }
+    if (text !== undefined) {
+        element.textContent = text;
+    }
+    return element;
+}
+
+function createSvgElement(tag, attributes) {
+    const element = document.createElementNS(SVG_NS, tag);
+    Object.entries(attributes || {}).forEach(([name, value]) => element.setAttribute(name, value));
+    return element;
+}
+
+function formatNumber(value) {
+    return Math.round(value || 0).toLocaleString();
+}
+
+// formatDuration formats a Go time.Duration (nanoseconds)
+function formatDuration(ns) {
+    if (!ns) {
+        return '-';
+    }
+    const totalMinutes = Math.round(ns / 6e10);
+    const hours = Math.floor(totalMinutes / 60);
+    const minutes = totalMinutes % 60;
+    return hours > 0 ? `${hours}h ${minutes}m` : `${minutes}m`;
+}
+
+function renderSummary(progress) {
+    summaryElement.innerHTML = '';
+    const cards = [
+        ['Level', progress.current.level || '-'],
+        ['XP per hour', formatNumber(progress.xpPerHour)],
+        ['Next level in', formatDuration(progress.timeToNextLevel)],
+    ];
+    if (progress.targetLevel) {
+        cards.push([`Level ${progress.targetLevel} in`, formatDuration(progress.timeToTargetLevel)]);
+    }
+
+    cards.forEach(([label, value]) => {
+        const card = createElement('div', 'progress-card');
+        card.appendChild(createElement('div', 'label', label));
+        card.appendChild(createElement('div', 'value', String(value)));
+        summaryElement.appendChild(card);
+    });
+}
+
+function emptyChart(container, text) {
+    container.innerHTML = '';
+    container.appendChild(createElement('div', 'progress-empty', text));
+}
+
+function renderExperienceChart(games) {
+    if (games.length < 2) {
+        emptyChart(experienceChart, 'Not enough games tracked yet');
+        return;
+    }
+
+    const svg = createSvgElement('svg', { viewBox: `0 0 ${CHART_WIDTH} ${CHART_HEIGHT}` });
+    const minXP = Math.min(...games.map((g) => g.experience));
+    const maxXP = Math.max(...games.map((g) => g.experience));
+    const range = Math.max(1, maxXP - minXP);
+    const step = (CHART_WIDTH - CHART_PADDING * 2) / (games.length - 1);
+
+    const points = games.map((game, index) => {
+        const x = CHART_PADDING + index * step;
+        const y = CHART_HEIGHT - CHART_PADDING - ((game.experience - minXP) / range) * (CHART_HEIGHT - CHART_PADDING * 2);
+        return `${x.toFixed(1)},${y.toFixed(1)}`;
+    });
+    svg.appendChild(createSvgElement('polyline', { class: 'line', points: points.join(' ') }));
+
+    const first = games[0];
+    const last = games[games.length - 1];
+    const startLabel = createSvgElement('text', { x: CHART_PADDING, y: CHART_HEIGHT - 10 });
+    startLabel.textContent = `${new Date(first.time).toLocaleDateString()} lvl ${first.level}`;
+    const endLabel = createSvgElement('text', { x: CHART_WIDTH - CHART_PADDING, y: CHART_HEIGHT - 10, 'text-anchor': 'end' });
+    endLabel.textContent = `${new Date(last.time).toLocaleDateString()} lvl ${last.level}`;
+    const maxLabel = createSvgElement('text', { x: CHART_PADDING, y: CHART_PADDING - 10 });
+    maxLabel.textContent = formatNumber(maxXP);
+    svg.append(startLabel, endLabel, maxLabel);
+
+    experienceChart.innerHTML = '';
+    experienceChart.appendChild(svg);
+}
+
+function renderRunsChart(runs) {
+    if (runs.length === 0) {
+        emptyChart(runsChart, 'No runs tracked yet');
+        return;
+    }
+
+    const rowHeight = 24;
+    const labelWidth = 160;
+    const height = runs.length * rowHeight + 10;
+    const svg = createSvgElement('svg', { viewBox: `0 0 ${CHART_WIDTH} ${height}` });
+    const maxRate = Math.max(1, ...runs.map((run) => run.xpPerHour));
+
+    runs.forEach((run, index) => {
+        const y = index * rowHeight + 5;
+        const width = (run.xpPerHour / maxRate) * (CHART_WIDTH - labelWidth - 200);
+        const label = createSvgElement('text', { x: 0, y: y + 14 });
+        label.textContent = run.name;
+        const bar = createSvgElement('rect', { class: 'bar', x: labelWidth, y, width: Math.max(1, width).toFixed(1), height: rowHeight - 6 });
+        const value = createSvgElement('text', { x: labelWidth + width + 6, y: y + 14 });
+        value.textContent = `${formatNumber(run.xpPerHour)} XP/h, ${formatNumber(run.xpPerRun)} per run (${run.runs} runs)`;
+        svg.append(label, bar, value);
+    });
+
+    runsChart.innerHTML = '';
+    runsChart.appendChild(svg);
+}
+
+function renderMilestones(milestones) {
+    milestonesElement.innerHTML = '';
+    if (milestones.length === 0) {
+        milestonesElement.appendChild(createElement('li', '', 'No milestones yet'));
+        return;
+    }
+    milestones.forEach((milestone) => {
+        milestonesElement.appendChild(createElement('li', '',
+            `${new Date(milestone.time).toLocaleString()}: ${milestone.description} (level ${milestone.level})`));
+    });
+}
+
+function loadProgress() {
+    statusElement.textContent = 'Loading...';
+    fetch(`/api/progress?characterName=${encodeURIComponent(characterName)}`)
+        .then((response) => {
+            if (!response.ok) {
+                return response.text().then((text) => {
+                    throw new Error(text);
+                });
+            }
+            return response.json();
+        })
+        .then((progress) => {
+            statusElement.textContent = '';
+            renderSummary(progress);
+            renderExperienceChart(progress.games);
+            renderRunsChart(progress.runs);
+            renderMilestones(progress.milestones);
+        })
+        .catch((error) => {
+            statusElement.textContent = `Error: ${error.message}`;
+        });
+}
+
+supervisorNameElement.textContent = `Supervisor: ${characterName}`;
+refreshBtn.addEventListener('click', loadProgress);
+
+loadProgress();
@@ -0,0 +1,144 @@
+const refreshBtn = document.getElementById('refresh-btn');
+const runBtn = document.getElementById('run-btn');
+const stateElement = document.getElementById('retention-state');
+const statusElement = document.getElementById('retention-status');
+const usageElement = document.getElementById('usage');
+const lastRunElement = document.getElementById('last-run');
+const resultsElement = document.getElementById('results');
+
+// Durations are sent in nanoseconds
+const NS_PER_HOUR = 3600 * 1e9;
+const NS_PER_DAY = 24 * NS_PER_HOUR;
+
+function createElement(tag, className, text) {
+    const element = document.createElement(tag);
+    if (className) {
+        element.className = className;
+    }
+    if (text !== undefined) {
+        element.textContent = text;
+    }
+    return element;
+}
+
+function fetchJSON(url, options) {
+    return fetch(url, options).then((response) => {
+        if (!response.ok) {
+            return response.text().then((text) => {
+                throw new Error(text);
+            });
+        }
+        return response.json();
+    });
+}
+
+function formatBytes(bytes) {
+    const units = ['B', 'KB', 'MB', 'GB'];
+    let value = bytes;
+    let unit = 0;
+    while (value >= 1024 && unit < units.length - 1) {
+        value /= 1024;
+        unit++;
+    }
+    return `${value.toFixed(unit === 0 ? 0 : 1)} ${units[unit]}`;
+}
+
+// Zero times are sent as 0001-01-01
+function formatDate(value) {
+    if (!value || value.startsWith('0001-')) {
+        return '-';
+    }
+    return new Date(value).toLocaleString();
+}
+
+function formatDuration(ns) {
+    if (ns >= NS_PER_DAY && ns % NS_PER_DAY === 0) {
+        return `${ns / NS_PER_DAY} day(s)`;
+    }
+    return `${Math.round(ns / NS_PER_HOUR * 10) / 10} hour(s)`;
+}
+
+function describePolicy(target) {
+    const policy = target.policy;
+    const rules = [];
+    if (policy.maxAge > 0) {
+        rules.push(`remove after ${formatDuration(policy.maxAge)}`);
+    }
+    if (policy.maxTotalSize > 0) {
+        rules.push(`up to ${formatBytes(policy.maxTotalSize)}`);
+    }
+    if (target.compress && policy.compressAfter > 0) {
+        rules.push(`compress after ${formatDuration(policy.compressAfter)}`);
+    }
+    if (policy.keepNewest > 0) {
+        rules.push(`always keep the newest ${policy.keepNewest}`);
+    }
+    return rules.length > 0 ? rules.join(', ') : 'Keep everything';
+}
+
+function render(status) {
+    stateElement.textContent = status.enabled
+        ? `Retention enabled, applied every ${formatDuration(status.interval)}`
+        : 'Retention disabled, enable it in the retention section of koolo.yaml';
+    runBtn.disabled = !status.enabled;
+
+    usageElement.innerHTML = '';
+    status.usage.forEach((usage) => {
+        const target = status.targets.find((t) => t.name === usage.target);
+        const row = document.createElement('tr');
+        row.append(
+            createElement('td', '', usage.target),
+            createElement('td', '', usage.dir),
+            createElement('td', '', usage.files),
+            createElement('td', '', formatBytes(usage.bytes)),
+            createElement('td', '', usage.compressedFiles),
+            createElement('td', '', formatDate(usage.oldest)),
+            createElement('td', '', formatDate(usage.newest)),
+            createElement('td', '', target ? describePolicy(target) : '-'),
+        );
+        usageElement.appendChild(row);
+    });
+
+    lastRunElement.textContent = formatDate(status.lastRun) === '-' ? 'Not run yet' : formatDate(status.lastRun);
+    resultsElement.innerHTML = '';
+    status.results.forEach((result) => {
+        const row = document.createElement('tr');
+        row.append(
+            createElement('td', '', result.target),
+            createElement('td', '', result.removed),
+            createElement('td', '', result.compressed),
+            createElement('td', '', formatBytes(result.freedBytes)),
+            createElement('td', 'errors', (result.errors || []).join('\n')),
+        );
+        resultsElement.appendChild(row);
+    });
+}
+
+function load() {
+    statusElement.textContent = 'Loading...';
+    fetchJSON('/api/retention')
+        .then((status) => {
+            render(status);
+            statusElement.textContent = '';
+        })
+        .catch((error) => {
+            statusElement.textContent = `Error: ${error.message}`;
+        });
+}
+
+refreshBtn.addEventListener('click', load);
+runBtn.addEventListener('click', () => {
+    runBtn.disabled = true;
+    statusElement.textContent = 'Cleaning up...';
+    fetchJSON('/api/retention/run', {method: 'POST'})
+        .then((status) => {
+            render(status);
+            statusElement.textContent = 'Clean up finished';
+        })
+        .catch((error) => {
+            runBtn.disabled = false;
+            statusElement.textContent = `Error: ${error.message}`;
+        });
+});
+
+load();
@@ -29,6 +29,7 @@ const BELT_COLUMN_OPTIONS = [
  * ExitGame?: boolean,
  * stopIfCheckFails?: boolean,
  * StopIfCheckFails?: boolean,
+ * condition?: string,
  * parameters?: never
  * }} RawRunEntry
  */
@@ -47,7 +48,8 @@ const BELT_COLUMN_OPTIONS = [
  * @typedef {{
  * level?: NumericLike,
  * Level?: NumericLike,
- * healthSettings?: RawHealthSettings
+ * healthSettings?: RawHealthSettings,
+ * overrides?: Record<string, unknown>
  * }} RawConfigEntry
  */
 
@@ -59,7 +61,8 @@ const BELT_COLUMN_OPTIONS = [
  * lightRes?: NumericLike,
  * poisonRes?: NumericLike,
  * aboveLowGold?: boolean,
- * aboveGoldThreshold?: boolean
+ * aboveGoldThreshold?: boolean,
+ * condition?: string
  * }} RawConditionEntry
  */
 
@@ -221,6 +224,9 @@ export class SequenceDataAdapter {
       exitGame: Boolean(raw.exitGame ?? raw.ExitGame),
       stopIfCheckFails: Boolean(raw.stopIfCheckFails ?? raw.StopIfCheckFails),
     });
+    if (typeof raw.condition === "string" && raw.condition.trim()) {
+      entry.condition = raw.condition.trim();
+    }
 
     this.state.ensureEntryUID(entry);
     return entry;
@@ -269,6 +275,11 @@ export class SequenceDataAdapter {
       entry.healthSettings.beltColumns = beltColumns;
     }
 
+    // Overrides are a partial character config, they are kept as they are
+    if (raw.overrides && typeof raw.overrides === "object" && Object.keys(raw.overrides).length) {
+      entry.overrides = raw.overrides;
+    }
+
     this.state.ensureEntryUID(entry);
     return entry;
   }
@@ -359,6 +370,9 @@ export class SequenceDataAdapter {
     if (entry.stopIfCheckFails) {
       result.stopIfCheckFails = true;
     }
+    if (entry.condition) {
+      result.condition = entry.condition;
+    }
 
     return result;
   }
@@ -396,6 +410,10 @@ export class SequenceDataAdapter {
       result.healthSettings = health;
     }
 
+    if (entry.overrides && typeof entry.overrides === "object" && Object.keys(entry.overrides).length) {
+      result.overrides = entry.overrides;
+    }
+
     if (!Object.keys(result).length) {
       return null;
     }
@@ -432,6 +450,9 @@ export class SequenceDataAdapter {
     if (condition.poisonRes != null) {
       result.poisonRes = condition.poisonRes;
     }
+    if (condition.condition) {
+      result.condition = condition.condition;
+    }
 
     return result;
   }
@@ -552,7 +573,7 @@ export class SequenceDataAdapter {
    * @returns {SequenceConditionEntry}
    */
   normalizeCondition(condition) {
-    return {
+    const normalized = /** @type {SequenceConditionEntry} */ ({
       level: parseOptionalNumber(condition.level),
       fireRes: parseOptionalNumber(condition.fireRes),
       coldRes: parseOptionalNumber(condition.coldRes),
@@ -560,7 +581,11 @@ export class SequenceDataAdapter {
       poisonRes: parseOptionalNumber(condition.poisonRes),
       aboveLowGold: Boolean(condition.aboveLowGold),
       aboveGoldThreshold: Boolean(condition.aboveGoldThreshold),
-    };
+    });
+    if (typeof condition.condition === "string" && condition.condition.trim()) {
+      normalized.condition = condition.condition.trim();
+    }
+    return normalized;
   }
 
   /**
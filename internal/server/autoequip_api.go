@@ -0,0 +1,213 @@
+package server
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/d2go/pkg/data/item"
+	"github.com/hectorgimenez/koolo/internal/action"
+	"github.com/hectorgimenez/koolo/internal/bot"
+	"github.com/hectorgimenez/koolo/internal/config"
+	"github.com/hectorgimenez/koolo/internal/remote/droplog"
+)
+
+// AutoEquipAPI explains the autoequip decisions of running characters
+type AutoEquipAPI struct {
+	logger  *slog.Logger
+	manager *bot.SupervisorManager
+}
+
+func NewAutoEquipAPI(logger *slog.Logger, manager *bot.SupervisorManager) *AutoEquipAPI {
+	return &AutoEquipAPI{
+		logger:  logger,
+		manager: manager,
+	}
+}
+
+// scoringSnapshot copies the data and config of the running character, the bot keeps refreshing them while the
+// request scores the items
+func (api *AutoEquipAPI) scoringSnapshot(characterName string) (action.ScoringSnapshot, bool) {
+	botCtx := api.manager.GetContext(characterName)
+	if botCtx == nil || botCtx.CharacterCfg == nil || botCtx.Data == nil {
+		return action.ScoringSnapshot{}, false
+	}
+
+	gameData := botCtx.DataSnapshot()
+	cfg := *botCtx.CharacterCfg
+
+	return action.NewScoringSnapshot(&gameData, &cfg), true
+}
+
+// handleExplainScore returns the PlayerScore (or MercScore if merc=true) breakdown of an item, for the given location
+// or for every location the item can be equipped on
+func (api *AutoEquipAPI) handleExplainScore(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	characterName := query.Get("characterName")
+	if characterName == "" {
+		http.Error(w, "characterName is required", http.StatusBadRequest)
+		return
+	}
+	unitID, err := strconv.Atoi(query.Get("unitId"))
+	if err != nil {
+		http.Error(w, "invalid unitId", http.StatusBadRequest)
+		return
+	}
+	merc := query.Get("merc") == "true"
+
+	snapshot, found := api.scoringSnapshot(characterName)
+	if !found {
+		http.Error(w, "character is not running", http.StatusNotFound)
+		return
+	}
+
+	var itm data.Item
+	found = false
+	for _, i := range snapshot.Data.Inventory.AllItems {
+		if i.UnitID == data.UnitID(unitID) {
+			itm, found = i, true
+			break
+		}
+	}
+	if !found {
+		http.Error(w, "item not found", http.StatusNotFound)
+		return
+	}
+
+	locations := itm.Desc().GetType().BodyLocs
+	if location := query.Get("location"); location != "" {
+		locations = []item.LocationType{item.LocationType(location)}
+	}
+
+	explain := action.ExplainPlayerScore
+	if merc {
+		explain = action.ExplainMercScore
+	}
+
+	explanations := make([]action.ScoreExplanation, 0, len(locations))
+	for _, loc := range locations {
+		if explanation, ok := explain(snapshot, itm, loc); ok {
+			explanations = append(explanations, explanation)
+		}
+	}
+	if len(explanations) == 0 {
+		http.Error(w, "the item can't be equipped in that location", http.StatusBadRequest)
+		return
+	}
+
+	api.writeJSON(w, http.StatusOK, explanations)
+}
+
+type upgradeReportResponse struct {
+	Character string `json:"character"`
+	action.UpgradeReport
+	// MuleSnapshots are the times the items of the mules not running were saved, by mule profile
+	MuleSnapshots map[string]time.Time `json:"muleSnapshots,omitempty"`
+	// UnavailableMules are the mule profiles that are not running and were never saved, their items can't be read
+	UnavailableMules []string `json:"unavailableMules,omitempty"`
+}
+
+// handleUpgrades ranks the upgrade candidates from the selected sources (inventory, stash, sharedStash and mule), all
+// of them by default. Mule items are read from the running mule profiles of the character, or from the items saved
+// the last time the mule run finished when they are not running.
+func (api *AutoEquipAPI) handleUpgrades(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	characterName := query.Get("characterName")
+	if characterName == "" {
+		http.Error(w, "characterName is required", http.StatusBadRequest)
+		return
+	}
+	sources := []string{action.UpgradeSourceInventory, action.UpgradeSourceStash, action.UpgradeSourceSharedStash, action.UpgradeSourceMule}
+	if raw := query.Get("sources"); raw != "" {
+		sources = strings.Split(raw, ",")
+	}
+	limit := 5
+	if raw := query.Get("limit"); raw != "" {
+		if parsed, err := strconv.Atoi(raw); err == nil {
+			limit = parsed
+		}
+	}
+
+	response := upgradeReportResponse{Character: characterName}
+	items := make([]action.AdvisorItem, 0)
+	if slices.Contains(sources, action.UpgradeSourceMule) {
+		if cfg, found := config.GetCharacter(characterName); found {
+			for _, mule := range cfg.Muling.MuleProfiles {
+				muleItems, found := api.muleItems(mule, &response)
+				if !found {
+					response.UnavailableMules = append(response.UnavailableMules, mule)
+					continue
+				}
+				for _, itm := range muleItems {
+					// The shared stash is the same one when the mule is in the same account, it's already a source
+					if source, ok := action.AdvisorSource(itm); ok && source != action.UpgradeSourceSharedStash {
+						items = append(items, action.AdvisorItem{Item: itm, Source: action.UpgradeSourceMule, Owner: mule})
+					}
+				}
+			}
+		}
+	}
+
+	snapshot, found := api.scoringSnapshot(characterName)
+	if !found {
+		http.Error(w, "character is not running", http.StatusNotFound)
+		return
+	}
+
+	for _, itm := range snapshot.Data.Inventory.AllItems {
+		if source, ok := action.AdvisorSource(itm); ok && slices.Contains(sources, source) {
+			items = append(items, action.AdvisorItem{Item: itm, Source: source})
+		}
+	}
+
+	response.UpgradeReport = action.AdviseUpgrades(snapshot, items, limit)
+	api.writeJSON(w, http.StatusOK, response)
+}
+
+// muleItems returns the current items of the mule when it's running, otherwise the ones saved the last time it
+// finished muling, their time is added to the response
+func (api *AutoEquipAPI) muleItems(mule string, response *upgradeReportResponse) ([]data.Item, bool) {
+	if muleCtx := api.manager.GetContext(mule); muleCtx != nil && muleCtx.Data != nil {
+		return muleCtx.DataSnapshot().Inventory.AllItems, true
+	}
+
+	snapshot, found, err := droplog.ReadStash(filepath.Join(config.LogDirectory(), "droplogs"), mule)
+	if err != nil {
+		api.logger.Warn("Failed to read the mule stash", slog.String("mule", mule), slog.Any("error", err))
+		return nil, false
+	}
+	if !found {
+		return nil, false
+	}
+
+	if response.MuleSnapshots == nil {
+		response.MuleSnapshots = make(map[string]time.Time)
+	}
+	response.MuleSnapshots[mule] = snapshot.Time
+
+	return snapshot.Items, true
+}
+
+func (api *AutoEquipAPI) writeJSON(w http.ResponseWriter, status int, payload any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(payload); err != nil {
+		api.logger.Error("failed to write JSON response", slog.Any("error", err))
+	}
+}
@@ -0,0 +1,120 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/hectorgimenez/koolo/internal/config"
+)
+
+const maxBundleUploadSize = 20 << 20
+
+// BundleAPI exports profiles as shareable bundles and imports them as new profiles
+type BundleAPI struct {
+	logger *slog.Logger
+}
+
+func NewBundleAPI(logger *slog.Logger) *BundleAPI {
+	return &BundleAPI{logger: logger}
+}
+
+type bundleImportResult struct {
+	Profile string                    `json:"profile"`
+	Files   []config.BundleFileImport `json:"files"`
+}
+
+func (api *BundleAPI) handleExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	supervisor := r.URL.Query().Get("supervisor")
+	if _, found := config.GetCharacter(supervisor); !found {
+		http.Error(w, "character not found", http.StatusNotFound)
+		return
+	}
+
+	content, err := config.ExportBundle(supervisor)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", supervisor+"-bundle.zip"))
+	if _, err = w.Write(content); err != nil {
+		api.logger.Error("failed to write bundle", slog.Any("error", err))
+	}
+}
+
+// handlePreview validates the uploaded bundle and returns what importing it would do, nothing is written
+func (api *BundleAPI) handlePreview(w http.ResponseWriter, r *http.Request) {
+	content, opts, ok := api.readUpload(w, r)
+	if !ok {
+		return
+	}
+
+	api.writeJSON(w, http.StatusOK, config.PreviewBundle(content, opts))
+}
+
+func (api *BundleAPI) handleImport(w http.ResponseWriter, r *http.Request) {
+	content, opts, ok := api.readUpload(w, r)
+	if !ok {
+		return
+	}
+
+	profile, files, err := config.ImportBundle(content, opts)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	api.logger.Info("Profile bundle imported", slog.String("profile", profile), slog.Int("files", len(files)))
+	api.writeJSON(w, http.StatusOK, bundleImportResult{Profile: profile, Files: files})
+}
+
+// readUpload reads the bundle file and the import options of a multipart form
+func (api *BundleAPI) readUpload(w http.ResponseWriter, r *http.Request) ([]byte, config.BundleImportOptions, bool) {
+	opts := config.BundleImportOptions{}
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return nil, opts, false
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxBundleUploadSize)
+	if err := r.ParseMultipartForm(maxBundleUploadSize); err != nil {
+		http.Error(w, "error parsing form: "+err.Error(), http.StatusBadRequest)
+		return nil, opts, false
+	}
+
+	file, _, err := r.FormFile("bundle")
+	if err != nil {
+		http.Error(w, "bundle file is required", http.StatusBadRequest)
+		return nil, opts, false
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		http.Error(w, "error reading bundle file", http.StatusBadRequest)
+		return nil, opts, false
+	}
+
+	opts.Name = r.FormValue("name")
+	opts.OverwriteBuild = r.FormValue("overwriteBuild") == "true"
+	opts.Source = historySource(r)
+
+	return content, opts, true
+}
+
+func (api *BundleAPI) writeJSON(w http.ResponseWriter, status int, payload any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(payload); err != nil {
+		api.logger.Error("failed to write JSON response", slog.Any("error", err))
+	}
+}
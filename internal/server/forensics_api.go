@@ -0,0 +1,78 @@
+package server
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"github.com/hectorgimenez/koolo/internal/bot"
+	"github.com/hectorgimenez/koolo/internal/config"
+)
+
+// ForensicsAPI serves the death and chicken reports saved by the supervisors
+type ForensicsAPI struct {
+	logger *slog.Logger
+}
+
+func NewForensicsAPI(logger *slog.Logger) *ForensicsAPI {
+	return &ForensicsAPI{logger: logger}
+}
+
+// characterName returns the requested supervisor, it must exist since it's part of the reports path
+func (api *ForensicsAPI) characterName(w http.ResponseWriter, r *http.Request) (string, bool) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return "", false
+	}
+
+	characterName := r.URL.Query().Get("characterName")
+	if _, found := config.GetCharacter(characterName); !found {
+		http.Error(w, "character not found", http.StatusNotFound)
+		return "", false
+	}
+
+	return characterName, true
+}
+
+func (api *ForensicsAPI) handleListReports(w http.ResponseWriter, r *http.Request) {
+	characterName, ok := api.characterName(w, r)
+	if !ok {
+		return
+	}
+
+	reports, err := bot.ListForensicsReports(characterName)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	api.writeJSON(w, http.StatusOK, reports)
+}
+
+func (api *ForensicsAPI) handleGetReport(w http.ResponseWriter, r *http.Request) {
+	characterName, ok := api.characterName(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := bot.LoadForensicsReport(characterName, r.URL.Query().Get("id"))
+	if err != nil {
+		status := http.StatusBadRequest
+		if os.IsNotExist(err) {
+			status = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	api.writeJSON(w, http.StatusOK, report)
+}
+
+func (api *ForensicsAPI) writeJSON(w http.ResponseWriter, status int, payload any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(payload); err != nil {
+		api.logger.Error("failed to write JSON response", slog.Any("error", err))
+	}
+}
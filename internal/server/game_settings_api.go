@@ -0,0 +1,97 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/hectorgimenez/koolo/internal/config"
+	"github.com/hectorgimenez/koolo/internal/config/gamesettings"
+)
+
+// GameSettingsAPI checks the Settings.json the game would start with, so the overrides can be reviewed before saving
+type GameSettingsAPI struct {
+	logger *slog.Logger
+}
+
+func NewGameSettingsAPI(logger *slog.Logger) *GameSettingsAPI {
+	return &GameSettingsAPI{logger: logger}
+}
+
+type gameSettingsCheckRequest struct {
+	Overrides   string `json:"overrides"`
+	ClassicMode bool   `json:"classicMode"`
+}
+
+type gameSettingsCheckResponse struct {
+	Changes  []gamesettings.Change  `json:"changes"`
+	Problems []gamesettings.Problem `json:"problems"`
+}
+
+// handleCheck applies the overrides, as written in the character settings form, to the base Settings.json
+func (api *GameSettingsAPI) handleCheck(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req gameSettingsCheckRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	overrides, err := parseGameSettingsOverrides(req.Overrides)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	report, err := config.CheckGameSettings(&config.CharacterCfg{GameSettings: overrides, ClassicMode: req.ClassicMode})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	api.writeJSON(w, http.StatusOK, gameSettingsCheckResponse{Changes: report.Changes, Problems: report.Problems})
+}
+
+func (api *GameSettingsAPI) writeJSON(w http.ResponseWriter, status int, payload any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(payload); err != nil {
+		api.logger.Error("failed to write JSON response", slog.Any("error", err))
+	}
+}
+
+// parseGameSettingsOverrides reads the overrides JSON object of the character settings form, empty means no overrides
+func parseGameSettingsOverrides(value string) (map[string]any, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	var overrides map[string]any
+	if err := json.Unmarshal([]byte(value), &overrides); err != nil {
+		return nil, fmt.Errorf("invalid game settings overrides, they must be a JSON object: %w", err)
+	}
+	if len(overrides) == 0 {
+		return nil, nil
+	}
+
+	return overrides, nil
+}
+
+func formatGameSettingsOverrides(overrides map[string]any) string {
+	if len(overrides) == 0 {
+		return ""
+	}
+
+	content, err := gamesettings.Encode(overrides)
+	if err != nil {
+		return ""
+	}
+
+	return string(content)
+}
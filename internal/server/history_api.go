@@ -0,0 +1,165 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/hectorgimenez/koolo/internal/config"
+	"github.com/hectorgimenez/koolo/internal/config/history"
+	"github.com/hectorgimenez/koolo/internal/config/secrets"
+)
+
+// HistoryAPI serves the config history, the differences between versions and the rollbacks
+type HistoryAPI struct {
+	logger *slog.Logger
+}
+
+func NewHistoryAPI(logger *slog.Logger) *HistoryAPI {
+	return &HistoryAPI{logger: logger}
+}
+
+type historyVersions struct {
+	Artifacts []string          `json:"artifacts"`
+	Versions  []history.Version `json:"versions"`
+}
+
+type historyDiff struct {
+	From  history.Version    `json:"from"`
+	To    *history.Version   `json:"to,omitempty"`
+	Lines []history.DiffLine `json:"lines"`
+}
+
+type rollbackRequest struct {
+	ID int64 `json:"id"`
+}
+
+// historySource tells the changes made from the web UI, where the browser sends the page making the request, from
+// direct API calls
+func historySource(r *http.Request) history.Source {
+	if r.Header.Get("Referer") != "" || r.Header.Get("Origin") != "" {
+		return history.SourceUI
+	}
+
+	return history.SourceAPI
+}
+
+// handleList returns the files with history, and the versions of the artifact given in the query
+func (api *HistoryAPI) handleList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	store, err := config.ConfigHistory()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	result := historyVersions{Artifacts: store.Artifacts(), Versions: make([]history.Version, 0)}
+	if artifact := r.URL.Query().Get("artifact"); artifact != "" {
+		result.Versions = store.Versions(artifact)
+	}
+
+	api.writeJSON(w, http.StatusOK, result)
+}
+
+// handleDiff compares two versions of a file, without "to" the version is compared with the current file
+func (api *HistoryAPI) handleDiff(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	store, err := config.ConfigHistory()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	fromID, err := strconv.ParseInt(r.URL.Query().Get("from"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid from version", http.StatusBadRequest)
+		return
+	}
+	from, fromContent, err := store.Content(fromID)
+	if err != nil {
+		api.writeError(w, err)
+		return
+	}
+
+	result := historyDiff{From: from}
+	var toContent []byte
+	if toParam := r.URL.Query().Get("to"); toParam != "" {
+		toID, err := strconv.ParseInt(toParam, 10, 64)
+		if err != nil {
+			http.Error(w, "invalid to version", http.StatusBadRequest)
+			return
+		}
+		to, content, err := store.Content(toID)
+		if err != nil {
+			api.writeError(w, err)
+			return
+		}
+		if to.Artifact != from.Artifact {
+			http.Error(w, "versions belong to different files", http.StatusBadRequest)
+			return
+		}
+		result.To = &to
+		toContent = content
+	} else {
+		// Artifacts are relative to the koolo folder, the working directory. A deleted file is shown as empty
+		toContent, _ = os.ReadFile(filepath.FromSlash(from.Artifact))
+	}
+
+	result.Lines = history.Diff(string(fromContent), string(toContent))
+	for i := range result.Lines {
+		result.Lines[i].Text = secrets.Redact(result.Lines[i].Text)
+	}
+
+	api.writeJSON(w, http.StatusOK, result)
+}
+
+func (api *HistoryAPI) handleRollback(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req rollbackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	version, err := config.RollbackConfigFile(req.ID, historySource(r))
+	if err != nil {
+		api.writeError(w, err)
+		return
+	}
+
+	api.logger.Info("Config rolled back", slog.String("file", version.Artifact), slog.Int64("version", version.ID))
+	api.writeJSON(w, http.StatusOK, version)
+}
+
+func (api *HistoryAPI) writeError(w http.ResponseWriter, err error) {
+	if errors.Is(err, history.ErrVersionNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
+
+func (api *HistoryAPI) writeJSON(w http.ResponseWriter, status int, payload any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(payload); err != nil {
+		api.logger.Error("failed to write JSON response", slog.Any("error", err))
+	}
+}
@@ -33,10 +33,14 @@ import (
 	"github.com/hectorgimenez/d2go/pkg/data/stat"
 	"github.com/hectorgimenez/koolo/internal/bot"
 	"github.com/hectorgimenez/koolo/internal/config"
+	"github.com/hectorgimenez/koolo/internal/config/history"
+	"github.com/hectorgimenez/koolo/internal/config/secrets"
 	ctx "github.com/hectorgimenez/koolo/internal/context"
 	"github.com/hectorgimenez/koolo/internal/event"
 	"github.com/hectorgimenez/koolo/internal/game"
+	"github.com/hectorgimenez/koolo/internal/logstream"
 	"github.com/hectorgimenez/koolo/internal/remote/droplog"
+	"github.com/hectorgimenez/koolo/internal/retention"
 	terrorzones "github.com/hectorgimenez/koolo/internal/terrorzone"
 	"github.com/hectorgimenez/koolo/internal/utils"
 	"github.com/hectorgimenez/koolo/internal/utils/winproc"
@@ -45,13 +49,23 @@ import (
 )
 
 type HttpServer struct {
-	logger      *slog.Logger
-	server      *http.Server
-	manager     *bot.SupervisorManager
-	templates   *template.Template
-	wsServer    *WebSocketServer
-	pickitAPI   *PickitAPI
-	sequenceAPI *SequenceAPI
+	logger          *slog.Logger
+	server          *http.Server
+	manager         *bot.SupervisorManager
+	templates       *template.Template
+	wsServer        *WebSocketServer
+	pickitAPI       *PickitAPI
+	sequenceAPI     *SequenceAPI
+	autoEquipAPI    *AutoEquipAPI
+	forensicsAPI    *ForensicsAPI
+	progressAPI     *ProgressAPI
+	profileAPI      *ProfileAPI
+	secretsAPI      *SecretsAPI
+	bundleAPI       *BundleAPI
+	historyAPI      *HistoryAPI
+	gameSettingsAPI *GameSettingsAPI
+	logsAPI         *LogsAPI
+	retentionAPI    *RetentionAPI
 }
 
 var (
@@ -188,7 +202,7 @@ func (s *HttpServer) BroadcastStatus() {
 	}
 }
 
-func New(logger *slog.Logger, manager *bot.SupervisorManager) (*HttpServer, error) {
+func New(logger *slog.Logger, manager *bot.SupervisorManager, retentionService *retention.Service) (*HttpServer, error) {
 	var templates *template.Template
 	helperFuncs := template.FuncMap{
 		"isInSlice": func(slice []stat.Resist, value string) bool {
@@ -197,6 +211,12 @@ func New(logger *slog.Logger, manager *bot.SupervisorManager) (*HttpServer, erro
 		"isTZSelected": func(slice []area.ID, value int) bool {
 			return slices.Contains(slice, area.ID(value))
 		},
+		"redact": func(value string) string {
+			if value == "" {
+				return ""
+			}
+			return secrets.Redacted
+		},
 		"executeTemplateByName": func(name string, data interface{}) template.HTML {
 			tmpl := templates.Lookup(name)
 			var buf bytes.Buffer
@@ -235,11 +255,21 @@ func New(logger *slog.Logger, manager *bot.SupervisorManager) (*HttpServer, erro
 	}
 
 	return &HttpServer{
-		logger:      logger,
-		manager:     manager,
-		templates:   templates,
-		pickitAPI:   NewPickitAPI(),
-		sequenceAPI: NewSequenceAPI(logger),
+		logger:          logger,
+		manager:         manager,
+		templates:       templates,
+		pickitAPI:       NewPickitAPI(),
+		sequenceAPI:     NewSequenceAPI(logger),
+		autoEquipAPI:    NewAutoEquipAPI(logger, manager),
+		forensicsAPI:    NewForensicsAPI(logger),
+		progressAPI:     NewProgressAPI(logger),
+		profileAPI:      NewProfileAPI(logger),
+		secretsAPI:      NewSecretsAPI(logger),
+		bundleAPI:       NewBundleAPI(logger),
+		historyAPI:      NewHistoryAPI(logger),
+		gameSettingsAPI: NewGameSettingsAPI(logger),
+		logsAPI:         NewLogsAPI(logger, logstream.Default),
+		retentionAPI:    NewRetentionAPI(logger, retentionService),
 	}, nil
 }
 
@@ -560,6 +590,7 @@ func (s *HttpServer) getStatusData() IndexData {
 				stats.IsCompanionFollower = true
 				stats.MuleEnabled = cfg.Muling.Enabled
 			}
+			stats.ValidationErrors = cfg.Runtime.ValidationErrors
 		}
 
 		status[supervisorName] = stats
@@ -633,6 +664,39 @@ func (s *HttpServer) Listen(port int) error {
 	http.HandleFunc("/api/sequence-editor/save", s.sequenceAPI.handleSaveSequence)
 	http.HandleFunc("/api/sequence-editor/delete", s.sequenceAPI.handleDeleteSequence)
 	http.HandleFunc("/api/sequence-editor/files", s.sequenceAPI.handleListSequenceFiles)
+	http.HandleFunc("/api/sequence-editor/simulate-build", s.sequenceAPI.handleSimulateBuild)
+	http.HandleFunc("/api/sequence-editor/dry-run", s.sequenceAPI.handleDryRunSequence)
+	http.HandleFunc("/api/autoequip/explain", s.autoEquipAPI.handleExplainScore)
+	http.HandleFunc("/api/autoequip/upgrades", s.autoEquipAPI.handleUpgrades)
+	http.HandleFunc("/gear-advisor", s.gearAdvisorPage)
+	http.HandleFunc("/api/forensics/reports", s.forensicsAPI.handleListReports)
+	http.HandleFunc("/api/forensics/report", s.forensicsAPI.handleGetReport)
+	http.HandleFunc("/forensics", s.forensicsPage)
+	http.HandleFunc("/api/progress", s.progressAPI.handleGetProgress)
+	http.HandleFunc("/progress", s.progressPage)
+	http.HandleFunc("/api/profiles/inheritance", s.profileAPI.handleGetInheritance)
+	http.HandleFunc("/api/secrets", s.secretsAPI.handleList)
+	http.HandleFunc("/api/secrets/set", s.secretsAPI.handleSet)
+	http.HandleFunc("/api/secrets/delete", s.secretsAPI.handleDelete)
+	http.HandleFunc("/api/secrets/unlock", s.secretsAPI.handleUnlock)
+	http.HandleFunc("/api/bundles/export", s.bundleAPI.handleExport)
+	http.HandleFunc("/api/bundles/preview", s.bundleAPI.handlePreview)
+	http.HandleFunc("/api/bundles/import", s.bundleAPI.handleImport)
+	http.HandleFunc("/bundles", s.bundlesPage)
+	http.HandleFunc("/api/config-history", s.historyAPI.handleList)
+	http.HandleFunc("/api/config-history/diff", s.historyAPI.handleDiff)
+	http.HandleFunc("/api/config-history/rollback", s.historyAPI.handleRollback)
+	http.HandleFunc("/config-history", s.configHistoryPage)
+
+	http.HandleFunc("/api/game-settings/check", s.gameSettingsAPI.handleCheck)
+
+	http.HandleFunc("/api/logs/search", s.logsAPI.handleSearch)
+	http.HandleFunc("/ws/logs", s.logsAPI.handleStream)
+	http.HandleFunc("/logs", s.logsPage)
+
+	http.HandleFunc("/api/retention", s.retentionAPI.handleStatus)
+	http.HandleFunc("/api/retention/run", s.retentionAPI.handleRun)
+	http.HandleFunc("/retention", s.retentionPage)
 
 	assets, _ := fs.Sub(assetsFS, "assets")
 	http.Handle("/assets/", http.StripPrefix("/assets/", http.FileServer(http.FS(assets))))
@@ -649,14 +713,15 @@ func (s *HttpServer) Listen(port int) error {
 }
 
 func (s *HttpServer) reloadConfig(w http.ResponseWriter, r *http.Request) {
-	result := s.manager.ReloadConfig()
-	if result != nil {
-		http.Error(w, result.Error(), http.StatusInternalServerError)
+	changes, err := s.manager.ReloadConfig()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	s.logger.Info("Config reloaded")
-	w.WriteHeader(http.StatusOK)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(changes)
 }
 
 func (s *HttpServer) Stop() error {
@@ -739,6 +804,85 @@ func (s *HttpServer) sequenceEditorPage(w http.ResponseWriter, r *http.Request)
 	}
 }
 
+func (s *HttpServer) gearAdvisorPage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	if err := s.templates.ExecuteTemplate(w, "gear_advisor.gohtml", nil); err != nil {
+		s.logger.Error("Failed to execute gear_advisor template", "error", err)
+		http.Error(w, fmt.Sprintf("Template error: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (s *HttpServer) forensicsPage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	if err := s.templates.ExecuteTemplate(w, "forensics.gohtml", nil); err != nil {
+		s.logger.Error("Failed to execute forensics template", "error", err)
+		http.Error(w, fmt.Sprintf("Template error: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (s *HttpServer) progressPage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	if err := s.templates.ExecuteTemplate(w, "progress.gohtml", nil); err != nil {
+		s.logger.Error("Failed to execute progress template", "error", err)
+		http.Error(w, fmt.Sprintf("Template error: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (s *HttpServer) bundlesPage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	if err := s.templates.ExecuteTemplate(w, "bundles.gohtml", s.manager.AvailableSupervisors()); err != nil {
+		s.logger.Error("Failed to execute bundles template", "error", err)
+		http.Error(w, fmt.Sprintf("Template error: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (s *HttpServer) configHistoryPage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	if err := s.templates.ExecuteTemplate(w, "config_history.gohtml", nil); err != nil {
+		s.logger.Error("Failed to execute config history template", "error", err)
+		http.Error(w, fmt.Sprintf("Template error: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (s *HttpServer) logsPage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	// Supervisors not started yet are listed too, their records are shown as soon as they start
+	supervisors := s.logsAPI.hub.Supervisors()
+	for _, supervisor := range s.manager.AvailableSupervisors() {
+		if !slices.Contains(supervisors, supervisor) {
+			supervisors = append(supervisors, supervisor)
+		}
+	}
+	sort.Strings(supervisors)
+
+	if err := s.templates.ExecuteTemplate(w, "logs.gohtml", LogsData{Supervisors: supervisors}); err != nil {
+		s.logger.Error("Failed to execute logs template", "error", err)
+		http.Error(w, fmt.Sprintf("Template error: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (s *HttpServer) retentionPage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	if err := s.templates.ExecuteTemplate(w, "retention.gohtml", nil); err != nil {
+		s.logger.Error("Failed to execute retention template", "error", err)
+		http.Error(w, fmt.Sprintf("Template error: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
 func (s *HttpServer) startSupervisor(w http.ResponseWriter, r *http.Request) {
 	supervisorList := s.manager.AvailableSupervisors()
 	Supervisor := r.URL.Query().Get("characterName")
@@ -1025,7 +1169,8 @@ func (s *HttpServer) config(w http.ResponseWriter, r *http.Request) {
 		newConfig.Discord.EnableRunFinishMessages = r.Form.Has("enable_run_finish_messages")
 		newConfig.Discord.EnableDiscordChickenMessages = r.Form.Has("enable_discord_chicken_messages")
 		newConfig.Discord.EnableDiscordErrorMessages = r.Form.Has("enable_discord_error_messages")
-		newConfig.Discord.Token = r.Form.Get("discord_token")
+		newConfig.Discord.EnableProgressMessages = r.Form.Has("enable_progress_messages")
+		newConfig.Discord.Token = secrets.Unredact(r.Form.Get("discord_token"), config.Koolo.Discord.Token)
 		newConfig.Discord.ChannelID = r.Form.Get("discord_channel_id")
 
 		// Discord admins who can use bot commands
@@ -1037,11 +1182,11 @@ func (s *HttpServer) config(w http.ResponseWriter, r *http.Request) {
 			return -1
 		}, discordAdmins)
 		newConfig.Discord.BotAdmins = strings.Split(cleanedAdmins, ",")
-		newConfig.Discord.Token = r.Form.Get("discord_token")
+		newConfig.Discord.Token = secrets.Unredact(r.Form.Get("discord_token"), config.Koolo.Discord.Token)
 		newConfig.Discord.ChannelID = r.Form.Get("discord_channel_id")
 		// Telegram
 		newConfig.Telegram.Enabled = r.Form.Get("telegram_enabled") == "true"
-		newConfig.Telegram.Token = r.Form.Get("telegram_token")
+		newConfig.Telegram.Token = secrets.Unredact(r.Form.Get("telegram_token"), config.Koolo.Telegram.Token)
 		telegramChatId, err := strconv.ParseInt(r.Form.Get("telegram_chat_id"), 10, 64)
 		if err != nil {
 			s.templates.ExecuteTemplate(w, "config.gohtml", ConfigData{KooloCfg: &newConfig, ErrorMessage: "Invalid Telegram Chat ID"})
@@ -1063,7 +1208,7 @@ func (s *HttpServer) config(w http.ResponseWriter, r *http.Request) {
 		}
 		newConfig.PingMonitor.SustainedDuration = pingDuration
 
-		err = config.ValidateAndSaveConfig(newConfig)
+		err = config.ValidateAndSaveConfig(newConfig, history.SourceUI)
 		if err != nil {
 			s.templates.ExecuteTemplate(w, "config.gohtml", ConfigData{KooloCfg: &newConfig, ErrorMessage: err.Error()})
 			return
@@ -1105,8 +1250,12 @@ func (s *HttpServer) characterSettings(w http.ResponseWriter, r *http.Request) {
 
 				return
 			}
+			cfg, _ = config.GetCharacter(supervisorName)
 		}
 
+		cfg.Extends = r.Form.Get("extends")
+		cfg.Abstract = r.Form.Has("abstract")
+
 		cfg.MaxGameLength, _ = strconv.Atoi(r.Form.Get("maxGameLength"))
 		cfg.CharacterName = r.Form.Get("characterName")
 		cfg.CommandLineArgs = r.Form.Get("commandLineArgs")
@@ -1114,13 +1263,23 @@ func (s *HttpServer) characterSettings(w http.ResponseWriter, r *http.Request) {
 		cfg.ClassicMode = r.Form.Has("classic_mode")
 		cfg.CloseMiniPanel = r.Form.Has("close_mini_panel")
 		cfg.HidePortraits = r.Form.Has("hide_portraits")
+		cfg.GameSettings, err = parseGameSettingsOverrides(r.Form.Get("gameSettings"))
+		if err != nil {
+			s.templates.ExecuteTemplate(w, "character_settings.gohtml", CharacterSettings{
+				Version:               config.Version,
+				ErrorMessage:          err.Error(),
+				Supervisor:            supervisorName,
+				LevelingSequenceFiles: sequenceFiles,
+			})
+			return
+		}
 
 		// Bnet config
 		cfg.Username = r.Form.Get("username")
-		cfg.Password = r.Form.Get("password")
+		cfg.Password = secrets.Unredact(r.Form.Get("password"), cfg.Password)
 		cfg.Realm = r.Form.Get("realm")
 		cfg.AuthMethod = r.Form.Get("authmethod")
-		cfg.AuthToken = r.Form.Get("AuthToken")
+		cfg.AuthToken = secrets.Unredact(r.Form.Get("AuthToken"), cfg.AuthToken)
 
 		// Scheduler config
 		cfg.Scheduler.Enabled = r.Form.Has("schedulerEnabled")
@@ -1578,7 +1737,15 @@ func (s *HttpServer) characterSettings(w http.ResponseWriter, r *http.Request) {
 
 		cfg.Muling.ReturnTo = r.FormValue("mulingReturnTo")
 
-		config.SaveSupervisorConfig(supervisorName, cfg)
+		if err = config.SaveSupervisorConfigFrom(history.SourceUI, supervisorName, cfg); err != nil {
+			s.templates.ExecuteTemplate(w, "character_settings.gohtml", CharacterSettings{
+				Version:               config.Version,
+				ErrorMessage:          err.Error(),
+				Supervisor:            supervisorName,
+				LevelingSequenceFiles: sequenceFiles,
+			})
+			return
+		}
 		http.Redirect(w, r, "/", http.StatusSeeOther)
 		return
 	}
@@ -1626,6 +1793,15 @@ func (s *HttpServer) characterSettings(w http.ResponseWriter, r *http.Request) {
 	sort.Strings(muleProfiles)
 	sort.Strings(farmerProfiles)
 
+	// Any other profile can be the base of this one, cycles are rejected when saving
+	baseProfiles := []string{}
+	for profileName := range allCharacters {
+		if profileName != supervisor {
+			baseProfiles = append(baseProfiles, profileName)
+		}
+	}
+	sort.Strings(baseProfiles)
+
 	// Filter out any invalid mule profiles from the config before rendering
 	// This prevents form validation errors when deleted mules are still referenced
 	validConfigMuleProfiles := []string{}
@@ -1648,7 +1824,9 @@ func (s *HttpServer) characterSettings(w http.ResponseWriter, r *http.Request) {
 		RunewordRecipeList:    config.AvailableRunewordRecipes,
 		AvailableProfiles:     muleProfiles,
 		FarmerProfiles:        farmerProfiles,
+		BaseProfiles:          baseProfiles,
 		LevelingSequenceFiles: sequenceFiles,
+		GameSettings:          formatGameSettingsOverrides(cfg.GameSettings),
 	})
 }
 
@@ -1726,7 +1904,7 @@ func (s *HttpServer) resetMuling(w http.ResponseWriter, r *http.Request) {
 	s.logger.Info("Resetting muling index for character", "character", characterName)
 	cfg.MulingState.CurrentMuleIndex = 0
 
-	err := config.SaveSupervisorConfig(characterName, cfg)
+	err := config.SaveSupervisorConfigFrom(historySource(r), characterName, cfg)
 	if err != nil {
 		http.Error(w, "Failed to save updated config", http.StatusInternalServerError)
 		return
@@ -1759,7 +1937,7 @@ func (s *HttpServer) openDroplogs(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]string{"status": "ok", "dir": dir})
 }
 
-// resetDroplogs removes droplog JSONL/HTML files from the droplogs directory.
+// resetDroplogs removes droplog JSONL (compressed too) and HTML files from the droplogs directory.
 func (s *HttpServer) resetDroplogs(w http.ResponseWriter, r *http.Request) {
 	base := config.Koolo.LogSaveDirectory
 	if base == "" {
@@ -1784,7 +1962,7 @@ func (s *HttpServer) resetDroplogs(w http.ResponseWriter, r *http.Request) {
 			continue
 		}
 		name := strings.ToLower(e.Name())
-		if strings.HasSuffix(name, ".jsonl") || strings.HasSuffix(name, ".html") {
+		if strings.HasSuffix(name, ".jsonl") || strings.HasSuffix(name, ".jsonl.gz") || strings.HasSuffix(name, ".html") {
 			_ = os.Remove(filepath.Join(dir, e.Name()))
 			removed++
 		}
@@ -0,0 +1,173 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/hectorgimenez/koolo/internal/logstream"
+)
+
+const (
+	defaultLogLimit = 500
+	// logStreamBuffer is the number of records waiting to be sent to a client before the next ones are dropped
+	logStreamBuffer = 1000
+	logWriteTimeout = 10 * time.Second
+)
+
+// LogsAPI serves the recent log records of koolo and the supervisors, searched or streamed live over a WebSocket
+type LogsAPI struct {
+	logger *slog.Logger
+	hub    *logstream.Hub
+}
+
+func NewLogsAPI(logger *slog.Logger, hub *logstream.Hub) *LogsAPI {
+	return &LogsAPI{logger: logger, hub: hub}
+}
+
+type logSearchResult struct {
+	Supervisors []string           `json:"supervisors"`
+	Records     []logstream.Record `json:"records"`
+}
+
+// logStreamMessage is sent to the WebSocket clients, Dropped is the number of records the client was too slow to get
+type logStreamMessage struct {
+	Records []logstream.Record `json:"records"`
+	Dropped uint64             `json:"dropped,omitempty"`
+}
+
+// handleSearch returns the newest records matching the filters: supervisor, level, q (text) and limit
+func (api *LogsAPI) handleSearch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	filter, limit, err := parseLogFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	api.writeJSON(w, http.StatusOK, logSearchResult{
+		Supervisors: api.hub.Supervisors(),
+		Records:     api.hub.Search(filter, limit),
+	})
+}
+
+// handleStream sends the newest records matching the filters, the same as the search, and then the new ones as they
+// are logged
+func (api *LogsAPI) handleStream(w http.ResponseWriter, r *http.Request) {
+	filter, limit, err := parseLogFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		api.logger.Error("Failed to upgrade log stream connection to WebSocket", slog.Any("error", err))
+		return
+	}
+	defer conn.Close()
+
+	// Subscribed before reading the history, so nothing logged in between is lost
+	sub := api.hub.Subscribe(filter, logStreamBuffer)
+	defer sub.Close()
+
+	history := api.hub.Search(filter, limit)
+	var lastID uint64
+	if len(history) > 0 {
+		lastID = history[len(history)-1].ID
+	}
+	if err = api.writeMessage(conn, logStreamMessage{Records: history}); err != nil {
+		return
+	}
+
+	// The client doesn't send anything, reading is only needed to know when the connection is closed
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	var dropped uint64
+	for {
+		select {
+		case <-closed:
+			return
+		case rec, ok := <-sub.Records():
+			if !ok {
+				return
+			}
+			if rec.ID <= lastID {
+				continue
+			}
+
+			// Records logged meanwhile are sent together, a busy supervisor can log hundreds per second
+			msg := logStreamMessage{Records: []logstream.Record{rec}}
+			for pending := len(sub.Records()); pending > 0; pending-- {
+				if next := <-sub.Records(); next.ID > lastID {
+					msg.Records = append(msg.Records, next)
+				}
+			}
+			if total := sub.Dropped(); total > dropped {
+				msg.Dropped = total - dropped
+				dropped = total
+			}
+
+			if err = api.writeMessage(conn, msg); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (api *LogsAPI) writeMessage(conn *websocket.Conn, msg logStreamMessage) error {
+	if err := conn.SetWriteDeadline(time.Now().Add(logWriteTimeout)); err != nil {
+		return err
+	}
+
+	return conn.WriteJSON(msg)
+}
+
+func (api *LogsAPI) writeJSON(w http.ResponseWriter, status int, payload any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(payload); err != nil {
+		api.logger.Error("failed to write JSON response", slog.Any("error", err))
+	}
+}
+
+func parseLogFilter(r *http.Request) (logstream.Filter, int, error) {
+	query := r.URL.Query()
+	filter := logstream.Filter{
+		Supervisor: query.Get("supervisor"),
+		Text:       query.Get("q"),
+	}
+
+	if level := query.Get("level"); level != "" {
+		if err := filter.MinLevel.UnmarshalText([]byte(level)); err != nil {
+			return filter, 0, fmt.Errorf("invalid log level %q", level)
+		}
+	}
+
+	limit := defaultLogLimit
+	if value := query.Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			return filter, 0, fmt.Errorf("invalid limit %q", value)
+		}
+		limit = parsed
+	}
+
+	return filter, limit, nil
+}
@@ -10,6 +10,8 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/hectorgimenez/koolo/internal/config"
+	"github.com/hectorgimenez/koolo/internal/config/history"
 	"github.com/hectorgimenez/koolo/internal/pickit"
 	"github.com/hectorgimenez/koolo/internal/utils"
 )
@@ -172,7 +174,7 @@ func (api *PickitAPI) handleCreateRule(w http.ResponseWriter, r *http.Request) {
 
 	// Save rule (implementation needed)
 	characterID := r.URL.Query().Get("character")
-	if err := api.saveRule(characterID, &rule); err != nil {
+	if err := api.saveRule(characterID, &rule, historySource(r)); err != nil {
 		http.Error(w, fmt.Sprintf("Error saving rule: %v", err), http.StatusInternalServerError)
 		return
 	}
@@ -216,7 +218,7 @@ func (api *PickitAPI) handleUpdateRule(w http.ResponseWriter, r *http.Request) {
 	rule.GeneratedNIP = nipLine
 
 	characterID := r.URL.Query().Get("character")
-	if err := api.updateRule(characterID, &rule); err != nil {
+	if err := api.updateRule(characterID, &rule, historySource(r)); err != nil {
 		http.Error(w, fmt.Sprintf("Error updating rule: %v", err), http.StatusInternalServerError)
 		return
 	}
@@ -237,7 +239,7 @@ func (api *PickitAPI) handleDeleteRule(w http.ResponseWriter, r *http.Request) {
 	ruleID := r.URL.Query().Get("id")
 	characterID := r.URL.Query().Get("character")
 
-	if err := api.deleteRule(characterID, ruleID); err != nil {
+	if err := api.deleteRule(characterID, ruleID, historySource(r)); err != nil {
 		http.Error(w, fmt.Sprintf("Error deleting rule: %v", err), http.StatusInternalServerError)
 		return
 	}
@@ -652,7 +654,7 @@ func (api *PickitAPI) loadCharacterRules(characterID string) ([]pickit.PickitRul
 	return rules, nil
 }
 
-func (api *PickitAPI) saveRule(characterID string, rule *pickit.PickitRule) error {
+func (api *PickitAPI) saveRule(characterID string, rule *pickit.PickitRule, source history.Source) error {
 	// Get character's pickit directory
 	pickitDir := filepath.Join("config", characterID, "pickit")
 
@@ -688,14 +690,14 @@ func (api *PickitAPI) saveRule(characterID string, rule *pickit.PickitRule) erro
 	content += nipLine + "\n"
 
 	// Write back to file
-	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+	if err := config.WriteConfigFile(filePath, []byte(content), source); err != nil {
 		return fmt.Errorf("failed to write pickit file: %w", err)
 	}
 
 	return nil
 }
 
-func (api *PickitAPI) updateRule(characterID string, rule *pickit.PickitRule) error {
+func (api *PickitAPI) updateRule(characterID string, rule *pickit.PickitRule, source history.Source) error {
 	// Parse rule ID to get file and line number
 	parts := strings.Split(rule.ID, ":")
 	if len(parts) != 2 {
@@ -725,14 +727,14 @@ func (api *PickitAPI) updateRule(characterID string, rule *pickit.PickitRule) er
 	}
 
 	// Write back
-	if err := os.WriteFile(filePath, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+	if err := config.WriteConfigFile(filePath, []byte(strings.Join(lines, "\n")), source); err != nil {
 		return fmt.Errorf("failed to update pickit file: %w", err)
 	}
 
 	return nil
 }
 
-func (api *PickitAPI) deleteRule(characterID, ruleID string) error {
+func (api *PickitAPI) deleteRule(characterID, ruleID string, source history.Source) error {
 	// Parse rule ID to get file and line number
 	parts := strings.Split(ruleID, ":")
 	if len(parts) != 2 {
@@ -756,7 +758,7 @@ func (api *PickitAPI) deleteRule(characterID, ruleID string) error {
 	}
 
 	// Write back
-	if err := os.WriteFile(filePath, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+	if err := config.WriteConfigFile(filePath, []byte(strings.Join(lines, "\n")), source); err != nil {
 		return fmt.Errorf("failed to delete from pickit file: %w", err)
 	}
 
@@ -925,7 +927,7 @@ func (api *PickitAPI) handleDeleteFileRule(w http.ResponseWriter, r *http.Reques
 
 	// Write back to file
 	newContent := strings.Join(newLines, "\n")
-	if err := os.WriteFile(filePath, []byte(newContent), 0644); err != nil {
+	if err := config.WriteConfigFile(filePath, []byte(newContent), historySource(r)); err != nil {
 		api.sendError(w, fmt.Sprintf("Failed to write file: %v", err), http.StatusInternalServerError)
 		return
 	}
@@ -1021,7 +1023,7 @@ func (api *PickitAPI) handleUpdateFileRule(w http.ResponseWriter, r *http.Reques
 
 	// Write back to file
 	newContent := strings.Join(lines, "\n")
-	if err := os.WriteFile(filePath, []byte(newContent), 0644); err != nil {
+	if err := config.WriteConfigFile(filePath, []byte(newContent), historySource(r)); err != nil {
 		api.sendError(w, fmt.Sprintf("Failed to write file: %v", err), http.StatusInternalServerError)
 		return
 	}
@@ -1102,7 +1104,7 @@ func (api *PickitAPI) handleAppendNIPLine(w http.ResponseWriter, r *http.Request
 	content += requestData.NIPLine + "\n"
 
 	// Write back to file
-	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+	if err := config.WriteConfigFile(filePath, []byte(content), historySource(r)); err != nil {
 		api.sendError(w, fmt.Sprintf("Failed to write file: %v", err), http.StatusInternalServerError)
 		return
 	}
@@ -0,0 +1,47 @@
+package server
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/hectorgimenez/koolo/internal/config"
+)
+
+// ProfileAPI serves the character profile files, like the inheritance between profiles
+type ProfileAPI struct {
+	logger *slog.Logger
+}
+
+func NewProfileAPI(logger *slog.Logger) *ProfileAPI {
+	return &ProfileAPI{logger: logger}
+}
+
+func (api *ProfileAPI) handleGetInheritance(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	supervisor := r.URL.Query().Get("supervisor")
+	if _, found := config.GetCharacter(supervisor); !found {
+		http.Error(w, "character not found", http.StatusNotFound)
+		return
+	}
+
+	inheritance, err := config.GetProfileInheritance(supervisor)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	api.writeJSON(w, http.StatusOK, inheritance)
+}
+
+func (api *ProfileAPI) writeJSON(w http.ResponseWriter, status int, payload any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(payload); err != nil {
+		api.logger.Error("failed to write JSON response", slog.Any("error", err))
+	}
+}
@@ -0,0 +1,60 @@
+package server
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/hectorgimenez/koolo/internal/bot"
+	"github.com/hectorgimenez/koolo/internal/config"
+)
+
+// ProgressAPI serves the persisted leveling progress, it's available even when the character is not running
+type ProgressAPI struct {
+	logger *slog.Logger
+}
+
+func NewProgressAPI(logger *slog.Logger) *ProgressAPI {
+	return &ProgressAPI{logger: logger}
+}
+
+type progressResponse struct {
+	bot.LevelingProgressSummary
+	Current bot.ProgressSample   `json:"current"`
+	Games   []bot.ProgressSample `json:"games"`
+}
+
+func (api *ProgressAPI) handleGetProgress(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	characterName := r.URL.Query().Get("characterName")
+	cfg, found := config.GetCharacter(characterName)
+	if !found {
+		http.Error(w, "character not found", http.StatusNotFound)
+		return
+	}
+
+	progress, err := bot.LoadLevelingProgress(characterName)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := progressResponse{
+		LevelingProgressSummary: progress.Summary(cfg.Game.StopLevelingAt, time.Now()),
+		Current:                 progress.Current,
+		Games:                   progress.Games,
+	}
+	if response.Games == nil {
+		response.Games = make([]bot.ProgressSample, 0)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(response); err != nil {
+		api.logger.Error("failed to write JSON response", slog.Any("error", err))
+	}
+}
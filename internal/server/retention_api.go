@@ -0,0 +1,56 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/hectorgimenez/koolo/internal/retention"
+)
+
+// RetentionAPI serves the disk usage of the logs, screenshots and droplogs, and runs the retention on demand
+type RetentionAPI struct {
+	logger  *slog.Logger
+	service *retention.Service
+}
+
+func NewRetentionAPI(logger *slog.Logger, service *retention.Service) *RetentionAPI {
+	return &RetentionAPI{logger: logger, service: service}
+}
+
+func (api *RetentionAPI) handleStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	api.writeJSON(w, http.StatusOK, api.service.Status())
+}
+
+// handleRun applies the retention policies now, the status is returned with the updated disk usage
+func (api *RetentionAPI) handleRun(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if _, err := api.service.Run(); err != nil {
+		if errors.Is(err, retention.ErrDisabled) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		// The errors are in the results too, the rest of the targets were applied
+		api.logger.Warn("Retention run finished with errors", slog.Any("error", err))
+	}
+
+	api.writeJSON(w, http.StatusOK, api.service.Status())
+}
+
+func (api *RetentionAPI) writeJSON(w http.ResponseWriter, status int, payload any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(payload); err != nil {
+		api.logger.Error("failed to write JSON response", slog.Any("error", err))
+	}
+}
@@ -0,0 +1,127 @@
+package server
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/hectorgimenez/koolo/internal/config"
+)
+
+// SecretsAPI manages the secrets store, secret values are write only and never returned
+type SecretsAPI struct {
+	logger *slog.Logger
+}
+
+func NewSecretsAPI(logger *slog.Logger) *SecretsAPI {
+	return &SecretsAPI{logger: logger}
+}
+
+type secretsStatus struct {
+	Unlocked bool     `json:"unlocked"`
+	Names    []string `json:"names"`
+	Error    string   `json:"error,omitempty"`
+}
+
+type secretRequest struct {
+	Name       string `json:"name"`
+	Value      string `json:"value"`
+	Passphrase string `json:"passphrase"`
+}
+
+func (api *SecretsAPI) handleList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	store, err := config.SecretsStore()
+	if err != nil {
+		api.writeJSON(w, http.StatusOK, secretsStatus{Names: make([]string, 0), Error: err.Error()})
+		return
+	}
+
+	api.writeJSON(w, http.StatusOK, secretsStatus{Unlocked: true, Names: store.Names()})
+}
+
+// handleSet stores a new secret or rotates the value of an existing one
+func (api *SecretsAPI) handleSet(w http.ResponseWriter, r *http.Request) {
+	req, ok := api.readRequest(w, r)
+	if !ok {
+		return
+	}
+
+	store, err := config.SecretsStore()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err = store.Set(req.Name, req.Value); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	api.logger.Info("Secret updated", slog.String("name", req.Name))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (api *SecretsAPI) handleDelete(w http.ResponseWriter, r *http.Request) {
+	req, ok := api.readRequest(w, r)
+	if !ok {
+		return
+	}
+
+	store, err := config.SecretsStore()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err = store.Delete(req.Name); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	api.logger.Info("Secret deleted", slog.String("name", req.Name))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleUnlock opens the store with a passphrase and reloads the config, so the profiles using secrets are enabled
+func (api *SecretsAPI) handleUnlock(w http.ResponseWriter, r *http.Request) {
+	req, ok := api.readRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := config.UnlockSecrets(req.Passphrase); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if err := config.Load(); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (api *SecretsAPI) readRequest(w http.ResponseWriter, r *http.Request) (secretRequest, bool) {
+	var req secretRequest
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return req, false
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return req, false
+	}
+
+	return req, true
+}
+
+func (api *SecretsAPI) writeJSON(w http.ResponseWriter, status int, payload any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(payload); err != nil {
+		api.logger.Error("failed to write JSON response", slog.Any("error", err))
+	}
+}
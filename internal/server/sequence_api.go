@@ -12,7 +12,9 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/hectorgimenez/koolo/internal/buildplan"
 	"github.com/hectorgimenez/koolo/internal/config"
+	"github.com/hectorgimenez/koolo/internal/config/history"
 	"github.com/hectorgimenez/koolo/internal/run"
 	"github.com/hectorgimenez/koolo/internal/utils"
 )
@@ -23,8 +25,9 @@ type SequenceAPI struct {
 }
 
 type sequenceLoadResponse struct {
-	Name     string                       `json:"name"`
-	Settings run.LevelingSequenceSettings `json:"settings"`
+	Name            string                       `json:"name"`
+	Settings        run.LevelingSequenceSettings `json:"settings"`
+	ValidationError string                       `json:"validationError,omitempty"`
 }
 
 type sequenceSaveRequest struct {
@@ -63,6 +66,28 @@ type sequenceFilesResponse struct {
 	Files []string `json:"files"`
 }
 
+type buildSimulationRequest struct {
+	Class string `json:"class"`
+	// Build is optional, the class build file is used when it's not set
+	Build *config.LevelingBuildConfig `json:"build,omitempty"`
+	// Quests is optional, the default quest timeline is used when it's not set
+	Quests   []buildplan.QuestCompletion `json:"quests,omitempty"`
+	MaxLevel int                         `json:"maxLevel,omitempty"`
+}
+
+type buildSimulationResponse struct {
+	Valid  bool                        `json:"valid"`
+	Quests []buildplan.QuestCompletion `json:"quests"`
+	buildplan.Result
+}
+
+type sequenceDryRunRequest struct {
+	// Name loads the sequence file, ignored when Settings is set (unsaved changes in the editor)
+	Name     string                        `json:"name,omitempty"`
+	Settings *run.LevelingSequenceSettings `json:"settings,omitempty"`
+	State    run.DryRunState               `json:"state"`
+}
+
 func NewSequenceAPI(logger *slog.Logger) *SequenceAPI {
 	return &SequenceAPI{
 		logger:       logger,
@@ -152,10 +177,15 @@ func (api *SequenceAPI) handleGetSequence(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	api.writeJSON(w, http.StatusOK, sequenceLoadResponse{
+	response := sequenceLoadResponse{
 		Name:     name,
 		Settings: settings,
-	})
+	}
+	if err := validateSequence(&settings); err != nil {
+		response.ValidationError = err.Error()
+	}
+
+	api.writeJSON(w, http.StatusOK, response)
 }
 
 func (api *SequenceAPI) handleBrowseSequence(w http.ResponseWriter, r *http.Request) {
@@ -249,7 +279,12 @@ func (api *SequenceAPI) handleSaveSequence(w http.ResponseWriter, r *http.Reques
 
 	api.normalizeSettings(&req.Settings)
 
-	if err := api.writeSequenceFile(filepath.Join(dir, name+".json"), req.Settings, true); err != nil {
+	if err := validateSequence(&req.Settings); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := api.writeSequenceFile(filepath.Join(dir, name+".json"), req.Settings, true, historySource(r)); err != nil {
 		api.internalError(w, fmt.Errorf("failed to save sequence: %w", err))
 		return
 	}
@@ -296,6 +331,100 @@ func (api *SequenceAPI) handleDeleteSequence(w http.ResponseWriter, r *http.Requ
 	api.writeJSON(w, http.StatusOK, map[string]string{"status": "deleted"})
 }
 
+func (api *SequenceAPI) handleSimulateBuild(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	defer r.Body.Close()
+
+	var req buildSimulationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("invalid request payload: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	// Also validates the class before using it as a file name
+	if _, found := buildplan.ClassFromConfig(req.Class); !found {
+		http.Error(w, fmt.Sprintf("unknown class %q", req.Class), http.StatusBadRequest)
+		return
+	}
+
+	if req.Build == nil {
+		build, err := config.LoadLevelingBuild(req.Class)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if build == nil {
+			http.Error(w, "class doesn't have a leveling build file", http.StatusNotFound)
+			return
+		}
+		req.Build = build
+	}
+	if req.Quests == nil {
+		req.Quests = buildplan.DefaultQuestTimeline()
+	}
+	if req.MaxLevel == 0 {
+		req.MaxLevel = buildplan.MaxLevel
+	}
+
+	result, err := buildplan.Simulate(req.Class, req.Build, req.Quests, req.MaxLevel)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	api.writeJSON(w, http.StatusOK, buildSimulationResponse{
+		Valid:  result.Valid(),
+		Quests: req.Quests,
+		Result: result,
+	})
+}
+
+func (api *SequenceAPI) handleDryRunSequence(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	defer r.Body.Close()
+
+	var req sequenceDryRunRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if req.Settings == nil {
+		name, err := api.extractSequenceName(req.Name)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		dir, err := api.baseDir()
+		if err != nil {
+			api.internalError(w, fmt.Errorf("failed to resolve sequence directory: %w", err))
+			return
+		}
+
+		settings, err := api.readSequenceFile(filepath.Join(dir, name+".json"))
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				http.Error(w, "sequence file not found", http.StatusNotFound)
+				return
+			}
+			api.internalError(w, fmt.Errorf("failed to read sequence file: %w", err))
+			return
+		}
+		req.Settings = &settings
+	}
+
+	api.writeJSON(w, http.StatusOK, run.DryRun(*req.Settings, req.State))
+}
+
 func (api *SequenceAPI) baseDir() (string, error) {
 	cwd, err := os.Getwd()
 	if err != nil {
@@ -371,7 +500,7 @@ func (api *SequenceAPI) extractSequenceName(raw string) (string, error) {
 	return raw, nil
 }
 
-func (api *SequenceAPI) writeSequenceFile(path string, settings run.LevelingSequenceSettings, overwrite bool) error {
+func (api *SequenceAPI) writeSequenceFile(path string, settings run.LevelingSequenceSettings, overwrite bool, source history.Source) error {
 	if !overwrite {
 		if _, err := os.Stat(path); err == nil {
 			return fmt.Errorf("file already exists: %s", path)
@@ -387,7 +516,7 @@ func (api *SequenceAPI) writeSequenceFile(path string, settings run.LevelingSequ
 
 	payload = append(payload, '\n')
 
-	if err := os.WriteFile(path, payload, 0o644); err != nil {
+	if err := config.WriteConfigFile(path, payload, source); err != nil {
 		return err
 	}
 
@@ -435,6 +564,11 @@ func (api *SequenceAPI) normalizeSettings(settings *run.LevelingSequenceSettings
 	normalizeDifficulty(&settings.Hell)
 }
 
+// validateSequence returns every invalid condition and config override, they would make the sequence fail to load
+func validateSequence(settings *run.LevelingSequenceSettings) error {
+	return errors.Join(run.ValidateConditions(settings), run.ValidateConfigOverrides(settings))
+}
+
 func (api *SequenceAPI) writeJSON(w http.ResponseWriter, status int, payload any) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
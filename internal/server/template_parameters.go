@@ -58,7 +58,14 @@ type CharacterSettings struct {
 	RunewordRecipeList    []string
 	AvailableProfiles     []string
 	FarmerProfiles        []string
+	BaseProfiles          []string
 	LevelingSequenceFiles []string
+	// GameSettings are the Settings.json overrides of the profile as indented JSON
+	GameSettings string
+}
+
+type LogsData struct {
+	Supervisors []string
 }
 
 type ConfigData struct {